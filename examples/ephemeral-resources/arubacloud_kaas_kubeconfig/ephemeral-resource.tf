@@ -0,0 +1,13 @@
+ephemeral "arubacloud_kaas_kubeconfig" "cluster" {
+  id         = arubacloud_kaas.basic.id
+  project_id = arubacloud_kaas.basic.project_id
+}
+
+# Credentials are fetched at plan/apply time and never written to state.
+provider "kubernetes" {
+  host                   = ephemeral.arubacloud_kaas_kubeconfig.cluster.host
+  cluster_ca_certificate = ephemeral.arubacloud_kaas_kubeconfig.cluster.cluster_ca_certificate
+  client_certificate     = ephemeral.arubacloud_kaas_kubeconfig.cluster.client_certificate
+  client_key             = ephemeral.arubacloud_kaas_kubeconfig.cluster.client_key
+  token                  = ephemeral.arubacloud_kaas_kubeconfig.cluster.token
+}
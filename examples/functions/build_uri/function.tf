@@ -0,0 +1,5 @@
+# Reference an existing VPC and subnet that are not managed by this configuration.
+locals {
+  vpc_uri    = provider::arubacloud::build_uri("vpc", var.project_id, var.vpc_id)
+  subnet_uri = provider::arubacloud::build_uri("subnet", var.project_id, var.subnet_id, var.vpc_id)
+}
@@ -0,0 +1,8 @@
+variable "vpc_uri" {
+  type = string
+
+  validation {
+    condition     = provider::arubacloud::is_uri(var.vpc_uri)
+    error_message = "vpc_uri must be an ArubaCloud resource URI such as /projects/<project_id>/providers/Aruba.Network/vpcs/<vpc_id>."
+  }
+}
@@ -0,0 +1,11 @@
+locals {
+  server = provider::arubacloud::parse_uri(arubacloud_cloudserver.example.uri)
+}
+
+output "server_project_id" {
+  value = local.server.project_id # e.g. "proj-abc"
+}
+
+output "server_kind" {
+  value = local.server.kind # "cloudserver"
+}
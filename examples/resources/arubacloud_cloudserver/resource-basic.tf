@@ -16,7 +16,8 @@ resource "arubacloud_cloudserver" "basic" {
     flavor_name      = "CSO4A8"  # 4 CPU, 8GB RAM (see https://api.arubacloud.com/docs/metadata/#cloudserver-flavors)
     key_pair_uri_ref = arubacloud_keypair.example.uri
     # Optional: cloud-init user data for bootstrapping (raw cloud-init YAML content)
-    # user_data      = file("cloud-init.yaml")
+    # user_data_wo         = file("cloud-init.yaml")
+    # user_data_wo_version = 1
   }
 
   storage = {
@@ -1,5 +1,7 @@
 resource "arubacloud_dbaasuser" "example" {
   dbaas_id = "example-dbaas-id"
   username = "example-user"
-  password = "example-password"
+  # Write-only: never stored in state (Terraform 1.11+).
+  password_wo         = "example-password"
+  password_wo_version = 1
 }
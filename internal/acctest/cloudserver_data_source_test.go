@@ -94,7 +94,10 @@ resource "arubacloud_blockstorage" "boot" {
   type           = "Standard"
   bootable       = true
   image          = %[2]q
-  timeout        = "2h"
+
+  timeouts {
+    create = "2h"
+  }
 }
 
 resource "arubacloud_cloudserver" "test" {
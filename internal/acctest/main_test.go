@@ -0,0 +1,99 @@
+package acctest
+
+import (
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/Arubacloud/terraform-provider-arubacloud/internal/fakeapi"
+)
+
+// TestMain runs the acceptance tests against the in-memory fake API instead
+// of the live one when ARUBACLOUD_ACC_FAKE=1, and otherwise sets up
+// recording or replaying cassettes.
+func TestMain(m *testing.M) {
+	if os.Getenv("ARUBACLOUD_ACC_FAKE") != "1" {
+		if err := setupCassettes(); err != nil {
+			fmt.Fprintf(os.Stderr, "cannot set up cassettes: %v\n", err)
+			os.Exit(1)
+		}
+		os.Exit(m.Run())
+	}
+	srv, err := startFakeAPI()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "cannot start the fake ArubaCloud API: %v\n", err)
+		os.Exit(1)
+	}
+	code := m.Run()
+	srv.Close()
+	os.Exit(code)
+}
+
+// Fixtures seeded in the fake API for the tests that expect existing
+// resources.
+const (
+	fakeProjectID   = "fake-project"
+	fakeDBaaSID     = "fake-dbaas"
+	fakeDatabase    = "fakedb"
+	fakeVPNTunnelID = "fake-vpntunnel"
+	fakeVPNRouteID  = "fake-vpnroute"
+	fakeBackupID    = "fake-backup"
+)
+
+// startFakeAPI starts the fake API, seeds the fixtures and points the
+// provider and the test environment at it. Every variable the tests read is
+// overridden, so that a live project or credential is never used.
+func startFakeAPI() (*fakeapi.Server, error) {
+	srv := fakeapi.New()
+	project := "/projects/" + fakeProjectID
+	dbaas := project + "/providers/Aruba.Database/dbaas/" + fakeDBaaSID
+	tunnel := project + "/providers/Aruba.Network/vpnTunnels/" + fakeVPNTunnelID
+	seeds := []struct {
+		path string
+		body map[string]any
+	}{
+		{project, map[string]any{"metadata": map[string]any{"name": "acceptance"}}},
+		{dbaas, map[string]any{"metadata": map[string]any{"name": "acceptance", "location": map[string]any{"value": "ITBG-Bergamo"}}}},
+		{dbaas + "/databases/" + fakeDatabase, map[string]any{"name": fakeDatabase}},
+		{tunnel, map[string]any{"metadata": map[string]any{"name": "acceptance", "location": map[string]any{"value": "ITBG-Bergamo"}}}},
+		{tunnel + "/vpnRoutes/" + fakeVPNRouteID, map[string]any{"metadata": map[string]any{"name": "acceptance"}}},
+		{project + "/providers/Aruba.Storage/backups/" + fakeBackupID, map[string]any{"metadata": map[string]any{"name": "acceptance"}}},
+	}
+	for _, seed := range seeds {
+		if err := srv.Seed(seed.path, seed.body); err != nil {
+			srv.Close()
+			return nil, err
+		}
+	}
+
+	env := map[string]string{
+		"TF_ACC":                             "1",
+		"ARUBACLOUD_BASE_URL":                srv.URL,
+		"ARUBACLOUD_TOKEN_ISSUER_URL":        srv.TokenURL(),
+		"ARUBACLOUD_CLIENT_ID":               "fake-client",
+		"ARUBACLOUD_CLIENT_SECRET":           "fake-secret",
+		"ARUBACLOUD_PROJECT_ID":              fakeProjectID,
+		"ARUBACLOUD_LOCATION":                "ITBG-Bergamo",
+		"ARUBACLOUD_ZONE":                    "ITBG-1",
+		"ARUBACLOUD_OS_IMAGE_ID":             "ubuntu-22.04",
+		"ARUBACLOUD_KAAS_NODE_INSTANCE":      "K2A4",
+		"ARUBACLOUD_DBAAS_ID":                fakeDBaaSID,
+		"ARUBACLOUD_DATABASE_NAME":           fakeDatabase,
+		"ARUBACLOUD_DBAAS_PASSWORD":          "Fake-Passw0rd!",
+		"ARUBACLOUD_VPNTUNNEL_ID":            fakeVPNTunnelID,
+		"ARUBACLOUD_VPNROUTE_ID":             fakeVPNRouteID,
+		"ARUBACLOUD_BACKUP_ID":               fakeBackupID,
+		"ARUBACLOUD_SSH_PUBLIC_KEY":          "ssh-ed25519 AAAAC3NzaC1lZDI1NTE5AAAAIGZha2Uta2V5LWZvci1vZmZsaW5lLWFjY2VwdGFuY2UtdGVzdHM fake@acceptance",
+		"ARUBACLOUD_SHARED_CREDENTIALS_FILE": os.DevNull,
+	}
+	for k, v := range env {
+		if err := os.Setenv(k, v); err != nil {
+			srv.Close()
+			return nil, err
+		}
+	}
+	for _, k := range []string{"ARUBACLOUD_ACCESS_TOKEN", "ARUBACLOUD_PROFILE", "ARUBACLOUD_TOKEN_CACHE_DIR"} {
+		_ = os.Unsetenv(k)
+	}
+	return srv, nil
+}
@@ -16,13 +16,18 @@ import (
 )
 
 // ProtoV6ProviderFactories is used to instantiate a provider during acceptance testing.
+// The provider is built on each call, once TestMain has chosen the cassette mode.
 var ProtoV6ProviderFactories = map[string]func() (tfprotov6.ProviderServer, error){
-	"arubacloud": providerserver.NewProtocol6WithError(provider.New("test")()),
+	"arubacloud": func() (tfprotov6.ProviderServer, error) {
+		return providerserver.NewProtocol6WithError(provider.NewWithTransport("test", cassetteTransport, cassetteOptions()...)())()
+	},
 }
 
-// PreCheck verifies the required environment variables are set before running acceptance tests.
+// PreCheck verifies the required environment variables are set before running acceptance tests,
+// and selects the test's cassette when recording or replaying.
 func PreCheck(t *testing.T) {
 	t.Helper()
+	useCassette(t)
 	for _, env := range []string{"ARUBACLOUD_CLIENT_ID", "ARUBACLOUD_CLIENT_SECRET"} {
 		if os.Getenv(env) == "" {
 			t.Fatalf("acceptance tests require %s to be set", env)
@@ -37,7 +42,22 @@ func AccClient() (*provider.ArubaCloudClient, error) {
 	if clientID == "" || clientSecret == "" {
 		return nil, fmt.Errorf("ARUBACLOUD_CLIENT_ID and ARUBACLOUD_CLIENT_SECRET must be set")
 	}
-	sdkClient, err := aruba.NewClient(aruba.DefaultOptions(clientID, clientSecret))
+	opts := aruba.DefaultOptions(clientID, clientSecret)
+	// Set by TestMain when the tests run against the fake API.
+	if baseURL := os.Getenv("ARUBACLOUD_BASE_URL"); baseURL != "" {
+		opts = opts.WithBaseURL(baseURL)
+	}
+	if tokenIssuerURL := os.Getenv("ARUBACLOUD_TOKEN_ISSUER_URL"); tokenIssuerURL != "" {
+		opts = opts.WithTokenIssuerURL(tokenIssuerURL)
+	}
+	httpClient, err := cassetteHTTPClient()
+	if err != nil {
+		return nil, fmt.Errorf("failed to open cassette: %w", err)
+	}
+	if httpClient != nil {
+		opts = opts.WithCustomHTTPClient(httpClient)
+	}
+	sdkClient, err := aruba.NewClient(opts)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create test client: %w", err)
 	}
@@ -0,0 +1,292 @@
+// Package cassette records the HTTP traffic of an acceptance test to a file
+// and replays it later without network access or credentials.
+//
+// A cassette is a JSON-lines file with one Interaction per line. While
+// recording, every request is sent to the live API and the sanitized
+// request/response pair is appended to the file. While replaying, requests
+// are answered from the file and nothing leaves the process.
+//
+// Replays are deterministic and fast: interactions are matched per method
+// and path in the order they were recorded, so the interleaving of parallel
+// requests does not matter, and transitional responses (a resource still
+// InCreation, a 409 conflict, a 429 or 5xx) that were followed by another
+// attempt are skipped, so wait loops and retries finish on their first try.
+// Other failures, such as a transient 400, are replayed; the provider's
+// WithReplayWaits option keeps the retries they cause from waiting.
+package cassette
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+)
+
+// Interaction is one recorded request/response pair.
+type Interaction struct {
+	Request  Request  `json:"request"`
+	Response Response `json:"response"`
+}
+
+// Request is the recorded part of a request. Headers are not recorded, and
+// the body is kept for reference only; it is not used for matching.
+type Request struct {
+	Method string `json:"method"`
+	URL    string `json:"url"`
+	Body   string `json:"body,omitempty"`
+}
+
+// Response is a recorded response.
+type Response struct {
+	Status int         `json:"status"`
+	Header http.Header `json:"header,omitempty"`
+	Body   string      `json:"body,omitempty"`
+}
+
+// Cassette is an open cassette file. It is safe for concurrent use.
+type Cassette struct {
+	path   string
+	record bool
+
+	mu   sync.Mutex
+	file *os.File
+	// queues holds the interactions not yet replayed, per key.
+	queues map[string][]*Interaction
+	// last holds the last interaction replayed per key, served again once
+	// the queue is exhausted.
+	last map[string]*Interaction
+}
+
+var (
+	openMu sync.Mutex
+	open   = map[string]*Cassette{}
+)
+
+// Load returns the cassette at path, shared by every caller in the process,
+// so that all provider instances and test helpers of one test append to, or
+// replay from, the same recording. When recording, the file is truncated on
+// the first Load; when replaying, it must exist.
+func Load(path string, record bool) (*Cassette, error) {
+	openMu.Lock()
+	defer openMu.Unlock()
+	if c, ok := open[path]; ok {
+		if c.record != record {
+			return nil, fmt.Errorf("cassette %s is already open for %s", path, c.mode())
+		}
+		return c, nil
+	}
+	c := &Cassette{path: path, record: record, queues: map[string][]*Interaction{}, last: map[string]*Interaction{}}
+	var err error
+	if record {
+		err = c.create()
+	} else {
+		err = c.read()
+	}
+	if err != nil {
+		return nil, err
+	}
+	open[path] = c
+	return c, nil
+}
+
+// Recording reports whether the cassette records live traffic.
+func (c *Cassette) Recording() bool {
+	return c.record
+}
+
+func (c *Cassette) mode() string {
+	if c.record {
+		return "recording"
+	}
+	return "replay"
+}
+
+func (c *Cassette) create() error {
+	if err := os.MkdirAll(filepath.Dir(c.path), 0o755); err != nil {
+		return fmt.Errorf("creating cassette directory: %w", err)
+	}
+	f, err := os.OpenFile(c.path, os.O_CREATE|os.O_TRUNC|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return fmt.Errorf("creating cassette: %w", err)
+	}
+	c.file = f
+	return nil
+}
+
+func (c *Cassette) read() error {
+	f, err := os.Open(c.path)
+	if err != nil {
+		return fmt.Errorf("opening cassette: %w", err)
+	}
+	defer f.Close()
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		if len(bytes.TrimSpace(scanner.Bytes())) == 0 {
+			continue
+		}
+		var in Interaction
+		if err := json.Unmarshal(scanner.Bytes(), &in); err != nil {
+			return fmt.Errorf("cassette %s line %d: %w", c.path, line, err)
+		}
+		k := key(in.Request.Method, in.Request.URL)
+		c.queues[k] = append(c.queues[k], &in)
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("reading cassette %s: %w", c.path, err)
+	}
+	return nil
+}
+
+// Transport returns a RoundTripper that records the traffic sent through
+// base, or replays it without calling base.
+func (c *Cassette) Transport(base http.RoundTripper) http.RoundTripper {
+	if c.record {
+		return &recorder{cassette: c, base: base}
+	}
+	return &player{cassette: c}
+}
+
+// append writes in to the cassette.
+func (c *Cassette) append(in *Interaction) error {
+	line, err := json.Marshal(in)
+	if err != nil {
+		return err
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	_, err = c.file.Write(append(line, '\n'))
+	return err
+}
+
+// next returns the interaction answering a request with key k, skipping
+// superseded ones.
+func (c *Cassette) next(k string) (*Interaction, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	queue := c.queues[k]
+	for len(queue) > 1 && transitional(queue[0]) {
+		queue = queue[1:]
+	}
+	if len(queue) == 0 {
+		in, ok := c.last[k]
+		return in, ok
+	}
+	in := queue[0]
+	c.queues[k] = queue[1:]
+	c.last[k] = in
+	return in, true
+}
+
+// transitional reports whether a recorded response is one a client waits
+// out or retries: a conflict, throttling, a server error, or a resource
+// still changing state.
+func transitional(in *Interaction) bool {
+	switch status := in.Response.Status; {
+	case status == http.StatusConflict, status == http.StatusTooManyRequests, status >= 500:
+		return true
+	case in.Request.Method != http.MethodGet || status != http.StatusOK:
+		return false
+	}
+	var body struct {
+		Status struct {
+			State string `json:"state"`
+		} `json:"status"`
+	}
+	if json.Unmarshal([]byte(in.Response.Body), &body) != nil {
+		return false
+	}
+	switch body.Status.State {
+	case "InCreation", "Creating", "Updating", "Deleting", "Pending", "Provisioning":
+		return true
+	}
+	return false
+}
+
+// recorder sends requests to the live API and records them.
+type recorder struct {
+	cassette *Cassette
+	base     http.RoundTripper
+}
+
+func (r *recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	var reqBody []byte
+	if req.Body != nil && req.Body != http.NoBody {
+		var err error
+		if reqBody, err = io.ReadAll(req.Body); err != nil {
+			return nil, err
+		}
+		_ = req.Body.Close()
+		req = req.Clone(req.Context())
+		req.Body = io.NopCloser(bytes.NewReader(reqBody))
+	}
+	resp, err := r.base.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	respBody, err := io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(respBody))
+
+	in := &Interaction{
+		Request: Request{
+			Method: req.Method,
+			URL:    sanitizeURL(req.URL),
+			Body:   sanitizeBody(req.Header.Get("Content-Type"), reqBody),
+		},
+		Response: Response{
+			Status: resp.StatusCode,
+			Header: sanitizeHeader(resp.Header),
+			Body:   sanitizeBody(resp.Header.Get("Content-Type"), respBody),
+		},
+	}
+	if err := r.cassette.append(in); err != nil {
+		return nil, fmt.Errorf("recording to cassette %s: %w", r.cassette.path, err)
+	}
+	return resp, nil
+}
+
+// player answers requests from the cassette.
+type player struct {
+	cassette *Cassette
+}
+
+func (p *player) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Body != nil {
+		_, _ = io.Copy(io.Discard, req.Body)
+		_ = req.Body.Close()
+	}
+	in, ok := p.cassette.next(key(req.Method, sanitizeURL(req.URL)))
+	if !ok {
+		return nil, fmt.Errorf("cassette %s has no recorded response for %s %s; record it again with ARUBACLOUD_RECORD=1",
+			p.cassette.path, req.Method, req.URL.Path)
+	}
+	header := in.Response.Header.Clone()
+	if header == nil {
+		header = http.Header{}
+	}
+	// A replay must not wait for delays that only mattered live.
+	if header.Get("Retry-After") != "" {
+		header.Set("Retry-After", "0")
+	}
+	return &http.Response{
+		Status:        strconv.Itoa(in.Response.Status) + " " + http.StatusText(in.Response.Status),
+		StatusCode:    in.Response.Status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(bytes.NewReader([]byte(in.Response.Body))),
+		ContentLength: int64(len(in.Response.Body)),
+		Request:       req,
+	}, nil
+}
@@ -0,0 +1,150 @@
+package cassette
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+func get(t *testing.T, client *http.Client, url string) (int, string) {
+	t.Helper()
+	resp, err := client.Get(url)
+	if err != nil {
+		t.Fatalf("GET %s: %v", url, err)
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(resp.Body)
+	return resp.StatusCode, string(body)
+}
+
+func TestRecordAndReplay(t *testing.T) {
+	var polls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Set-Cookie", "session=abc")
+		switch r.URL.Path {
+		case "/token":
+			_, _ = io.WriteString(w, `{"access_token":"live-token","expires_in":300}`)
+		case "/vpcs/a":
+			state := "InCreation"
+			if polls.Add(1) > 2 {
+				state = "Active"
+			}
+			_, _ = io.WriteString(w, `{"metadata":{"id":"a"},"status":{"state":"`+state+`"}}`)
+		case "/busy":
+			if polls.Add(1) == 4 {
+				w.Header().Set("Retry-After", "30")
+				w.WriteHeader(http.StatusTooManyRequests)
+				return
+			}
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	path := filepath.Join(t.TempDir(), "TestRecordAndReplay.jsonl")
+	rec, err := Load(path, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	live := &http.Client{Transport: rec.Transport(http.DefaultTransport)}
+	get(t, live, srv.URL+"/token")
+	for i := 0; i < 3; i++ {
+		get(t, live, srv.URL+"/vpcs/a")
+	}
+	get(t, live, srv.URL+"/busy")
+	get(t, live, srv.URL+"/busy")
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(data), "live-token") || strings.Contains(string(data), "session=abc") {
+		t.Errorf("cassette contains secrets:\n%s", data)
+	}
+	if n := strings.Count(string(data), "\n"); n != 6 {
+		t.Errorf("cassette has %d interactions, want 6", n)
+	}
+
+	// Replay from a fresh process view of the file, with the server gone.
+	srv.Close()
+	delete(open, path)
+	play, err := Load(path, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	replay := &http.Client{Transport: play.Transport(nil)}
+
+	if _, body := get(t, replay, "http://replay.invalid/token"); !strings.Contains(body, Redacted) {
+		t.Errorf("token body = %s, want the token redacted", body)
+	}
+	// The InCreation polls are skipped: the first poll sees Active, and so
+	// do polls beyond those recorded.
+	for i := 0; i < 4; i++ {
+		if _, body := get(t, replay, "http://replay.invalid/vpcs/a"); !strings.Contains(body, "Active") {
+			t.Errorf("poll %d = %s, want Active", i, body)
+		}
+	}
+	// The 429 was followed by a retry, so it is skipped too.
+	if status, _ := get(t, replay, "http://replay.invalid/busy"); status != http.StatusNoContent {
+		t.Errorf("busy status = %d, want 204", status)
+	}
+
+	if _, err := replay.Get("http://replay.invalid/unknown"); err == nil || !strings.Contains(err.Error(), "no recorded response") {
+		t.Errorf("unrecorded request error = %v", err)
+	}
+}
+
+func TestLoadIsShared(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "shared.jsonl")
+	a, err := Load(path, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := Load(path, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if a != b {
+		t.Error("Load returned different cassettes for the same path")
+	}
+	if _, err := Load(path, false); err == nil {
+		t.Error("Load for replay of a cassette being recorded succeeded")
+	}
+	if _, err := Load(filepath.Join(t.TempDir(), "missing.jsonl"), false); err == nil {
+		t.Error("Load for replay of a missing cassette succeeded")
+	}
+}
+
+func TestSanitizeBody(t *testing.T) {
+	tests := []struct {
+		name        string
+		contentType string
+		body        string
+		want        string
+	}{
+		{"json", "application/json",
+			`{"name":"db","password":"p","properties":{"userData":"#cloud-config","settings":{"psk":"k"}},"tags":["a"]}`,
+			`{"name":"db","password":"REDACTED","properties":{"settings":{"psk":"REDACTED"},"userData":"REDACTED"},"tags":["a"]}`},
+		{"json numbers are kept exactly", "", `{"size":12345678901234567890}`, `{"size":12345678901234567890}`},
+		{"form", "application/x-www-form-urlencoded", "client_id=id&client_secret=s&grant_type=client_credentials",
+			"client_id=id&client_secret=REDACTED&grant_type=client_credentials"},
+		{"text", "text/plain", "bad gateway", "bad gateway"},
+		{"binary", "application/octet-stream", "\x00\x01", Redacted},
+		{"empty", "application/json", "", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := sanitizeBody(tt.contentType, []byte(tt.body)); got != tt.want {
+				t.Errorf("sanitizeBody() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
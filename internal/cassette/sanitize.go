@@ -0,0 +1,135 @@
+package cassette
+
+import (
+	"bytes"
+	"encoding/json"
+	"mime"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// Redacted replaces every secret in a cassette.
+const Redacted = "REDACTED"
+
+// sensitiveKeys are the JSON keys, form fields and query parameters whose
+// values are redacted, compared case-insensitively with "_" and "-"
+// removed. Keys containing "password" or "secret" are always redacted.
+var sensitiveKeys = map[string]bool{
+	"accesstoken":  true,
+	"refreshtoken": true,
+	"idtoken":      true,
+	"token":        true,
+	"psk":          true,
+	"presharedkey": true,
+	"kubeconfig":   true,
+	"userdata":     true,
+	"privatekey":   true,
+	"apikey":       true,
+}
+
+// keptHeaders are the response headers recorded. Everything else, including
+// cookies and dates, is dropped.
+var keptHeaders = []string{"Content-Type", "Location", "Retry-After"}
+
+func sensitive(key string) bool {
+	k := strings.ToLower(strings.NewReplacer("_", "", "-", "").Replace(key))
+	return sensitiveKeys[k] || strings.Contains(k, "password") || strings.Contains(k, "secret")
+}
+
+// key identifies the requests an interaction can answer: the method, path
+// and query, but not the host, so that a cassette replays against any
+// base_url.
+func key(method, rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return method + " " + rawURL
+	}
+	k := method + " " + u.Path
+	if u.RawQuery != "" {
+		k += "?" + u.RawQuery
+	}
+	return k
+}
+
+// sanitizeURL returns u with sensitive query parameters redacted and the
+// query in canonical order.
+func sanitizeURL(u *url.URL) string {
+	c := *u
+	c.User = nil
+	if c.RawQuery != "" {
+		c.RawQuery = redactValues(c.Query()).Encode()
+	}
+	return c.String()
+}
+
+func redactValues(values url.Values) url.Values {
+	for k := range values {
+		if sensitive(k) {
+			values[k] = []string{Redacted}
+		}
+	}
+	return values
+}
+
+// sanitizeHeader returns the recorded subset of h.
+func sanitizeHeader(h http.Header) http.Header {
+	out := http.Header{}
+	for _, name := range keptHeaders {
+		if v := h.Values(name); len(v) > 0 {
+			out[name] = v
+		}
+	}
+	if len(out) == 0 {
+		return nil
+	}
+	return out
+}
+
+// sanitizeBody returns body with the values of sensitive keys redacted.
+// JSON and form bodies are redacted field by field; other bodies are kept
+// only when they are text.
+func sanitizeBody(contentType string, body []byte) string {
+	if len(bytes.TrimSpace(body)) == 0 {
+		return ""
+	}
+	dec := json.NewDecoder(bytes.NewReader(body))
+	dec.UseNumber()
+	var v interface{}
+	if err := dec.Decode(&v); err == nil && !dec.More() {
+		out, err := json.Marshal(redactJSON(v))
+		if err == nil {
+			return string(out)
+		}
+	}
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+	switch {
+	case mediaType == "application/x-www-form-urlencoded":
+		values, err := url.ParseQuery(string(body))
+		if err != nil {
+			return Redacted
+		}
+		return redactValues(values).Encode()
+	case strings.HasPrefix(mediaType, "text/"):
+		return string(body)
+	}
+	return Redacted
+}
+
+func redactJSON(v interface{}) interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		for k, item := range v {
+			if _, isString := item.(string); isString && sensitive(k) {
+				v[k] = Redacted
+				continue
+			}
+			v[k] = redactJSON(item)
+		}
+	case []interface{}:
+		for i, item := range v {
+			v[i] = redactJSON(item)
+		}
+	}
+	return v
+}
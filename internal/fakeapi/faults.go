@@ -0,0 +1,94 @@
+package fakeapi
+
+import (
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Fault describes an injected failure. A request matches when its method and
+// path match; the first matching fault that has not been used up applies.
+type Fault struct {
+	// Method restricts the fault to one HTTP method; empty matches any.
+	Method string
+	// Path restricts the fault to request paths containing it; empty
+	// matches any path, including the token endpoint.
+	Path string
+	// Times is the number of requests the fault applies to; 0 means every
+	// matching request.
+	Times int
+
+	// Latency delays the response. On its own it does not fail the request.
+	Latency time.Duration
+	// Status answers with this HTTP status and a problem-details body
+	// instead of serving the request, e.g. 500, 503 or 429.
+	Status int
+	// RetryAfter is sent as the Retry-After header with Status, e.g. "2".
+	RetryAfter string
+	// EOF closes the connection without a response.
+	EOF bool
+
+	hits int
+}
+
+// Inject adds a fault. Faults are matched in the order they were added.
+func (s *Server) Inject(f Fault) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.faults = append(s.faults, &f)
+}
+
+// ClearFaults removes every injected fault.
+func (s *Server) ClearFaults() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.faults = nil
+}
+
+// applyFault applies the fault matching r, if any, and reports whether it
+// answered the request.
+func (s *Server) applyFault(w http.ResponseWriter, r *http.Request) bool {
+	s.mu.Lock()
+	var fault Fault
+	found := false
+	for _, f := range s.faults {
+		if (f.Method != "" && f.Method != r.Method) || !strings.Contains(r.URL.Path, f.Path) {
+			continue
+		}
+		if f.Times > 0 && f.hits >= f.Times {
+			continue
+		}
+		f.hits++
+		fault, found = *f, true
+		break
+	}
+	s.mu.Unlock()
+	if !found {
+		return false
+	}
+
+	if fault.Latency > 0 {
+		select {
+		case <-time.After(fault.Latency):
+		case <-r.Context().Done():
+			return true
+		}
+	}
+	switch {
+	case fault.EOF:
+		if hj, ok := w.(http.Hijacker); ok {
+			if conn, _, err := hj.Hijack(); err == nil {
+				_ = conn.Close()
+				return true
+			}
+		}
+		panic(http.ErrAbortHandler)
+	case fault.Status != 0:
+		if fault.RetryAfter != "" {
+			w.Header().Set("Retry-After", fault.RetryAfter)
+		}
+		writeProblem(w, fault.Status, http.StatusText(fault.Status), "injected fault")
+		return true
+	}
+	return false
+}
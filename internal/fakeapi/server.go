@@ -0,0 +1,569 @@
+// Package fakeapi is a stateful, in-memory fake of the ArubaCloud REST API.
+//
+// It serves the OAuth2 token endpoint and the project, Aruba.Network,
+// Aruba.Compute, Aruba.Storage, Aruba.Database, Aruba.Container,
+// Aruba.Schedule and Aruba.Security namespaces, so that the provider can run
+// full create, read, update and delete cycles without credentials or network
+// access. Resources are stored by URI and echo the request body back with the
+// fields the API assigns (metadata.id, metadata.uri, timestamps and
+// status.state). Created and updated resources go through InCreation or
+// Updating before they are Active, resources that still have children or are
+// referenced by another resource cannot be deleted, and faults such as
+// latency, 5xx and 429 responses or dropped connections can be injected.
+//
+// The fake depends only on the standard library so that both the provider
+// unit tests and the acceptance tests can use it.
+package fakeapi
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// TokenPath is the path of the OAuth2 token endpoint.
+const TokenPath = "/token"
+
+// Resource states reported in status.state.
+const (
+	StateInCreation = "InCreation"
+	StateUpdating   = "Updating"
+	StateActive     = "Active"
+	StateDeleting   = "Deleting"
+)
+
+// namespaces lists the provider namespaces the fake serves.
+var namespaces = map[string]bool{
+	"Aruba.Network":   true,
+	"Aruba.Compute":   true,
+	"Aruba.Storage":   true,
+	"Aruba.Database":  true,
+	"Aruba.Container": true,
+	"Aruba.Schedule":  true,
+	"Aruba.Security":  true,
+}
+
+// collectionAliases maps the collection spellings used by the SDK to the
+// spelling the API returns in URIs.
+var collectionAliases = map[string]string{
+	"blockStorages": "volumes",
+	"registries":    "containerRegistries",
+	"elasticIps":    "elasticIPs",
+}
+
+// Server is a fake ArubaCloud API listening on a local address. Point the
+// provider base_url at URL and token_issuer_url at URL+TokenPath.
+type Server struct {
+	*httptest.Server
+
+	activateAfter int
+	deleteAfter   int
+	now           func() time.Time
+	clientID      string
+	clientSecret  string
+
+	mu       sync.Mutex
+	objects  map[string]*object
+	faults   []*Fault
+	nextID   int
+	tokens   int
+	requests []string
+}
+
+// object is a stored resource. pending counts the reads left before a
+// transitional state settles.
+type object struct {
+	body    map[string]any
+	pending int
+}
+
+// Option configures a Server.
+type Option func(*Server)
+
+// WithActivateAfter sets how many reads see a created or updated resource in
+// InCreation or Updating before it is Active. The default is 1; 0 makes
+// resources Active at once.
+func WithActivateAfter(reads int) Option {
+	return func(s *Server) { s.activateAfter = reads }
+}
+
+// WithDeleteAfter sets how many reads see a deleted resource in Deleting
+// before it is gone. The default is 0, which removes it at once.
+func WithDeleteAfter(reads int) Option {
+	return func(s *Server) { s.deleteAfter = reads }
+}
+
+// WithClock sets the clock used for creation and update timestamps.
+func WithClock(now func() time.Time) Option {
+	return func(s *Server) { s.now = now }
+}
+
+// WithCredentials makes the token endpoint accept only the given client ID
+// and secret. By default any non-empty pair is accepted.
+func WithCredentials(clientID, clientSecret string) Option {
+	return func(s *Server) { s.clientID, s.clientSecret = clientID, clientSecret }
+}
+
+// New starts a fake API. Close it when done.
+func New(opts ...Option) *Server {
+	s := &Server{
+		activateAfter: 1,
+		now:           time.Now,
+		objects:       make(map[string]*object),
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	s.Server = httptest.NewServer(s)
+	return s
+}
+
+// TokenURL returns the URL of the token endpoint.
+func (s *Server) TokenURL() string {
+	return s.URL + TokenPath
+}
+
+// ServeHTTP implements http.Handler.
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	s.requests = append(s.requests, r.Method+" "+r.URL.Path)
+	s.mu.Unlock()
+
+	if s.applyFault(w, r) {
+		return
+	}
+	if r.URL.Path == TokenPath {
+		s.serveToken(w, r)
+		return
+	}
+	if !strings.HasPrefix(r.Header.Get("Authorization"), "Bearer ") {
+		writeProblem(w, http.StatusUnauthorized, "Unauthorized", "missing bearer token")
+		return
+	}
+
+	key, item, err := canonicalPath(r.URL.Path)
+	if err != nil {
+		writeProblem(w, http.StatusNotFound, "Not Found", err.Error())
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	switch {
+	case !item && r.Method == http.MethodGet:
+		s.list(w, key)
+	case !item && r.Method == http.MethodPost:
+		s.create(w, r, key)
+	case item && r.Method == http.MethodGet:
+		s.get(w, key)
+	case item && (r.Method == http.MethodPut || r.Method == http.MethodPatch):
+		s.update(w, r, key)
+	case item && r.Method == http.MethodDelete:
+		s.delete(w, key)
+	default:
+		writeProblem(w, http.StatusMethodNotAllowed, "Method Not Allowed", r.Method+" "+r.URL.Path)
+	}
+}
+
+// serveToken answers client-credentials token requests.
+func (s *Server) serveToken(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeProblem(w, http.StatusMethodNotAllowed, "Method Not Allowed", r.Method+" "+r.URL.Path)
+		return
+	}
+	_ = r.ParseForm()
+	clientID, clientSecret, ok := r.BasicAuth()
+	if !ok {
+		clientID, clientSecret = r.PostForm.Get("client_id"), r.PostForm.Get("client_secret")
+	}
+	valid := clientID != "" && clientSecret != ""
+	if s.clientID != "" {
+		valid = clientID == s.clientID && clientSecret == s.clientSecret
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if r.PostForm.Get("grant_type") != "client_credentials" || !valid {
+		w.WriteHeader(http.StatusUnauthorized)
+		_ = json.NewEncoder(w).Encode(map[string]string{"error": "invalid_client"})
+		return
+	}
+	s.mu.Lock()
+	s.tokens++
+	n := s.tokens
+	s.mu.Unlock()
+	_ = json.NewEncoder(w).Encode(map[string]any{
+		"access_token": fmt.Sprintf("fake-token-%d", n),
+		"token_type":   "Bearer",
+		"expires_in":   3600,
+	})
+}
+
+// canonicalPath validates an API path and returns it with SDK collection
+// spellings replaced by the API ones. item reports whether the path names a
+// single resource rather than a collection.
+func canonicalPath(p string) (key string, item bool, err error) {
+	segs := strings.Split(strings.Trim(p, "/"), "/")
+	if segs[0] != "projects" {
+		return "", false, fmt.Errorf("unknown path %s", p)
+	}
+	for _, seg := range segs {
+		if seg == "" {
+			return "", false, fmt.Errorf("empty segment in %s", p)
+		}
+	}
+	if len(segs) <= 2 {
+		return "/" + strings.Join(segs, "/"), len(segs) == 2, nil
+	}
+	if len(segs) < 5 || segs[2] != "providers" || !namespaces[segs[3]] {
+		return "", false, fmt.Errorf("unknown path %s", p)
+	}
+	for i := 4; i < len(segs); i += 2 {
+		if alias, ok := collectionAliases[segs[i]]; ok {
+			segs[i] = alias
+		}
+	}
+	return "/" + strings.Join(segs, "/"), len(segs)%2 == 0, nil
+}
+
+// parentOf returns the resource a collection belongs to, or "" for the
+// project collection.
+func parentOf(collection string) string {
+	segs := strings.Split(strings.Trim(collection, "/"), "/")
+	switch {
+	case len(segs) == 1:
+		return ""
+	case len(segs) == 5:
+		// /projects/<id>/providers/<namespace>/<collection>
+		return "/" + strings.Join(segs[:2], "/")
+	default:
+		return "/" + strings.Join(segs[:len(segs)-1], "/")
+	}
+}
+
+func (s *Server) list(w http.ResponseWriter, collection string) {
+	if parent := parentOf(collection); parent != "" && s.objects[parent] == nil {
+		writeProblem(w, http.StatusNotFound, "Not Found", parent+" does not exist")
+		return
+	}
+	values := []any{}
+	for _, key := range s.sortedKeys() {
+		if strings.HasPrefix(key, collection+"/") && !strings.Contains(key[len(collection)+1:], "/") {
+			values = append(values, s.objects[key].body)
+		}
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"total": len(values), "values": values})
+}
+
+func (s *Server) create(w http.ResponseWriter, r *http.Request, collection string) {
+	if parent := parentOf(collection); parent != "" && s.objects[parent] == nil {
+		writeProblem(w, http.StatusNotFound, "Not Found", parent+" does not exist")
+		return
+	}
+	body := map[string]any{}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil && !errors.Is(err, io.EOF) {
+		writeProblem(w, http.StatusBadRequest, "Bad Request", "invalid JSON body: "+err.Error())
+		return
+	}
+
+	id := s.newID(body)
+	key := collection + "/" + id
+	if s.objects[key] != nil {
+		writeProblem(w, http.StatusConflict, "Conflict", key+" already exists")
+		return
+	}
+	obj := &object{body: body, pending: s.activateAfter}
+	s.assign(key, obj, StateInCreation)
+	s.objects[key] = obj
+	writeJSON(w, http.StatusCreated, obj.body)
+}
+
+func (s *Server) get(w http.ResponseWriter, key string) {
+	obj := s.objects[key]
+	if obj == nil {
+		writeProblem(w, http.StatusNotFound, "Not Found", key+" does not exist")
+		return
+	}
+	writeJSON(w, http.StatusOK, obj.body)
+	if obj.pending > 0 {
+		obj.pending--
+		if obj.pending == 0 {
+			if stateOf(obj.body) == StateDeleting {
+				delete(s.objects, key)
+				return
+			}
+			setState(obj.body, StateActive)
+		}
+	}
+}
+
+func (s *Server) update(w http.ResponseWriter, r *http.Request, key string) {
+	obj := s.objects[key]
+	if obj == nil {
+		writeProblem(w, http.StatusNotFound, "Not Found", key+" does not exist")
+		return
+	}
+	if state := stateOf(obj.body); state != StateActive {
+		writeProblem(w, http.StatusConflict, "Conflict", fmt.Sprintf("%s is %s", key, state))
+		return
+	}
+	patch := map[string]any{}
+	if err := json.NewDecoder(r.Body).Decode(&patch); err != nil {
+		writeProblem(w, http.StatusBadRequest, "Bad Request", "invalid JSON body: "+err.Error())
+		return
+	}
+	for k, v := range patch {
+		if k == "metadata" || k == "status" {
+			continue
+		}
+		obj.body[k] = v
+	}
+	if meta, ok := patch["metadata"].(map[string]any); ok {
+		stored := metadataOf(obj.body)
+		for k, v := range meta {
+			switch k {
+			case "id", "uri", "project", "creationDate", "createdBy":
+			default:
+				stored[k] = v
+			}
+		}
+		stored["updateDate"] = s.timestamp()
+	}
+	obj.pending = s.activateAfter
+	if obj.pending > 0 {
+		setState(obj.body, StateUpdating)
+	}
+	writeJSON(w, http.StatusOK, obj.body)
+}
+
+func (s *Server) delete(w http.ResponseWriter, key string) {
+	obj := s.objects[key]
+	if obj == nil {
+		writeProblem(w, http.StatusNotFound, "Not Found", key+" does not exist")
+		return
+	}
+	if reason := s.dependents(key); reason != "" {
+		writeProblem(w, http.StatusConflict, "Conflict", fmt.Sprintf("cannot delete %s: %s", key, reason))
+		return
+	}
+	if s.deleteAfter == 0 {
+		delete(s.objects, key)
+	} else {
+		obj.pending = s.deleteAfter
+		setState(obj.body, StateDeleting)
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// dependents describes why key cannot be deleted: a child resource nested
+// under it, or another resource whose body holds its URI. It returns "" when
+// nothing depends on key.
+func (s *Server) dependents(key string) string {
+	segs := strings.Split(key, "/")
+	id, collection := segs[len(segs)-1], segs[len(segs)-2]
+	spellings := []string{collection}
+	for alias, canonical := range collectionAliases {
+		if canonical == collection {
+			spellings = append(spellings, alias)
+		}
+	}
+	for _, other := range s.sortedKeys() {
+		if strings.HasPrefix(other, key+"/") {
+			return "it still contains " + other
+		}
+	}
+	for _, other := range s.sortedKeys() {
+		if other == key || strings.HasPrefix(other, key+"/") {
+			continue
+		}
+		raw, _ := json.Marshal(s.objects[other].body)
+		for _, spelling := range spellings {
+			if strings.Contains(string(raw), "/"+spelling+"/"+id+`"`) {
+				return "it is referenced by " + other
+			}
+		}
+	}
+	return ""
+}
+
+// newID returns the ID of a resource created from body. Resources without
+// metadata, such as databases and DBaaS users, are identified by their name.
+func (s *Server) newID(body map[string]any) string {
+	if _, ok := body["metadata"]; !ok {
+		for _, field := range []string{"name", "username"} {
+			if v, ok := body[field].(string); ok && v != "" {
+				return url.PathEscape(v)
+			}
+		}
+	}
+	s.nextID++
+	return fmt.Sprintf("fa4e%020d", s.nextID)
+}
+
+// assign sets the fields the API assigns to a new resource.
+func (s *Server) assign(key string, obj *object, transitional string) {
+	segs := strings.Split(strings.Trim(key, "/"), "/")
+	id := segs[len(segs)-1]
+	if _, ok := obj.body["metadata"]; ok || len(segs) == 2 {
+		meta := metadataOf(obj.body)
+		meta["id"] = id
+		meta["uri"] = key
+		meta["creationDate"] = s.timestamp()
+		if len(segs) > 2 {
+			meta["project"] = map[string]any{"id": segs[1]}
+		}
+	}
+	if defaults := computedDefaults[segs[len(segs)-2]]; defaults != nil {
+		defaults(s, obj.body)
+	}
+	state := StateActive
+	if obj.pending > 0 {
+		state = transitional
+	}
+	setState(obj.body, state)
+}
+
+// computedDefaults fills server-computed properties of some collections.
+var computedDefaults = map[string]func(s *Server, body map[string]any){
+	"elasticIPs": func(s *Server, body map[string]any) {
+		props, _ := body["properties"].(map[string]any)
+		if props == nil {
+			props = map[string]any{}
+			body["properties"] = props
+		}
+		if _, ok := props["address"]; !ok {
+			props["address"] = fmt.Sprintf("203.0.113.%d", s.nextID%254+1)
+		}
+	},
+}
+
+// Seed stores a resource at path, as if it had been created and were
+// Active. Use it for fixtures the tests do not create, such as an existing
+// project or DBaaS cluster. Parents are not required to exist.
+func (s *Server) Seed(path string, body map[string]any) error {
+	key, item, err := canonicalPath(path)
+	if err != nil {
+		return err
+	}
+	if !item {
+		return fmt.Errorf("%s is a collection, not a resource", path)
+	}
+	if body == nil {
+		body = map[string]any{}
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	obj := &object{body: body}
+	s.assign(key, obj, StateActive)
+	s.objects[key] = obj
+	return nil
+}
+
+// Remove deletes the resource stored at path and everything nested under it,
+// as an out-of-band deletion would, without the dependency checks of DELETE.
+// It reports whether the resource existed.
+func (s *Server) Remove(path string) bool {
+	key, _, err := canonicalPath(path)
+	if err != nil {
+		return false
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, found := s.objects[key]
+	for k := range s.objects {
+		if k == key || strings.HasPrefix(k, key+"/") {
+			delete(s.objects, k)
+		}
+	}
+	return found
+}
+
+// Get returns a copy of the resource stored at path.
+func (s *Server) Get(path string) (map[string]any, bool) {
+	key, _, err := canonicalPath(path)
+	if err != nil {
+		return nil, false
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	obj := s.objects[key]
+	if obj == nil {
+		return nil, false
+	}
+	raw, _ := json.Marshal(obj.body)
+	var body map[string]any
+	_ = json.Unmarshal(raw, &body)
+	return body, true
+}
+
+// Paths returns the paths of all stored resources, sorted.
+func (s *Server) Paths() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.sortedKeys()
+}
+
+// Requests returns the "METHOD path" of every request served so far,
+// including token requests.
+func (s *Server) Requests() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string(nil), s.requests...)
+}
+
+func (s *Server) sortedKeys() []string {
+	keys := make([]string, 0, len(s.objects))
+	for k := range s.objects {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func (s *Server) timestamp() string {
+	return s.now().UTC().Format(time.RFC3339)
+}
+
+// metadataOf returns the metadata object of body, creating it if needed.
+func metadataOf(body map[string]any) map[string]any {
+	meta, ok := body["metadata"].(map[string]any)
+	if !ok {
+		meta = map[string]any{}
+		body["metadata"] = meta
+	}
+	return meta
+}
+
+func stateOf(body map[string]any) string {
+	status, _ := body["status"].(map[string]any)
+	state, _ := status["state"].(string)
+	return state
+}
+
+func setState(body map[string]any, state string) {
+	body["status"] = map[string]any{"state": state}
+}
+
+func writeJSON(w http.ResponseWriter, status int, body any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(body)
+}
+
+// writeProblem writes an RFC 7807 problem-details response, as the API does
+// for errors.
+func writeProblem(w http.ResponseWriter, status int, title, detail string) {
+	w.Header().Set("Content-Type", "application/problem+json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(map[string]any{
+		"title":  title,
+		"detail": detail,
+		"status": status,
+	})
+}
@@ -0,0 +1,281 @@
+package fakeapi
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+)
+
+// client sends authenticated JSON requests to a fake API.
+type client struct {
+	t   *testing.T
+	srv *Server
+}
+
+func newClient(t *testing.T, opts ...Option) *client {
+	t.Helper()
+	srv := New(opts...)
+	t.Cleanup(srv.Close)
+	return &client{t: t, srv: srv}
+}
+
+// do sends a request and returns the status code and the decoded body.
+func (c *client) do(method, path string, body any) (int, map[string]any) {
+	c.t.Helper()
+	var reader io.Reader
+	if body != nil {
+		raw, _ := json.Marshal(body)
+		reader = strings.NewReader(string(raw))
+	}
+	req, _ := http.NewRequest(method, c.srv.URL+path, reader)
+	req.Header.Set("Authorization", "Bearer test")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		c.t.Fatalf("%s %s: %v", method, path, err)
+	}
+	defer resp.Body.Close()
+	var decoded map[string]any
+	_ = json.NewDecoder(resp.Body).Decode(&decoded)
+	return resp.StatusCode, decoded
+}
+
+func state(body map[string]any) string {
+	return stateOf(body)
+}
+
+func metadata(body map[string]any) map[string]any {
+	meta, _ := body["metadata"].(map[string]any)
+	return meta
+}
+
+func TestToken(t *testing.T) {
+	c := newClient(t, WithCredentials("id", "secret"))
+
+	resp, err := http.PostForm(c.srv.TokenURL(), url.Values{
+		"grant_type": {"client_credentials"}, "client_id": {"id"}, "client_secret": {"secret"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var token map[string]any
+	_ = json.NewDecoder(resp.Body).Decode(&token)
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK || token["access_token"] != "fake-token-1" {
+		t.Errorf("token response = %d %v", resp.StatusCode, token)
+	}
+
+	resp, err = http.PostForm(c.srv.TokenURL(), url.Values{
+		"grant_type": {"client_credentials"}, "client_id": {"id"}, "client_secret": {"wrong"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusUnauthorized {
+		t.Errorf("wrong secret: status = %d, want 401", resp.StatusCode)
+	}
+
+	req, _ := http.NewRequest(http.MethodGet, c.srv.URL+"/projects", nil)
+	resp, err = http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusUnauthorized {
+		t.Errorf("request without token: status = %d, want 401", resp.StatusCode)
+	}
+}
+
+func TestLifecycle(t *testing.T) {
+	c := newClient(t, WithClock(func() time.Time { return time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC) }))
+
+	status, project := c.do(http.MethodPost, "/projects", map[string]any{"metadata": map[string]any{"name": "p"}})
+	if status != http.StatusCreated {
+		t.Fatalf("create project: status = %d", status)
+	}
+	projectID := metadata(project)["id"].(string)
+
+	vpcs := "/projects/" + projectID + "/providers/Aruba.Network/vpcs"
+	status, vpc := c.do(http.MethodPost, vpcs, map[string]any{
+		"metadata":   map[string]any{"name": "net", "location": map[string]any{"value": "ITBG-Bergamo"}},
+		"properties": map[string]any{"default": false},
+	})
+	if status != http.StatusCreated || state(vpc) != StateInCreation {
+		t.Fatalf("create vpc: %d %v", status, vpc)
+	}
+	meta := metadata(vpc)
+	uri := meta["uri"].(string)
+	if uri != vpcs+"/"+meta["id"].(string) || meta["creationDate"] != "2026-01-02T03:04:05Z" {
+		t.Errorf("assigned metadata = %v", meta)
+	}
+	if project := meta["project"].(map[string]any); project["id"] != projectID {
+		t.Errorf("metadata.project = %v", project)
+	}
+
+	// The first read sees InCreation, the next ones Active.
+	if _, got := c.do(http.MethodGet, uri, nil); state(got) != StateInCreation {
+		t.Errorf("first read state = %q", state(got))
+	}
+	if _, got := c.do(http.MethodGet, uri, nil); state(got) != StateActive || metadata(got)["name"] != "net" {
+		t.Errorf("second read = %v", got)
+	}
+
+	status, updated := c.do(http.MethodPut, uri, map[string]any{"metadata": map[string]any{"name": "renamed", "id": "other"}})
+	if status != http.StatusOK || state(updated) != StateUpdating || metadata(updated)["name"] != "renamed" || metadata(updated)["id"] == "other" {
+		t.Errorf("update = %d %v", status, updated)
+	}
+	c.do(http.MethodGet, uri, nil)
+
+	if _, list := c.do(http.MethodGet, vpcs, nil); list["total"] != float64(1) {
+		t.Errorf("list = %v", list)
+	}
+
+	if status, _ := c.do(http.MethodDelete, uri, nil); status != http.StatusNoContent {
+		t.Errorf("delete: status = %d", status)
+	}
+	if status, _ := c.do(http.MethodGet, uri, nil); status != http.StatusNotFound {
+		t.Errorf("read after delete: status = %d, want 404", status)
+	}
+}
+
+func TestAliasesAndFlatResources(t *testing.T) {
+	c := newClient(t, WithActivateAfter(0))
+	_ = c.srv.Seed("/projects/p1", nil)
+	if err := c.srv.Seed("/projects/p1/providers/Aruba.Database/dbaas/d1", map[string]any{"metadata": map[string]any{"name": "db"}}); err != nil {
+		t.Fatal(err)
+	}
+
+	status, vol := c.do(http.MethodPost, "/projects/p1/providers/Aruba.Storage/blockStorages", map[string]any{"metadata": map[string]any{"name": "v"}})
+	if status != http.StatusCreated || state(vol) != StateActive {
+		t.Fatalf("create volume: %d %v", status, vol)
+	}
+	uri := metadata(vol)["uri"].(string)
+	if !strings.Contains(uri, "/volumes/") {
+		t.Errorf("uri = %q, want the API spelling", uri)
+	}
+	if status, _ := c.do(http.MethodGet, strings.Replace(uri, "/volumes/", "/blockStorages/", 1), nil); status != http.StatusOK {
+		t.Errorf("read with the SDK spelling: status = %d", status)
+	}
+
+	status, db := c.do(http.MethodPost, "/projects/p1/providers/Aruba.Database/dbaas/d1/databases", map[string]any{"name": "app"})
+	if status != http.StatusCreated || db["name"] != "app" {
+		t.Fatalf("create database: %d %v", status, db)
+	}
+	if status, _ := c.do(http.MethodGet, "/projects/p1/providers/Aruba.Database/dbaas/d1/databases/app", nil); status != http.StatusOK {
+		t.Errorf("read database by name: status = %d", status)
+	}
+	if status, _ := c.do(http.MethodPost, "/projects/p1/providers/Aruba.Database/dbaas/d1/databases", map[string]any{"name": "app"}); status != http.StatusConflict {
+		t.Errorf("duplicate database: status = %d, want 409", status)
+	}
+	if status, _ := c.do(http.MethodPost, "/projects/p1/providers/Aruba.Database/dbaas/missing/databases", map[string]any{"name": "app"}); status != http.StatusNotFound {
+		t.Errorf("database in a missing cluster: status = %d, want 404", status)
+	}
+	if status, _ := c.do(http.MethodGet, "/projects/p1/providers/Aruba.Unknown/things", nil); status != http.StatusNotFound {
+		t.Errorf("unknown namespace: status = %d, want 404", status)
+	}
+}
+
+func TestDeleteConflicts(t *testing.T) {
+	c := newClient(t, WithActivateAfter(0), WithDeleteAfter(1))
+	_ = c.srv.Seed("/projects/p1", nil)
+
+	_, vpc := c.do(http.MethodPost, "/projects/p1/providers/Aruba.Network/vpcs", map[string]any{"metadata": map[string]any{"name": "net"}})
+	vpcURI := metadata(vpc)["uri"].(string)
+	_, subnet := c.do(http.MethodPost, vpcURI+"/subnets", map[string]any{"metadata": map[string]any{"name": "sub"}})
+	subnetURI := metadata(subnet)["uri"].(string)
+	_, server := c.do(http.MethodPost, "/projects/p1/providers/Aruba.Compute/cloudServers", map[string]any{
+		"metadata":   map[string]any{"name": "vm"},
+		"properties": map[string]any{"subnets": []any{map[string]any{"uri": subnetURI}}},
+	})
+	serverURI := metadata(server)["uri"].(string)
+
+	if status, body := c.do(http.MethodDelete, vpcURI, nil); status != http.StatusConflict || !strings.Contains(body["detail"].(string), "contains") {
+		t.Errorf("delete vpc with a subnet = %d %v, want 409", status, body)
+	}
+	if status, body := c.do(http.MethodDelete, subnetURI, nil); status != http.StatusConflict || !strings.Contains(body["detail"].(string), serverURI) {
+		t.Errorf("delete referenced subnet = %d %v, want 409 naming the server", status, body)
+	}
+
+	// Deleting resources are reported once, then gone.
+	c.do(http.MethodDelete, serverURI, nil)
+	if _, got := c.do(http.MethodGet, serverURI, nil); state(got) != StateDeleting {
+		t.Errorf("state after delete = %q, want Deleting", state(got))
+	}
+	if status, _ := c.do(http.MethodGet, serverURI, nil); status != http.StatusNotFound {
+		t.Errorf("second read after delete: status = %d, want 404", status)
+	}
+	if status, _ := c.do(http.MethodDelete, subnetURI, nil); status != http.StatusNoContent {
+		t.Errorf("delete unreferenced subnet: status = %d", status)
+	}
+}
+
+func TestFaults(t *testing.T) {
+	c := newClient(t)
+	_ = c.srv.Seed("/projects/p1", nil)
+
+	c.srv.Inject(Fault{Method: http.MethodGet, Path: "/projects/p1", Status: http.StatusServiceUnavailable, Times: 1})
+	if status, _ := c.do(http.MethodGet, "/projects/p1", nil); status != http.StatusServiceUnavailable {
+		t.Errorf("first read: status = %d, want 503", status)
+	}
+	if status, _ := c.do(http.MethodGet, "/projects/p1", nil); status != http.StatusOK {
+		t.Errorf("second read: status = %d, want the fault used up", status)
+	}
+
+	c.srv.Inject(Fault{Path: "/projects", Status: http.StatusTooManyRequests, RetryAfter: "2", Times: 1})
+	req, _ := http.NewRequest(http.MethodGet, c.srv.URL+"/projects", nil)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusTooManyRequests || resp.Header.Get("Retry-After") != "2" {
+		t.Errorf("throttled response = %d, Retry-After %q", resp.StatusCode, resp.Header.Get("Retry-After"))
+	}
+
+	c.srv.Inject(Fault{Path: "/projects", Latency: 50 * time.Millisecond, Times: 1})
+	start := time.Now()
+	if status, _ := c.do(http.MethodGet, "/projects/p1", nil); status != http.StatusOK {
+		t.Errorf("slow read: status = %d", status)
+	}
+	if elapsed := time.Since(start); elapsed < 50*time.Millisecond {
+		t.Errorf("slow read took %s", elapsed)
+	}
+
+	c.srv.Inject(Fault{Path: "/projects", EOF: true, Times: 1})
+	// A fresh connection, so that the client does not retry the request on
+	// another one.
+	fresh := &http.Client{Transport: &http.Transport{DisableKeepAlives: true}}
+	req, _ = http.NewRequest(http.MethodGet, c.srv.URL+"/projects/p1", nil)
+	if _, err := fresh.Do(req); err == nil || !(errors.Is(err, io.EOF) || strings.Contains(err.Error(), "EOF")) {
+		t.Errorf("dropped connection error = %v, want EOF", err)
+	}
+
+	c.srv.ClearFaults()
+	if status, _ := c.do(http.MethodGet, "/projects/p1", nil); status != http.StatusOK {
+		t.Errorf("read after ClearFaults: status = %d", status)
+	}
+}
+
+func TestSeedAndRemove(t *testing.T) {
+	c := newClient(t)
+	if err := c.srv.Seed("/projects/p1/providers/Aruba.Network/vpcs", nil); err == nil {
+		t.Error("Seed() of a collection should fail")
+	}
+	_ = c.srv.Seed("/projects/p1", nil)
+	_ = c.srv.Seed("/projects/p1/providers/Aruba.Network/vpcs/v1", map[string]any{"metadata": map[string]any{"name": "net"}})
+	if body, ok := c.srv.Get("/projects/p1/providers/Aruba.Network/vpcs/v1"); !ok || state(body) != StateActive {
+		t.Errorf("seeded vpc = %v, %v", body, ok)
+	}
+
+	if !c.srv.Remove("/projects/p1") {
+		t.Error("Remove() of a seeded project reported it missing")
+	}
+	if paths := c.srv.Paths(); len(paths) != 0 {
+		t.Errorf("paths after Remove() = %v, want none", paths)
+	}
+}
@@ -0,0 +1,124 @@
+package provider
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+)
+
+// accessTokenClientID is the placeholder client ID handed to the SDK when
+// the provider authenticates with access_token. The SDK still runs its
+// client-credentials flow, but apiTransport answers the token request with
+// the supplied token, so the placeholder never leaves the process.
+const accessTokenClientID = "terraform-provider-arubacloud-access-token"
+
+// defaultAccessTokenLifetime is reported to the SDK as the lifetime of an
+// access token whose expiry is unknown.
+const defaultAccessTokenLifetime = time.Hour
+
+// accessToken is a bearer token supplied by the user instead of client
+// credentials.
+type accessToken struct {
+	value string
+	// expiry is read from the exp claim when the token is a JWT; it is zero
+	// for opaque tokens.
+	expiry time.Time
+}
+
+// newAccessToken wraps value, reading its expiry when it is a JWT. The
+// signature is not verified; the expiry is only used for warnings.
+func newAccessToken(value string) *accessToken {
+	return &accessToken{value: value, expiry: jwtExpiry(value)}
+}
+
+// jwtExpiry returns the exp claim of a JWT, or the zero time when token is
+// not a JWT or has no exp claim.
+func jwtExpiry(token string) time.Time {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return time.Time{}
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(parts[1], "="))
+	if err != nil {
+		return time.Time{}
+	}
+	var claims struct {
+		Exp json.Number `json:"exp"`
+	}
+	if err := json.Unmarshal(payload, &claims); err != nil {
+		return time.Time{}
+	}
+	exp, err := claims.Exp.Float64()
+	if err != nil || exp <= 0 {
+		return time.Time{}
+	}
+	return time.Unix(int64(exp), 0)
+}
+
+// expiresBefore reports whether the token is known to expire before t.
+func (a *accessToken) expiresBefore(t time.Time) bool {
+	return a != nil && !a.expiry.IsZero() && a.expiry.Before(t)
+}
+
+// isTokenRequest reports whether req is an OAuth2 token request: a form POST,
+// which the API itself never receives.
+func isTokenRequest(req *http.Request) bool {
+	if req.Method != http.MethodPost {
+		return false
+	}
+	mediaType, _, err := mime.ParseMediaType(req.Header.Get("Content-Type"))
+	return err == nil && mediaType == "application/x-www-form-urlencoded"
+}
+
+// tokenResponse answers an OAuth2 token request with the access token.
+func (a *accessToken) tokenResponse(req *http.Request) *http.Response {
+	lifetime := defaultAccessTokenLifetime
+	if !a.expiry.IsZero() {
+		lifetime = a.expiry.Sub(timeNow())
+	}
+	return tokenJSONResponse(req, a.value, "Bearer", lifetime)
+}
+
+// tokenJSONResponse builds a successful OAuth2 token response for req.
+func tokenJSONResponse(req *http.Request, value, tokenType string, lifetime time.Duration) *http.Response {
+	body, _ := json.Marshal(map[string]interface{}{
+		"access_token": value,
+		"token_type":   tokenType,
+		"expires_in":   int64(lifetime.Seconds()),
+	})
+	return &http.Response{
+		Status:        "200 OK",
+		StatusCode:    http.StatusOK,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        http.Header{"Content-Type": []string{"application/json"}},
+		Body:          io.NopCloser(bytes.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}
+}
+
+// warnAccessTokenExpiry adds a warning when the provider authenticates with
+// an access token that expires before deadline, the latest time operation
+// may still call the API.
+func (c *ArubaCloudClient) warnAccessTokenExpiry(deadline time.Time, operation string, diags *diag.Diagnostics) {
+	if c == nil || !c.accessToken.expiresBefore(deadline) {
+		return
+	}
+	diags.AddWarning(
+		"ArubaCloud access token expires during the operation",
+		fmt.Sprintf("The access token expires at %s, but %s may take until %s. Once the token has expired, "+
+			"every API call fails with an authentication error and the operation cannot complete. "+
+			"Supply a token with a longer lifetime, or use client_id and client_secret for long-running applies.",
+			c.accessToken.expiry.Format(time.RFC3339), operation, deadline.Format(time.RFC3339)),
+	)
+}
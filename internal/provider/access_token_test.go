@@ -0,0 +1,218 @@
+package provider
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	providerframe "github.com/hashicorp/terraform-plugin-framework/provider"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+// testJWT returns an unsigned JWT whose exp claim is expiry.
+func testJWT(expiry time.Time) string {
+	enc := base64.RawURLEncoding
+	header := enc.EncodeToString([]byte(`{"alg":"none","typ":"JWT"}`))
+	payload := enc.EncodeToString([]byte(fmt.Sprintf(`{"sub":"pipeline","exp":%d}`, expiry.Unix())))
+	return header + "." + payload + ".signature"
+}
+
+func TestJWTExpiry(t *testing.T) {
+	expiry := time.Date(2026, 3, 4, 5, 6, 7, 0, time.UTC)
+	if got := jwtExpiry(testJWT(expiry)); !got.Equal(expiry) {
+		t.Errorf("jwtExpiry() = %s, want %s", got, expiry)
+	}
+	for _, opaque := range []string{"", "opaque-token", "a.b.c", "a.e30.c"} {
+		if got := jwtExpiry(opaque); !got.IsZero() {
+			t.Errorf("jwtExpiry(%q) = %s, want zero", opaque, got)
+		}
+	}
+}
+
+// TestAPITransport_AccessToken checks that API requests carry the access
+// token and that token requests are answered without reaching the network.
+func TestAPITransport_AccessToken(t *testing.T) {
+	var apiCalls, tokenCalls int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/token" {
+			atomic.AddInt32(&tokenCalls, 1)
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		atomic.AddInt32(&apiCalls, 1)
+		if got := r.Header.Get("Authorization"); got != "Bearer broker-token" {
+			t.Errorf("Authorization = %q, want the access token", got)
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	t.Cleanup(srv.Close)
+
+	transport := newAPITransport(nil, noRetry)
+	transport.token = newAccessToken("broker-token")
+	client := &http.Client{Transport: transport}
+
+	resp, err := client.PostForm(srv.URL+"/token", url.Values{"grant_type": {"client_credentials"}})
+	if err != nil {
+		t.Fatalf("token request error: %v", err)
+	}
+	var token struct {
+		AccessToken string `json:"access_token"`
+		TokenType   string `json:"token_type"`
+		ExpiresIn   int64  `json:"expires_in"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&token); err != nil {
+		t.Fatalf("decoding token response: %v", err)
+	}
+	resp.Body.Close()
+	if token.AccessToken != "broker-token" || token.TokenType != "Bearer" || token.ExpiresIn <= 0 {
+		t.Errorf("token response = %+v", token)
+	}
+
+	req, _ := http.NewRequest(http.MethodGet, srv.URL+"/projects", nil)
+	req.Header.Set("Authorization", "Bearer from-the-sdk")
+	resp, err = client.Do(req)
+	if err != nil {
+		t.Fatalf("API request error: %v", err)
+	}
+	_, _ = io.Copy(io.Discard, resp.Body)
+	resp.Body.Close()
+
+	if n := atomic.LoadInt32(&tokenCalls); n != 0 {
+		t.Errorf("token endpoint called %d times, want 0", n)
+	}
+	if n := atomic.LoadInt32(&apiCalls); n != 1 {
+		t.Errorf("API called %d times, want 1", n)
+	}
+	if got := req.Header.Get("Authorization"); got != "Bearer from-the-sdk" {
+		t.Errorf("caller's request was modified: Authorization = %q", got)
+	}
+}
+
+func TestWarnAccessTokenExpiry(t *testing.T) {
+	now := time.Now()
+	client := &ArubaCloudClient{accessToken: newAccessToken(testJWT(now.Add(5 * time.Minute)))}
+
+	var diags diag.Diagnostics
+	client.warnAccessTokenExpiry(now.Add(30*time.Minute), `creating KaaS cluster "prod"`, &diags)
+	if len(diags.Warnings()) != 1 || !strings.Contains(diags.Warnings()[0].Detail(), `creating KaaS cluster "prod"`) {
+		t.Errorf("expected one warning naming the operation, got %v", diags)
+	}
+
+	diags = nil
+	client.warnAccessTokenExpiry(now.Add(time.Minute), "a short operation", &diags)
+	(&ArubaCloudClient{}).warnAccessTokenExpiry(now.Add(time.Hour), "client credentials", &diags)
+	(&ArubaCloudClient{accessToken: newAccessToken("opaque")}).warnAccessTokenExpiry(now.Add(time.Hour), "opaque token", &diags)
+	if diags.WarningsCount() != 0 {
+		t.Errorf("unexpected warnings: %v", diags)
+	}
+}
+
+// TestProviderConfigure_AccessToken verifies that ARUBACLOUD_ACCESS_TOKEN
+// replaces client_id and client_secret.
+func TestProviderConfigure_AccessToken(t *testing.T) {
+	ctx := context.Background()
+	p := newTestProvider(t)
+	t.Setenv("ARUBACLOUD_ACCESS_TOKEN", testJWT(time.Now().Add(2*time.Hour)))
+
+	resp := &providerframe.ConfigureResponse{}
+	p.Configure(ctx, providerframe.ConfigureRequest{Config: buildProviderConfig(t, p, nil)}, resp)
+	if resp.Diagnostics.HasError() || resp.Diagnostics.WarningsCount() != 0 {
+		t.Fatalf("unexpected diagnostics from Configure(): %v", resp.Diagnostics)
+	}
+	client, ok := resp.ResourceData.(*ArubaCloudClient)
+	if !ok {
+		t.Fatalf("ResourceData is %T, want *ArubaCloudClient", resp.ResourceData)
+	}
+	if client.accessToken == nil || client.ClientID != "" || client.ClientSecret != "" {
+		t.Errorf("client = %+v, want access-token authentication only", client)
+	}
+}
+
+func TestProviderConfigure_AccessTokenErrors(t *testing.T) {
+	cases := map[string]struct {
+		env         map[string]string
+		credentials string
+		overrides   map[string]tftypes.Value
+		want        string
+	}{
+		"conflicts with client_id": {
+			overrides: map[string]tftypes.Value{
+				"access_token": tftypes.NewValue(tftypes.String, "broker-token"),
+				"client_id":    tftypes.NewValue(tftypes.String, "test-key"),
+			},
+			want: "Conflicting ArubaCloud Credentials",
+		},
+		"conflicts with ARUBACLOUD_CLIENT_SECRET": {
+			env: map[string]string{
+				"ARUBACLOUD_ACCESS_TOKEN":  "broker-token",
+				"ARUBACLOUD_CLIENT_SECRET": "test-secret",
+			},
+			want: "Conflicting ArubaCloud Credentials",
+		},
+		"conflicts with profile client_id": {
+			env:         map[string]string{"ARUBACLOUD_ACCESS_TOKEN": "broker-token"},
+			credentials: "[default]\nclient_id = profile-id\n",
+			want:        "Conflicting ArubaCloud Credentials",
+		},
+		"conflicts with profile credential_process": {
+			credentials: "[default]\ncredential_process = fetch-credentials\n",
+			overrides: map[string]tftypes.Value{
+				"access_token": tftypes.NewValue(tftypes.String, "broker-token"),
+			},
+			want: "Conflicting ArubaCloud Credentials",
+		},
+		"expired": {
+			overrides: map[string]tftypes.Value{
+				"access_token": tftypes.NewValue(tftypes.String, testJWT(time.Now().Add(-time.Minute))),
+			},
+			want: "Expired ArubaCloud Access Token",
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			p := newTestProvider(t)
+			for k, v := range tc.env {
+				t.Setenv(k, v)
+			}
+			if tc.credentials != "" {
+				writeCredentialsFile(t, tc.credentials)
+			}
+			resp := &providerframe.ConfigureResponse{}
+			p.Configure(context.Background(), providerframe.ConfigureRequest{Config: buildProviderConfig(t, p, tc.overrides)}, resp)
+
+			errs := resp.Diagnostics.Errors()
+			if len(errs) != 1 || errs[0].Summary() != tc.want {
+				t.Errorf("expected one %q error, got %v", tc.want, resp.Diagnostics)
+			}
+		})
+	}
+}
+
+// TestProviderConfigure_AccessTokenNearExpiry verifies that Configure() warns
+// when the token expires before the provider resource_timeout elapses.
+func TestProviderConfigure_AccessTokenNearExpiry(t *testing.T) {
+	ctx := context.Background()
+	p := newTestProvider(t)
+
+	config := buildProviderConfig(t, p, map[string]tftypes.Value{
+		"access_token": tftypes.NewValue(tftypes.String, testJWT(time.Now().Add(10*time.Minute))),
+	})
+	resp := &providerframe.ConfigureResponse{}
+	p.Configure(ctx, providerframe.ConfigureRequest{Config: config}, resp)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("unexpected error from Configure(): %v", resp.Diagnostics)
+	}
+	if resp.Diagnostics.WarningsCount() != 1 {
+		t.Errorf("expected one expiry warning, got %v", resp.Diagnostics)
+	}
+}
@@ -0,0 +1,404 @@
+package provider
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+)
+
+// FieldError is one field-level validation error of an API response.
+type FieldError struct {
+	// Field is the API field as reported by the API, for example
+	// "properties.dhcp.range.start".
+	Field   string
+	Message string
+}
+
+// attributePathMapping maps the API field api, and the fields under it, to
+// attr. When nested is true the fields under api are mapped to the
+// attributes under attr, converting their names to snake_case; otherwise
+// they are reported on attr itself, as for a {"uri": ...} reference.
+type attributePathMapping struct {
+	api    string
+	attr   path.Path
+	nested bool
+}
+
+// apiField maps api, and the fields under it, to the attribute attr.
+func apiField(api string, attr path.Path) attributePathMapping {
+	return attributePathMapping{api: api, attr: attr}
+}
+
+// apiObject maps the fields under api to the attributes under attr.
+func apiObject(api string, attr path.Path) attributePathMapping {
+	return attributePathMapping{api: api, attr: attr, nested: true}
+}
+
+// withMetadata returns mappings with those of the name, location and tags
+// metadata most resources have.
+func withMetadata(mappings ...attributePathMapping) []attributePathMapping {
+	return append([]attributePathMapping{
+		apiField("metadata.name", path.Root("name")),
+		apiField("metadata.location", path.Root("location")),
+		apiObject("metadata.tags", path.Root("tags")),
+	}, mappings...)
+}
+
+// resourceAttributePaths maps the API fields of each resource to its
+// attributes, keyed by the lower-cased resource name passed to
+// CheckResponseErr. When several entries match a field the longest wins, so
+// a resource usually maps "properties" to the root and lists the fields that
+// are renamed or grouped differently in its schema.
+var resourceAttributePaths = map[string][]attributePathMapping{
+	"backup": withMetadata(
+		apiObject("properties", path.Empty()),
+		apiField("properties.origin", path.Root("volume_id")),
+		apiField("properties.billingPlan", path.Root("billing_period")),
+	),
+	"blockstorage": withMetadata(
+		apiObject("properties", path.Empty()),
+		apiField("properties.dataCenter", path.Root("zone")),
+		apiField("properties.billingPlan", path.Root("billing_period")),
+	),
+	"cloudserver": withMetadata(
+		apiField("properties.dataCenter", path.Root("zone")),
+		apiField("properties.flavorName", path.Root("settings").AtName("flavor_name")),
+		apiField("properties.flavor", path.Root("settings").AtName("flavor_name")),
+		apiField("properties.keyPair", path.Root("settings").AtName("key_pair_uri_ref")),
+		apiField("properties.userData", path.Root("settings").AtName("user_data")),
+		apiField("properties.vpc", path.Root("network").AtName("vpc_uri_ref")),
+		apiField("properties.elasticIp", path.Root("network").AtName("elastic_ip_uri_ref")),
+		apiField("properties.subnets", path.Root("network").AtName("subnet_uri_refs")),
+		apiField("properties.securityGroups", path.Root("network").AtName("securitygroup_uri_refs")),
+		apiField("properties.bootVolume", path.Root("storage").AtName("boot_volume_uri_ref")),
+	),
+	"containerregistry": withMetadata(
+		apiObject("properties", path.Empty()),
+		apiField("properties.billingPlan", path.Root("billing_period")),
+		apiField("properties.publicIp", path.Root("network").AtName("public_ip_uri_ref")),
+		apiField("properties.vpc", path.Root("network").AtName("vpc_uri_ref")),
+		apiField("properties.subnet", path.Root("network").AtName("subnet_uri_ref")),
+		apiField("properties.securityGroup", path.Root("network").AtName("security_group_uri_ref")),
+		apiField("properties.blockStorage", path.Root("storage").AtName("block_storage_uri_ref")),
+		apiField("properties.adminUser", path.Root("settings").AtName("admin_user")),
+		apiField("properties.concurrentUsers", path.Root("settings").AtName("concurrent_users_flavor")),
+	),
+	"database": {
+		apiField("metadata.name", path.Root("name")),
+		apiField("name", path.Root("name")),
+		apiField("properties.name", path.Root("name")),
+	},
+	"databasebackup": withMetadata(
+		apiObject("properties", path.Empty()),
+		apiField("properties.dataCenter", path.Root("zone")),
+		apiField("properties.dbaas", path.Root("dbaas_id")),
+		apiField("properties.database", path.Root("database")),
+		apiField("properties.billingPlan", path.Root("billing_period")),
+	),
+	"databasegrant": {
+		apiField("properties.database", path.Root("database")),
+		apiField("properties.grantee", path.Root("user_id")),
+		apiField("properties.role", path.Root("role")),
+	},
+	"dbaas": withMetadata(
+		apiField("properties.dataCenter", path.Root("zone")),
+		apiField("properties.engine", path.Root("engine_id")),
+		apiField("properties.flavor", path.Root("flavor")),
+		apiObject("properties.storage", path.Root("storage")),
+		apiField("properties.billingPlan", path.Root("billing_period")),
+		apiField("properties.billingPeriod", path.Root("billing_period")),
+		apiField("properties.vpc", path.Root("network").AtName("vpc_uri_ref")),
+		apiField("properties.subnet", path.Root("network").AtName("subnet_uri_ref")),
+		apiField("properties.securityGroup", path.Root("network").AtName("security_group_uri_ref")),
+		apiField("properties.elasticIp", path.Root("network").AtName("elastic_ip_uri_ref")),
+	),
+	"dbaasuser": {
+		apiField("username", path.Root("username")),
+		apiField("password", path.Root("password")),
+		apiField("properties.username", path.Root("username")),
+		apiField("properties.password", path.Root("password")),
+	},
+	"elasticip": withMetadata(
+		apiObject("properties", path.Empty()),
+		apiField("properties.billingPlan", path.Root("billing_period")),
+	),
+	"kaas": withMetadata(
+		apiField("properties.billingPlan", path.Root("billing_period")),
+		apiField("properties.vpc", path.Root("network").AtName("vpc_uri_ref")),
+		apiField("properties.subnet", path.Root("network").AtName("subnet_uri_ref")),
+		apiObject("properties.nodeCidr", path.Root("network").AtName("node_cidr")),
+		apiField("properties.podCidr", path.Root("network").AtName("pod_cidr")),
+		apiField("properties.securityGroup", path.Root("network").AtName("security_group_name")),
+		apiField("properties.kubernetesVersion", path.Root("settings").AtName("kubernetes_version")),
+		apiObject("properties.nodePools", path.Root("settings").AtName("node_pools")),
+		apiObject("properties.nodesPool", path.Root("settings").AtName("node_pools")),
+		apiField("properties.ha", path.Root("settings").AtName("ha")),
+	),
+	"keypair": withMetadata(
+		apiField("properties.value", path.Root("value")),
+	),
+	"kms": withMetadata(
+		apiObject("properties", path.Empty()),
+		apiField("properties.billingPlan", path.Root("billing_period")),
+	),
+	"project": {
+		apiField("metadata.name", path.Root("name")),
+		apiObject("metadata.tags", path.Root("tags")),
+		apiObject("properties", path.Empty()),
+	},
+	"restore": withMetadata(
+		apiField("properties.target", path.Root("volume_id")),
+		apiField("properties.volume", path.Root("volume_id")),
+	),
+	"schedulejob": withMetadata(
+		apiObject("properties", path.Root("properties")),
+		apiField("properties.cronExpression", path.Root("properties").AtName("cron")),
+	),
+	"securitygroup": withMetadata(),
+	"securityrule": withMetadata(
+		apiObject("properties", path.Root("properties")),
+	),
+	"snapshot": withMetadata(
+		apiObject("properties", path.Empty()),
+		apiField("properties.volume", path.Root("volume_uri")),
+		apiField("properties.billingPlan", path.Root("billing_period")),
+	),
+	"subnet": withMetadata(
+		apiField("properties.type", path.Root("type")),
+		apiObject("properties.network", path.Root("network")),
+		apiObject("properties.dhcp", path.Root("network").AtName("dhcp")),
+	),
+	"vpc": withMetadata(),
+	"vpcpeering": withMetadata(
+		apiField("properties.remoteVpc", path.Root("peer_vpc")),
+		apiField("properties.peerVpc", path.Root("peer_vpc")),
+	),
+	"vpcpeeringroute": {
+		apiField("metadata.name", path.Root("name")),
+		apiObject("metadata.tags", path.Root("tags")),
+		apiObject("properties", path.Empty()),
+		apiField("properties.billingPlan", path.Root("billing_period")),
+	},
+	"vpnroute": withMetadata(
+		apiObject("properties", path.Root("properties")),
+	),
+	"vpntunnel": withMetadata(
+		apiObject("properties", path.Root("properties")),
+		apiField("properties.billingPlan", path.Root("properties").AtName("billing_period")),
+	),
+}
+
+// apiFieldIndex matches the index of a list element in an API field.
+var apiFieldIndex = regexp.MustCompile(`\[(\d+)\]`)
+
+// apiFieldSegments splits an API field, in dotted, JSON Pointer or JSONPath
+// notation, into its segments.
+func apiFieldSegments(field string) []string {
+	field = strings.TrimPrefix(strings.TrimSpace(field), "$")
+	field = apiFieldIndex.ReplaceAllString(field, ".$1")
+	field = strings.ReplaceAll(field, "/", ".")
+	var segments []string
+	for _, s := range strings.Split(field, ".") {
+		if s != "" {
+			segments = append(segments, s)
+		}
+	}
+	return segments
+}
+
+// attributePath returns the attribute of resource that the API field field
+// refers to, or false when the resource has no mapping for it. Fields
+// reported without their "properties" or "metadata" prefix, as some APIs
+// do, are looked up with each prefix as well.
+func attributePath(resource, field string) (path.Path, bool) {
+	mappings, ok := resourceAttributePaths[strings.ToLower(resource)]
+	if !ok {
+		return path.Empty(), false
+	}
+	segments := apiFieldSegments(field)
+	if len(segments) == 0 {
+		return path.Empty(), false
+	}
+	candidates := [][]string{segments}
+	if first := strings.ToLower(segments[0]); first != "properties" && first != "metadata" {
+		candidates = append(candidates,
+			append([]string{"properties"}, segments...),
+			append([]string{"metadata"}, segments...))
+	}
+	for _, segments := range candidates {
+		if p, ok := mapAttributePath(mappings, segments); ok {
+			return p, true
+		}
+	}
+	return path.Empty(), false
+}
+
+// mapAttributePath maps segments with the longest matching mapping.
+func mapAttributePath(mappings []attributePathMapping, segments []string) (path.Path, bool) {
+	var best *attributePathMapping
+	bestLen := 0
+	for i := range mappings {
+		api := strings.Split(mappings[i].api, ".")
+		if len(api) > len(segments) || len(api) <= bestLen {
+			continue
+		}
+		matched := true
+		for j := range api {
+			if !strings.EqualFold(api[j], segments[j]) {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			best, bestLen = &mappings[i], len(api)
+		}
+	}
+	if best == nil {
+		return path.Empty(), false
+	}
+	p := best.attr
+	if best.nested {
+		for _, s := range segments[bestLen:] {
+			if n, err := strconv.Atoi(s); err == nil {
+				p = p.AtListIndex(n)
+			} else {
+				p = p.AtName(snakeCase(s))
+			}
+		}
+	}
+	if len(p.Steps()) == 0 {
+		return path.Empty(), false
+	}
+	return p, true
+}
+
+// snakeCase converts an API field name such as "sizeGB" or "VpnType" to
+// the snake_case of attribute names.
+func snakeCase(s string) string {
+	runes := []rune(s)
+	var b strings.Builder
+	for i, r := range runes {
+		if unicode.IsUpper(r) && i > 0 {
+			prev := runes[i-1]
+			nextLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+			if unicode.IsLower(prev) || unicode.IsDigit(prev) || (unicode.IsUpper(prev) && nextLower) {
+				b.WriteByte('_')
+			}
+		}
+		b.WriteRune(unicode.ToLower(r))
+	}
+	return b.String()
+}
+
+// rawFieldErrors extracts the field errors of a raw RFC 7807 response body,
+// accepting the key names used across the API ("field" or "propertyName",
+// "message" or "errorMessage") and the ASP.NET map of messages per field.
+func rawFieldErrors(rawBody []byte) []FieldError {
+	var top struct {
+		Errors json.RawMessage `json:"errors"`
+	}
+	if len(rawBody) == 0 || json.Unmarshal(rawBody, &top) != nil || len(top.Errors) == 0 {
+		return nil
+	}
+	var out []FieldError
+	var entries []map[string]interface{}
+	if json.Unmarshal(top.Errors, &entries) == nil {
+		for _, entry := range entries {
+			fe := FieldError{
+				Field:   entryString(entry, "field", "propertyName", "property", "path", "pointer", "name"),
+				Message: entryString(entry, "message", "errorMessage", "detail", "reason"),
+			}
+			if fe.Field != "" {
+				out = append(out, fe)
+			}
+		}
+		return out
+	}
+	var byField map[string][]string
+	if json.Unmarshal(top.Errors, &byField) == nil {
+		fields := make([]string, 0, len(byField))
+		for field := range byField {
+			fields = append(fields, field)
+		}
+		sort.Strings(fields)
+		for _, field := range fields {
+			out = append(out, FieldError{Field: field, Message: strings.Join(byField[field], "; ")})
+		}
+	}
+	return out
+}
+
+// entryString returns the first non-empty string of entry under one of keys,
+// ignoring case.
+func entryString(entry map[string]interface{}, keys ...string) string {
+	for _, k := range keys {
+		for ek, v := range entry {
+			if s, ok := v.(string); ok && s != "" && strings.EqualFold(ek, k) {
+				return sanitizeAPIString(s)
+			}
+		}
+	}
+	return ""
+}
+
+// appendAttributeErrors appends an attribute error for each validation error
+// of a rejected create or update whose API field maps to an attribute, so
+// that Terraform points at the offending line of the configuration. It
+// reports whether every validation error was appended; when it did not, the
+// caller reports the whole error as well.
+func appendAttributeErrors(diags *diag.Diagnostics, summary string, provErr *ProviderError) bool {
+	if len(provErr.FieldErrors) == 0 || (provErr.Operation != "create" && provErr.Operation != "update") {
+		return false
+	}
+	type attributeError struct {
+		path   path.Path
+		detail string
+	}
+	attrErrs := make([]attributeError, 0, len(provErr.FieldErrors))
+	for _, fe := range provErr.FieldErrors {
+		p, ok := attributePath(provErr.Resource, fe.Field)
+		if !ok {
+			return false
+		}
+		message := fe.Message
+		if message == "" {
+			message = "invalid value"
+		}
+		detail := fmt.Sprintf("Failed to %s %s: the API rejected %s: %s", provErr.Operation, provErr.Resource, fe.Field, message)
+		var extra []string
+		if provErr.Instance != "" {
+			extra = append(extra, "instance: "+provErr.Instance)
+		}
+		if extra = append(extra, provErr.requestIDs()...); len(extra) > 0 {
+			detail += "\n\n" + strings.Join(extra, ", ")
+		}
+		attrErrs = append(attrErrs, attributeError{path: p, detail: detail})
+	}
+	for _, e := range attrErrs {
+		diags.AddAttributeError(e.path, summary, e.detail)
+	}
+	return true
+}
+
+// AppendAPIError appends provErr to diags: one attribute error per
+// validation error when they all map to attributes of the resource, a
+// single "API Error" with the whole error otherwise.
+func AppendAPIError(diags *diag.Diagnostics, provErr *ProviderError) {
+	if !appendAttributeErrors(diags, "API Error", provErr) {
+		diags.AddError("API Error", provErr.Error())
+	}
+}
+
+// asProviderError returns the *ProviderError in err's chain, if any.
+func asProviderError(err error) (*ProviderError, bool) {
+	var provErr *ProviderError
+	return provErr, errors.As(err, &provErr) && provErr != nil
+}
@@ -0,0 +1,179 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	aruba "github.com/Arubacloud/sdk-go/pkg/aruba"
+	sdktypes "github.com/Arubacloud/sdk-go/pkg/types"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+)
+
+func TestAttributePath(t *testing.T) {
+	tests := []struct {
+		resource string
+		field    string
+		want     path.Path
+	}{
+		{"Subnet", "properties.dhcp.range.start", path.Root("network").AtName("dhcp").AtName("range").AtName("start")},
+		{"Subnet", "properties.dhcp.routes[1].gateway", path.Root("network").AtName("dhcp").AtName("routes").AtListIndex(1).AtName("gateway")},
+		{"Subnet", "metadata.location.value", path.Root("location")},
+		{"CloudServer", "properties.flavorName", path.Root("settings").AtName("flavor_name")},
+		{"CloudServer", "properties.vpc.uri", path.Root("network").AtName("vpc_uri_ref")},
+		{"KaaS", "properties.nodePools[0].nodes", path.Root("settings").AtName("node_pools").AtListIndex(0).AtName("nodes")},
+		{"VPNTunnel", "properties.vpnClientSettings.ike.dhGroup", path.Root("properties").AtName("vpn_client_settings").AtName("ike").AtName("dh_group")},
+		{"BlockStorage", "SizeGB", path.Root("size_gb")},
+		{"Snapshot", "/properties/billingPlan/billingPeriod", path.Root("billing_period")},
+		{"Schedulejob", "CronExpression", path.Root("properties").AtName("cron")},
+		{"DBaaSUser", "Password", path.Root("password")},
+		{"Keypair", "Tags[2]", path.Root("tags").AtListIndex(2)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.resource+"/"+tt.field, func(t *testing.T) {
+			got, ok := attributePath(tt.resource, tt.field)
+			if !ok || !got.Equal(tt.want) {
+				t.Errorf("attributePath() = %s, %v; want %s", got, ok, tt.want)
+			}
+		})
+	}
+
+	for _, tc := range []struct{ resource, field string }{
+		{"CloudServer", "properties.unknownThing"},
+		{"Volume", "properties.sizeGb"},
+		{"Subnet", ""},
+	} {
+		if got, ok := attributePath(tc.resource, tc.field); ok {
+			t.Errorf("attributePath(%q, %q) = %s, want no mapping", tc.resource, tc.field, got)
+		}
+	}
+}
+
+func TestSnakeCase(t *testing.T) {
+	for in, want := range map[string]string{
+		"sizeGb":             "size_gb",
+		"sizeGB":             "size_gb",
+		"VpnType":            "vpn_type",
+		"IPConfigurations":   "ip_configurations",
+		"dpdInterval":        "dpd_interval",
+		"available_space":    "available_space",
+		"peerClientPublicIp": "peer_client_public_ip",
+	} {
+		if got := snakeCase(in); got != want {
+			t.Errorf("snakeCase(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+// TestResourceAttributePaths_MatchSchemas verifies that every mapping table
+// belongs to a resource and only targets attributes of its schema.
+func TestResourceAttributePaths_MatchSchemas(t *testing.T) {
+	ctx := context.Background()
+	schemas := map[string]resource.SchemaResponse{}
+	for _, rFunc := range (&ArubaCloudProvider{}).Resources(ctx) {
+		r := rFunc()
+		metadataResp := &resource.MetadataResponse{}
+		r.Metadata(ctx, resource.MetadataRequest{ProviderTypeName: "arubacloud"}, metadataResp)
+		schemaResp := &resource.SchemaResponse{}
+		r.Schema(ctx, resource.SchemaRequest{}, schemaResp)
+		schemas[strings.TrimPrefix(metadataResp.TypeName, "arubacloud_")] = *schemaResp
+	}
+
+	for name, mappings := range resourceAttributePaths {
+		schemaResp, ok := schemas[name]
+		if !ok {
+			t.Errorf("mapping table %q does not belong to a resource", name)
+			continue
+		}
+		for _, m := range mappings {
+			if len(m.attr.Steps()) == 0 {
+				continue
+			}
+			if _, diags := schemaResp.Schema.AttributeAtPath(ctx, m.attr); diags.HasError() {
+				t.Errorf("%s: %s maps to %s, which is not an attribute", name, m.api, m.attr)
+			}
+		}
+	}
+}
+
+func TestCheckResponseErr_FieldErrors(t *testing.T) {
+	typed := CheckResponseErr("create", "Subnet", &aruba.HTTPError{
+		StatusCode: 400,
+		ErrResp: &sdktypes.ErrorResponse{
+			Title:  strPtr("Bad Request"),
+			Errors: []sdktypes.ValidationError{{Field: "properties.dhcp.range.start", Message: "must be\tinside the subnet"}},
+		},
+	})
+	if len(typed.FieldErrors) != 1 || typed.FieldErrors[0] != (FieldError{Field: "properties.dhcp.range.start", Message: "must be inside the subnet"}) {
+		t.Errorf("FieldErrors = %+v", typed.FieldErrors)
+	}
+
+	raw := CheckResponseErr("create", "ScheduleJob", &aruba.HTTPError{
+		StatusCode: 400,
+		ErrResp:    &sdktypes.ErrorResponse{Errors: []sdktypes.ValidationError{{}}},
+		Body:       []byte(`{"errors":[{"propertyName":"CronExpression","errorMessage":"invalid cron format"}]}`),
+	})
+	if len(raw.FieldErrors) != 1 || raw.FieldErrors[0] != (FieldError{Field: "CronExpression", Message: "invalid cron format"}) {
+		t.Errorf("FieldErrors from the raw body = %+v", raw.FieldErrors)
+	}
+
+	if got := rawFieldErrors([]byte(`{"errors":{"Tag":["too short"],"Name":["required","too long"]}}`)); len(got) != 2 ||
+		got[0] != (FieldError{Field: "Name", Message: "required; too long"}) || got[1].Field != "Tag" {
+		t.Errorf("rawFieldErrors() of a map = %+v", got)
+	}
+}
+
+func TestAppendAPIError(t *testing.T) {
+	validation := func(operation string, fields ...string) *ProviderError {
+		provErr := newResponseError(operation, "Subnet", 400, "Bad Request", "", "/projects/p1/subnets", true)
+		for _, f := range fields {
+			provErr.FieldErrors = append(provErr.FieldErrors, FieldError{Field: f, Message: "invalid"})
+		}
+		return provErr
+	}
+
+	t.Run("mapped fields", func(t *testing.T) {
+		var diags diag.Diagnostics
+		AppendAPIError(&diags, validation("create", "properties.dhcp.range.start", "metadata.name"))
+		if len(diags) != 2 {
+			t.Fatalf("got %d diagnostics, want one per field: %v", len(diags), diags)
+		}
+		withPath, ok := diags[0].(diag.DiagnosticWithPath)
+		if !ok || !withPath.Path().Equal(path.Root("network").AtName("dhcp").AtName("range").AtName("start")) {
+			t.Errorf("diagnostic %v has no network.dhcp.range.start path", diags[0])
+		}
+		if !strings.Contains(diags[0].Detail(), "properties.dhcp.range.start: invalid") {
+			t.Errorf("detail = %q, want the API field and message", diags[0].Detail())
+		}
+	})
+
+	for name, provErr := range map[string]*ProviderError{
+		"unmapped field":    validation("create", "properties.dhcp.range.start", "properties.somethingElse"),
+		"read":              validation("read", "properties.dhcp.range.start"),
+		"no field errors":   validation("update"),
+		"transport failure": NewTransportError("create", "Subnet", fmt.Errorf("connection reset")),
+	} {
+		t.Run(name, func(t *testing.T) {
+			var diags diag.Diagnostics
+			AppendAPIError(&diags, provErr)
+			if len(diags) != 1 || diags[0].Summary() != "API Error" || diags[0].Detail() != provErr.Error() {
+				t.Errorf("got %v, want one API Error with the whole error", diags)
+			}
+			if _, ok := diags[0].(diag.DiagnosticWithPath); ok {
+				t.Error("the API Error has an attribute path")
+			}
+		})
+	}
+
+	t.Run("LogAndAppendAPIError", func(t *testing.T) {
+		var diags diag.Diagnostics
+		LogAndAppendAPIError(context.TODO(), &diags, "Create Error", validation("create", "properties.type"), nil)
+		withPath, ok := diags[0].(diag.DiagnosticWithPath)
+		if len(diags) != 1 || !ok || !withPath.Path().Equal(path.Root("type")) || diags[0].Summary() != "Create Error" {
+			t.Errorf("got %v, want a Create Error on type", diags)
+		}
+	})
+}
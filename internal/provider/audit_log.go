@@ -0,0 +1,214 @@
+package provider
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// auditEntry is one line of the audit log: one create, update or delete
+// request sent to the API, including each retry of it.
+type auditEntry struct {
+	Time         string `json:"time"`
+	Operation    string `json:"operation"`
+	ResourceType string `json:"resource_type"`
+	ResourceName string `json:"resource_name,omitempty"`
+	Method       string `json:"method"`
+	URI          string `json:"uri"`
+	Status       int    `json:"status"`
+	DurationMS   int64  `json:"duration_ms"`
+	Attempt      int    `json:"attempt"`
+	RequestID    string `json:"request_id,omitempty"`
+	Error        string `json:"error,omitempty"`
+}
+
+// auditLog appends an auditEntry per mutating API request to a file.
+//
+// Terraform does not tell providers the address of the resource being
+// changed, so entries carry the resource type and name instead. Attempt
+// counts the consecutive requests with the same method on the same resource,
+// whether replayed by apiTransport or retried by DeleteResourceWithRetry and
+// CreateWithTransientRetry, and restarts after the first response no retry
+// follows (see awaitsRetry).
+//
+// Each entry is written with a single append, so entries from concurrent
+// operations, and from provider processes sharing the file, never interleave.
+type auditLog struct {
+	file *os.File
+
+	mu       sync.Mutex
+	attempts map[string]int
+}
+
+var (
+	auditLogsMu sync.Mutex
+	auditLogs   = map[string]*auditLog{}
+)
+
+// openAuditLog returns the audit log writing to path, shared by every
+// provider instance of the process, creating the file with 0600 permissions.
+func openAuditLog(path string) (*auditLog, error) {
+	path, err := expandHome(path)
+	if err != nil {
+		return nil, err
+	}
+	if abs, err := filepath.Abs(path); err == nil {
+		path = abs
+	}
+	auditLogsMu.Lock()
+	defer auditLogsMu.Unlock()
+	if a, ok := auditLogs[path]; ok {
+		return a, nil
+	}
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o600)
+	if err != nil {
+		return nil, fmt.Errorf("cannot open audit log: %w", err)
+	}
+	a := &auditLog{file: f, attempts: map[string]int{}}
+	auditLogs[path] = a
+	return a, nil
+}
+
+// auditOperation returns the operation audited for req, or false when req
+// does not change a resource.
+func auditOperation(req *http.Request) (string, bool) {
+	switch req.Method {
+	case http.MethodPost:
+		if isTokenRequest(req) {
+			return "", false
+		}
+		return "create", true
+	case http.MethodPut, http.MethodPatch:
+		return "update", true
+	case http.MethodDelete:
+		return "delete", true
+	}
+	return "", false
+}
+
+// record appends the entry for one attempt of req, sent at start. retried
+// reports whether apiTransport sends req again.
+func (a *auditLog) record(req *http.Request, resp *http.Response, err error, start time.Time, retried bool) {
+	operation, ok := auditOperation(req)
+	if !ok {
+		return
+	}
+	uri := req.URL.Path
+	if i := strings.Index(uri, "/projects/"); i > 0 {
+		uri = uri[i:]
+	}
+	entry := auditEntry{
+		Time:       start.UTC().Format(time.RFC3339Nano),
+		Operation:  operation,
+		Method:     req.Method,
+		URI:        uri,
+		DurationMS: timeNow().Sub(start).Milliseconds(),
+		RequestID:  req.Header.Get(requestIDHeader),
+	}
+	entry.ResourceType, entry.ResourceName = auditResource(req, uri)
+	if err != nil {
+		entry.Error = err.Error()
+	} else {
+		entry.Status = resp.StatusCode
+	}
+	// A POST to a collection is identified by the name it creates.
+	key := req.Method + " " + uri + " " + entry.ResourceName
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.attempts[key]++
+	entry.Attempt = a.attempts[key]
+	if !retried && !awaitsRetry(resp, err) {
+		delete(a.attempts, key)
+	}
+	line, _ := json.Marshal(entry)
+	_, _ = a.file.Write(append(line, '\n'))
+}
+
+// awaitsRetry reports whether the create and delete retry helpers may send a
+// request again after resp or err: a transport failure, a 429, a 5xx, or a
+// 4xx other than 404 without field validation errors. Any other response is
+// final.
+func awaitsRetry(resp *http.Response, err error) bool {
+	switch {
+	case err != nil:
+		return true
+	case resp.StatusCode < 400 || resp.StatusCode == http.StatusNotFound:
+		return false
+	case resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500:
+		return true
+	}
+	return len(rawFieldErrors(peekBody(resp))) == 0
+}
+
+// peekBody returns the start of the body of resp, leaving the body readable
+// from its beginning.
+func peekBody(resp *http.Response) []byte {
+	if resp.Body == nil {
+		return nil
+	}
+	raw, _ := io.ReadAll(io.LimitReader(resp.Body, maxProblemBodySize))
+	resp.Body = struct {
+		io.Reader
+		io.Closer
+	}{io.MultiReader(bytes.NewReader(raw), resp.Body), resp.Body}
+	return raw
+}
+
+// auditResource returns the Terraform resource type and the name of the
+// resource req changes: the ID at the end of its URI or, for a create, the
+// name in the request body.
+func auditResource(req *http.Request, uri string) (resourceType, name string) {
+	u, err := ParseResourceURI(uri)
+	if err != nil {
+		// A create is sent to the collection, one segment short of a URI.
+		if u, err = ParseResourceURI(uri + "/-"); err != nil {
+			return "", ""
+		}
+		u.ID = ""
+	}
+	resourceType = u.Kind
+	if _, known := uriKinds[u.Kind]; known || u.Kind == uriKindProject {
+		resourceType = "arubacloud_" + u.Kind
+	}
+	if u.ID != "" {
+		return resourceType, u.ID
+	}
+	return resourceType, requestBodyName(req)
+}
+
+// requestBodyName returns the name in a JSON request body, from
+// metadata.name or, for databases and users, name or username.
+func requestBodyName(req *http.Request) string {
+	if req.GetBody == nil {
+		return ""
+	}
+	body, err := req.GetBody()
+	if err != nil {
+		return ""
+	}
+	defer body.Close()
+	var v struct {
+		Metadata struct {
+			Name string `json:"name"`
+		} `json:"metadata"`
+		Name     string `json:"name"`
+		Username string `json:"username"`
+	}
+	if json.NewDecoder(io.LimitReader(body, maxProblemBodySize)).Decode(&v) != nil {
+		return ""
+	}
+	for _, name := range []string{v.Metadata.Name, v.Name, v.Username} {
+		if name != "" {
+			return name
+		}
+	}
+	return ""
+}
@@ -0,0 +1,261 @@
+package provider
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	providerframe "github.com/hashicorp/terraform-plugin-framework/provider"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+// readAuditLog returns the entries of the audit log at path.
+func readAuditLog(t *testing.T, path string) []auditEntry {
+	t.Helper()
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	var entries []auditEntry
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var e auditEntry
+		if err := json.Unmarshal(scanner.Bytes(), &e); err != nil {
+			t.Fatalf("audit log line %q: %v", scanner.Text(), err)
+		}
+		entries = append(entries, e)
+	}
+	return entries
+}
+
+func newAuditTransport(t *testing.T) (*apiTransport, string) {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "audit.log")
+	audit, err := openAuditLog(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	transport := newAPITransport(nil, noRetry)
+	transport.audit = audit
+	return transport, path
+}
+
+// TestAuditLog_RecordsMutatingRequests verifies that creates, updates and
+// deletes are audited with their attempt number, and reads and token
+// requests are not.
+func TestAuditLog_RecordsMutatingRequests(t *testing.T) {
+	var deletes atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodPost:
+			w.WriteHeader(http.StatusCreated)
+		case http.MethodDelete:
+			if deletes.Add(1) == 1 {
+				w.WriteHeader(http.StatusConflict)
+				return
+			}
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			w.WriteHeader(http.StatusOK)
+		}
+	}))
+	defer srv.Close()
+	transport, path := newAuditTransport(t)
+	client := &http.Client{Transport: transport}
+	vpcs := srv.URL + "/projects/p1/providers/Aruba.Network/vpcs"
+
+	send := func(method, url, contentType, body string) {
+		t.Helper()
+		req, _ := http.NewRequest(method, url, strings.NewReader(body))
+		if contentType != "" {
+			req.Header.Set("Content-Type", contentType)
+		}
+		resp, err := client.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+	}
+	send(http.MethodPost, srv.URL+"/token", "application/x-www-form-urlencoded", "grant_type=client_credentials")
+	send(http.MethodPost, vpcs, "application/json", `{"metadata":{"name":"my-vpc"}}`)
+	send(http.MethodGet, vpcs+"/v1", "", "")
+	send(http.MethodPut, vpcs+"/v1", "application/json", `{}`)
+	// DeleteResourceWithRetry sends the delete again after a conflict.
+	send(http.MethodDelete, vpcs+"/v1", "", "")
+	send(http.MethodDelete, vpcs+"/v1", "", "")
+
+	entries := readAuditLog(t, path)
+	want := []struct {
+		operation, name string
+		status, attempt int
+	}{
+		{"create", "my-vpc", http.StatusCreated, 1},
+		{"update", "v1", http.StatusOK, 1},
+		{"delete", "v1", http.StatusConflict, 1},
+		{"delete", "v1", http.StatusNoContent, 2},
+	}
+	if len(entries) != len(want) {
+		t.Fatalf("audit log has %d entries, want %d: %+v", len(entries), len(want), entries)
+	}
+	for i, w := range want {
+		e := entries[i]
+		if e.Operation != w.operation || e.ResourceName != w.name || e.Status != w.status || e.Attempt != w.attempt {
+			t.Errorf("entry %d = %+v, want %s of %s with status %d, attempt %d", i, e, w.operation, w.name, w.status, w.attempt)
+		}
+		if e.ResourceType != "arubacloud_vpc" || !strings.HasPrefix(e.URI, "/projects/p1/providers/Aruba.Network/vpcs") || e.Time == "" {
+			t.Errorf("entry %d = %+v, want an arubacloud_vpc entry with URI and time", i, e)
+		}
+	}
+}
+
+// TestAuditLog_AttemptRestartsAfterFinalResponse verifies that a response no
+// retry follows, such as a validation error, restarts the attempt count,
+// while replays by the transport and responses the retry helpers retry keep
+// counting.
+func TestAuditLog_AttemptRestartsAfterFinalResponse(t *testing.T) {
+	var puts atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodPost:
+			w.Header().Set("Content-Type", "application/problem+json")
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"title":"Bad Request","errors":[{"field":"metadata.name","message":"invalid"}]}`))
+		case http.MethodPut:
+			if puts.Add(1) == 1 {
+				w.WriteHeader(http.StatusBadGateway)
+				return
+			}
+			w.WriteHeader(http.StatusOK)
+		case http.MethodDelete:
+			w.WriteHeader(http.StatusConflict)
+		}
+	}))
+	defer srv.Close()
+	transport, path := newAuditTransport(t)
+	transport.retry = fastRetry
+	client := &http.Client{Transport: transport}
+	vpcs := srv.URL + "/projects/p1/providers/Aruba.Network/vpcs"
+
+	send := func(method, url, body string) {
+		t.Helper()
+		req, _ := http.NewRequest(method, url, strings.NewReader(body))
+		resp, err := client.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+	}
+	send(http.MethodPost, vpcs, `{"metadata":{"name":"bad name"}}`)
+	send(http.MethodPost, vpcs, `{"metadata":{"name":"bad name"}}`)
+	send(http.MethodPut, vpcs+"/v1", `{}`)
+	send(http.MethodDelete, vpcs+"/v1", "")
+	send(http.MethodDelete, vpcs+"/v1", "")
+
+	entries := readAuditLog(t, path)
+	want := []struct{ status, attempt int }{
+		{http.StatusBadRequest, 1},
+		{http.StatusBadRequest, 1},
+		{http.StatusBadGateway, 1},
+		{http.StatusOK, 2},
+		{http.StatusConflict, 1},
+		{http.StatusConflict, 2},
+	}
+	if len(entries) != len(want) {
+		t.Fatalf("audit log has %d entries, want %d: %+v", len(entries), len(want), entries)
+	}
+	for i, w := range want {
+		if entries[i].Status != w.status || entries[i].Attempt != w.attempt {
+			t.Errorf("entry %d = %+v, want status %d, attempt %d", i, entries[i], w.status, w.attempt)
+		}
+	}
+
+	transport.audit.mu.Lock()
+	defer transport.audit.mu.Unlock()
+	if len(transport.audit.attempts) != 1 {
+		t.Errorf("attempt counts = %v, want only the conflicting delete", transport.audit.attempts)
+	}
+}
+
+// TestAuditLog_Concurrent verifies that concurrent operations write whole
+// lines.
+func TestAuditLog_Concurrent(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer srv.Close()
+	transport, path := newAuditTransport(t)
+	client := &http.Client{Transport: transport}
+
+	const n = 50
+	var wg sync.WaitGroup
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			body := fmt.Sprintf(`{"metadata":{"name":"vol-%d"}}`, i)
+			resp, err := client.Post(srv.URL+"/projects/p1/providers/Aruba.Storage/blockStorages", "application/json", strings.NewReader(body))
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			resp.Body.Close()
+		}(i)
+	}
+	wg.Wait()
+
+	entries := readAuditLog(t, path)
+	names := map[string]bool{}
+	for _, e := range entries {
+		if e.ResourceType != "arubacloud_blockstorage" || e.Attempt != 1 {
+			t.Errorf("entry = %+v, want a first arubacloud_blockstorage attempt", e)
+		}
+		names[e.ResourceName] = true
+	}
+	if len(entries) != n || len(names) != n {
+		t.Errorf("audit log has %d entries for %d names, want %d", len(entries), len(names), n)
+	}
+}
+
+// TestProviderConfigure_AuditLogPath verifies that Configure() opens the
+// audit log and reports one it cannot open.
+func TestProviderConfigure_AuditLogPath(t *testing.T) {
+	ctx := context.Background()
+	p := newTestProvider(t)
+	creds := map[string]tftypes.Value{
+		"client_id":     tftypes.NewValue(tftypes.String, "test-key"),
+		"client_secret": tftypes.NewValue(tftypes.String, "test-secret"),
+	}
+
+	file := filepath.Join(t.TempDir(), "audit.log")
+	t.Setenv("ARUBACLOUD_AUDIT_LOG_PATH", file)
+	resp := &providerframe.ConfigureResponse{}
+	p.Configure(ctx, providerframe.ConfigureRequest{Config: buildProviderConfig(t, p, creds)}, resp)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("unexpected error from Configure(): %v", resp.Diagnostics)
+	}
+	info, err := os.Stat(file)
+	if err != nil {
+		t.Fatalf("audit log not created: %v", err)
+	}
+	if info.Mode().Perm() != 0o600 {
+		t.Errorf("audit log permissions = %v, want 0600", info.Mode().Perm())
+	}
+
+	creds["audit_log_path"] = tftypes.NewValue(tftypes.String, filepath.Join(t.TempDir(), "missing", "audit.log"))
+	resp = &providerframe.ConfigureResponse{}
+	p.Configure(ctx, providerframe.ConfigureRequest{Config: buildProviderConfig(t, p, creds)}, resp)
+	errs := resp.Diagnostics.Errors()
+	if len(errs) != 1 || errs[0].Summary() != "Invalid audit_log_path" {
+		t.Errorf("expected one Invalid audit_log_path error, got %v", resp.Diagnostics)
+	}
+}
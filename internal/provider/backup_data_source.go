@@ -5,7 +5,6 @@ import (
 	"fmt"
 	"strings"
 
-	aruba "github.com/Arubacloud/sdk-go/pkg/aruba"
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
 	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/types"
@@ -104,11 +103,15 @@ func (d *BackupDataSource) Configure(ctx context.Context, req datasource.Configu
 }
 
 func (d *BackupDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	ctx = withCorrelationID(ctx)
 	var data BackupDataSourceModel
 	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
+	if !d.client.checkGuardrails("read", "Backup", data.ProjectID, &resp.Diagnostics) {
+		return
+	}
 
 	projectID := data.ProjectID.ValueString()
 	backupID := data.Id.ValueString()
@@ -118,9 +121,9 @@ func (d *BackupDataSource) Read(ctx context.Context, req datasource.ReadRequest,
 	}
 
 	backup, err := d.client.Client.FromStorage().Backups().Get(ctx,
-		aruba.URI("/projects/"+projectID+"/providers/Aruba.Storage/backups/"+backupID))
+		newResourceURI(uriKindBackup, projectID, backupID).Ref())
 	if provErr := CheckResponseErr("read", "Backup", err); provErr != nil {
-		resp.Diagnostics.AddError("API Error", provErr.Error())
+		AppendAPIError(&resp.Diagnostics, provErr)
 		return
 	}
 
@@ -7,6 +7,7 @@ import (
 	"time"
 
 	aruba "github.com/Arubacloud/sdk-go/pkg/aruba"
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
 	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
@@ -20,17 +21,20 @@ import (
 )
 
 type BackupResourceModel struct {
-	Id            types.String `tfsdk:"id"`
-	Uri           types.String `tfsdk:"uri"`
-	Name          types.String `tfsdk:"name"`
-	Location      types.String `tfsdk:"location"`
-	Tags          types.List   `tfsdk:"tags"`
-	ProjectID     types.String `tfsdk:"project_id"`
-	Type          types.String `tfsdk:"type"`
-	VolumeID      types.String `tfsdk:"volume_id"`
-	RetentionDays types.Int64  `tfsdk:"retention_days"`
-	BillingPeriod types.String `tfsdk:"billing_period"`
-	Timeout       types.String `tfsdk:"timeout"`
+	Id                 types.String   `tfsdk:"id"`
+	Uri                types.String   `tfsdk:"uri"`
+	Name               types.String   `tfsdk:"name"`
+	Location           types.String   `tfsdk:"location"`
+	Tags               types.List     `tfsdk:"tags"`
+	TagsAll            types.List     `tfsdk:"tags_all"`
+	ProjectID          types.String   `tfsdk:"project_id"`
+	Type               types.String   `tfsdk:"type"`
+	VolumeID           types.String   `tfsdk:"volume_id"`
+	RetentionDays      types.Int64    `tfsdk:"retention_days"`
+	BillingPeriod      types.String   `tfsdk:"billing_period"`
+	DeletionProtection types.Bool     `tfsdk:"deletion_protection"`
+	Timeout            types.String   `tfsdk:"timeout"`
+	Timeouts           timeouts.Value `tfsdk:"timeouts"`
 }
 
 type BackupResource struct {
@@ -38,6 +42,7 @@ type BackupResource struct {
 }
 
 var _ resource.Resource = &BackupResource{}
+var _ resource.ResourceWithModifyPlan = &BackupResource{}
 var _ resource.ResourceWithImportState = &BackupResource{}
 
 func NewBackupResource() resource.Resource {
@@ -71,9 +76,11 @@ func (r *BackupResource) Schema(ctx context.Context, req resource.SchemaRequest,
 				Required:            true,
 			},
 			"location": schema.StringAttribute{
-				MarkdownDescription: "Region identifier (e.g., `ITBG-Bergamo`). See the [available locations and zones](https://api.arubacloud.com/docs/metadata/#location-and-data-center). (Immutable — changing this value forces the resource to be destroyed and re-created.)",
-				Required:            true,
+				MarkdownDescription: defaultedDescription("Region identifier (e.g., `ITBG-Bergamo`). See the [available locations and zones](https://api.arubacloud.com/docs/metadata/#location-and-data-center). (Immutable — changing this value forces the resource to be destroyed and re-created.)", "location"),
+				Optional:            true,
+				Computed:            true,
 				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
 					stringplanmodifier.RequiresReplace(),
 				},
 			},
@@ -82,10 +89,13 @@ func (r *BackupResource) Schema(ctx context.Context, req resource.SchemaRequest,
 				MarkdownDescription: "List of string tags attached to the resource for filtering and organisation.",
 				Optional:            true,
 			},
+			"tags_all": tagsAllAttribute(),
 			"project_id": schema.StringAttribute{
-				MarkdownDescription: "ID of the project that owns this resource. (Immutable — changing this value forces the resource to be destroyed and re-created.)",
-				Required:            true,
+				MarkdownDescription: defaultedDescription("ID of the project that owns this resource. (Immutable — changing this value forces the resource to be destroyed and re-created.)", "project_id"),
+				Optional:            true,
+				Computed:            true,
 				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
 					stringplanmodifier.RequiresReplace(),
 				},
 			},
@@ -118,11 +128,16 @@ func (r *BackupResource) Schema(ctx context.Context, req resource.SchemaRequest,
 				Optional:            true,
 				Validators:          []validator.String{stringvalidator.OneOf("Hour", "Month", "Year")},
 			},
+			"deletion_protection": deletionProtectionAttribute(),
 			"timeout": schema.StringAttribute{
-				MarkdownDescription: "Per-resource timeout override (e.g. `\"15m\"`, `\"1h\"`). Overrides the provider-level `resource_timeout` for this resource's Create and Delete operations. Uses Go duration syntax.",
+				MarkdownDescription: "Per-resource timeout override (e.g. `\"15m\"`, `\"1h\"`) applied to every operation not set in the `timeouts` block. Overrides the provider-level `resource_timeout`. Uses Go duration syntax. Deprecated — use the `timeouts` block instead.",
+				DeprecationMessage:  "Use the timeouts block, which sets the create, read, update and delete timeouts individually.",
 				Optional:            true,
 			},
 		},
+		Blocks: map[string]schema.Block{
+			"timeouts": timeouts.BlockAll(ctx),
+		},
 	}
 }
 
@@ -142,23 +157,43 @@ func (r *BackupResource) Configure(ctx context.Context, req resource.ConfigureRe
 }
 
 func backupRef(data *BackupResourceModel) aruba.Ref {
-	if !data.Uri.IsNull() && data.Uri.ValueString() != "" {
-		return aruba.URI(data.Uri.ValueString())
-	}
-	return aruba.URI("/projects/" + data.ProjectID.ValueString() + "/providers/Aruba.Storage/backups/" + data.Id.ValueString())
+	return uriRef(data.Uri, newResourceURI(uriKindBackup, data.ProjectID.ValueString(), data.Id.ValueString()))
+}
+
+func (r *BackupResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	r.client.planTagsAll(ctx, req, resp, true)
+	r.client.planProviderDefaults(ctx, req, resp,
+		providerDefault{attr: "project_id", replace: true},
+		providerDefault{attr: "location", replace: true},
+	)
+	r.client.planDataLoss(ctx, req, resp, "Backup",
+		"the backup with all its restore points",
+		path.Root("project_id"),
+		path.Root("location"),
+		path.Root("type"),
+		path.Root("volume_id"),
+		path.Root("retention_days"),
+	)
 }
 
 func (r *BackupResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	ctx = withCorrelationID(ctx)
 	var data BackupResourceModel
 	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	timeout := r.client.operationTimeout(ctx, data.Timeouts.Create, data.Timeout, &resp.Diagnostics)
 	if resp.Diagnostics.HasError() {
 		return
 	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	if !r.client.checkGuardrails("create", "Backup", data.ProjectID, &resp.Diagnostics) {
+		return
+	}
 
 	projectID := data.ProjectID.ValueString()
 	volumeID := data.VolumeID.ValueString()
 
-	tags := ListToTags(ctx, data.Tags, &resp.Diagnostics)
+	tags := r.client.requestTags(ctx, data.Tags, &data.TagsAll, &resp.Diagnostics)
 	if resp.Diagnostics.HasError() {
 		return
 	}
@@ -169,7 +204,7 @@ func (r *BackupResource) Create(ctx context.Context, req resource.CreateRequest,
 	const volPollInterval = 5 * time.Second
 	const volPollMaxAttempts = 24 // up to 120 s
 
-	volumeURI := aruba.URI("/projects/" + projectID + "/providers/Aruba.Storage/blockStorages/" + volumeID)
+	volumeURI := newResourceURI(uriKindBlockStorage, projectID, volumeID).Ref()
 	var vol *aruba.BlockStorage
 	for attempt := 0; attempt < volPollMaxAttempts; attempt++ {
 		var getErr error
@@ -194,7 +229,7 @@ func (r *BackupResource) Create(ctx context.Context, req resource.CreateRequest,
 		case <-ctx.Done():
 			resp.Diagnostics.AddError("Context cancelled", "Cancelled while waiting for volume to become visible")
 			return
-		case <-time.After(volPollInterval):
+		case <-time.After(retryDelay(volPollInterval)):
 		}
 	}
 	if vol.URI() == "" {
@@ -204,7 +239,7 @@ func (r *BackupResource) Create(ctx context.Context, req resource.CreateRequest,
 
 	builder := aruba.NewStorageBackup().
 		Named(data.Name.ValueString()).
-		InProject(aruba.URI("/projects/" + projectID)).
+		InProject(newResourceURI(uriKindProject, projectID, "").Ref()).
 		InRegion(aruba.Region(data.Location.ValueString())).
 		OfType(aruba.StorageBackupType(data.Type.ValueString())).
 		FromVolume(aruba.URI(vol.URI())).
@@ -219,7 +254,7 @@ func (r *BackupResource) Create(ctx context.Context, req resource.CreateRequest,
 
 	backup, err := r.client.Client.FromStorage().Backups().Create(ctx, builder)
 	if provErr := CheckResponseErr("create", "Backup", err); provErr != nil {
-		resp.Diagnostics.AddError("API Error", provErr.Error())
+		AppendAPIError(&resp.Diagnostics, provErr)
 		return
 	}
 
@@ -231,7 +266,7 @@ func (r *BackupResource) Create(ctx context.Context, req resource.CreateRequest,
 		return
 	}
 
-	if waitErr := backup.WaitUntilReady(ctx, sdkWaitOptions(effectiveTimeout(data.Timeout, r.client.ResourceTimeout))...); waitErr != nil {
+	if waitErr := backup.WaitUntilReady(ctx, sdkWaitOptions(timeout)...); waitErr != nil {
 		ReportWaitResult(&resp.Diagnostics, waitErr, "Backup", data.Id.ValueString())
 		return
 	}
@@ -253,11 +288,22 @@ func (r *BackupResource) Create(ctx context.Context, req resource.CreateRequest,
 }
 
 func (r *BackupResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	ctx = withCorrelationID(ctx)
 	var data BackupResourceModel
 	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	timeout := r.client.operationTimeout(ctx, data.Timeouts.Read, data.Timeout, &resp.Diagnostics)
 	if resp.Diagnostics.HasError() {
 		return
 	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	if !r.client.checkGuardrails("read", "Backup", data.ProjectID, &resp.Diagnostics) {
+		return
+	}
+	if data.DeletionProtection.IsNull() {
+		// Imported, or written by a provider version without the attribute.
+		data.DeletionProtection = types.BoolValue(false)
+	}
 	if data.Id.IsNull() || data.Id.ValueString() == "" {
 		resp.State.RemoveResource(ctx)
 		return
@@ -269,7 +315,7 @@ func (r *BackupResource) Read(ctx context.Context, req resource.ReadRequest, res
 			resp.State.RemoveResource(ctx)
 			return
 		}
-		resp.Diagnostics.AddError("API Error", provErr.Error())
+		AppendAPIError(&resp.Diagnostics, provErr)
 		return
 	}
 
@@ -280,13 +326,13 @@ func (r *BackupResource) Read(ctx context.Context, req resource.ReadRequest, res
 			fmt.Sprintf("Backup %q is in a terminal failure state (%s). "+
 				"Run `terraform destroy` to clean it up, or `terraform apply -replace=<address>` to recreate it.", data.Id.ValueString(), st))
 	case IsCreatingState(st):
-		if waitErr := backup.WaitUntilReady(ctx, sdkWaitOptions(r.client.ResourceTimeout)...); waitErr != nil {
+		if waitErr := backup.WaitUntilReady(ctx, sdkWaitOptions(timeout)...); waitErr != nil {
 			ReportWaitResult(&resp.Diagnostics, waitErr, "Backup", data.Id.ValueString())
 			return
 		}
 		backup, err = r.client.Client.FromStorage().Backups().Get(ctx, backupRef(&data))
 		if provErr := CheckResponseErr("read", "Backup", err); provErr != nil {
-			resp.Diagnostics.AddError("API Error", provErr.Error())
+			AppendAPIError(&resp.Diagnostics, provErr)
 			return
 		}
 	}
@@ -301,7 +347,7 @@ func applyBackupToState(backup *aruba.StorageBackup, data *BackupResourceModel)
 	data.Id = types.StringValue(backup.ID())
 	data.Uri = strVal(backup.URI())
 	data.Name = types.StringValue(backup.Name())
-	data.Tags = TagsToListPreserveNull(backup.Tags(), data.Tags)
+	data.Tags, data.TagsAll = flattenTags(backup.Tags(), data.Tags, data.TagsAll)
 	if backup.Region() != "" {
 		data.Location = types.StringValue(string(backup.Region()))
 	}
@@ -324,23 +370,34 @@ func applyBackupToState(backup *aruba.StorageBackup, data *BackupResourceModel)
 }
 
 func (r *BackupResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	ctx = withCorrelationID(ctx)
 	var data BackupResourceModel
 	var state BackupResourceModel
 
 	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
 	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	timeout := r.client.operationTimeout(ctx, data.Timeouts.Update, data.Timeout, &resp.Diagnostics)
 	if resp.Diagnostics.HasError() {
 		return
 	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	if !r.client.checkGuardrails("update", "Backup", data.ProjectID, &resp.Diagnostics) {
+		return
+	}
+	if deletionProtectionOnlyChange(req.Plan, req.State) {
+		resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+		return
+	}
 
-	tags := ListToTags(ctx, data.Tags, &resp.Diagnostics)
+	tags := r.client.requestTags(ctx, data.Tags, &data.TagsAll, &resp.Diagnostics)
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
 	backup, err := r.client.Client.FromStorage().Backups().Get(ctx, backupRef(&state))
 	if provErr := CheckResponseErr("read", "Backup", err); provErr != nil {
-		resp.Diagnostics.AddError("API Error", provErr.Error())
+		AppendAPIError(&resp.Diagnostics, provErr)
 		return
 	}
 
@@ -356,7 +413,7 @@ func (r *BackupResource) Update(ctx context.Context, req resource.UpdateRequest,
 
 	updated, err := r.client.Client.FromStorage().Backups().Update(ctx, backup)
 	if provErr := CheckResponseErr("update", "Backup", err); provErr != nil {
-		resp.Diagnostics.AddError("API Error", provErr.Error())
+		AppendAPIError(&resp.Diagnostics, provErr)
 		return
 	}
 
@@ -370,17 +427,27 @@ func (r *BackupResource) Update(ctx context.Context, req resource.UpdateRequest,
 	data.Location = state.Location
 	data.RetentionDays = state.RetentionDays
 	data.Name = types.StringValue(updated.Name())
-	data.Tags = TagsToListPreserveNull(updated.Tags(), data.Tags)
+	data.Tags, data.TagsAll = flattenTags(updated.Tags(), data.Tags, data.TagsAll)
 
 	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
 }
 
 func (r *BackupResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	ctx = withCorrelationID(ctx)
 	var data BackupResourceModel
 	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	timeout := r.client.operationTimeout(ctx, data.Timeouts.Delete, data.Timeout, &resp.Diagnostics)
 	if resp.Diagnostics.HasError() {
 		return
 	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	if !r.client.checkGuardrails("delete", "Backup", data.ProjectID, &resp.Diagnostics) {
+		return
+	}
+	if !checkDeletionProtection(data.DeletionProtection, "Backup", data.Id.ValueString(), &resp.Diagnostics) {
+		return
+	}
 
 	ref := backupRef(&data)
 	backupID := data.Id.ValueString()
@@ -400,12 +467,12 @@ func (r *BackupResource) Delete(ctx context.Context, req resource.DeleteRequest,
 	err := DeleteResourceWithRetry(ctx, func() error {
 		return CheckResponseErrAsError("delete", "Backup",
 			r.client.Client.FromStorage().Backups().Delete(ctx, ref))
-	}, "Backup", backupID, effectiveTimeout(data.Timeout, r.client.ResourceTimeout), deletionChecker)
+	}, "Backup", backupID, timeout, deletionChecker)
 	if err != nil {
 		resp.Diagnostics.AddError("Error deleting Backup", err.Error())
 		return
 	}
-	if waitErr := WaitForResourceDeleted(ctx, deletionChecker, "Backup", backupID, remainingTimeout(deleteStart, effectiveTimeout(data.Timeout, r.client.ResourceTimeout))); waitErr != nil {
+	if waitErr := WaitForResourceDeleted(ctx, deletionChecker, "Backup", backupID, remainingTimeout(deleteStart, timeout)); waitErr != nil {
 		resp.Diagnostics.AddError("Error waiting for Backup deletion", waitErr.Error())
 		return
 	}
@@ -4,7 +4,6 @@ import (
 	"context"
 	"fmt"
 
-	aruba "github.com/Arubacloud/sdk-go/pkg/aruba"
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
 	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/types"
@@ -128,11 +127,15 @@ func (d *BlockStorageDataSource) Configure(ctx context.Context, req datasource.C
 }
 
 func (d *BlockStorageDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	ctx = withCorrelationID(ctx)
 	var data BlockStorageDataSourceModel
 	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
+	if !d.client.checkGuardrails("read", "BlockStorage", data.ProjectId, &resp.Diagnostics) {
+		return
+	}
 
 	projectID := data.ProjectId.ValueString()
 	volumeID := data.Id.ValueString()
@@ -142,9 +145,9 @@ func (d *BlockStorageDataSource) Read(ctx context.Context, req datasource.ReadRe
 	}
 
 	vol, err := d.client.Client.FromStorage().Volumes().Get(ctx,
-		aruba.URI("/projects/"+projectID+"/providers/Aruba.Storage/blockStorages/"+volumeID))
+		newResourceURI(uriKindBlockStorage, projectID, volumeID).Ref())
 	if provErr := CheckResponseErr("read", "BlockStorage", err); provErr != nil {
-		resp.Diagnostics.AddError("API Error", provErr.Error())
+		AppendAPIError(&resp.Diagnostics, provErr)
 		return
 	}
 
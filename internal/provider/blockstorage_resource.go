@@ -6,6 +6,7 @@ import (
 	"time"
 
 	aruba "github.com/Arubacloud/sdk-go/pkg/aruba"
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
 	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
@@ -18,6 +19,7 @@ import (
 )
 
 var _ resource.Resource = &BlockStorageResource{}
+var _ resource.ResourceWithModifyPlan = &BlockStorageResource{}
 var _ resource.ResourceWithImportState = &BlockStorageResource{}
 
 func NewBlockStorageResource() resource.Resource {
@@ -25,19 +27,22 @@ func NewBlockStorageResource() resource.Resource {
 }
 
 type BlockStorageResourceModel struct {
-	Id            types.String `tfsdk:"id"`
-	Uri           types.String `tfsdk:"uri"`
-	Name          types.String `tfsdk:"name"`
-	ProjectID     types.String `tfsdk:"project_id"`
-	Location      types.String `tfsdk:"location"`
-	SizeGB        types.Int64  `tfsdk:"size_gb"`
-	BillingPeriod types.String `tfsdk:"billing_period"`
-	Zone          types.String `tfsdk:"zone"`
-	Type          types.String `tfsdk:"type"`
-	Bootable      types.Bool   `tfsdk:"bootable"`
-	Image         types.String `tfsdk:"image"`
-	Tags          types.List   `tfsdk:"tags"`
-	Timeout       types.String `tfsdk:"timeout"`
+	Id                 types.String   `tfsdk:"id"`
+	Uri                types.String   `tfsdk:"uri"`
+	Name               types.String   `tfsdk:"name"`
+	ProjectID          types.String   `tfsdk:"project_id"`
+	Location           types.String   `tfsdk:"location"`
+	SizeGB             types.Int64    `tfsdk:"size_gb"`
+	BillingPeriod      types.String   `tfsdk:"billing_period"`
+	Zone               types.String   `tfsdk:"zone"`
+	Type               types.String   `tfsdk:"type"`
+	Bootable           types.Bool     `tfsdk:"bootable"`
+	Image              types.String   `tfsdk:"image"`
+	Tags               types.List     `tfsdk:"tags"`
+	TagsAll            types.List     `tfsdk:"tags_all"`
+	DeletionProtection types.Bool     `tfsdk:"deletion_protection"`
+	Timeout            types.String   `tfsdk:"timeout"`
+	Timeouts           timeouts.Value `tfsdk:"timeouts"`
 }
 
 type BlockStorageResource struct {
@@ -68,16 +73,20 @@ func (r *BlockStorageResource) Schema(ctx context.Context, req resource.SchemaRe
 				Required:            true,
 			},
 			"project_id": schema.StringAttribute{
-				MarkdownDescription: "ID of the project that owns this resource. (Immutable — changing this value forces the resource to be destroyed and re-created.)",
-				Required:            true,
+				MarkdownDescription: defaultedDescription("ID of the project that owns this resource. (Immutable — changing this value forces the resource to be destroyed and re-created.)", "project_id"),
+				Optional:            true,
+				Computed:            true,
 				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
 					stringplanmodifier.RequiresReplace(),
 				},
 			},
 			"location": schema.StringAttribute{
-				MarkdownDescription: "Region identifier (e.g., `ITBG-Bergamo`). See the [available locations and zones](https://api.arubacloud.com/docs/metadata/#location-and-data-center). (Immutable — changing this value forces the resource to be destroyed and re-created.)",
-				Required:            true,
+				MarkdownDescription: defaultedDescription("Region identifier (e.g., `ITBG-Bergamo`). See the [available locations and zones](https://api.arubacloud.com/docs/metadata/#location-and-data-center). (Immutable — changing this value forces the resource to be destroyed and re-created.)", "location"),
+				Optional:            true,
+				Computed:            true,
 				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
 					stringplanmodifier.RequiresReplace(),
 				},
 			},
@@ -114,11 +123,17 @@ func (r *BlockStorageResource) Schema(ctx context.Context, req resource.SchemaRe
 				MarkdownDescription: "List of string tags attached to the resource for filtering and organisation.",
 				Optional:            true,
 			},
+			"tags_all":            tagsAllAttribute(),
+			"deletion_protection": deletionProtectionAttribute(),
 			"timeout": schema.StringAttribute{
-				MarkdownDescription: "Per-resource timeout override (e.g. `\"15m\"`, `\"1h\"`). Overrides the provider-level `resource_timeout` for this resource's Create and Delete operations. Uses Go duration syntax.",
+				MarkdownDescription: "Per-resource timeout override (e.g. `\"15m\"`, `\"1h\"`) applied to every operation not set in the `timeouts` block. Overrides the provider-level `resource_timeout`. Uses Go duration syntax. Deprecated — use the `timeouts` block instead.",
+				DeprecationMessage:  "Use the timeouts block, which sets the create, read, update and delete timeouts individually.",
 				Optional:            true,
 			},
 		},
+		Blocks: map[string]schema.Block{
+			"timeouts": timeouts.BlockAll(ctx),
+		},
 	}
 }
 
@@ -138,17 +153,14 @@ func (r *BlockStorageResource) Configure(ctx context.Context, req resource.Confi
 }
 
 func blockStorageRef(data *BlockStorageResourceModel) aruba.Ref {
-	if !data.Uri.IsNull() && data.Uri.ValueString() != "" {
-		return aruba.URI(data.Uri.ValueString())
-	}
-	return aruba.URI("/projects/" + data.ProjectID.ValueString() + "/providers/Aruba.Storage/blockStorages/" + data.Id.ValueString())
+	return uriRef(data.Uri, newResourceURI(uriKindBlockStorage, data.ProjectID.ValueString(), data.Id.ValueString()))
 }
 
 func applyBlockStorageToModel(vol *aruba.BlockStorage, data *BlockStorageResourceModel) {
 	data.Id = types.StringValue(vol.ID())
 	data.Uri = strVal(vol.URI())
 	data.Name = types.StringValue(vol.Name())
-	data.Tags = TagsToListPreserveNull(vol.Tags(), data.Tags)
+	data.Tags, data.TagsAll = flattenTags(vol.Tags(), data.Tags, data.TagsAll)
 	if vol.Region() != "" {
 		data.Location = types.StringValue(string(vol.Region()))
 	}
@@ -173,12 +185,33 @@ func applyBlockStorageToModel(vol *aruba.BlockStorage, data *BlockStorageResourc
 	}
 }
 
+func (r *BlockStorageResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	r.client.planTagsAll(ctx, req, resp, true)
+	r.client.planProviderDefaults(ctx, req, resp,
+		providerDefault{attr: "project_id", replace: true},
+		providerDefault{attr: "location", replace: true},
+	)
+	r.client.planDataLoss(ctx, req, resp, "BlockStorage",
+		"the volume with all data written to it",
+		path.Root("project_id"),
+		path.Root("location"),
+		path.Root("type"),
+	)
+}
+
 func (r *BlockStorageResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	ctx = withCorrelationID(ctx)
 	var data BlockStorageResourceModel
 	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	timeout := r.client.operationTimeout(ctx, data.Timeouts.Create, data.Timeout, &resp.Diagnostics)
 	if resp.Diagnostics.HasError() {
 		return
 	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	if !r.client.checkGuardrails("create", "BlockStorage", data.ProjectID, &resp.Diagnostics) {
+		return
+	}
 
 	projectID := data.ProjectID.ValueString()
 
@@ -189,14 +222,14 @@ func (r *BlockStorageResource) Create(ctx context.Context, req resource.CreateRe
 		}
 	}
 
-	tags := ListToTags(ctx, data.Tags, &resp.Diagnostics)
+	tags := r.client.requestTags(ctx, data.Tags, &data.TagsAll, &resp.Diagnostics)
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
 	builder := aruba.NewBlockStorage().
 		Named(data.Name.ValueString()).
-		InProject(aruba.URI("/projects/" + projectID)).
+		InProject(newResourceURI(uriKindProject, projectID, "").Ref()).
 		InRegion(aruba.Region(data.Location.ValueString())).
 		SizedGB(int(data.SizeGB.ValueInt64())).
 		OfType(aruba.BlockStorageType(data.Type.ValueString())).
@@ -215,7 +248,7 @@ func (r *BlockStorageResource) Create(ctx context.Context, req resource.CreateRe
 
 	vol, err := r.client.Client.FromStorage().Volumes().Create(ctx, builder)
 	if provErr := CheckResponseErr("create", "BlockStorage", err); provErr != nil {
-		resp.Diagnostics.AddError("API Error", provErr.Error())
+		AppendAPIError(&resp.Diagnostics, provErr)
 		return
 	}
 
@@ -227,7 +260,7 @@ func (r *BlockStorageResource) Create(ctx context.Context, req resource.CreateRe
 		return
 	}
 
-	if waitErr := vol.WaitUntilReady(ctx, sdkWaitOptions(effectiveTimeout(data.Timeout, r.client.ResourceTimeout))...); waitErr != nil {
+	if waitErr := vol.WaitUntilReady(ctx, sdkWaitOptions(timeout)...); waitErr != nil {
 		ReportWaitResult(&resp.Diagnostics, waitErr, "BlockStorage", data.Id.ValueString())
 		return
 	}
@@ -249,11 +282,22 @@ func (r *BlockStorageResource) Create(ctx context.Context, req resource.CreateRe
 }
 
 func (r *BlockStorageResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	ctx = withCorrelationID(ctx)
 	var data BlockStorageResourceModel
 	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	timeout := r.client.operationTimeout(ctx, data.Timeouts.Read, data.Timeout, &resp.Diagnostics)
 	if resp.Diagnostics.HasError() {
 		return
 	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	if !r.client.checkGuardrails("read", "BlockStorage", data.ProjectID, &resp.Diagnostics) {
+		return
+	}
+	if data.DeletionProtection.IsNull() {
+		// Imported, or written by a provider version without the attribute.
+		data.DeletionProtection = types.BoolValue(false)
+	}
 	if data.Id.IsNull() || data.Id.ValueString() == "" {
 		resp.State.RemoveResource(ctx)
 		return
@@ -265,7 +309,7 @@ func (r *BlockStorageResource) Read(ctx context.Context, req resource.ReadReques
 			resp.State.RemoveResource(ctx)
 			return
 		}
-		resp.Diagnostics.AddError("API Error", provErr.Error())
+		AppendAPIError(&resp.Diagnostics, provErr)
 		return
 	}
 
@@ -276,13 +320,13 @@ func (r *BlockStorageResource) Read(ctx context.Context, req resource.ReadReques
 			fmt.Sprintf("BlockStorage %q is in a terminal failure state (%s). "+
 				"Run `terraform destroy` to clean it up, or `terraform apply -replace=<address>` to recreate it.", data.Id.ValueString(), st))
 	case IsCreatingState(st):
-		if waitErr := vol.WaitUntilReady(ctx, sdkWaitOptions(r.client.ResourceTimeout)...); waitErr != nil {
+		if waitErr := vol.WaitUntilReady(ctx, sdkWaitOptions(timeout)...); waitErr != nil {
 			ReportWaitResult(&resp.Diagnostics, waitErr, "BlockStorage", data.Id.ValueString())
 			return
 		}
 		vol, err = r.client.Client.FromStorage().Volumes().Get(ctx, blockStorageRef(&data))
 		if provErr := CheckResponseErr("read", "BlockStorage", err); provErr != nil {
-			resp.Diagnostics.AddError("API Error", provErr.Error())
+			AppendAPIError(&resp.Diagnostics, provErr)
 			return
 		}
 	}
@@ -294,23 +338,34 @@ func (r *BlockStorageResource) Read(ctx context.Context, req resource.ReadReques
 }
 
 func (r *BlockStorageResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	ctx = withCorrelationID(ctx)
 	var data BlockStorageResourceModel
 	var state BlockStorageResourceModel
 
 	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
 	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	timeout := r.client.operationTimeout(ctx, data.Timeouts.Update, data.Timeout, &resp.Diagnostics)
 	if resp.Diagnostics.HasError() {
 		return
 	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	if !r.client.checkGuardrails("update", "BlockStorage", data.ProjectID, &resp.Diagnostics) {
+		return
+	}
+	if deletionProtectionOnlyChange(req.Plan, req.State) {
+		resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+		return
+	}
 
-	tags := ListToTags(ctx, data.Tags, &resp.Diagnostics)
+	tags := r.client.requestTags(ctx, data.Tags, &data.TagsAll, &resp.Diagnostics)
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
 	vol, err := r.client.Client.FromStorage().Volumes().Get(ctx, blockStorageRef(&state))
 	if provErr := CheckResponseErr("read", "BlockStorage", err); provErr != nil {
-		resp.Diagnostics.AddError("API Error", provErr.Error())
+		AppendAPIError(&resp.Diagnostics, provErr)
 		return
 	}
 
@@ -337,12 +392,12 @@ func (r *BlockStorageResource) Update(ctx context.Context, req resource.UpdateRe
 
 	updated, err := r.client.Client.FromStorage().Volumes().Update(ctx, vol)
 	if provErr := CheckResponseErr("update", "BlockStorage", err); provErr != nil {
-		resp.Diagnostics.AddError("API Error", provErr.Error())
+		AppendAPIError(&resp.Diagnostics, provErr)
 		return
 	}
 
 	// Wait for update to settle.
-	if waitErr := updated.WaitUntilReady(ctx, sdkWaitOptions(r.client.ResourceTimeout)...); waitErr != nil {
+	if waitErr := updated.WaitUntilReady(ctx, sdkWaitOptions(timeout)...); waitErr != nil {
 		ReportWaitResult(&resp.Diagnostics, waitErr, "BlockStorage", state.Id.ValueString())
 		return
 	}
@@ -361,7 +416,7 @@ func (r *BlockStorageResource) Update(ctx context.Context, req resource.UpdateRe
 		data.Bootable = state.Bootable
 		data.Image = state.Image
 		data.Name = types.StringValue(updated.Name())
-		data.Tags = TagsToListPreserveNull(updated.Tags(), data.Tags)
+		data.Tags, data.TagsAll = flattenTags(updated.Tags(), data.Tags, data.TagsAll)
 		data.SizeGB = types.Int64Value(int64(updated.SizeGB()))
 		data.BillingPeriod = strVal(string(updated.BillingPeriod()))
 	} else {
@@ -374,16 +429,25 @@ func (r *BlockStorageResource) Update(ctx context.Context, req resource.UpdateRe
 }
 
 func (r *BlockStorageResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	ctx = withCorrelationID(ctx)
 	var data BlockStorageResourceModel
 	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	timeout := r.client.operationTimeout(ctx, data.Timeouts.Delete, data.Timeout, &resp.Diagnostics)
 	if resp.Diagnostics.HasError() {
 		return
 	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	if !r.client.checkGuardrails("delete", "BlockStorage", data.ProjectID, &resp.Diagnostics) {
+		return
+	}
+	if !checkDeletionProtection(data.DeletionProtection, "BlockStorage", data.Id.ValueString(), &resp.Diagnostics) {
+		return
+	}
 
 	ref := blockStorageRef(&data)
 	volumeID := data.Id.ValueString()
 	projectID := data.ProjectID.ValueString()
-	timeout := effectiveTimeout(data.Timeout, r.client.ResourceTimeout)
 
 	// ArubaCloud API bug: a snapshot whose source volume is deleted first
 	// becomes permanently undeletable. To avoid this, list all snapshots in the
@@ -425,7 +489,7 @@ func (r *BlockStorageResource) Delete(ctx context.Context, req resource.DeleteRe
 // that were taken from this volume. This works around an ArubaCloud API bug where
 // a snapshot becomes permanently undeletable once its source volume is destroyed.
 func (r *BlockStorageResource) deleteAssociatedSnapshots(ctx context.Context, projectID, volumeID, volumeURI string, timeout time.Duration) error {
-	projectRef := aruba.URI("/projects/" + projectID)
+	projectRef := newResourceURI(uriKindProject, projectID, "").Ref()
 	snapList, listErr := r.client.Client.FromStorage().Snapshots().List(ctx, projectRef)
 	if listErr != nil {
 		// Non-fatal: log and continue so the volume delete can still proceed.
@@ -436,7 +500,7 @@ func (r *BlockStorageResource) deleteAssociatedSnapshots(ctx context.Context, pr
 	}
 
 	// Canonical volume URI path used as the fallback match key.
-	canonicalVolumeURI := "/projects/" + projectID + "/providers/Aruba.Storage/volumes/" + volumeID
+	canonicalVolumeURI := newResourceURI(uriKindBlockStorage, projectID, volumeID).String()
 
 	var iterErr error
 	_ = snapList.All(ctx, func(snap *aruba.Snapshot) bool {
@@ -451,7 +515,7 @@ func (r *BlockStorageResource) deleteAssociatedSnapshots(ctx context.Context, pr
 		if snapURI != "" {
 			snapRef = aruba.URI(snapURI)
 		} else {
-			snapRef = aruba.URI("/projects/" + projectID + "/providers/Aruba.Storage/snapshots/" + snapID)
+			snapRef = newResourceURI(uriKindSnapshot, projectID, snapID).Ref()
 		}
 
 		tflog.Info(ctx, "deleting snapshot before source volume to avoid API bug",
@@ -0,0 +1,67 @@
+package provider
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-framework/function"
+)
+
+var _ function.Function = &BuildURIFunction{}
+
+// BuildURIFunction implements provider::arubacloud::build_uri.
+type BuildURIFunction struct{}
+
+func NewBuildURIFunction() function.Function {
+	return &BuildURIFunction{}
+}
+
+func (f *BuildURIFunction) Metadata(ctx context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "build_uri"
+}
+
+func (f *BuildURIFunction) Definition(ctx context.Context, req function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary: "Assemble an ArubaCloud resource URI from its identifiers",
+		MarkdownDescription: "Returns the canonical URI of an ArubaCloud resource, e.g. " +
+			"`build_uri(\"vpc\", \"proj-1\", \"vpc-1\")` returns `/projects/proj-1/providers/Aruba.Network/vpcs/vpc-1`. " +
+			"Nested resources take the IDs of their parents as trailing arguments, outermost first: " +
+			"`build_uri(\"securityrule\", \"proj-1\", \"rule-1\", \"vpc-1\", \"sg-1\")`. " +
+			"`kind` matches the `arubacloud_<kind>` resource type (`vpc`, `subnet`, `cloudserver`, `dbaasuser`, …). " +
+			"For `kind = \"project\"`, `id` must be empty or equal to `project_id`.",
+		Parameters: []function.Parameter{
+			function.StringParameter{
+				Name:                "kind",
+				MarkdownDescription: "Resource kind, matching the `arubacloud_<kind>` resource type.",
+			},
+			function.StringParameter{
+				Name:                "project_id",
+				MarkdownDescription: "ID of the project that owns the resource.",
+			},
+			function.StringParameter{
+				Name:                "id",
+				MarkdownDescription: "ID of the resource itself.",
+			},
+		},
+		VariadicParameter: function.StringParameter{
+			Name:                "parent_ids",
+			MarkdownDescription: "IDs of the enclosing resources, outermost first (e.g. the VPC ID for a subnet).",
+		},
+		Return: function.StringReturn{},
+	}
+}
+
+func (f *BuildURIFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var kind, projectID, id string
+	var parentIDs []string
+	resp.Error = function.ConcatFuncErrors(resp.Error, req.Arguments.Get(ctx, &kind, &projectID, &id, &parentIDs))
+	if resp.Error != nil {
+		return
+	}
+
+	uri, err := BuildResourceURI(kind, projectID, id, parentIDs...)
+	if err != nil {
+		resp.Error = function.NewFuncError(err.Error())
+		return
+	}
+	resp.Error = function.ConcatFuncErrors(resp.Error, resp.Result.Set(ctx, uri.String()))
+}
@@ -4,7 +4,6 @@ import (
 	"context"
 	"fmt"
 
-	aruba "github.com/Arubacloud/sdk-go/pkg/aruba"
 	"github.com/hashicorp/terraform-plugin-framework/attr"
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
 	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
@@ -134,11 +133,15 @@ func (d *CloudServerDataSource) Configure(ctx context.Context, req datasource.Co
 }
 
 func (d *CloudServerDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	ctx = withCorrelationID(ctx)
 	var data CloudServerDataSourceModel
 	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
+	if !d.client.checkGuardrails("read", "CloudServer", data.ProjectID, &resp.Diagnostics) {
+		return
+	}
 
 	projectID := data.ProjectID.ValueString()
 	serverID := data.Id.ValueString()
@@ -147,10 +150,10 @@ func (d *CloudServerDataSource) Read(ctx context.Context, req datasource.ReadReq
 		return
 	}
 
-	ref := aruba.URI("/projects/" + projectID + "/compute/cloudServers/" + serverID)
+	ref := newResourceURI(uriKindCloudServer, projectID, serverID).Ref()
 	server, err := d.client.Client.FromCompute().CloudServers().Get(ctx, ref)
 	if provErr := CheckResponseErr("read", "Cloudserver", err); provErr != nil {
-		resp.Diagnostics.AddError("API Error", provErr.Error())
+		AppendAPIError(&resp.Diagnostics, provErr)
 		return
 	}
 
@@ -6,31 +6,38 @@ import (
 	"time"
 
 	aruba "github.com/Arubacloud/sdk-go/pkg/aruba"
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
 	"github.com/hashicorp/terraform-plugin-framework/attr"
 	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/listplanmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-framework/types/basetypes"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
 )
 
 type CloudServerResourceModel struct {
-	Id        types.String `tfsdk:"id"`
-	Uri       types.String `tfsdk:"uri"`
-	Name      types.String `tfsdk:"name"`
-	Location  types.String `tfsdk:"location"`
-	ProjectID types.String `tfsdk:"project_id"`
-	Zone      types.String `tfsdk:"zone"`
-	Tags      types.List   `tfsdk:"tags"`
-	Network   types.Object `tfsdk:"network"`
-	Settings  types.Object `tfsdk:"settings"`
-	Storage   types.Object `tfsdk:"storage"`
-	Timeout   types.String `tfsdk:"timeout"`
+	Id        types.String   `tfsdk:"id"`
+	Uri       types.String   `tfsdk:"uri"`
+	Name      types.String   `tfsdk:"name"`
+	Location  types.String   `tfsdk:"location"`
+	ProjectID types.String   `tfsdk:"project_id"`
+	Zone      types.String   `tfsdk:"zone"`
+	Tags      types.List     `tfsdk:"tags"`
+	TagsAll   types.List     `tfsdk:"tags_all"`
+	Network   types.Object   `tfsdk:"network"`
+	Settings  types.Object   `tfsdk:"settings"`
+	Storage   types.Object   `tfsdk:"storage"`
+	Timeout   types.String   `tfsdk:"timeout"`
+	Timeouts  timeouts.Value `tfsdk:"timeouts"`
 }
 
 type CloudServerNetworkModel struct {
@@ -41,9 +48,11 @@ type CloudServerNetworkModel struct {
 }
 
 type CloudServerSettingsModel struct {
-	FlavorName    types.String `tfsdk:"flavor_name"`
-	KeyPairUriRef types.String `tfsdk:"key_pair_uri_ref"`
-	UserData      types.String `tfsdk:"user_data"`
+	FlavorName        types.String `tfsdk:"flavor_name"`
+	KeyPairUriRef     types.String `tfsdk:"key_pair_uri_ref"`
+	UserData          types.String `tfsdk:"user_data"`
+	UserDataWO        types.String `tfsdk:"user_data_wo"`
+	UserDataWOVersion types.Int64  `tfsdk:"user_data_wo_version"`
 }
 
 type CloudServerStorageModel struct {
@@ -55,6 +64,7 @@ type CloudServerResource struct {
 }
 
 var _ resource.Resource = &CloudServerResource{}
+var _ resource.ResourceWithModifyPlan = &CloudServerResource{}
 var _ resource.ResourceWithImportState = &CloudServerResource{}
 
 func NewCloudServerResource() resource.Resource {
@@ -91,23 +101,29 @@ func (r *CloudServerResource) Schema(ctx context.Context, req resource.SchemaReq
 				},
 			},
 			"location": schema.StringAttribute{
-				MarkdownDescription: "Region identifier for the resource (e.g., `ITBG-Bergamo`). See the [available locations and zones](https://api.arubacloud.com/docs/metadata/#location-and-data-center). Changing this value forces a new resource.",
-				Required:            true,
+				MarkdownDescription: defaultedDescription("Region identifier for the resource (e.g., `ITBG-Bergamo`). See the [available locations and zones](https://api.arubacloud.com/docs/metadata/#location-and-data-center). Changing this value forces a new resource.", "location"),
+				Optional:            true,
+				Computed:            true,
 				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
 					stringplanmodifier.RequiresReplace(),
 				},
 			},
 			"project_id": schema.StringAttribute{
-				MarkdownDescription: "ID of the project that owns this resource. Changing this value forces a new resource.",
-				Required:            true,
+				MarkdownDescription: defaultedDescription("ID of the project that owns this resource. Changing this value forces a new resource.", "project_id"),
+				Optional:            true,
+				Computed:            true,
 				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
 					stringplanmodifier.RequiresReplace(),
 				},
 			},
 			"zone": schema.StringAttribute{
-				MarkdownDescription: "Availability zone within the region (e.g., `ITBG-1`). See [available zones](https://api.arubacloud.com/docs/metadata/#location-and-data-center). Changing this value forces a new resource.",
-				Required:            true,
+				MarkdownDescription: defaultedDescription("Availability zone within the region (e.g., `ITBG-1`). See [available zones](https://api.arubacloud.com/docs/metadata/#location-and-data-center). Changing this value forces a new resource.", "zone"),
+				Optional:            true,
+				Computed:            true,
 				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
 					stringplanmodifier.RequiresReplace(),
 				},
 			},
@@ -119,6 +135,7 @@ func (r *CloudServerResource) Schema(ctx context.Context, req resource.SchemaReq
 					listplanmodifier.RequiresReplace(),
 				},
 			},
+			"tags_all": tagsAllAttribute(),
 			"network": schema.SingleNestedAttribute{
 				MarkdownDescription: "Network configuration for the CloudServer.",
 				Required:            true,
@@ -177,12 +194,32 @@ func (r *CloudServerResource) Schema(ctx context.Context, req resource.SchemaReq
 						},
 					},
 					"user_data": schema.StringAttribute{
-						MarkdownDescription: "Cloud-Init configuration passed verbatim to the instance at first boot (raw YAML or shell-script). Write-only — this value is sent to the API but is not returned in subsequent read responses. Changing this value forces a new resource.",
+						MarkdownDescription: "Cloud-Init configuration passed verbatim to the instance at first boot (raw YAML or shell-script). Deprecated — the value is stored in Terraform state; use `user_data_wo` instead. Conflicts with `user_data_wo`. Changing this value forces a new resource.",
 						Optional:            true,
 						Sensitive:           true,
+						DeprecationMessage:  "Use settings.user_data_wo (Terraform 1.11+) so the Cloud-Init payload is never stored in state.",
 						PlanModifiers: []planmodifier.String{
 							stringplanmodifier.RequiresReplace(),
 						},
+						Validators: []validator.String{
+							stringvalidator.ConflictsWith(path.MatchRelative().AtParent().AtName("user_data_wo")),
+						},
+					},
+					"user_data_wo": schema.StringAttribute{
+						MarkdownDescription: "Cloud-Init configuration passed verbatim to the instance at first boot (raw YAML or shell-script). Write-only (Terraform 1.11+) — the value is sent to the API but never stored in plan or state. Bump `user_data_wo_version` to boot a new instance with changed user data.",
+						Optional:            true,
+						Sensitive:           true,
+						WriteOnly:           true,
+					},
+					"user_data_wo_version": schema.Int64Attribute{
+						MarkdownDescription: "Version of `user_data_wo`. Terraform cannot detect changes to write-only values, so change this number whenever `user_data_wo` changes. Changing this value forces a new resource.",
+						Optional:            true,
+						PlanModifiers: []planmodifier.Int64{
+							int64planmodifier.RequiresReplace(),
+						},
+						Validators: []validator.Int64{
+							int64validator.AlsoRequires(path.MatchRelative().AtParent().AtName("user_data_wo")),
+						},
 					},
 				},
 			},
@@ -201,10 +238,14 @@ func (r *CloudServerResource) Schema(ctx context.Context, req resource.SchemaReq
 				},
 			},
 			"timeout": schema.StringAttribute{
-				MarkdownDescription: "Per-resource timeout override (e.g. `\"15m\"`, `\"1h\"`). Overrides the provider-level `resource_timeout` for this resource's Create and Delete operations. Uses Go duration syntax.",
+				MarkdownDescription: "Per-resource timeout override (e.g. `\"15m\"`, `\"1h\"`) applied to every operation not set in the `timeouts` block. Overrides the provider-level `resource_timeout`. Uses Go duration syntax. Deprecated — use the `timeouts` block instead.",
+				DeprecationMessage:  "Use the timeouts block, which sets the create, read, update and delete timeouts individually.",
 				Optional:            true,
 			},
 		},
+		Blocks: map[string]schema.Block{
+			"timeouts": timeouts.BlockAll(ctx),
+		},
 	}
 }
 
@@ -223,12 +264,28 @@ func (r *CloudServerResource) Configure(ctx context.Context, req resource.Config
 	r.client = client
 }
 
+func (r *CloudServerResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	r.client.planTagsAll(ctx, req, resp, false)
+	r.client.planProviderDefaults(ctx, req, resp,
+		providerDefault{attr: "project_id", replace: true},
+		providerDefault{attr: "location", replace: true},
+		providerDefault{attr: "zone", replace: true},
+	)
+}
+
 func (r *CloudServerResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	ctx = withCorrelationID(ctx)
 	var data CloudServerResourceModel
 	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	timeout := r.client.operationTimeout(ctx, data.Timeouts.Create, data.Timeout, &resp.Diagnostics)
 	if resp.Diagnostics.HasError() {
 		return
 	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	if !r.client.checkGuardrails("create", "CloudServer", data.ProjectID, &resp.Diagnostics) {
+		return
+	}
 
 	projectID := data.ProjectID.ValueString()
 	if projectID == "" {
@@ -252,7 +309,7 @@ func (r *CloudServerResource) Create(ctx context.Context, req resource.CreateReq
 		return
 	}
 
-	tags := ListToTags(ctx, data.Tags, &resp.Diagnostics)
+	tags := r.client.requestTags(ctx, data.Tags, &data.TagsAll, &resp.Diagnostics)
 	if resp.Diagnostics.HasError() {
 		return
 	}
@@ -283,7 +340,7 @@ func (r *CloudServerResource) Create(ctx context.Context, req resource.CreateReq
 
 	builder := aruba.NewCloudServer().
 		Named(data.Name.ValueString()).
-		InProject(aruba.URI("/projects/" + projectID)).
+		InProject(newResourceURI(uriKindProject, projectID, "").Ref()).
 		InRegion(aruba.Region(data.Location.ValueString())).
 		InZone(aruba.Zone(data.Zone.ValueString())).
 		OfFlavor(aruba.CloudServerFlavor(settingsModel.FlavorName.ValueString())).
@@ -299,13 +356,18 @@ func (r *CloudServerResource) Create(ctx context.Context, req resource.CreateReq
 	if !networkModel.ElasticIpUriRef.IsNull() && networkModel.ElasticIpUriRef.ValueString() != "" {
 		builder = builder.WithElasticIP(aruba.URI(networkModel.ElasticIpUriRef.ValueString()))
 	}
-	if !settingsModel.UserData.IsNull() && settingsModel.UserData.ValueString() != "" {
-		builder = builder.WithUserData(settingsModel.UserData.ValueString())
+	userData, diags := writeOnlyOrDeprecated(ctx, req.Config, path.Root("settings").AtName("user_data_wo"), settingsModel.UserData)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	if !userData.IsNull() && userData.ValueString() != "" {
+		builder = builder.WithUserData(userData.ValueString())
 	}
 
 	server, err := r.client.Client.FromCompute().CloudServers().Create(ctx, builder)
 	if provErr := CheckResponseErr("create", "CloudServer", err); provErr != nil {
-		resp.Diagnostics.AddError("API Error", provErr.Error())
+		AppendAPIError(&resp.Diagnostics, provErr)
 		return
 	}
 
@@ -323,7 +385,7 @@ func (r *CloudServerResource) Create(ctx context.Context, req resource.CreateReq
 		return
 	}
 
-	if err := server.WaitUntilReady(ctx, sdkWaitOptions(effectiveTimeout(data.Timeout, r.client.ResourceTimeout))...); err != nil {
+	if err := server.WaitUntilReady(ctx, sdkWaitOptions(timeout)...); err != nil {
 		ReportWaitResult(&resp.Diagnostics, err, "CloudServer", serverID)
 		return
 	}
@@ -350,18 +412,22 @@ func (r *CloudServerResource) Create(ctx context.Context, req resource.CreateReq
 // cloudServerRef returns the Ref to use for Get/Update/Delete.
 // Falls back to a constructed URI for the import flow where stored URI may be empty.
 func cloudServerRef(data *CloudServerResourceModel) aruba.Ref {
-	if !data.Uri.IsNull() && data.Uri.ValueString() != "" {
-		return aruba.URI(data.Uri.ValueString())
-	}
-	return aruba.URI("/projects/" + data.ProjectID.ValueString() + "/compute/cloudServers/" + data.Id.ValueString())
+	return uriRef(data.Uri, newResourceURI(uriKindCloudServer, data.ProjectID.ValueString(), data.Id.ValueString()))
 }
 
 func (r *CloudServerResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	ctx = withCorrelationID(ctx)
 	var originalState CloudServerResourceModel
 	resp.Diagnostics.Append(req.State.Get(ctx, &originalState)...)
+	timeout := r.client.operationTimeout(ctx, originalState.Timeouts.Read, originalState.Timeout, &resp.Diagnostics)
 	if resp.Diagnostics.HasError() {
 		return
 	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	if !r.client.checkGuardrails("read", "CloudServer", originalState.ProjectID, &resp.Diagnostics) {
+		return
+	}
 
 	serverID := originalState.Id.ValueString()
 	if originalState.Id.IsUnknown() || originalState.Id.IsNull() || serverID == "" {
@@ -375,7 +441,7 @@ func (r *CloudServerResource) Read(ctx context.Context, req resource.ReadRequest
 			resp.State.RemoveResource(ctx)
 			return
 		}
-		resp.Diagnostics.AddError("API Error", provErr.Error())
+		AppendAPIError(&resp.Diagnostics, provErr)
 		return
 	}
 
@@ -389,14 +455,14 @@ func (r *CloudServerResource) Read(ctx context.Context, req resource.ReadRequest
 				"Run `terraform destroy` to clean it up, or `terraform apply -replace=<address>` to recreate it.", serverID, st),
 		)
 	case IsCreatingState(st):
-		if waitErr := server.WaitUntilReady(ctx, sdkWaitOptions(r.client.ResourceTimeout)...); waitErr != nil {
+		if waitErr := server.WaitUntilReady(ctx, sdkWaitOptions(timeout)...); waitErr != nil {
 			ReportWaitResult(&resp.Diagnostics, waitErr, "CloudServer", serverID)
 			return
 		}
 		// Re-read after wait.
 		server, err = r.client.Client.FromCompute().CloudServers().Get(ctx, cloudServerRef(&originalState))
 		if provErr := CheckResponseErr("read", "CloudServer", err); provErr != nil {
-			resp.Diagnostics.AddError("API Error", provErr.Error())
+			AppendAPIError(&resp.Diagnostics, provErr)
 			return
 		}
 	}
@@ -425,7 +491,7 @@ func (r *CloudServerResource) applyServerToState(
 		data.Uri = types.StringNull()
 	}
 	data.Name = types.StringValue(server.Name())
-	data.Tags = TagsToListPreserveNull(server.Tags(), data.Tags)
+	data.Tags, data.TagsAll = flattenTags(server.Tags(), data.Tags, data.TagsAll)
 
 	raw := server.Raw()
 	if raw != nil {
@@ -437,6 +503,8 @@ func (r *CloudServerResource) applyServerToState(
 
 	if originalState != nil {
 		data.ProjectID = originalState.ProjectID
+		data.Timeout = originalState.Timeout
+		data.Timeouts = originalState.Timeouts
 	}
 
 	// ── Network object ────────────────────────────────────────────────────────
@@ -490,9 +558,11 @@ func (r *CloudServerResource) applyServerToState(
 		}
 	}
 	settingsAttrs := map[string]attr.Value{
-		"flavor_name":      types.StringValue(string(server.Flavor())),
-		"key_pair_uri_ref": resolveKeyPairUriRef(server.KeyPair(), origSettings.KeyPairUriRef),
-		"user_data":        origSettings.UserData, // write-only; never returned by API
+		"flavor_name":          types.StringValue(string(server.Flavor())),
+		"key_pair_uri_ref":     resolveKeyPairUriRef(server.KeyPair(), origSettings.KeyPairUriRef),
+		"user_data":            origSettings.UserData, // never returned by API
+		"user_data_wo":         types.StringNull(),
+		"user_data_wo_version": origSettings.UserDataWOVersion,
 	}
 	settingsObj, d := types.ObjectValue(csSettingsAttrTypes(), settingsAttrs)
 	diags.Append(d...)
@@ -533,9 +603,11 @@ func csNetworkAttrTypes() map[string]attr.Type {
 
 func csSettingsAttrTypes() map[string]attr.Type {
 	return map[string]attr.Type{
-		"flavor_name":      types.StringType,
-		"key_pair_uri_ref": types.StringType,
-		"user_data":        types.StringType,
+		"flavor_name":          types.StringType,
+		"key_pair_uri_ref":     types.StringType,
+		"user_data":            types.StringType,
+		"user_data_wo":         types.StringType,
+		"user_data_wo_version": types.Int64Type,
 	}
 }
 
@@ -544,6 +616,7 @@ func csStorageAttrTypes() map[string]attr.Type {
 }
 
 func (r *CloudServerResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	ctx = withCorrelationID(ctx)
 	var data CloudServerResourceModel
 	var state CloudServerResourceModel
 	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
@@ -551,6 +624,9 @@ func (r *CloudServerResource) Update(ctx context.Context, req resource.UpdateReq
 	if resp.Diagnostics.HasError() {
 		return
 	}
+	if !r.client.checkGuardrails("update", "CloudServer", data.ProjectID, &resp.Diagnostics) {
+		return
+	}
 	// All API-backed fields carry RequiresReplace; Update() is only reached
 	// when the local-only `timeout` field changes. Preserve computed fields.
 	data.Id = state.Id
@@ -559,11 +635,18 @@ func (r *CloudServerResource) Update(ctx context.Context, req resource.UpdateReq
 }
 
 func (r *CloudServerResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	ctx = withCorrelationID(ctx)
 	var data CloudServerResourceModel
 	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	timeout := r.client.operationTimeout(ctx, data.Timeouts.Delete, data.Timeout, &resp.Diagnostics)
 	if resp.Diagnostics.HasError() {
 		return
 	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	if !r.client.checkGuardrails("delete", "CloudServer", data.ProjectID, &resp.Diagnostics) {
+		return
+	}
 
 	serverID := data.Id.ValueString()
 	if serverID == "" {
@@ -593,7 +676,7 @@ func (r *CloudServerResource) Delete(ctx context.Context, req resource.DeleteReq
 		},
 		"CloudServer",
 		serverID,
-		effectiveTimeout(data.Timeout, r.client.ResourceTimeout),
+		timeout,
 		deletionChecker,
 	)
 	if err != nil {
@@ -601,7 +684,7 @@ func (r *CloudServerResource) Delete(ctx context.Context, req resource.DeleteReq
 		return
 	}
 
-	if waitErr := WaitForResourceDeleted(ctx, deletionChecker, "CloudServer", serverID, remainingTimeout(deleteStart, effectiveTimeout(data.Timeout, r.client.ResourceTimeout))); waitErr != nil {
+	if waitErr := WaitForResourceDeleted(ctx, deletionChecker, "CloudServer", serverID, remainingTimeout(deleteStart, timeout)); waitErr != nil {
 		resp.Diagnostics.AddError("Error waiting for CloudServer deletion", waitErr.Error())
 		return
 	}
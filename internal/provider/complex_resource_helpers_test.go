@@ -32,6 +32,11 @@ func buildFullTFValue(ty tftypes.Type) tftypes.Value {
 	if objType, ok := ty.(tftypes.Object); ok {
 		attrs := make(map[string]tftypes.Value, len(objType.AttributeTypes))
 		for name, attrType := range objType.AttributeTypes {
+			if name == "timeouts" {
+				// "test-value" is not a duration; leave the block unset.
+				attrs[name] = tftypes.NewValue(attrType, nil)
+				continue
+			}
 			attrs[name] = buildFullTFValue(attrType)
 		}
 		return tftypes.NewValue(objType, attrs)
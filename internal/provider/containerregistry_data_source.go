@@ -4,7 +4,6 @@ import (
 	"context"
 	"fmt"
 
-	aruba "github.com/Arubacloud/sdk-go/pkg/aruba"
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
 	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/types"
@@ -126,11 +125,15 @@ func (d *ContainerRegistryDataSource) Configure(ctx context.Context, req datasou
 }
 
 func (d *ContainerRegistryDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	ctx = withCorrelationID(ctx)
 	var data ContainerRegistryDataSourceModel
 	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
+	if !d.client.checkGuardrails("read", "ContainerRegistry", data.ProjectID, &resp.Diagnostics) {
+		return
+	}
 
 	projectID := data.ProjectID.ValueString()
 	registryID := data.Id.ValueString()
@@ -140,9 +143,9 @@ func (d *ContainerRegistryDataSource) Read(ctx context.Context, req datasource.R
 	}
 
 	registry, err := d.client.Client.FromContainer().ContainerRegistry().Get(ctx,
-		aruba.URI("/projects/"+projectID+"/providers/Aruba.Container/registries/"+registryID))
+		newResourceURI(uriKindContainerRegistry, projectID, registryID).Ref())
 	if provErr := CheckResponseErr("read", "ContainerRegistry", err); provErr != nil {
-		resp.Diagnostics.AddError("API Error", provErr.Error())
+		AppendAPIError(&resp.Diagnostics, provErr)
 		return
 	}
 
@@ -6,6 +6,7 @@ import (
 	"time"
 
 	aruba "github.com/Arubacloud/sdk-go/pkg/aruba"
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
 	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
 	"github.com/hashicorp/terraform-plugin-framework/attr"
 	"github.com/hashicorp/terraform-plugin-framework/path"
@@ -20,17 +21,20 @@ import (
 )
 
 type ContainerRegistryResourceModel struct {
-	Id            types.String `tfsdk:"id"`
-	Uri           types.String `tfsdk:"uri"`
-	Name          types.String `tfsdk:"name"`
-	Location      types.String `tfsdk:"location"`
-	Tags          types.List   `tfsdk:"tags"`
-	ProjectID     types.String `tfsdk:"project_id"`
-	BillingPeriod types.String `tfsdk:"billing_period"`
-	Network       types.Object `tfsdk:"network"`
-	Storage       types.Object `tfsdk:"storage"`
-	Settings      types.Object `tfsdk:"settings"`
-	Timeout       types.String `tfsdk:"timeout"`
+	Id                 types.String   `tfsdk:"id"`
+	Uri                types.String   `tfsdk:"uri"`
+	Name               types.String   `tfsdk:"name"`
+	Location           types.String   `tfsdk:"location"`
+	Tags               types.List     `tfsdk:"tags"`
+	TagsAll            types.List     `tfsdk:"tags_all"`
+	ProjectID          types.String   `tfsdk:"project_id"`
+	BillingPeriod      types.String   `tfsdk:"billing_period"`
+	Network            types.Object   `tfsdk:"network"`
+	Storage            types.Object   `tfsdk:"storage"`
+	Settings           types.Object   `tfsdk:"settings"`
+	DeletionProtection types.Bool     `tfsdk:"deletion_protection"`
+	Timeout            types.String   `tfsdk:"timeout"`
+	Timeouts           timeouts.Value `tfsdk:"timeouts"`
 }
 
 type ContainerRegistryNetworkModel struct {
@@ -54,6 +58,7 @@ type ContainerRegistryResource struct {
 }
 
 var _ resource.Resource = &ContainerRegistryResource{}
+var _ resource.ResourceWithModifyPlan = &ContainerRegistryResource{}
 var _ resource.ResourceWithImportState = &ContainerRegistryResource{}
 
 func NewContainerRegistryResource() resource.Resource {
@@ -84,17 +89,22 @@ func (r *ContainerRegistryResource) Schema(ctx context.Context, req resource.Sch
 				Required:            true,
 			},
 			"location": schema.StringAttribute{
-				MarkdownDescription: "Region identifier (e.g., `ITBG-Bergamo`). See the [available locations and zones](https://api.arubacloud.com/docs/metadata/#location-and-data-center).",
-				Required:            true,
+				MarkdownDescription: defaultedDescription("Region identifier (e.g., `ITBG-Bergamo`). See the [available locations and zones](https://api.arubacloud.com/docs/metadata/#location-and-data-center).", "location"),
+				Optional:            true,
+				Computed:            true,
+				PlanModifiers:       []planmodifier.String{stringplanmodifier.UseStateForUnknown()},
 			},
 			"tags": schema.ListAttribute{
 				ElementType:         types.StringType,
 				MarkdownDescription: "List of string tags attached to the resource for filtering and organisation.",
 				Optional:            true,
 			},
+			"tags_all": tagsAllAttribute(),
 			"project_id": schema.StringAttribute{
-				MarkdownDescription: "ID of the project that owns this resource.",
-				Required:            true,
+				MarkdownDescription: defaultedDescription("ID of the project that owns this resource.", "project_id"),
+				Optional:            true,
+				Computed:            true,
+				PlanModifiers:       []planmodifier.String{stringplanmodifier.UseStateForUnknown()},
 			},
 			"billing_period": schema.StringAttribute{
 				MarkdownDescription: "Billing cycle. Accepted values: `Hour`, `Month`, `Year`.",
@@ -152,11 +162,16 @@ func (r *ContainerRegistryResource) Schema(ctx context.Context, req resource.Sch
 					},
 				},
 			},
+			"deletion_protection": deletionProtectionAttribute(),
 			"timeout": schema.StringAttribute{
-				MarkdownDescription: "Per-resource timeout override (e.g. `\"15m\"`, `\"1h\"`). Overrides the provider-level `resource_timeout` for this resource's Create and Delete operations. Uses Go duration syntax.",
+				MarkdownDescription: "Per-resource timeout override (e.g. `\"15m\"`, `\"1h\"`) applied to every operation not set in the `timeouts` block. Overrides the provider-level `resource_timeout`. Uses Go duration syntax. Deprecated — use the `timeouts` block instead.",
+				DeprecationMessage:  "Use the timeouts block, which sets the create, read, update and delete timeouts individually.",
 				Optional:            true,
 			},
 		},
+		Blocks: map[string]schema.Block{
+			"timeouts": timeouts.BlockAll(ctx),
+		},
 	}
 }
 
@@ -177,15 +192,13 @@ func (r *ContainerRegistryResource) Configure(ctx context.Context, req resource.
 
 func containerRegistryRef(data *ContainerRegistryResourceModel) aruba.Ref {
 	// Prefer the ID-based path so the SDK can always extract the resource ID.
-	// The API returns URIs with "containerRegistries" in the path, but the SDK
-	// expects the "registries" path segment — using the stored URI verbatim
-	// would cause "cannot determine registry ID from Ref" errors.
+	// A stored URI is still accepted: uriRef rewrites the API's
+	// "containerRegistries" segment to the "registries" spelling the SDK expects.
 	if !data.Id.IsNull() && !data.Id.IsUnknown() && data.Id.ValueString() != "" {
-		return aruba.URI("/projects/" + data.ProjectID.ValueString() +
-			"/providers/Aruba.Container/registries/" + data.Id.ValueString())
+		return newResourceURI(uriKindContainerRegistry, data.ProjectID.ValueString(), data.Id.ValueString()).Ref()
 	}
 	if !data.Uri.IsNull() && data.Uri.ValueString() != "" {
-		return aruba.URI(data.Uri.ValueString())
+		return uriRef(data.Uri, ResourceURI{})
 	}
 	return aruba.URI("")
 }
@@ -194,7 +207,7 @@ func applyContainerRegistryToModel(reg *aruba.ContainerRegistry, data *Container
 	data.Id = types.StringValue(reg.ID())
 	data.Uri = strVal(reg.URI())
 	data.Name = types.StringValue(reg.Name())
-	data.Tags = TagsToListPreserveNull(reg.Tags(), data.Tags)
+	data.Tags, data.TagsAll = flattenTags(reg.Tags(), data.Tags, data.TagsAll)
 	if reg.Region() != "" {
 		data.Location = types.StringValue(string(reg.Region()))
 	}
@@ -235,14 +248,31 @@ func applyContainerRegistryToModel(reg *aruba.ContainerRegistry, data *Container
 	data.Settings = settingsObj
 }
 
+func (r *ContainerRegistryResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	r.client.planTagsAll(ctx, req, resp, true)
+	r.client.planProviderDefaults(ctx, req, resp,
+		providerDefault{attr: "project_id", replace: false},
+		providerDefault{attr: "location", replace: false},
+	)
+	r.client.planDataLoss(ctx, req, resp, "ContainerRegistry",
+		"the registry with every image pushed to it")
+}
+
 func (r *ContainerRegistryResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	ctx = withCorrelationID(ctx)
 	var data ContainerRegistryResourceModel
 	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	timeout := r.client.operationTimeout(ctx, data.Timeouts.Create, data.Timeout, &resp.Diagnostics)
 	if resp.Diagnostics.HasError() {
 		return
 	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	if !r.client.checkGuardrails("create", "ContainerRegistry", data.ProjectID, &resp.Diagnostics) {
+		return
+	}
 
-	tags := ListToTags(ctx, data.Tags, &resp.Diagnostics)
+	tags := r.client.requestTags(ctx, data.Tags, &data.TagsAll, &resp.Diagnostics)
 	if resp.Diagnostics.HasError() {
 		return
 	}
@@ -262,7 +292,7 @@ func (r *ContainerRegistryResource) Create(ctx context.Context, req resource.Cre
 	projectID := data.ProjectID.ValueString()
 	builder := aruba.NewContainerRegistry().
 		Named(data.Name.ValueString()).
-		InProject(aruba.URI("/projects/" + projectID)).
+		InProject(newResourceURI(uriKindProject, projectID, "").Ref()).
 		InRegion(aruba.Region(data.Location.ValueString())).
 		Tagged(tags...).
 		WithElasticIP(aruba.URI(networkModel.PublicIpUriRef.ValueString())).
@@ -291,7 +321,7 @@ func (r *ContainerRegistryResource) Create(ctx context.Context, req resource.Cre
 
 	registry, err := r.client.Client.FromContainer().ContainerRegistry().Create(ctx, builder)
 	if provErr := CheckResponseErr("create", "ContainerRegistry", err); provErr != nil {
-		resp.Diagnostics.AddError("API Error", provErr.Error())
+		AppendAPIError(&resp.Diagnostics, provErr)
 		return
 	}
 
@@ -310,7 +340,7 @@ func (r *ContainerRegistryResource) Create(ctx context.Context, req resource.Cre
 		}
 		return string(reg.State()), nil
 	}
-	if waitErr := WaitForResourceActive(ctx, crChecker, "ContainerRegistry", data.Id.ValueString(), effectiveTimeout(data.Timeout, r.client.ResourceTimeout)); waitErr != nil {
+	if waitErr := WaitForResourceActive(ctx, crChecker, "ContainerRegistry", data.Id.ValueString(), timeout); waitErr != nil {
 		ReportWaitResult(&resp.Diagnostics, waitErr, "ContainerRegistry", data.Id.ValueString())
 		return
 	}
@@ -332,11 +362,22 @@ func (r *ContainerRegistryResource) Create(ctx context.Context, req resource.Cre
 }
 
 func (r *ContainerRegistryResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	ctx = withCorrelationID(ctx)
 	var data ContainerRegistryResourceModel
 	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	timeout := r.client.operationTimeout(ctx, data.Timeouts.Read, data.Timeout, &resp.Diagnostics)
 	if resp.Diagnostics.HasError() {
 		return
 	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	if !r.client.checkGuardrails("read", "ContainerRegistry", data.ProjectID, &resp.Diagnostics) {
+		return
+	}
+	if data.DeletionProtection.IsNull() {
+		// Imported, or written by a provider version without the attribute.
+		data.DeletionProtection = types.BoolValue(false)
+	}
 	if data.Id.IsNull() || data.Id.ValueString() == "" {
 		resp.State.RemoveResource(ctx)
 		return
@@ -348,7 +389,7 @@ func (r *ContainerRegistryResource) Read(ctx context.Context, req resource.ReadR
 			resp.State.RemoveResource(ctx)
 			return
 		}
-		resp.Diagnostics.AddError("API Error", provErr.Error())
+		AppendAPIError(&resp.Diagnostics, provErr)
 		return
 	}
 
@@ -366,13 +407,13 @@ func (r *ContainerRegistryResource) Read(ctx context.Context, req resource.ReadR
 			}
 			return string(reg.State()), nil
 		}
-		if waitErr := WaitForResourceActive(ctx, crChecker, "ContainerRegistry", data.Id.ValueString(), r.client.ResourceTimeout); waitErr != nil {
+		if waitErr := WaitForResourceActive(ctx, crChecker, "ContainerRegistry", data.Id.ValueString(), timeout); waitErr != nil {
 			ReportWaitResult(&resp.Diagnostics, waitErr, "ContainerRegistry", data.Id.ValueString())
 			return
 		}
 		registry, err = r.client.Client.FromContainer().ContainerRegistry().Get(ctx, containerRegistryRef(&data))
 		if provErr := CheckResponseErr("read", "ContainerRegistry", err); provErr != nil {
-			resp.Diagnostics.AddError("API Error", provErr.Error())
+			AppendAPIError(&resp.Diagnostics, provErr)
 			return
 		}
 	}
@@ -384,23 +425,34 @@ func (r *ContainerRegistryResource) Read(ctx context.Context, req resource.ReadR
 }
 
 func (r *ContainerRegistryResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	ctx = withCorrelationID(ctx)
 	var data ContainerRegistryResourceModel
 	var state ContainerRegistryResourceModel
 
 	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
 	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	timeout := r.client.operationTimeout(ctx, data.Timeouts.Update, data.Timeout, &resp.Diagnostics)
 	if resp.Diagnostics.HasError() {
 		return
 	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	if !r.client.checkGuardrails("update", "ContainerRegistry", data.ProjectID, &resp.Diagnostics) {
+		return
+	}
+	if deletionProtectionOnlyChange(req.Plan, req.State) {
+		resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+		return
+	}
 
-	tags := ListToTags(ctx, data.Tags, &resp.Diagnostics)
+	tags := r.client.requestTags(ctx, data.Tags, &data.TagsAll, &resp.Diagnostics)
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
 	registry, err := r.client.Client.FromContainer().ContainerRegistry().Get(ctx, containerRegistryRef(&state))
 	if provErr := CheckResponseErr("read", "ContainerRegistry", err); provErr != nil {
-		resp.Diagnostics.AddError("API Error", provErr.Error())
+		AppendAPIError(&resp.Diagnostics, provErr)
 		return
 	}
 
@@ -452,7 +504,7 @@ func (r *ContainerRegistryResource) Update(ctx context.Context, req resource.Upd
 
 	updated, err := r.client.Client.FromContainer().ContainerRegistry().Update(ctx, registry)
 	if provErr := CheckResponseErr("update", "ContainerRegistry", err); provErr != nil {
-		resp.Diagnostics.AddError("API Error", provErr.Error())
+		AppendAPIError(&resp.Diagnostics, provErr)
 		return
 	}
 
@@ -467,11 +519,21 @@ func (r *ContainerRegistryResource) Update(ctx context.Context, req resource.Upd
 }
 
 func (r *ContainerRegistryResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	ctx = withCorrelationID(ctx)
 	var data ContainerRegistryResourceModel
 	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	timeout := r.client.operationTimeout(ctx, data.Timeouts.Delete, data.Timeout, &resp.Diagnostics)
 	if resp.Diagnostics.HasError() {
 		return
 	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	if !r.client.checkGuardrails("delete", "ContainerRegistry", data.ProjectID, &resp.Diagnostics) {
+		return
+	}
+	if !checkDeletionProtection(data.DeletionProtection, "ContainerRegistry", data.Id.ValueString(), &resp.Diagnostics) {
+		return
+	}
 
 	ref := containerRegistryRef(&data)
 	registryID := data.Id.ValueString()
@@ -499,12 +561,12 @@ func (r *ContainerRegistryResource) Delete(ctx context.Context, req resource.Del
 	err := DeleteResourceWithRetry(ctx, func() error {
 		return CheckResponseErrAsError("delete", "ContainerRegistry",
 			r.client.Client.FromContainer().ContainerRegistry().Delete(ctx, ref))
-	}, "ContainerRegistry", registryID, effectiveTimeout(data.Timeout, r.client.ResourceTimeout), deletionChecker)
+	}, "ContainerRegistry", registryID, timeout, deletionChecker)
 	if err != nil {
 		resp.Diagnostics.AddError("Error deleting ContainerRegistry", err.Error())
 		return
 	}
-	if waitErr := WaitForResourceDeleted(ctx, deletionChecker, "ContainerRegistry", registryID, remainingTimeout(deleteStart, effectiveTimeout(data.Timeout, r.client.ResourceTimeout))); waitErr != nil {
+	if waitErr := WaitForResourceDeleted(ctx, deletionChecker, "ContainerRegistry", registryID, remainingTimeout(deleteStart, timeout)); waitErr != nil {
 		resp.Diagnostics.AddError("Error waiting for ContainerRegistry deletion", waitErr.Error())
 		return
 	}
@@ -0,0 +1,180 @@
+package provider
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+)
+
+// credentialProcessTimeout bounds a single run of the credential_process command.
+var credentialProcessTimeout = time.Minute
+
+// maxCredentialProcessStderr is how much of the command's stderr is quoted in
+// diagnostics.
+const maxCredentialProcessStderr = 1024
+
+// processCredentials is the JSON document printed by the credential_process
+// command. Expiry is optional; without it the credentials are used for the
+// life of the provider process.
+type processCredentials struct {
+	ClientID     string     `json:"client_id"`
+	ClientSecret string     `json:"client_secret"`
+	Expiry       *time.Time `json:"expiry,omitempty"`
+}
+
+// expired reports whether the credentials are no longer valid at now.
+func (c processCredentials) expired(now time.Time) bool {
+	return c.Expiry != nil && !now.Before(*c.Expiry)
+}
+
+// credentialProcessCache holds the output of each credential_process command
+// for the life of the provider process, so that provider aliases and repeated
+// Configure calls run the command once. Entries are dropped once expired.
+var credentialProcessCache = struct {
+	sync.Mutex
+	entries map[string]processCredentials
+}{entries: map[string]processCredentials{}}
+
+// runCredentialProcess returns the credentials printed by command, running it
+// only when no unexpired result is cached.
+func runCredentialProcess(ctx context.Context, command string) (processCredentials, error) {
+	credentialProcessCache.Lock()
+	defer credentialProcessCache.Unlock()
+
+	if creds, ok := credentialProcessCache.entries[command]; ok && !creds.expired(timeNow()) {
+		return creds, nil
+	}
+	creds, err := execCredentialProcess(ctx, command)
+	if err != nil {
+		return processCredentials{}, err
+	}
+	credentialProcessCache.entries[command] = creds
+	return creds, nil
+}
+
+// execCredentialProcess runs command and parses its output. The command is
+// not run through a shell. The output itself is never quoted in errors, as
+// it may hold a partial secret.
+func execCredentialProcess(ctx context.Context, command string) (processCredentials, error) {
+	argv, err := splitCommandLine(command)
+	if err != nil {
+		return processCredentials{}, err
+	}
+	if len(argv) == 0 {
+		return processCredentials{}, errors.New("the command is empty")
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, credentialProcessTimeout)
+	defer cancel()
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, argv[0], argv[1:]...)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			err = fmt.Errorf("timed out after %s", credentialProcessTimeout)
+		}
+		msg := strings.TrimSpace(stderr.String())
+		if len(msg) > maxCredentialProcessStderr {
+			msg = msg[:maxCredentialProcessStderr] + "..."
+		}
+		if msg == "" {
+			return processCredentials{}, fmt.Errorf("%q failed: %w", argv[0], err)
+		}
+		return processCredentials{}, fmt.Errorf("%q failed: %w\nstderr: %s", argv[0], err, msg)
+	}
+
+	var creds processCredentials
+	dec := json.NewDecoder(&stdout)
+	if err := dec.Decode(&creds); err != nil {
+		return processCredentials{}, fmt.Errorf("%q printed malformed output (%s); expected a JSON object such as "+
+			`{"client_id": "...", "client_secret": "...", "expiry": "2026-01-02T15:04:05Z"}`, argv[0], describeJSONError(err))
+	}
+	if creds.ClientSecret == "" {
+		return processCredentials{}, fmt.Errorf("%q printed no client_secret", argv[0])
+	}
+	if creds.expired(timeNow()) {
+		return processCredentials{}, fmt.Errorf("%q printed credentials that expired at %s", argv[0], creds.Expiry.Format(time.RFC3339))
+	}
+	return creds, nil
+}
+
+// describeJSONError names the kind of decoding failure without echoing the
+// offending value.
+func describeJSONError(err error) string {
+	var syntaxErr *json.SyntaxError
+	var typeErr *json.UnmarshalTypeError
+	var timeErr *time.ParseError
+	switch {
+	case errors.Is(err, io.EOF):
+		return "no output"
+	case errors.As(err, &syntaxErr):
+		return fmt.Sprintf("invalid JSON at byte %d", syntaxErr.Offset)
+	case errors.As(err, &typeErr):
+		return fmt.Sprintf("field %q must be a %s", typeErr.Field, typeErr.Type)
+	case errors.As(err, &timeErr):
+		return "expiry must be an RFC 3339 timestamp"
+	default:
+		return "cannot decode JSON"
+	}
+}
+
+// splitCommandLine splits a command line into arguments on whitespace.
+// Single quotes preserve their content literally; inside double quotes a
+// backslash escapes a double quote. Backslashes are otherwise literal, so
+// Windows paths need no escaping.
+func splitCommandLine(s string) ([]string, error) {
+	var (
+		args    []string
+		current strings.Builder
+		inArg   bool
+		quote   rune
+	)
+	runes := []rune(s)
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+		switch {
+		case quote == '\'':
+			if r == '\'' {
+				quote = 0
+			} else {
+				current.WriteRune(r)
+			}
+		case quote == '"':
+			switch {
+			case r == '"':
+				quote = 0
+			case r == '\\' && i+1 < len(runes) && runes[i+1] == '"':
+				current.WriteRune('"')
+				i++
+			default:
+				current.WriteRune(r)
+			}
+		case r == '\'' || r == '"':
+			quote, inArg = r, true
+		case r == ' ' || r == '\t' || r == '\n' || r == '\r':
+			if inArg {
+				args = append(args, current.String())
+				current.Reset()
+				inArg = false
+			}
+		default:
+			current.WriteRune(r)
+			inArg = true
+		}
+	}
+	if quote != 0 {
+		return nil, fmt.Errorf("unterminated %c quote in command", quote)
+	}
+	if inArg {
+		args = append(args, current.String())
+	}
+	return args, nil
+}
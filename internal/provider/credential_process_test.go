@@ -0,0 +1,238 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	providerframe "github.com/hashicorp/terraform-plugin-framework/provider"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+// TestCredentialProcessHelper is not a real test: it is the command run by
+// the credential_process tests, re-executing the test binary. The output is
+// chosen by the argument after "--", and every run is appended to the file
+// named by ARUBACLOUD_TEST_PROCESS_RUNS.
+func TestCredentialProcessHelper(t *testing.T) {
+	if os.Getenv("ARUBACLOUD_TEST_CREDENTIAL_PROCESS") != "1" {
+		t.Skip("helper process for the credential_process tests")
+	}
+	if runs := os.Getenv("ARUBACLOUD_TEST_PROCESS_RUNS"); runs != "" {
+		f, err := os.OpenFile(runs, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+		if err == nil {
+			fmt.Fprintln(f, "run")
+			f.Close()
+		}
+	}
+	mode := os.Args[len(os.Args)-1]
+	switch mode {
+	case "ok":
+		fmt.Println(`{"client_id": "process-id", "client_secret": "process-secret"}`)
+	case "expiring":
+		fmt.Printf(`{"client_id": "process-id", "client_secret": "process-secret", "expiry": %q}`+"\n",
+			time.Now().Add(time.Hour).Format(time.RFC3339))
+	case "expired":
+		fmt.Println(`{"client_id": "process-id", "client_secret": "process-secret", "expiry": "2020-01-01T00:00:00Z"}`)
+	case "malformed":
+		fmt.Println(`client_secret=process-secret`)
+	case "bad-expiry":
+		fmt.Println(`{"client_secret": "process-secret", "expiry": "tomorrow"}`)
+	case "no-secret":
+		fmt.Println(`{"client_id": "process-id"}`)
+	case "fail":
+		fmt.Fprintln(os.Stderr, "vault: permission denied")
+		os.Exit(3)
+	}
+	os.Exit(0)
+}
+
+// credentialProcessCommand returns a credential_process command line that
+// runs TestCredentialProcessHelper in the given mode, and the file counting
+// its runs. It also empties the credential_process cache.
+func credentialProcessCommand(t *testing.T, mode string) (string, string) {
+	t.Helper()
+	runs := filepath.Join(t.TempDir(), "runs")
+	t.Setenv("ARUBACLOUD_TEST_CREDENTIAL_PROCESS", "1")
+	t.Setenv("ARUBACLOUD_TEST_PROCESS_RUNS", runs)
+
+	credentialProcessCache.Lock()
+	credentialProcessCache.entries = map[string]processCredentials{}
+	credentialProcessCache.Unlock()
+
+	return fmt.Sprintf("%q -test.run=^TestCredentialProcessHelper$ -- %s", os.Args[0], mode), runs
+}
+
+// processRuns returns how many times the helper process ran.
+func processRuns(t *testing.T, runs string) int {
+	t.Helper()
+	data, err := os.ReadFile(runs)
+	if os.IsNotExist(err) {
+		return 0
+	}
+	if err != nil {
+		t.Fatalf("reading run count: %v", err)
+	}
+	return strings.Count(string(data), "run")
+}
+
+func TestSplitCommandLine(t *testing.T) {
+	cases := map[string][]string{
+		"vault-creds prod":                      {"vault-creds", "prod"},
+		"  a   b\tc ":                           {"a", "b", "c"},
+		`"/opt/my tools/creds" --profile 'a b'`: {"/opt/my tools/creds", "--profile", "a b"},
+		`cmd "say \"hi\"" 'it''s'`:              {"cmd", `say "hi"`, "its"},
+		`C:\tools\creds.exe --out json`:         {`C:\tools\creds.exe`, "--out", "json"},
+		`cmd ""`:                                {"cmd", ""},
+		"":                                      nil,
+	}
+	for in, want := range cases {
+		got, err := splitCommandLine(in)
+		if err != nil {
+			t.Errorf("splitCommandLine(%q) error: %v", in, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("splitCommandLine(%q) = %q, want %q", in, got, want)
+		}
+	}
+
+	if _, err := splitCommandLine(`cmd "unterminated`); err == nil {
+		t.Error("expected an error for an unterminated quote")
+	}
+}
+
+func TestRunCredentialProcess(t *testing.T) {
+	ctx := context.Background()
+	command, runs := credentialProcessCommand(t, "ok")
+
+	creds, err := runCredentialProcess(ctx, command)
+	if err != nil {
+		t.Fatalf("runCredentialProcess() error: %v", err)
+	}
+	if creds.ClientID != "process-id" || creds.ClientSecret != "process-secret" || creds.Expiry != nil {
+		t.Errorf("runCredentialProcess() = %+v", creds)
+	}
+
+	// The result is cached for the life of the process.
+	if _, err := runCredentialProcess(ctx, command); err != nil {
+		t.Fatalf("second runCredentialProcess() error: %v", err)
+	}
+	if n := processRuns(t, runs); n != 1 {
+		t.Errorf("command ran %d times, want 1", n)
+	}
+}
+
+func TestRunCredentialProcess_ExpiredCacheEntry(t *testing.T) {
+	ctx := context.Background()
+	command, runs := credentialProcessCommand(t, "expiring")
+
+	creds, err := runCredentialProcess(ctx, command)
+	if err != nil {
+		t.Fatalf("runCredentialProcess() error: %v", err)
+	}
+	if creds.Expiry == nil {
+		t.Fatal("expiry was not parsed")
+	}
+
+	orig := timeNow
+	timeNow = func() time.Time { return creds.Expiry.Add(time.Minute) }
+	t.Cleanup(func() { timeNow = orig })
+
+	// Past the cached expiry the command runs again; the fresh output is
+	// itself expired at the fake time, so the call fails.
+	if _, err := runCredentialProcess(ctx, command); err == nil {
+		t.Error("expected an error once the credentials expired")
+	}
+	if n := processRuns(t, runs); n != 2 {
+		t.Errorf("command ran %d times, want 2", n)
+	}
+}
+
+func TestRunCredentialProcess_Errors(t *testing.T) {
+	cases := map[string][]string{
+		"fail":       {"exit status 3", "vault: permission denied"},
+		"malformed":  {"malformed output", "invalid JSON", `"client_secret"`},
+		"bad-expiry": {"malformed output", "RFC 3339"},
+		"no-secret":  {"no client_secret"},
+		"expired":    {"expired at 2020-01-01T00:00:00Z"},
+	}
+	for mode, wants := range cases {
+		t.Run(mode, func(t *testing.T) {
+			command, _ := credentialProcessCommand(t, mode)
+			_, err := runCredentialProcess(context.Background(), command)
+			if err == nil {
+				t.Fatal("expected an error")
+			}
+			for _, want := range wants {
+				if !strings.Contains(err.Error(), want) {
+					t.Errorf("error %q does not mention %q", err, want)
+				}
+			}
+			if strings.Contains(err.Error(), "process-secret") {
+				t.Errorf("error %q leaks the command output", err)
+			}
+		})
+	}
+
+	if _, err := runCredentialProcess(context.Background(), "/nonexistent/arubacloud-creds"); err == nil {
+		t.Error("expected an error for a missing command")
+	}
+}
+
+// TestProviderConfigure_CredentialProcess verifies that Configure() takes the
+// credentials no other source set from credential_process.
+func TestProviderConfigure_CredentialProcess(t *testing.T) {
+	ctx := context.Background()
+	p := newTestProvider(t)
+	command, _ := credentialProcessCommand(t, "ok")
+
+	config := buildProviderConfig(t, p, map[string]tftypes.Value{
+		"client_id":          tftypes.NewValue(tftypes.String, "hcl-id"),
+		"credential_process": tftypes.NewValue(tftypes.String, command),
+	})
+	resp := &providerframe.ConfigureResponse{}
+	p.Configure(ctx, providerframe.ConfigureRequest{Config: config}, resp)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("unexpected error from Configure(): %v", resp.Diagnostics)
+	}
+	client, ok := resp.ResourceData.(*ArubaCloudClient)
+	if !ok {
+		t.Fatalf("ResourceData is %T, want *ArubaCloudClient", resp.ResourceData)
+	}
+	if client.ClientID != "hcl-id" {
+		t.Errorf("ClientID = %q, want the provider attribute to win", client.ClientID)
+	}
+	if client.ClientSecret != "process-secret" {
+		t.Errorf("ClientSecret = %q, want the credential_process value", client.ClientSecret)
+	}
+}
+
+// TestProviderConfigure_CredentialProcessFails verifies that a failing
+// command is reported on the credential_process attribute.
+func TestProviderConfigure_CredentialProcessFails(t *testing.T) {
+	ctx := context.Background()
+	p := newTestProvider(t)
+	command, _ := credentialProcessCommand(t, "fail")
+
+	config := buildProviderConfig(t, p, map[string]tftypes.Value{
+		"credential_process": tftypes.NewValue(tftypes.String, command),
+	})
+	resp := &providerframe.ConfigureResponse{}
+	p.Configure(ctx, providerframe.ConfigureRequest{Config: config}, resp)
+
+	errs := resp.Diagnostics.Errors()
+	if len(errs) != 1 {
+		t.Fatalf("expected one error, got %v", resp.Diagnostics)
+	}
+	if !strings.Contains(errs[0].Detail(), "vault: permission denied") {
+		t.Errorf("detail %q does not quote the command's stderr", errs[0].Detail())
+	}
+	if resp.ResourceData != nil {
+		t.Error("ResourceData should not be set when credential_process fails")
+	}
+}
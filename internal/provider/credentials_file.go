@@ -0,0 +1,207 @@
+package provider
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"gopkg.in/yaml.v3"
+)
+
+// defaultCredentialsFile is the shared credentials file read when neither
+// shared_credentials_file nor ARUBACLOUD_SHARED_CREDENTIALS_FILE is set.
+const defaultCredentialsFile = "~/.arubacloud/credentials"
+
+// defaultProfileName is the profile used when neither profile nor
+// ARUBACLOUD_PROFILE is set.
+const defaultProfileName = "default"
+
+// profileSettings lists the provider attributes a credentials profile may set.
+var profileSettings = map[string]bool{
+	"client_id":          true,
+	"client_secret":      true,
+	"base_url":           true,
+	"token_issuer_url":   true,
+	"default_project_id": true,
+	"default_location":   true,
+	"default_zone":       true,
+	"credential_process": true,
+}
+
+// credentialsProfile is one named profile of the shared credentials file.
+type credentialsProfile struct {
+	name   string
+	file   string
+	values map[string]string
+}
+
+// source describes the profile in diagnostics.
+func (p *credentialsProfile) source() string {
+	return fmt.Sprintf("profile %q in %s", p.name, p.file)
+}
+
+// expandHome replaces a leading "~" with the user's home directory.
+func expandHome(p string) (string, error) {
+	if p != "~" && !strings.HasPrefix(p, "~/") && !strings.HasPrefix(p, `~\`) {
+		return p, nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("cannot resolve %q: %w", p, err)
+	}
+	return filepath.Join(home, p[1:]), nil
+}
+
+// loadCredentialsProfile reads profile name from the shared credentials file.
+// When the profile was not selected explicitly (explicit is false), a missing
+// file or a missing profile is not an error and nil is returned; an explicitly
+// selected profile must exist.
+func loadCredentialsProfile(file, name string, explicit bool) (*credentialsProfile, error) {
+	path, err := expandHome(file)
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) && !explicit {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("cannot read shared credentials file: %w", err)
+	}
+
+	profiles, err := parseCredentialsFile(data)
+	if err != nil {
+		return nil, fmt.Errorf("cannot parse shared credentials file %s: %w", path, err)
+	}
+	values, ok := profiles[name]
+	if !ok {
+		if !explicit {
+			return nil, nil
+		}
+		names := make([]string, 0, len(profiles))
+		for n := range profiles {
+			names = append(names, n)
+		}
+		sort.Strings(names)
+		return nil, fmt.Errorf("profile %q not found in %s (available profiles: %s)", name, path, strings.Join(names, ", "))
+	}
+	for key := range values {
+		if !profileSettings[key] {
+			supported := make([]string, 0, len(profileSettings))
+			for k := range profileSettings {
+				supported = append(supported, k)
+			}
+			sort.Strings(supported)
+			return nil, fmt.Errorf("profile %q in %s sets unknown key %q; supported keys are %s", name, path, key, strings.Join(supported, ", "))
+		}
+	}
+	return &credentialsProfile{name: name, file: path, values: values}, nil
+}
+
+// parseCredentialsFile parses a shared credentials file into profiles. The
+// file is INI when its first significant line is a [section] header, and
+// YAML (a mapping of profile names to settings) otherwise.
+func parseCredentialsFile(data []byte) (map[string]map[string]string, error) {
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, ";") {
+			continue
+		}
+		if strings.HasPrefix(line, "[") {
+			return parseCredentialsINI(data)
+		}
+		break
+	}
+
+	profiles := map[string]map[string]string{}
+	if err := yaml.Unmarshal(data, &profiles); err != nil {
+		return nil, err
+	}
+	return profiles, nil
+}
+
+// parseCredentialsINI parses the INI form of the shared credentials file:
+// one [profile] section per profile with key = value lines. Lines starting
+// with # or ; are comments.
+func parseCredentialsINI(data []byte) (map[string]map[string]string, error) {
+	profiles := map[string]map[string]string{}
+	var current map[string]string
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSpace(scanner.Text())
+		switch {
+		case line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, ";"):
+			continue
+		case strings.HasPrefix(line, "["):
+			if !strings.HasSuffix(line, "]") {
+				return nil, fmt.Errorf("line %d: unterminated section header %q", n, line)
+			}
+			name := strings.TrimSpace(line[1 : len(line)-1])
+			if name == "" {
+				return nil, fmt.Errorf("line %d: empty profile name", n)
+			}
+			current = map[string]string{}
+			profiles[name] = current
+		default:
+			key, value, ok := strings.Cut(line, "=")
+			if !ok {
+				return nil, fmt.Errorf("line %d: expected key = value", n)
+			}
+			if current == nil {
+				return nil, fmt.Errorf("line %d: setting outside of a [profile] section", n)
+			}
+			value = strings.TrimSpace(value)
+			if len(value) >= 2 && (value[0] == '"' && value[len(value)-1] == '"' || value[0] == '\'' && value[len(value)-1] == '\'') {
+				value = value[1 : len(value)-1]
+			}
+			current[strings.TrimSpace(key)] = value
+		}
+	}
+	return profiles, scanner.Err()
+}
+
+// settingResolver resolves a provider setting from, in order of precedence,
+// the provider configuration, its environment variable and the selected
+// credentials profile, and records which source supplied it.
+type settingResolver struct {
+	profile *credentialsProfile
+}
+
+// resolve returns the value of attr and a description of its source, or two
+// empty strings when no source sets it. envVar may be empty for settings
+// without an environment variable.
+func (r settingResolver) resolve(attr string, configured types.String, envVar string) (value, source string) {
+	if !configured.IsNull() && !configured.IsUnknown() && configured.ValueString() != "" {
+		return configured.ValueString(), fmt.Sprintf("the provider attribute %q", attr)
+	}
+	if envVar != "" {
+		if v := os.Getenv(envVar); v != "" {
+			return v, fmt.Sprintf("the %s environment variable", envVar)
+		}
+	}
+	if r.profile != nil {
+		if v := r.profile.values[attr]; v != "" {
+			return v, r.profile.source()
+		}
+	}
+	return "", ""
+}
+
+// credentialSourcesDetail explains, for a missing credential, which sources
+// were checked and where the other credential came from, so that a
+// credential split across sources is easy to spot.
+func credentialSourcesDetail(name, attr, envVar, profileSource, otherName, otherSource string) string {
+	detail := fmt.Sprintf("No %s was found in the provider attribute %q, the %s environment variable or %s.", name, attr, envVar, profileSource)
+	if otherSource != "" {
+		detail += fmt.Sprintf(" The %s was supplied by %s.", otherName, otherSource)
+	}
+	return detail
+}
@@ -0,0 +1,235 @@
+package provider
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	providerframe "github.com/hashicorp/terraform-plugin-framework/provider"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+const testCredentialsINI = `# ArubaCloud tenants
+[default]
+client_id     = default-id
+client_secret = default-secret
+
+[prod]
+client_id          = prod-id
+client_secret      = "prod-secret"
+token_issuer_url   = https://login.example.com/token
+default_project_id = prod-project
+default_location   = ITBG-Bergamo
+`
+
+const testCredentialsYAML = `# ArubaCloud tenants
+default:
+  client_id: default-id
+  client_secret: default-secret
+prod:
+  client_id: prod-id
+  client_secret: prod-secret
+  token_issuer_url: https://login.example.com/token
+  default_project_id: prod-project
+  default_location: ITBG-Bergamo
+`
+
+// writeCredentialsFile writes content to a temporary credentials file and
+// points ARUBACLOUD_SHARED_CREDENTIALS_FILE at it.
+func writeCredentialsFile(t *testing.T, content string) string {
+	t.Helper()
+	file := filepath.Join(t.TempDir(), "credentials")
+	if err := os.WriteFile(file, []byte(content), 0o600); err != nil {
+		t.Fatalf("writing credentials file: %v", err)
+	}
+	t.Setenv("ARUBACLOUD_SHARED_CREDENTIALS_FILE", file)
+	return file
+}
+
+func TestParseCredentialsFile(t *testing.T) {
+	want := map[string]map[string]string{
+		"default": {"client_id": "default-id", "client_secret": "default-secret"},
+		"prod": {
+			"client_id":          "prod-id",
+			"client_secret":      "prod-secret",
+			"token_issuer_url":   "https://login.example.com/token",
+			"default_project_id": "prod-project",
+			"default_location":   "ITBG-Bergamo",
+		},
+	}
+	for name, content := range map[string]string{"ini": testCredentialsINI, "yaml": testCredentialsYAML} {
+		t.Run(name, func(t *testing.T) {
+			got, err := parseCredentialsFile([]byte(content))
+			if err != nil {
+				t.Fatalf("parseCredentialsFile() error: %v", err)
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("parseCredentialsFile() = %v, want %v", got, want)
+			}
+		})
+	}
+}
+
+func TestParseCredentialsFile_Invalid(t *testing.T) {
+	cases := map[string]string{
+		"unterminated section": "[default\nclient_id = x\n",
+		"missing equals":       "[default]\nclient_id\n",
+		"empty section":        "[]\nclient_id = x\n",
+		"yaml not a mapping":   "- client_id\n",
+	}
+	for name, content := range cases {
+		t.Run(name, func(t *testing.T) {
+			if _, err := parseCredentialsFile([]byte(content)); err == nil {
+				t.Error("expected a parse error")
+			}
+		})
+	}
+}
+
+func TestLoadCredentialsProfile(t *testing.T) {
+	file := writeCredentialsFile(t, testCredentialsINI)
+	missing := filepath.Join(t.TempDir(), "credentials")
+
+	profile, err := loadCredentialsProfile(file, "prod", true)
+	if err != nil || profile == nil {
+		t.Fatalf("loadCredentialsProfile(prod) = %v, %v", profile, err)
+	}
+	if profile.values["default_project_id"] != "prod-project" {
+		t.Errorf("default_project_id = %q, want prod-project", profile.values["default_project_id"])
+	}
+
+	// The implicit default profile is optional.
+	if profile, err := loadCredentialsProfile(missing, defaultProfileName, false); profile != nil || err != nil {
+		t.Errorf("implicit profile with missing file = %v, %v, want nil, nil", profile, err)
+	}
+
+	// An explicitly selected profile must exist.
+	if _, err := loadCredentialsProfile(missing, "prod", true); err == nil {
+		t.Error("expected an error for an explicit profile in a missing file")
+	}
+	_, err = loadCredentialsProfile(file, "staging", true)
+	if err == nil || !strings.Contains(err.Error(), "default, prod") {
+		t.Errorf("unknown profile error = %v, want it to list the available profiles", err)
+	}
+
+	unknownKey := writeCredentialsFile(t, "[default]\nclient_id = x\nclinet_secret = y\n")
+	if _, err := loadCredentialsProfile(unknownKey, defaultProfileName, false); err == nil || !strings.Contains(err.Error(), "clinet_secret") {
+		t.Errorf("unknown key error = %v, want it to name the key", err)
+	}
+}
+
+func TestSettingResolver_Precedence(t *testing.T) {
+	r := settingResolver{profile: &credentialsProfile{
+		name:   "prod",
+		file:   "/creds",
+		values: map[string]string{"client_id": "from-profile"},
+	}}
+
+	t.Setenv("ARUBACLOUD_CLIENT_ID", "")
+	if v, src := r.resolve("client_id", types.StringNull(), "ARUBACLOUD_CLIENT_ID"); v != "from-profile" || src != `profile "prod" in /creds` {
+		t.Errorf("profile only: got %q from %q", v, src)
+	}
+
+	t.Setenv("ARUBACLOUD_CLIENT_ID", "from-env")
+	if v, src := r.resolve("client_id", types.StringNull(), "ARUBACLOUD_CLIENT_ID"); v != "from-env" || src != "the ARUBACLOUD_CLIENT_ID environment variable" {
+		t.Errorf("env over profile: got %q from %q", v, src)
+	}
+
+	if v, src := r.resolve("client_id", types.StringValue("from-hcl"), "ARUBACLOUD_CLIENT_ID"); v != "from-hcl" || src != `the provider attribute "client_id"` {
+		t.Errorf("HCL over env: got %q from %q", v, src)
+	}
+
+	if v, src := r.resolve("base_url", types.StringNull(), ""); v != "" || src != "" {
+		t.Errorf("unset setting: got %q from %q, want empty", v, src)
+	}
+}
+
+// TestProviderConfigure_Profile verifies that Configure() takes credentials
+// and defaults from the selected profile, and that environment variables and
+// provider attributes override it.
+func TestProviderConfigure_Profile(t *testing.T) {
+	ctx := context.Background()
+	p := newTestProvider(t)
+	writeCredentialsFile(t, testCredentialsYAML)
+	t.Setenv("ARUBACLOUD_PROFILE", "prod")
+	t.Setenv("ARUBACLOUD_CLIENT_SECRET", "env-secret")
+
+	config := buildProviderConfig(t, p, map[string]tftypes.Value{
+		"default_location": tftypes.NewValue(tftypes.String, "ITMI-Milano"),
+	})
+	resp := &providerframe.ConfigureResponse{}
+	p.Configure(ctx, providerframe.ConfigureRequest{Config: config}, resp)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("unexpected error from Configure(): %v", resp.Diagnostics)
+	}
+	client, ok := resp.ResourceData.(*ArubaCloudClient)
+	if !ok {
+		t.Fatalf("ResourceData is %T, want *ArubaCloudClient", resp.ResourceData)
+	}
+	if client.ClientID != "prod-id" {
+		t.Errorf("ClientID = %q, want the profile value", client.ClientID)
+	}
+	if client.ClientSecret != "env-secret" {
+		t.Errorf("ClientSecret = %q, want the environment value", client.ClientSecret)
+	}
+	if client.DefaultProjectID != "prod-project" {
+		t.Errorf("DefaultProjectID = %q, want the profile value", client.DefaultProjectID)
+	}
+	if client.DefaultLocation != "ITMI-Milano" {
+		t.Errorf("DefaultLocation = %q, want the provider attribute", client.DefaultLocation)
+	}
+}
+
+// TestProviderConfigure_UnknownProfile verifies that an explicitly selected
+// profile that does not exist is reported on the profile attribute.
+func TestProviderConfigure_UnknownProfile(t *testing.T) {
+	ctx := context.Background()
+	p := newTestProvider(t)
+	writeCredentialsFile(t, testCredentialsINI)
+
+	config := buildProviderConfig(t, p, map[string]tftypes.Value{
+		"profile": tftypes.NewValue(tftypes.String, "staging"),
+	})
+	resp := &providerframe.ConfigureResponse{}
+	p.Configure(ctx, providerframe.ConfigureRequest{Config: config}, resp)
+
+	if !resp.Diagnostics.HasError() {
+		t.Fatal("expected an error for an unknown profile")
+	}
+	for _, d := range resp.Diagnostics.Errors() {
+		if d, ok := d.(interface{ Path() path.Path }); !ok || !d.Path().Equal(path.Root("profile")) {
+			t.Errorf("error is not on the profile attribute: %v", resp.Diagnostics)
+		}
+	}
+}
+
+// TestProviderConfigure_MissingCredentialNamesSources verifies that a missing
+// credential error names the sources checked and the source of the other
+// credential.
+func TestProviderConfigure_MissingCredentialNamesSources(t *testing.T) {
+	ctx := context.Background()
+	p := newTestProvider(t)
+	writeCredentialsFile(t, "[prod]\nclient_id = prod-id\n")
+
+	config := buildProviderConfig(t, p, map[string]tftypes.Value{
+		"profile": tftypes.NewValue(tftypes.String, "prod"),
+	})
+	resp := &providerframe.ConfigureResponse{}
+	p.Configure(ctx, providerframe.ConfigureRequest{Config: config}, resp)
+
+	errs := resp.Diagnostics.Errors()
+	if len(errs) != 1 {
+		t.Fatalf("expected one error for the missing client_secret, got %v", resp.Diagnostics)
+	}
+	detail := errs[0].Detail()
+	for _, want := range []string{"ARUBACLOUD_CLIENT_SECRET environment variable", `profile "prod" in`, "client ID was supplied by profile"} {
+		if !strings.Contains(detail, want) {
+			t.Errorf("detail %q does not mention %q", detail, want)
+		}
+	}
+}
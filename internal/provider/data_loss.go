@@ -0,0 +1,47 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// planDataLoss runs the plan-time protections of a resource that holds data.
+// It enforces deletion_protection with planDeletionProtection, then warns
+// when the plan replaces the resource, naming the attributes that force the
+// replacement and the data that is lost. With fail_on_destructive_replace
+// the warning is an error. dataLost completes "Destroying it deletes ...".
+// Like planDeletionProtection it is called from ModifyPlan after every other
+// plan modification.
+func (c *ArubaCloudClient) planDataLoss(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse, resourceType, dataLost string, replaceAttrs ...path.Path) {
+	planDeletionProtection(ctx, req, resp, resourceType, replaceAttrs...)
+	if resp.Diagnostics.HasError() || req.State.Raw.IsNull() || req.Plan.Raw.IsNull() {
+		return
+	}
+
+	attrs := replacedAttributes(ctx, req, resp, replaceAttrs...)
+	if len(attrs) == 0 {
+		return
+	}
+	var id types.String
+	resp.Diagnostics.Append(req.State.GetAttribute(ctx, path.Root("id"), &id)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	detail := fmt.Sprintf("Changing %s forces Terraform to destroy %s %q and create a new one. Destroying it deletes %s. "+
+		"This cannot be undone.", strings.Join(attrs, ", "), resourceType, id.ValueString(), dataLost)
+	if c != nil && c.FailOnDestructiveReplace {
+		resp.Diagnostics.AddError("Replacement deletes data",
+			detail+" The provider is configured with fail_on_destructive_replace = true, so the plan fails. "+
+				"Revert the change, or remove fail_on_destructive_replace to allow the replacement.")
+		return
+	}
+	resp.Diagnostics.AddWarning("Replacement deletes data",
+		detail+" Back up the data before applying, or set fail_on_destructive_replace = true in the provider "+
+			"configuration to make such plans fail.")
+}
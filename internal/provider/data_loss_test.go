@@ -0,0 +1,83 @@
+package provider
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+func TestPlanDataLoss(t *testing.T) {
+	ctx := context.Background()
+
+	s := schema.Schema{
+		Attributes: map[string]schema.Attribute{
+			"id":                  schema.StringAttribute{Computed: true},
+			"name":                schema.StringAttribute{Required: true},
+			"type":                schema.StringAttribute{Required: true},
+			"deletion_protection": deletionProtectionAttribute(),
+		},
+	}
+	objType := s.Type().TerraformType(ctx).(tftypes.Object)
+	obj := func(name, volumeType string, protected bool) tftypes.Value {
+		return tftypes.NewValue(objType, map[string]tftypes.Value{
+			"id":                  tftypes.NewValue(tftypes.String, "vol-1"),
+			"name":                tftypes.NewValue(tftypes.String, name),
+			"type":                tftypes.NewValue(tftypes.String, volumeType),
+			"deletion_protection": tftypes.NewValue(tftypes.Bool, protected),
+		})
+	}
+	null := tftypes.NewValue(objType, nil)
+
+	cases := []struct {
+		name        string
+		client      *ArubaCloudClient
+		state, plan tftypes.Value
+		want        diag.Severity
+		wantSummary string
+	}{
+		{"create", &ArubaCloudClient{}, null, obj("data", "Standard", false), 0, ""},
+		{"in-place update", &ArubaCloudClient{}, obj("data", "Standard", false), obj("renamed", "Standard", false), 0, ""},
+		{"destroy", &ArubaCloudClient{}, obj("data", "Standard", false), null, 0, ""},
+		{"replacement warns", &ArubaCloudClient{}, obj("data", "Standard", false), obj("data", "Performance", false), diag.SeverityWarning, "Replacement deletes data"},
+		{"replacement without client warns", nil, obj("data", "Standard", false), obj("data", "Performance", false), diag.SeverityWarning, "Replacement deletes data"},
+		{"replacement fails when configured", &ArubaCloudClient{FailOnDestructiveReplace: true}, obj("data", "Standard", false), obj("data", "Performance", false), diag.SeverityError, "Replacement deletes data"},
+		{"replacement of a protected resource", &ArubaCloudClient{}, obj("data", "Standard", true), obj("data", "Performance", true), diag.SeverityError, "Deletion protection enabled"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := resource.ModifyPlanRequest{
+				State: tfsdk.State{Raw: tc.state, Schema: s},
+				Plan:  tfsdk.Plan{Raw: tc.plan, Schema: s},
+			}
+			resp := &resource.ModifyPlanResponse{Plan: tfsdk.Plan{Raw: tc.plan, Schema: s}}
+			tc.client.planDataLoss(ctx, req, resp, "BlockStorage", "the volume with all data written to it", path.Root("type"))
+
+			if tc.wantSummary == "" {
+				if len(resp.Diagnostics) != 0 {
+					t.Fatalf("unexpected diagnostics: %v", resp.Diagnostics)
+				}
+				return
+			}
+			if len(resp.Diagnostics) != 1 {
+				t.Fatalf("expected one diagnostic, got %v", resp.Diagnostics)
+			}
+			d := resp.Diagnostics[0]
+			if d.Severity() != tc.want || d.Summary() != tc.wantSummary {
+				t.Errorf("got %s %q, want %s %q", d.Severity(), d.Summary(), tc.want, tc.wantSummary)
+			}
+			if !strings.Contains(d.Detail(), "type") {
+				t.Errorf("detail does not name the attribute forcing replacement: %s", d.Detail())
+			}
+			if tc.wantSummary == "Replacement deletes data" && !strings.Contains(d.Detail(), "the volume with all data written to it") {
+				t.Errorf("detail does not name the data that is lost: %s", d.Detail())
+			}
+		})
+	}
+}
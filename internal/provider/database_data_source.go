@@ -4,7 +4,6 @@ import (
 	"context"
 	"fmt"
 
-	aruba "github.com/Arubacloud/sdk-go/pkg/aruba"
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
 	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/types"
@@ -77,11 +76,15 @@ func (d *DatabaseDataSource) Configure(ctx context.Context, req datasource.Confi
 }
 
 func (d *DatabaseDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	ctx = withCorrelationID(ctx)
 	var data DatabaseDataSourceModel
 	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
+	if !d.client.checkGuardrails("read", "Database", data.ProjectID, &resp.Diagnostics) {
+		return
+	}
 
 	projectID := data.ProjectID.ValueString()
 	dbaasID := data.DBaaSID.ValueString()
@@ -92,9 +95,9 @@ func (d *DatabaseDataSource) Read(ctx context.Context, req datasource.ReadReques
 	}
 
 	db, err := d.client.Client.FromDatabase().Databases().Get(ctx,
-		aruba.URI("/projects/"+projectID+"/providers/Aruba.Database/dbaas/"+dbaasID+"/databases/"+databaseName))
+		newResourceURI(uriKindDatabase, projectID, databaseName, dbaasID).Ref())
 	if provErr := CheckResponseErr("read", "Database", err); provErr != nil {
-		resp.Diagnostics.AddError("API Error", provErr.Error())
+		AppendAPIError(&resp.Diagnostics, provErr)
 		return
 	}
 
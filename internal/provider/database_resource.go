@@ -6,6 +6,7 @@ import (
 	"time"
 
 	aruba "github.com/Arubacloud/sdk-go/pkg/aruba"
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
@@ -16,12 +17,14 @@ import (
 )
 
 type DatabaseResourceModel struct {
-	Id        types.String `tfsdk:"id"`
-	Uri       types.String `tfsdk:"uri"`
-	ProjectID types.String `tfsdk:"project_id"`
-	DBaaSID   types.String `tfsdk:"dbaas_id"`
-	Name      types.String `tfsdk:"name"`
-	Timeout   types.String `tfsdk:"timeout"`
+	Id                 types.String   `tfsdk:"id"`
+	Uri                types.String   `tfsdk:"uri"`
+	ProjectID          types.String   `tfsdk:"project_id"`
+	DBaaSID            types.String   `tfsdk:"dbaas_id"`
+	Name               types.String   `tfsdk:"name"`
+	DeletionProtection types.Bool     `tfsdk:"deletion_protection"`
+	Timeout            types.String   `tfsdk:"timeout"`
+	Timeouts           timeouts.Value `tfsdk:"timeouts"`
 }
 
 type DatabaseResource struct {
@@ -29,6 +32,7 @@ type DatabaseResource struct {
 }
 
 var _ resource.Resource = &DatabaseResource{}
+var _ resource.ResourceWithModifyPlan = &DatabaseResource{}
 var _ resource.ResourceWithImportState = &DatabaseResource{}
 
 func NewDatabaseResource() resource.Resource {
@@ -58,8 +62,10 @@ func (r *DatabaseResource) Schema(ctx context.Context, req resource.SchemaReques
 				},
 			},
 			"project_id": schema.StringAttribute{
-				MarkdownDescription: "ID of the project that owns this resource.",
-				Required:            true,
+				MarkdownDescription: defaultedDescription("ID of the project that owns this resource.", "project_id"),
+				Optional:            true,
+				Computed:            true,
+				PlanModifiers:       []planmodifier.String{stringplanmodifier.UseStateForUnknown()},
 			},
 			"dbaas_id": schema.StringAttribute{
 				MarkdownDescription: "ID of the parent DBaaS cluster this database belongs to.",
@@ -72,11 +78,16 @@ func (r *DatabaseResource) Schema(ctx context.Context, req resource.SchemaReques
 					stringplanmodifier.RequiresReplace(),
 				},
 			},
+			"deletion_protection": deletionProtectionAttribute(),
 			"timeout": schema.StringAttribute{
-				MarkdownDescription: "Per-resource timeout override (e.g. `\"15m\"`, `\"1h\"`). Overrides the provider-level `resource_timeout` for this resource's Create and Delete operations. Uses Go duration syntax.",
+				MarkdownDescription: "Per-resource timeout override (e.g. `\"15m\"`, `\"1h\"`) applied to every operation not set in the `timeouts` block. Overrides the provider-level `resource_timeout`. Uses Go duration syntax. Deprecated — use the `timeouts` block instead.",
+				DeprecationMessage:  "Use the timeouts block, which sets the create, read, update and delete timeouts individually.",
 				Optional:            true,
 			},
 		},
+		Blocks: map[string]schema.Block{
+			"timeouts": timeouts.BlockAll(ctx),
+		},
 	}
 }
 
@@ -96,21 +107,36 @@ func (r *DatabaseResource) Configure(ctx context.Context, req resource.Configure
 }
 
 func databaseRef(data *DatabaseResourceModel) aruba.Ref {
-	return aruba.URI("/projects/" + data.ProjectID.ValueString() +
-		"/providers/Aruba.Database/dbaas/" + data.DBaaSID.ValueString() +
-		"/databases/" + data.Id.ValueString())
+	return newResourceURI(uriKindDatabase, data.ProjectID.ValueString(), data.Id.ValueString(), data.DBaaSID.ValueString()).Ref()
+}
+
+func (r *DatabaseResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	r.client.planProviderDefaults(ctx, req, resp,
+		providerDefault{attr: "project_id", replace: false},
+	)
+	r.client.planDataLoss(ctx, req, resp, "Database",
+		"the database with all its tables and data",
+		path.Root("name"),
+	)
 }
 
 func (r *DatabaseResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	ctx = withCorrelationID(ctx)
 	var data DatabaseResourceModel
 	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	timeout := r.client.operationTimeout(ctx, data.Timeouts.Create, data.Timeout, &resp.Diagnostics)
 	if resp.Diagnostics.HasError() {
 		return
 	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	if !r.client.checkGuardrails("create", "Database", data.ProjectID, &resp.Diagnostics) {
+		return
+	}
 
 	projectID := data.ProjectID.ValueString()
 	dbaasID := data.DBaaSID.ValueString()
-	dbaasURI := "/projects/" + projectID + "/providers/Aruba.Database/dbaas/" + dbaasID
+	dbaasURI := newResourceURI(uriKindDBaaS, projectID, dbaasID).String()
 
 	var db *aruba.Database
 	if createErr := CreateWithTransientRetry(ctx, func() error {
@@ -121,7 +147,7 @@ func (r *DatabaseResource) Create(ctx context.Context, req resource.CreateReques
 				InDBaaS(aruba.URI(dbaasURI)),
 		)
 		return CheckResponseErrAsError("create", "Database", err)
-	}, "Database", data.Name.ValueString(), effectiveTimeout(data.Timeout, r.client.ResourceTimeout)); createErr != nil {
+	}, "Database", data.Name.ValueString(), timeout); createErr != nil {
 		resp.Diagnostics.AddError("Error creating database", createErr.Error())
 		return
 	}
@@ -143,7 +169,7 @@ func (r *DatabaseResource) Create(ctx context.Context, req resource.CreateReques
 		}
 		return "Active", nil
 	}
-	if err := WaitForResourceActive(ctx, checker, "Database", data.Id.ValueString(), effectiveTimeout(data.Timeout, r.client.ResourceTimeout)); err != nil {
+	if err := WaitForResourceActive(ctx, checker, "Database", data.Id.ValueString(), timeout); err != nil {
 		ReportWaitResult(&resp.Diagnostics, err, "Database", data.Id.ValueString())
 		return
 	}
@@ -156,11 +182,22 @@ func (r *DatabaseResource) Create(ctx context.Context, req resource.CreateReques
 }
 
 func (r *DatabaseResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	ctx = withCorrelationID(ctx)
 	var data DatabaseResourceModel
 	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	timeout := r.client.operationTimeout(ctx, data.Timeouts.Read, data.Timeout, &resp.Diagnostics)
 	if resp.Diagnostics.HasError() {
 		return
 	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	if !r.client.checkGuardrails("read", "Database", data.ProjectID, &resp.Diagnostics) {
+		return
+	}
+	if data.DeletionProtection.IsNull() {
+		// Imported, or written by a provider version without the attribute.
+		data.DeletionProtection = types.BoolValue(false)
+	}
 	if data.Id.IsNull() || data.Id.ValueString() == "" {
 		resp.State.RemoveResource(ctx)
 		return
@@ -172,7 +209,7 @@ func (r *DatabaseResource) Read(ctx context.Context, req resource.ReadRequest, r
 			resp.State.RemoveResource(ctx)
 			return
 		}
-		resp.Diagnostics.AddError("API Error", provErr.Error())
+		AppendAPIError(&resp.Diagnostics, provErr)
 		return
 	}
 
@@ -184,18 +221,29 @@ func (r *DatabaseResource) Read(ctx context.Context, req resource.ReadRequest, r
 }
 
 func (r *DatabaseResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	ctx = withCorrelationID(ctx)
 	var data DatabaseResourceModel
 	var state DatabaseResourceModel
 
 	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
 	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	timeout := r.client.operationTimeout(ctx, data.Timeouts.Update, data.Timeout, &resp.Diagnostics)
 	if resp.Diagnostics.HasError() {
 		return
 	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	if !r.client.checkGuardrails("update", "Database", data.ProjectID, &resp.Diagnostics) {
+		return
+	}
+	if deletionProtectionOnlyChange(req.Plan, req.State) {
+		resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+		return
+	}
 
 	db, err := r.client.Client.FromDatabase().Databases().Get(ctx, databaseRef(&state))
 	if provErr := CheckResponseErr("read", "Database", err); provErr != nil {
-		resp.Diagnostics.AddError("API Error", provErr.Error())
+		AppendAPIError(&resp.Diagnostics, provErr)
 		return
 	}
 
@@ -203,7 +251,7 @@ func (r *DatabaseResource) Update(ctx context.Context, req resource.UpdateReques
 
 	updated, err := r.client.Client.FromDatabase().Databases().Update(ctx, db)
 	if provErr := CheckResponseErr("update", "Database", err); provErr != nil {
-		resp.Diagnostics.AddError("API Error", provErr.Error())
+		AppendAPIError(&resp.Diagnostics, provErr)
 		return
 	}
 
@@ -217,11 +265,21 @@ func (r *DatabaseResource) Update(ctx context.Context, req resource.UpdateReques
 }
 
 func (r *DatabaseResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	ctx = withCorrelationID(ctx)
 	var data DatabaseResourceModel
 	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	timeout := r.client.operationTimeout(ctx, data.Timeouts.Delete, data.Timeout, &resp.Diagnostics)
 	if resp.Diagnostics.HasError() {
 		return
 	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	if !r.client.checkGuardrails("delete", "Database", data.ProjectID, &resp.Diagnostics) {
+		return
+	}
+	if !checkDeletionProtection(data.DeletionProtection, "Database", data.Id.ValueString(), &resp.Diagnostics) {
+		return
+	}
 
 	ref := databaseRef(&data)
 	databaseName := data.Id.ValueString()
@@ -241,12 +299,12 @@ func (r *DatabaseResource) Delete(ctx context.Context, req resource.DeleteReques
 	err := DeleteResourceWithRetry(ctx, func() error {
 		return CheckResponseErrAsError("delete", "Database",
 			r.client.Client.FromDatabase().Databases().Delete(ctx, ref))
-	}, "Database", databaseName, effectiveTimeout(data.Timeout, r.client.ResourceTimeout), deletionChecker)
+	}, "Database", databaseName, timeout, deletionChecker)
 	if err != nil {
 		resp.Diagnostics.AddError("Error deleting database", err.Error())
 		return
 	}
-	if waitErr := WaitForResourceDeleted(ctx, deletionChecker, "Database", databaseName, remainingTimeout(deleteStart, effectiveTimeout(data.Timeout, r.client.ResourceTimeout))); waitErr != nil {
+	if waitErr := WaitForResourceDeleted(ctx, deletionChecker, "Database", databaseName, remainingTimeout(deleteStart, timeout)); waitErr != nil {
 		resp.Diagnostics.AddError("Error waiting for Database deletion", waitErr.Error())
 		return
 	}
@@ -4,7 +4,6 @@ import (
 	"context"
 	"fmt"
 
-	aruba "github.com/Arubacloud/sdk-go/pkg/aruba"
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
 	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/types"
@@ -103,11 +102,15 @@ func (d *DatabaseBackupDataSource) Configure(ctx context.Context, req datasource
 }
 
 func (d *DatabaseBackupDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	ctx = withCorrelationID(ctx)
 	var data DatabaseBackupDataSourceModel
 	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
+	if !d.client.checkGuardrails("read", "DBaaSBackup", data.ProjectID, &resp.Diagnostics) {
+		return
+	}
 
 	projectID := data.ProjectID.ValueString()
 	backupID := data.Id.ValueString()
@@ -117,9 +120,9 @@ func (d *DatabaseBackupDataSource) Read(ctx context.Context, req datasource.Read
 	}
 
 	backup, err := d.client.Client.FromDatabase().Backups().Get(ctx,
-		aruba.URI("/projects/"+projectID+"/providers/Aruba.Database/backups/"+backupID))
+		newResourceURI(uriKindDatabaseBackup, projectID, backupID).Ref())
 	if provErr := CheckResponseErr("read", "DBaaSBackup", err); provErr != nil {
-		resp.Diagnostics.AddError("API Error", provErr.Error())
+		AppendAPIError(&resp.Diagnostics, provErr)
 		return
 	}
 
@@ -7,6 +7,7 @@ import (
 	"time"
 
 	aruba "github.com/Arubacloud/sdk-go/pkg/aruba"
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
 	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
@@ -19,17 +20,19 @@ import (
 )
 
 type DatabaseBackupResourceModel struct {
-	Id            types.String `tfsdk:"id"`
-	Uri           types.String `tfsdk:"uri"`
-	ProjectID     types.String `tfsdk:"project_id"`
-	Name          types.String `tfsdk:"name"`
-	Location      types.String `tfsdk:"location"`
-	Tags          types.List   `tfsdk:"tags"`
-	Zone          types.String `tfsdk:"zone"`
-	DBaaSID       types.String `tfsdk:"dbaas_id"`
-	Database      types.String `tfsdk:"database"`
-	BillingPeriod types.String `tfsdk:"billing_period"`
-	Timeout       types.String `tfsdk:"timeout"`
+	Id            types.String   `tfsdk:"id"`
+	Uri           types.String   `tfsdk:"uri"`
+	ProjectID     types.String   `tfsdk:"project_id"`
+	Name          types.String   `tfsdk:"name"`
+	Location      types.String   `tfsdk:"location"`
+	Tags          types.List     `tfsdk:"tags"`
+	TagsAll       types.List     `tfsdk:"tags_all"`
+	Zone          types.String   `tfsdk:"zone"`
+	DBaaSID       types.String   `tfsdk:"dbaas_id"`
+	Database      types.String   `tfsdk:"database"`
+	BillingPeriod types.String   `tfsdk:"billing_period"`
+	Timeout       types.String   `tfsdk:"timeout"`
+	Timeouts      timeouts.Value `tfsdk:"timeouts"`
 }
 
 type DatabaseBackupResource struct {
@@ -37,6 +40,7 @@ type DatabaseBackupResource struct {
 }
 
 var _ resource.Resource = &DatabaseBackupResource{}
+var _ resource.ResourceWithModifyPlan = &DatabaseBackupResource{}
 var _ resource.ResourceWithImportState = &DatabaseBackupResource{}
 
 func NewDatabaseBackupResource() resource.Resource {
@@ -66,8 +70,10 @@ func (r *DatabaseBackupResource) Schema(ctx context.Context, req resource.Schema
 				},
 			},
 			"project_id": schema.StringAttribute{
-				MarkdownDescription: "ID of the project that owns this resource.",
-				Required:            true,
+				MarkdownDescription: defaultedDescription("ID of the project that owns this resource.", "project_id"),
+				Optional:            true,
+				Computed:            true,
+				PlanModifiers:       []planmodifier.String{stringplanmodifier.UseStateForUnknown()},
 			},
 			"name": schema.StringAttribute{
 				MarkdownDescription: "Auto-generated backup name assigned by the API (e.g. `mysql_wordpress_20260713140736`). The value provided in config is not used — the API always generates its own name.",
@@ -77,17 +83,22 @@ func (r *DatabaseBackupResource) Schema(ctx context.Context, req resource.Schema
 				},
 			},
 			"location": schema.StringAttribute{
-				MarkdownDescription: "Region identifier (e.g., `ITBG-Bergamo`). See the [available locations and zones](https://api.arubacloud.com/docs/metadata/#location-and-data-center).",
-				Required:            true,
+				MarkdownDescription: defaultedDescription("Region identifier (e.g., `ITBG-Bergamo`). See the [available locations and zones](https://api.arubacloud.com/docs/metadata/#location-and-data-center).", "location"),
+				Optional:            true,
+				Computed:            true,
+				PlanModifiers:       []planmodifier.String{stringplanmodifier.UseStateForUnknown()},
 			},
 			"tags": schema.ListAttribute{
 				ElementType:         types.StringType,
 				MarkdownDescription: "List of string tags attached to the resource for filtering and organisation.",
 				Optional:            true,
 			},
+			"tags_all": tagsAllAttribute(),
 			"zone": schema.StringAttribute{
-				MarkdownDescription: "Availability zone within the region where the backup is stored.",
-				Required:            true,
+				MarkdownDescription: defaultedDescription("Availability zone within the region where the backup is stored.", "zone"),
+				Optional:            true,
+				Computed:            true,
+				PlanModifiers:       []planmodifier.String{stringplanmodifier.UseStateForUnknown()},
 			},
 			"dbaas_id": schema.StringAttribute{
 				MarkdownDescription: "ID of the DBaaS cluster or database to back up.",
@@ -103,10 +114,14 @@ func (r *DatabaseBackupResource) Schema(ctx context.Context, req resource.Schema
 				Validators:          []validator.String{stringvalidator.OneOf("Hour", "Month", "Year")},
 			},
 			"timeout": schema.StringAttribute{
-				MarkdownDescription: "Per-resource timeout override (e.g. `\"15m\"`, `\"1h\"`). Overrides the provider-level `resource_timeout` for this resource's Create and Delete operations. Uses Go duration syntax.",
+				MarkdownDescription: "Per-resource timeout override (e.g. `\"15m\"`, `\"1h\"`) applied to every operation not set in the `timeouts` block. Overrides the provider-level `resource_timeout`. Uses Go duration syntax. Deprecated — use the `timeouts` block instead.",
+				DeprecationMessage:  "Use the timeouts block, which sets the create, read, update and delete timeouts individually.",
 				Optional:            true,
 			},
 		},
+		Blocks: map[string]schema.Block{
+			"timeouts": timeouts.BlockAll(ctx),
+		},
 	}
 }
 
@@ -126,31 +141,43 @@ func (r *DatabaseBackupResource) Configure(ctx context.Context, req resource.Con
 }
 
 func databaseBackupRef(data *DatabaseBackupResourceModel) aruba.Ref {
-	if !data.Uri.IsNull() && data.Uri.ValueString() != "" {
-		return aruba.URI(data.Uri.ValueString())
-	}
-	return aruba.URI("/projects/" + data.ProjectID.ValueString() +
-		"/providers/Aruba.Database/backups/" + data.Id.ValueString())
+	return uriRef(data.Uri, newResourceURI(uriKindDatabaseBackup, data.ProjectID.ValueString(), data.Id.ValueString()))
+}
+
+func (r *DatabaseBackupResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	r.client.planTagsAll(ctx, req, resp, false)
+	r.client.planProviderDefaults(ctx, req, resp,
+		providerDefault{attr: "project_id", replace: false},
+		providerDefault{attr: "location", replace: false},
+		providerDefault{attr: "zone", replace: false},
+	)
 }
 
 func (r *DatabaseBackupResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	ctx = withCorrelationID(ctx)
 	var data DatabaseBackupResourceModel
 	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	timeout := r.client.operationTimeout(ctx, data.Timeouts.Create, data.Timeout, &resp.Diagnostics)
 	if resp.Diagnostics.HasError() {
 		return
 	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	if !r.client.checkGuardrails("create", "DatabaseBackup", data.ProjectID, &resp.Diagnostics) {
+		return
+	}
 
 	projectID := data.ProjectID.ValueString()
 	dbaasID := data.DBaaSID.ValueString()
 	databaseName := data.Database.ValueString()
 
-	tags := ListToTags(ctx, data.Tags, &resp.Diagnostics)
+	tags := r.client.requestTags(ctx, data.Tags, &data.TagsAll, &resp.Diagnostics)
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
-	dbaasURI := "/projects/" + projectID + "/providers/Aruba.Database/dbaas/" + dbaasID
-	databaseURI := dbaasURI + "/databases/" + databaseName
+	dbaasURI := newResourceURI(uriKindDBaaS, projectID, dbaasID).String()
+	databaseURI := newResourceURI(uriKindDatabase, projectID, databaseName, dbaasID).String()
 
 	// Poll until the database is reachable before creating the backup.
 	// The backup API may lag behind the Databases.Get() endpoint, so we extend
@@ -181,7 +208,7 @@ func (r *DatabaseBackupResource) Create(ctx context.Context, req resource.Create
 		case <-ctx.Done():
 			resp.Diagnostics.AddError("Context cancelled", "Cancelled while waiting for database to become visible")
 			return
-		case <-time.After(dbPollInterval):
+		case <-time.After(retryDelay(dbPollInterval)):
 		}
 	}
 
@@ -189,11 +216,11 @@ func (r *DatabaseBackupResource) Create(ctx context.Context, req resource.Create
 	// by 20–30 min after the database becomes visible. Retry until the effective
 	// resource timeout is exhausted rather than capping at a fixed count.
 	const backupRetryInterval = 15 * time.Second
-	backupDeadline := time.Now().Add(effectiveTimeout(data.Timeout, r.client.ResourceTimeout))
+	backupDeadline := time.Now().Add(timeout)
 
 	backupBuilder := aruba.NewDBaaSBackup().
 		Named(data.Name.ValueString()).
-		InProject(aruba.URI("/projects/" + projectID)).
+		InProject(newResourceURI(uriKindProject, projectID, "").Ref()).
 		InRegion(aruba.Region(data.Location.ValueString())).
 		InZone(aruba.Zone(data.Zone.ValueString())).
 		FromDBaaS(aruba.URI(dbaasURI)).
@@ -202,7 +229,7 @@ func (r *DatabaseBackupResource) Create(ctx context.Context, req resource.Create
 		Tagged(tags...)
 
 	var backup *aruba.DBaaSBackup
-	var lastProvErr error
+	var lastProvErr *ProviderError
 	for attempt := 0; ; attempt++ {
 		var createErr error
 		backup, createErr = r.client.Client.FromDatabase().Backups().Create(ctx, backupBuilder)
@@ -229,11 +256,11 @@ func (r *DatabaseBackupResource) Create(ctx context.Context, req resource.Create
 		case <-ctx.Done():
 			resp.Diagnostics.AddError("Context cancelled", "Cancelled while waiting for backup API")
 			return
-		case <-time.After(backupRetryInterval):
+		case <-time.After(retryDelay(backupRetryInterval)):
 		}
 	}
 	if lastProvErr != nil {
-		resp.Diagnostics.AddError("API Error", lastProvErr.Error())
+		AppendAPIError(&resp.Diagnostics, lastProvErr)
 		return
 	}
 
@@ -250,7 +277,7 @@ func (r *DatabaseBackupResource) Create(ctx context.Context, req resource.Create
 		return
 	}
 
-	if waitErr := backup.WaitUntilReady(ctx, sdkWaitOptions(effectiveTimeout(data.Timeout, r.client.ResourceTimeout))...); waitErr != nil {
+	if waitErr := backup.WaitUntilReady(ctx, sdkWaitOptions(timeout)...); waitErr != nil {
 		ReportWaitResult(&resp.Diagnostics, waitErr, "DatabaseBackup", data.Id.ValueString())
 		return
 	}
@@ -263,11 +290,18 @@ func (r *DatabaseBackupResource) Create(ctx context.Context, req resource.Create
 }
 
 func (r *DatabaseBackupResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	ctx = withCorrelationID(ctx)
 	var data DatabaseBackupResourceModel
 	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	timeout := r.client.operationTimeout(ctx, data.Timeouts.Read, data.Timeout, &resp.Diagnostics)
 	if resp.Diagnostics.HasError() {
 		return
 	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	if !r.client.checkGuardrails("read", "DatabaseBackup", data.ProjectID, &resp.Diagnostics) {
+		return
+	}
 	if data.Id.IsNull() || data.Id.ValueString() == "" {
 		resp.State.RemoveResource(ctx)
 		return
@@ -279,7 +313,7 @@ func (r *DatabaseBackupResource) Read(ctx context.Context, req resource.ReadRequ
 			resp.State.RemoveResource(ctx)
 			return
 		}
-		resp.Diagnostics.AddError("API Error", provErr.Error())
+		AppendAPIError(&resp.Diagnostics, provErr)
 		return
 	}
 
@@ -290,13 +324,13 @@ func (r *DatabaseBackupResource) Read(ctx context.Context, req resource.ReadRequ
 			fmt.Sprintf("DatabaseBackup %q is in a terminal failure state (%s). "+
 				"Run `terraform destroy` to clean it up, or `terraform apply -replace=<address>` to recreate it.", data.Id.ValueString(), st))
 	case IsCreatingState(st):
-		if waitErr := backup.WaitUntilReady(ctx, sdkWaitOptions(r.client.ResourceTimeout)...); waitErr != nil {
+		if waitErr := backup.WaitUntilReady(ctx, sdkWaitOptions(timeout)...); waitErr != nil {
 			ReportWaitResult(&resp.Diagnostics, waitErr, "DatabaseBackup", data.Id.ValueString())
 			return
 		}
 		backup, err = r.client.Client.FromDatabase().Backups().Get(ctx, databaseBackupRef(&data))
 		if provErr := CheckResponseErr("read", "DatabaseBackup", err); provErr != nil {
-			resp.Diagnostics.AddError("API Error", provErr.Error())
+			AppendAPIError(&resp.Diagnostics, provErr)
 			return
 		}
 	}
@@ -304,7 +338,7 @@ func (r *DatabaseBackupResource) Read(ctx context.Context, req resource.ReadRequ
 	data.Id = types.StringValue(backup.ID())
 	data.Uri = strVal(backup.URI())
 	data.Name = types.StringValue(backup.Name())
-	data.Tags = TagsToListPreserveNull(backup.Tags(), data.Tags)
+	data.Tags, data.TagsAll = flattenTags(backup.Tags(), data.Tags, data.TagsAll)
 	if backup.Region() != "" {
 		data.Location = types.StringValue(string(backup.Region()))
 	}
@@ -329,6 +363,7 @@ func (r *DatabaseBackupResource) Read(ctx context.Context, req resource.ReadRequ
 }
 
 func (r *DatabaseBackupResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	ctx = withCorrelationID(ctx)
 	// Database backups do not support updates.
 	resp.Diagnostics.AddWarning(
 		"Update Not Supported",
@@ -336,15 +371,23 @@ func (r *DatabaseBackupResource) Update(ctx context.Context, req resource.Update
 	)
 	var data DatabaseBackupResourceModel
 	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	r.client.resolveTagsAll(data.Tags, &data.TagsAll)
 	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
 }
 
 func (r *DatabaseBackupResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	ctx = withCorrelationID(ctx)
 	var data DatabaseBackupResourceModel
 	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	timeout := r.client.operationTimeout(ctx, data.Timeouts.Delete, data.Timeout, &resp.Diagnostics)
 	if resp.Diagnostics.HasError() {
 		return
 	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	if !r.client.checkGuardrails("delete", "DatabaseBackup", data.ProjectID, &resp.Diagnostics) {
+		return
+	}
 
 	ref := databaseBackupRef(&data)
 	backupID := data.Id.ValueString()
@@ -364,12 +407,12 @@ func (r *DatabaseBackupResource) Delete(ctx context.Context, req resource.Delete
 	err := DeleteResourceWithRetry(ctx, func() error {
 		return CheckResponseErrAsError("delete", "DatabaseBackup",
 			r.client.Client.FromDatabase().Backups().Delete(ctx, ref))
-	}, "DatabaseBackup", backupID, effectiveTimeout(data.Timeout, r.client.ResourceTimeout), deletionChecker)
+	}, "DatabaseBackup", backupID, timeout, deletionChecker)
 	if err != nil {
 		resp.Diagnostics.AddError("Error deleting DatabaseBackup", err.Error())
 		return
 	}
-	if waitErr := WaitForResourceDeleted(ctx, deletionChecker, "DatabaseBackup", backupID, remainingTimeout(deleteStart, effectiveTimeout(data.Timeout, r.client.ResourceTimeout))); waitErr != nil {
+	if waitErr := WaitForResourceDeleted(ctx, deletionChecker, "DatabaseBackup", backupID, remainingTimeout(deleteStart, timeout)); waitErr != nil {
 		resp.Diagnostics.AddError("Error waiting for DatabaseBackup deletion", waitErr.Error())
 		return
 	}
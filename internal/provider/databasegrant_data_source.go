@@ -4,7 +4,6 @@ import (
 	"context"
 	"fmt"
 
-	aruba "github.com/Arubacloud/sdk-go/pkg/aruba"
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
 	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/types"
@@ -87,11 +86,15 @@ func (d *DatabaseGrantDataSource) Configure(ctx context.Context, req datasource.
 }
 
 func (d *DatabaseGrantDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	ctx = withCorrelationID(ctx)
 	var data DatabaseGrantDataSourceModel
 	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
+	if !d.client.checkGuardrails("read", "DatabaseGrant", data.ProjectID, &resp.Diagnostics) {
+		return
+	}
 
 	projectID := data.ProjectID.ValueString()
 	dbaasID := data.DBaaSID.ValueString()
@@ -104,9 +107,9 @@ func (d *DatabaseGrantDataSource) Read(ctx context.Context, req datasource.ReadR
 
 	// Use user ID as the grant lookup key within the database.
 	grant, err := d.client.Client.FromDatabase().Grants().Get(ctx,
-		aruba.URI("/projects/"+projectID+"/providers/Aruba.Database/dbaas/"+dbaasID+"/databases/"+database+"/grants/"+userID))
+		newResourceURI(uriKindDatabaseGrant, projectID, userID, dbaasID, database).Ref())
 	if provErr := CheckResponseErr("read", "DatabaseGrant", err); provErr != nil {
-		resp.Diagnostics.AddError("API Error", provErr.Error())
+		AppendAPIError(&resp.Diagnostics, provErr)
 		return
 	}
 
@@ -6,6 +6,7 @@ import (
 	"time"
 
 	aruba "github.com/Arubacloud/sdk-go/pkg/aruba"
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
@@ -16,14 +17,15 @@ import (
 )
 
 type DatabaseGrantResourceModel struct {
-	Id        types.String `tfsdk:"id"`
-	Uri       types.String `tfsdk:"uri"`
-	ProjectID types.String `tfsdk:"project_id"`
-	DBaaSID   types.String `tfsdk:"dbaas_id"`
-	Database  types.String `tfsdk:"database"`
-	UserID    types.String `tfsdk:"user_id"`
-	Role      types.String `tfsdk:"role"`
-	Timeout   types.String `tfsdk:"timeout"`
+	Id        types.String   `tfsdk:"id"`
+	Uri       types.String   `tfsdk:"uri"`
+	ProjectID types.String   `tfsdk:"project_id"`
+	DBaaSID   types.String   `tfsdk:"dbaas_id"`
+	Database  types.String   `tfsdk:"database"`
+	UserID    types.String   `tfsdk:"user_id"`
+	Role      types.String   `tfsdk:"role"`
+	Timeout   types.String   `tfsdk:"timeout"`
+	Timeouts  timeouts.Value `tfsdk:"timeouts"`
 }
 
 type DatabaseGrantResource struct {
@@ -31,6 +33,7 @@ type DatabaseGrantResource struct {
 }
 
 var _ resource.Resource = &DatabaseGrantResource{}
+var _ resource.ResourceWithModifyPlan = &DatabaseGrantResource{}
 var _ resource.ResourceWithImportState = &DatabaseGrantResource{}
 
 func NewDatabaseGrantResource() resource.Resource {
@@ -60,9 +63,11 @@ func (r *DatabaseGrantResource) Schema(ctx context.Context, req resource.SchemaR
 				},
 			},
 			"project_id": schema.StringAttribute{
-				MarkdownDescription: "ID of the project that owns this resource. (Immutable — changing this value forces the resource to be destroyed and re-created.)",
-				Required:            true,
+				MarkdownDescription: defaultedDescription("ID of the project that owns this resource. (Immutable — changing this value forces the resource to be destroyed and re-created.)", "project_id"),
+				Optional:            true,
+				Computed:            true,
 				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
 					stringplanmodifier.RequiresReplace(),
 				},
 			},
@@ -95,10 +100,14 @@ func (r *DatabaseGrantResource) Schema(ctx context.Context, req resource.SchemaR
 				},
 			},
 			"timeout": schema.StringAttribute{
-				MarkdownDescription: "Per-resource timeout override (e.g. `\"15m\"`, `\"1h\"`). Overrides the provider-level `resource_timeout` for this resource's Create and Delete operations. Uses Go duration syntax.",
+				MarkdownDescription: "Per-resource timeout override (e.g. `\"15m\"`, `\"1h\"`) applied to every operation not set in the `timeouts` block. Overrides the provider-level `resource_timeout`. Uses Go duration syntax. Deprecated — use the `timeouts` block instead.",
+				DeprecationMessage:  "Use the timeouts block, which sets the create, read, update and delete timeouts individually.",
 				Optional:            true,
 			},
 		},
+		Blocks: map[string]schema.Block{
+			"timeouts": timeouts.BlockAll(ctx),
+		},
 	}
 }
 
@@ -120,10 +129,7 @@ func (r *DatabaseGrantResource) Configure(ctx context.Context, req resource.Conf
 // grantCompositeRef constructs a URI using the user ID as the grant key.
 // This matches the legacy behavior where userID was used as the grant identifier.
 func grantCompositeRef(projectID, dbaasID, databaseName, userID string) aruba.Ref {
-	return aruba.URI("/projects/" + projectID +
-		"/providers/Aruba.Database/dbaas/" + dbaasID +
-		"/databases/" + databaseName +
-		"/grants/" + userID)
+	return newResourceURI(uriKindDatabaseGrant, projectID, userID, dbaasID, databaseName).Ref()
 }
 
 // grantRefFromModel extracts IDs from the composite stored ID (project/dbaas/db/user).
@@ -135,21 +141,32 @@ func grantRefFromModel(data *DatabaseGrantResourceModel) aruba.Ref {
 	return grantCompositeRef(projectID, dbaasID, databaseName, userID)
 }
 
+func (r *DatabaseGrantResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	r.client.planProviderDefaults(ctx, req, resp,
+		providerDefault{attr: "project_id", replace: true},
+	)
+}
+
 func (r *DatabaseGrantResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	ctx = withCorrelationID(ctx)
 	var data DatabaseGrantResourceModel
 	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	timeout := r.client.operationTimeout(ctx, data.Timeouts.Create, data.Timeout, &resp.Diagnostics)
 	if resp.Diagnostics.HasError() {
 		return
 	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	if !r.client.checkGuardrails("create", "DatabaseGrant", data.ProjectID, &resp.Diagnostics) {
+		return
+	}
 
 	projectID := data.ProjectID.ValueString()
 	dbaasID := data.DBaaSID.ValueString()
 	databaseName := data.Database.ValueString()
 	userID := data.UserID.ValueString()
 
-	databaseURI := "/projects/" + projectID +
-		"/providers/Aruba.Database/dbaas/" + dbaasID +
-		"/databases/" + databaseName
+	databaseURI := newResourceURI(uriKindDatabase, projectID, databaseName, dbaasID).String()
 
 	grant, err := r.client.Client.FromDatabase().Grants().Create(ctx,
 		aruba.NewGrant().
@@ -158,7 +175,7 @@ func (r *DatabaseGrantResource) Create(ctx context.Context, req resource.CreateR
 			OfRole(data.Role.ValueString()),
 	)
 	if provErr := CheckResponseErr("create", "DatabaseGrant", err); provErr != nil {
-		resp.Diagnostics.AddError("API Error", provErr.Error())
+		AppendAPIError(&resp.Diagnostics, provErr)
 		return
 	}
 
@@ -172,11 +189,18 @@ func (r *DatabaseGrantResource) Create(ctx context.Context, req resource.CreateR
 }
 
 func (r *DatabaseGrantResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	ctx = withCorrelationID(ctx)
 	var data DatabaseGrantResourceModel
 	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	timeout := r.client.operationTimeout(ctx, data.Timeouts.Read, data.Timeout, &resp.Diagnostics)
 	if resp.Diagnostics.HasError() {
 		return
 	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	if !r.client.checkGuardrails("read", "DatabaseGrant", data.ProjectID, &resp.Diagnostics) {
+		return
+	}
 	if data.Id.IsNull() || data.Id.ValueString() == "" {
 		resp.State.RemoveResource(ctx)
 		return
@@ -188,7 +212,7 @@ func (r *DatabaseGrantResource) Read(ctx context.Context, req resource.ReadReque
 			resp.State.RemoveResource(ctx)
 			return
 		}
-		resp.Diagnostics.AddError("API Error", provErr.Error())
+		AppendAPIError(&resp.Diagnostics, provErr)
 		return
 	}
 
@@ -209,11 +233,18 @@ func (r *DatabaseGrantResource) Update(_ context.Context, _ resource.UpdateReque
 }
 
 func (r *DatabaseGrantResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	ctx = withCorrelationID(ctx)
 	var data DatabaseGrantResourceModel
 	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	timeout := r.client.operationTimeout(ctx, data.Timeouts.Delete, data.Timeout, &resp.Diagnostics)
 	if resp.Diagnostics.HasError() {
 		return
 	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	if !r.client.checkGuardrails("delete", "DatabaseGrant", data.ProjectID, &resp.Diagnostics) {
+		return
+	}
 
 	ref := grantRefFromModel(&data)
 	grantID := data.Id.ValueString()
@@ -233,12 +264,12 @@ func (r *DatabaseGrantResource) Delete(ctx context.Context, req resource.DeleteR
 	err := DeleteResourceWithRetry(ctx, func() error {
 		return CheckResponseErrAsError("delete", "DatabaseGrant",
 			r.client.Client.FromDatabase().Grants().Delete(ctx, ref))
-	}, "DatabaseGrant", grantID, effectiveTimeout(data.Timeout, r.client.ResourceTimeout), deletionChecker)
+	}, "DatabaseGrant", grantID, timeout, deletionChecker)
 	if err != nil {
 		resp.Diagnostics.AddError("Error deleting DatabaseGrant", err.Error())
 		return
 	}
-	if waitErr := WaitForResourceDeleted(ctx, deletionChecker, "DatabaseGrant", grantID, remainingTimeout(deleteStart, effectiveTimeout(data.Timeout, r.client.ResourceTimeout))); waitErr != nil {
+	if waitErr := WaitForResourceDeleted(ctx, deletionChecker, "DatabaseGrant", grantID, remainingTimeout(deleteStart, timeout)); waitErr != nil {
 		resp.Diagnostics.AddError("Error waiting for DatabaseGrant deletion", waitErr.Error())
 		return
 	}
@@ -4,7 +4,6 @@ import (
 	"context"
 	"fmt"
 
-	aruba "github.com/Arubacloud/sdk-go/pkg/aruba"
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
 	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/types"
@@ -145,11 +144,15 @@ func (d *DBaaSDataSource) Configure(ctx context.Context, req datasource.Configur
 }
 
 func (d *DBaaSDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	ctx = withCorrelationID(ctx)
 	var data DBaaSDataSourceModel
 	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
+	if !d.client.checkGuardrails("read", "DBaaS", data.ProjectID, &resp.Diagnostics) {
+		return
+	}
 
 	projectID := data.ProjectID.ValueString()
 	dbaasID := data.Id.ValueString()
@@ -159,9 +162,9 @@ func (d *DBaaSDataSource) Read(ctx context.Context, req datasource.ReadRequest,
 	}
 
 	dbaas, err := d.client.Client.FromDatabase().DBaaS().Get(ctx,
-		aruba.URI("/projects/"+projectID+"/providers/Aruba.Database/dbaas/"+dbaasID))
+		newResourceURI(uriKindDBaaS, projectID, dbaasID).Ref())
 	if provErr := CheckResponseErr("read", "DBaaS", err); provErr != nil {
-		resp.Diagnostics.AddError("API Error", provErr.Error())
+		AppendAPIError(&resp.Diagnostics, provErr)
 		return
 	}
 
@@ -6,6 +6,7 @@ import (
 	"time"
 
 	aruba "github.com/Arubacloud/sdk-go/pkg/aruba"
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
 	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
 	"github.com/hashicorp/terraform-plugin-framework/attr"
 	"github.com/hashicorp/terraform-plugin-framework/path"
@@ -19,19 +20,22 @@ import (
 )
 
 type DBaaSResourceModel struct {
-	Id            types.String `tfsdk:"id"`
-	Uri           types.String `tfsdk:"uri"`
-	Name          types.String `tfsdk:"name"`
-	Location      types.String `tfsdk:"location"`
-	Zone          types.String `tfsdk:"zone"`
-	Tags          types.List   `tfsdk:"tags"`
-	ProjectID     types.String `tfsdk:"project_id"`
-	EngineID      types.String `tfsdk:"engine_id"`
-	Flavor        types.String `tfsdk:"flavor"`
-	Storage       types.Object `tfsdk:"storage"`
-	Network       types.Object `tfsdk:"network"`
-	BillingPeriod types.String `tfsdk:"billing_period"`
-	Timeout       types.String `tfsdk:"timeout"`
+	Id                 types.String   `tfsdk:"id"`
+	Uri                types.String   `tfsdk:"uri"`
+	Name               types.String   `tfsdk:"name"`
+	Location           types.String   `tfsdk:"location"`
+	Zone               types.String   `tfsdk:"zone"`
+	Tags               types.List     `tfsdk:"tags"`
+	TagsAll            types.List     `tfsdk:"tags_all"`
+	ProjectID          types.String   `tfsdk:"project_id"`
+	EngineID           types.String   `tfsdk:"engine_id"`
+	Flavor             types.String   `tfsdk:"flavor"`
+	Storage            types.Object   `tfsdk:"storage"`
+	Network            types.Object   `tfsdk:"network"`
+	BillingPeriod      types.String   `tfsdk:"billing_period"`
+	DeletionProtection types.Bool     `tfsdk:"deletion_protection"`
+	Timeout            types.String   `tfsdk:"timeout"`
+	Timeouts           timeouts.Value `tfsdk:"timeouts"`
 }
 
 type DBaaSResource struct {
@@ -39,6 +43,7 @@ type DBaaSResource struct {
 }
 
 var _ resource.Resource = &DBaaSResource{}
+var _ resource.ResourceWithModifyPlan = &DBaaSResource{}
 var _ resource.ResourceWithImportState = &DBaaSResource{}
 
 func NewDBaaSResource() resource.Resource {
@@ -72,16 +77,20 @@ func (r *DBaaSResource) Schema(ctx context.Context, req resource.SchemaRequest,
 				Required:            true,
 			},
 			"location": schema.StringAttribute{
-				MarkdownDescription: "Region identifier (e.g., `ITBG-Bergamo`). See the [available locations and zones](https://api.arubacloud.com/docs/metadata/#location-and-data-center). (Immutable — changing this value forces the resource to be destroyed and re-created.)",
-				Required:            true,
+				MarkdownDescription: defaultedDescription("Region identifier (e.g., `ITBG-Bergamo`). See the [available locations and zones](https://api.arubacloud.com/docs/metadata/#location-and-data-center). (Immutable — changing this value forces the resource to be destroyed and re-created.)", "location"),
+				Optional:            true,
+				Computed:            true,
 				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
 					stringplanmodifier.RequiresReplace(),
 				},
 			},
 			"zone": schema.StringAttribute{
-				MarkdownDescription: "Availability zone within the region where the DBaaS cluster is deployed. (Immutable — changing this value forces the resource to be destroyed and re-created.)",
-				Required:            true,
+				MarkdownDescription: defaultedDescription("Availability zone within the region where the DBaaS cluster is deployed. (Immutable — changing this value forces the resource to be destroyed and re-created.)", "zone"),
+				Optional:            true,
+				Computed:            true,
 				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
 					stringplanmodifier.RequiresReplace(),
 				},
 			},
@@ -90,10 +99,13 @@ func (r *DBaaSResource) Schema(ctx context.Context, req resource.SchemaRequest,
 				MarkdownDescription: "List of string tags attached to the resource for filtering and organisation.",
 				Optional:            true,
 			},
+			"tags_all": tagsAllAttribute(),
 			"project_id": schema.StringAttribute{
-				MarkdownDescription: "ID of the project that owns this resource. (Immutable — changing this value forces the resource to be destroyed and re-created.)",
-				Required:            true,
+				MarkdownDescription: defaultedDescription("ID of the project that owns this resource. (Immutable — changing this value forces the resource to be destroyed and re-created.)", "project_id"),
+				Optional:            true,
+				Computed:            true,
 				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
 					stringplanmodifier.RequiresReplace(),
 				},
 			},
@@ -169,11 +181,16 @@ func (r *DBaaSResource) Schema(ctx context.Context, req resource.SchemaRequest,
 				PlanModifiers:       []planmodifier.String{useStateIfConfigNull{}},
 				Validators:          []validator.String{stringvalidator.OneOf("Hour", "Month", "Year")},
 			},
+			"deletion_protection": deletionProtectionAttribute(),
 			"timeout": schema.StringAttribute{
-				MarkdownDescription: "Per-resource timeout override (e.g. `\"15m\"`, `\"1h\"`). Overrides the provider-level `resource_timeout` for this resource's Create and Delete operations. Uses Go duration syntax.",
+				MarkdownDescription: "Per-resource timeout override (e.g. `\"15m\"`, `\"1h\"`) applied to every operation not set in the `timeouts` block. Overrides the provider-level `resource_timeout`. Uses Go duration syntax. Deprecated — use the `timeouts` block instead.",
+				DeprecationMessage:  "Use the timeouts block, which sets the create, read, update and delete timeouts individually.",
 				Optional:            true,
 			},
 		},
+		Blocks: map[string]schema.Block{
+			"timeouts": timeouts.BlockAll(ctx),
+		},
 	}
 }
 
@@ -193,10 +210,7 @@ func (r *DBaaSResource) Configure(ctx context.Context, req resource.ConfigureReq
 }
 
 func dbaasRef(data *DBaaSResourceModel) aruba.Ref {
-	if !data.Uri.IsNull() && data.Uri.ValueString() != "" {
-		return aruba.URI(data.Uri.ValueString())
-	}
-	return aruba.URI("/projects/" + data.ProjectID.ValueString() + "/providers/Aruba.Database/dbaas/" + data.Id.ValueString())
+	return uriRef(data.Uri, newResourceURI(uriKindDBaaS, data.ProjectID.ValueString(), data.Id.ValueString()))
 }
 
 // dbaasNetworkAttrTypes returns the attr.Type map for the network object.
@@ -260,15 +274,38 @@ func buildNetworkObject(vpc, subnet, sg, eip string) types.Object {
 	return obj
 }
 
+func (r *DBaaSResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	r.client.planTagsAll(ctx, req, resp, true)
+	r.client.planProviderDefaults(ctx, req, resp,
+		providerDefault{attr: "project_id", replace: true},
+		providerDefault{attr: "location", replace: true},
+		providerDefault{attr: "zone", replace: true},
+	)
+	r.client.planDataLoss(ctx, req, resp, "DBaaS",
+		"the cluster with every database, user and grant on it",
+		path.Root("project_id"),
+		path.Root("location"),
+		path.Root("zone"),
+		path.Root("engine_id"),
+	)
+}
+
 func (r *DBaaSResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	ctx = withCorrelationID(ctx)
 	var data DBaaSResourceModel
 	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	timeout := r.client.operationTimeout(ctx, data.Timeouts.Create, data.Timeout, &resp.Diagnostics)
 	if resp.Diagnostics.HasError() {
 		return
 	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	if !r.client.checkGuardrails("create", "DBaaS", data.ProjectID, &resp.Diagnostics) {
+		return
+	}
 
 	projectID := data.ProjectID.ValueString()
-	tags := ListToTags(ctx, data.Tags, &resp.Diagnostics)
+	tags := r.client.requestTags(ctx, data.Tags, &data.TagsAll, &resp.Diagnostics)
 	if resp.Diagnostics.HasError() {
 		return
 	}
@@ -294,7 +331,7 @@ func (r *DBaaSResource) Create(ctx context.Context, req resource.CreateRequest,
 
 	builder := aruba.NewDBaaS().
 		Named(data.Name.ValueString()).
-		InProject(aruba.URI("/projects/" + projectID)).
+		InProject(newResourceURI(uriKindProject, projectID, "").Ref()).
 		InRegion(aruba.Region(data.Location.ValueString())).
 		InZone(aruba.Zone(data.Zone.ValueString())).
 		OfEngine(aruba.DatabaseEngine(data.EngineID.ValueString())).
@@ -328,7 +365,7 @@ func (r *DBaaSResource) Create(ctx context.Context, req resource.CreateRequest,
 
 	dbaas, err := r.client.Client.FromDatabase().DBaaS().Create(ctx, builder)
 	if provErr := CheckResponseErr("create", "DBaaS", err); provErr != nil {
-		resp.Diagnostics.AddError("API Error", provErr.Error())
+		AppendAPIError(&resp.Diagnostics, provErr)
 		return
 	}
 
@@ -346,7 +383,7 @@ func (r *DBaaSResource) Create(ctx context.Context, req resource.CreateRequest,
 		return
 	}
 
-	if waitErr := dbaas.WaitUntilReady(ctx, sdkWaitOptions(effectiveTimeout(data.Timeout, r.client.ResourceTimeout))...); waitErr != nil {
+	if waitErr := dbaas.WaitUntilReady(ctx, sdkWaitOptions(timeout)...); waitErr != nil {
 		ReportWaitResult(&resp.Diagnostics, waitErr, "DBaaS", data.Id.ValueString())
 		return
 	}
@@ -377,11 +414,22 @@ func (r *DBaaSResource) Create(ctx context.Context, req resource.CreateRequest,
 }
 
 func (r *DBaaSResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	ctx = withCorrelationID(ctx)
 	var data DBaaSResourceModel
 	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	timeout := r.client.operationTimeout(ctx, data.Timeouts.Read, data.Timeout, &resp.Diagnostics)
 	if resp.Diagnostics.HasError() {
 		return
 	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	if !r.client.checkGuardrails("read", "DBaaS", data.ProjectID, &resp.Diagnostics) {
+		return
+	}
+	if data.DeletionProtection.IsNull() {
+		// Imported, or written by a provider version without the attribute.
+		data.DeletionProtection = types.BoolValue(false)
+	}
 	if data.Id.IsNull() || data.Id.ValueString() == "" {
 		resp.State.RemoveResource(ctx)
 		return
@@ -393,7 +441,7 @@ func (r *DBaaSResource) Read(ctx context.Context, req resource.ReadRequest, resp
 			resp.State.RemoveResource(ctx)
 			return
 		}
-		resp.Diagnostics.AddError("API Error", provErr.Error())
+		AppendAPIError(&resp.Diagnostics, provErr)
 		return
 	}
 
@@ -404,13 +452,13 @@ func (r *DBaaSResource) Read(ctx context.Context, req resource.ReadRequest, resp
 			fmt.Sprintf("DBaaS %q is in a terminal failure state (%s). "+
 				"Run `terraform destroy` to clean it up, or `terraform apply -replace=<address>` to recreate it.", data.Id.ValueString(), st))
 	case IsCreatingState(st):
-		if waitErr := dbaas.WaitUntilReady(ctx, sdkWaitOptions(r.client.ResourceTimeout)...); waitErr != nil {
+		if waitErr := dbaas.WaitUntilReady(ctx, sdkWaitOptions(timeout)...); waitErr != nil {
 			ReportWaitResult(&resp.Diagnostics, waitErr, "DBaaS", data.Id.ValueString())
 			return
 		}
 		dbaas, err = r.client.Client.FromDatabase().DBaaS().Get(ctx, dbaasRef(&data))
 		if provErr := CheckResponseErr("read", "DBaaS", err); provErr != nil {
-			resp.Diagnostics.AddError("API Error", provErr.Error())
+			AppendAPIError(&resp.Diagnostics, provErr)
 			return
 		}
 	}
@@ -426,7 +474,7 @@ func (r *DBaaSResource) Read(ctx context.Context, req resource.ReadRequest, resp
 	data.Id = types.StringValue(dbaas.ID())
 	data.Uri = strVal(dbaas.URI())
 	data.Name = types.StringValue(dbaas.Name())
-	data.Tags = TagsToListPreserveNull(dbaas.Tags(), data.Tags)
+	data.Tags, data.TagsAll = flattenTags(dbaas.Tags(), data.Tags, data.TagsAll)
 	data.ProjectID = projectID
 	data.Zone = zone // zone not returned by API
 
@@ -488,23 +536,34 @@ func (r *DBaaSResource) Read(ctx context.Context, req resource.ReadRequest, resp
 }
 
 func (r *DBaaSResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	ctx = withCorrelationID(ctx)
 	var data DBaaSResourceModel
 	var state DBaaSResourceModel
 
 	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
 	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	timeout := r.client.operationTimeout(ctx, data.Timeouts.Update, data.Timeout, &resp.Diagnostics)
 	if resp.Diagnostics.HasError() {
 		return
 	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	if !r.client.checkGuardrails("update", "DBaaS", data.ProjectID, &resp.Diagnostics) {
+		return
+	}
+	if deletionProtectionOnlyChange(req.Plan, req.State) {
+		resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+		return
+	}
 
-	tags := ListToTags(ctx, data.Tags, &resp.Diagnostics)
+	tags := r.client.requestTags(ctx, data.Tags, &data.TagsAll, &resp.Diagnostics)
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
 	dbaas, err := r.client.Client.FromDatabase().DBaaS().Get(ctx, dbaasRef(&state))
 	if provErr := CheckResponseErr("read", "DBaaS", err); provErr != nil {
-		resp.Diagnostics.AddError("API Error", provErr.Error())
+		AppendAPIError(&resp.Diagnostics, provErr)
 		return
 	}
 
@@ -569,7 +628,7 @@ func (r *DBaaSResource) Update(ctx context.Context, req resource.UpdateRequest,
 
 	updated, err := r.client.Client.FromDatabase().DBaaS().Update(ctx, dbaas)
 	if provErr := CheckResponseErr("update", "DBaaS", err); provErr != nil {
-		resp.Diagnostics.AddError("API Error", provErr.Error())
+		AppendAPIError(&resp.Diagnostics, provErr)
 		return
 	}
 
@@ -590,11 +649,21 @@ func (r *DBaaSResource) Update(ctx context.Context, req resource.UpdateRequest,
 }
 
 func (r *DBaaSResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	ctx = withCorrelationID(ctx)
 	var data DBaaSResourceModel
 	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	timeout := r.client.operationTimeout(ctx, data.Timeouts.Delete, data.Timeout, &resp.Diagnostics)
 	if resp.Diagnostics.HasError() {
 		return
 	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	if !r.client.checkGuardrails("delete", "DBaaS", data.ProjectID, &resp.Diagnostics) {
+		return
+	}
+	if !checkDeletionProtection(data.DeletionProtection, "DBaaS", data.Id.ValueString(), &resp.Diagnostics) {
+		return
+	}
 
 	ref := dbaasRef(&data)
 	dbaasID := data.Id.ValueString()
@@ -614,12 +683,12 @@ func (r *DBaaSResource) Delete(ctx context.Context, req resource.DeleteRequest,
 	err := DeleteResourceWithRetry(ctx, func() error {
 		return CheckResponseErrAsError("delete", "DBaaS",
 			r.client.Client.FromDatabase().DBaaS().Delete(ctx, ref))
-	}, "DBaaS", dbaasID, effectiveTimeout(data.Timeout, r.client.ResourceTimeout), deletionChecker)
+	}, "DBaaS", dbaasID, timeout, deletionChecker)
 	if err != nil {
 		resp.Diagnostics.AddError("Error deleting DBaaS", err.Error())
 		return
 	}
-	if waitErr := WaitForResourceDeleted(ctx, deletionChecker, "DBaaS", dbaasID, remainingTimeout(deleteStart, effectiveTimeout(data.Timeout, r.client.ResourceTimeout))); waitErr != nil {
+	if waitErr := WaitForResourceDeleted(ctx, deletionChecker, "DBaaS", dbaasID, remainingTimeout(deleteStart, timeout)); waitErr != nil {
 		resp.Diagnostics.AddError("Error waiting for DBaaS deletion", waitErr.Error())
 		return
 	}
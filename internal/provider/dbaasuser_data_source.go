@@ -4,7 +4,6 @@ import (
 	"context"
 	"fmt"
 
-	aruba "github.com/Arubacloud/sdk-go/pkg/aruba"
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
 	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/types"
@@ -83,11 +82,15 @@ func (d *DBaaSUserDataSource) Configure(ctx context.Context, req datasource.Conf
 }
 
 func (d *DBaaSUserDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	ctx = withCorrelationID(ctx)
 	var data DBaaSUserDataSourceModel
 	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
+	if !d.client.checkGuardrails("read", "DBaaSUser", data.ProjectID, &resp.Diagnostics) {
+		return
+	}
 
 	projectID := data.ProjectID.ValueString()
 	dbaasID := data.DBaaSID.ValueString()
@@ -98,9 +101,9 @@ func (d *DBaaSUserDataSource) Read(ctx context.Context, req datasource.ReadReque
 	}
 
 	user, err := d.client.Client.FromDatabase().Users().Get(ctx,
-		aruba.URI("/projects/"+projectID+"/providers/Aruba.Database/dbaas/"+dbaasID+"/users/"+username))
+		newResourceURI(uriKindDBaaSUser, projectID, username, dbaasID).Ref())
 	if provErr := CheckResponseErr("read", "DBaaSUser", err); provErr != nil {
-		resp.Diagnostics.AddError("API Error", provErr.Error())
+		AppendAPIError(&resp.Diagnostics, provErr)
 		return
 	}
 
@@ -4,26 +4,34 @@ import (
 	"context"
 	"encoding/base64"
 	"fmt"
+	"strings"
 	"time"
 
 	aruba "github.com/Arubacloud/sdk-go/pkg/aruba"
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
 )
 
 type DBaaSUserResourceModel struct {
-	Id        types.String `tfsdk:"id"`
-	Uri       types.String `tfsdk:"uri"`
-	ProjectID types.String `tfsdk:"project_id"`
-	DBaaSID   types.String `tfsdk:"dbaas_id"`
-	Username  types.String `tfsdk:"username"`
-	Password  types.String `tfsdk:"password"`
-	Timeout   types.String `tfsdk:"timeout"`
+	Id                types.String   `tfsdk:"id"`
+	Uri               types.String   `tfsdk:"uri"`
+	ProjectID         types.String   `tfsdk:"project_id"`
+	DBaaSID           types.String   `tfsdk:"dbaas_id"`
+	Username          types.String   `tfsdk:"username"`
+	Password          types.String   `tfsdk:"password"`
+	PasswordWO        types.String   `tfsdk:"password_wo"`
+	PasswordWOVersion types.Int64    `tfsdk:"password_wo_version"`
+	Timeout           types.String   `tfsdk:"timeout"`
+	Timeouts          timeouts.Value `tfsdk:"timeouts"`
 }
 
 type DBaaSUserResource struct {
@@ -31,6 +39,7 @@ type DBaaSUserResource struct {
 }
 
 var _ resource.Resource = &DBaaSUserResource{}
+var _ resource.ResourceWithModifyPlan = &DBaaSUserResource{}
 var _ resource.ResourceWithImportState = &DBaaSUserResource{}
 
 func NewDBaaSUserResource() resource.Resource {
@@ -60,9 +69,11 @@ func (r *DBaaSUserResource) Schema(ctx context.Context, req resource.SchemaReque
 				},
 			},
 			"project_id": schema.StringAttribute{
-				MarkdownDescription: "ID of the project that owns this resource. (Immutable — changing this value forces the resource to be destroyed and re-created.)",
-				Required:            true,
+				MarkdownDescription: defaultedDescription("ID of the project that owns this resource. (Immutable — changing this value forces the resource to be destroyed and re-created.)", "project_id"),
+				Optional:            true,
+				Computed:            true,
 				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
 					stringplanmodifier.RequiresReplace(),
 				},
 			},
@@ -81,18 +92,36 @@ func (r *DBaaSUserResource) Schema(ctx context.Context, req resource.SchemaReque
 				},
 			},
 			"password": schema.StringAttribute{
-				MarkdownDescription: "Password for the DBaaS user. Write-only — this value is sent to the API but is not returned in subsequent read responses. The DBaaS user API does not support password updates. (Immutable — changing this value forces the resource to be destroyed and re-created.)",
-				Required:            true,
+				MarkdownDescription: "Password for the DBaaS user. Deprecated — the value is stored in Terraform state; use `password_wo` instead. Exactly one of `password` or `password_wo` must be set. Changing the password rotates it in place; see the resource documentation for how database grants are preserved.",
+				Optional:            true,
 				Sensitive:           true,
-				PlanModifiers: []planmodifier.String{
-					stringplanmodifier.RequiresReplace(),
+				DeprecationMessage:  "Use password_wo (Terraform 1.11+) so the password is never stored in state.",
+				Validators: []validator.String{
+					stringvalidator.ExactlyOneOf(path.MatchRoot("password_wo")),
+				},
+			},
+			"password_wo": schema.StringAttribute{
+				MarkdownDescription: "Password for the DBaaS user. Write-only (Terraform 1.11+) — the value is sent to the API but never stored in plan or state. Bump `password_wo_version` to send a new value.",
+				Optional:            true,
+				Sensitive:           true,
+				WriteOnly:           true,
+			},
+			"password_wo_version": schema.Int64Attribute{
+				MarkdownDescription: "Version of `password_wo`. Terraform cannot detect changes to write-only values, so change this number whenever `password_wo` changes. Changing this value rotates the password in place.",
+				Optional:            true,
+				Validators: []validator.Int64{
+					int64validator.AlsoRequires(path.MatchRoot("password_wo")),
 				},
 			},
 			"timeout": schema.StringAttribute{
-				MarkdownDescription: "Per-resource timeout override (e.g. `\"15m\"`, `\"1h\"`). Overrides the provider-level `resource_timeout` for this resource's Create and Delete operations. Uses Go duration syntax.",
+				MarkdownDescription: "Per-resource timeout override (e.g. `\"15m\"`, `\"1h\"`) applied to every operation not set in the `timeouts` block. Overrides the provider-level `resource_timeout`. Uses Go duration syntax. Deprecated — use the `timeouts` block instead.",
+				DeprecationMessage:  "Use the timeouts block, which sets the create, read, update and delete timeouts individually.",
 				Optional:            true,
 			},
 		},
+		Blocks: map[string]schema.Block{
+			"timeouts": timeouts.BlockAll(ctx),
+		},
 	}
 }
 
@@ -112,24 +141,72 @@ func (r *DBaaSUserResource) Configure(ctx context.Context, req resource.Configur
 }
 
 func dbaasUserRef(data *DBaaSUserResourceModel) aruba.Ref {
-	return aruba.URI("/projects/" + data.ProjectID.ValueString() +
-		"/providers/Aruba.Database/dbaas/" + data.DBaaSID.ValueString() +
-		"/users/" + data.Id.ValueString())
+	return newResourceURI(uriKindDBaaSUser, data.ProjectID.ValueString(), data.Id.ValueString(), data.DBaaSID.ValueString()).Ref()
+}
+
+func dbaasParentURI(projectID, dbaasID string) string {
+	return newResourceURI(uriKindDBaaS, projectID, dbaasID).String()
+}
+
+// createUser creates a DBaaS user, retrying transient failures. The password
+// is sent base64-encoded as required by the DBaaS user API.
+func (r *DBaaSUserResource) createUser(ctx context.Context, dbaasURI, username, password string, timeout time.Duration) (*aruba.User, error) {
+	var user *aruba.User
+	err := CreateWithTransientRetry(ctx, func() error {
+		var err error
+		user, err = r.client.Client.FromDatabase().Users().Create(ctx,
+			aruba.NewUser().
+				WithUsername(username).
+				WithPassword(base64.StdEncoding.EncodeToString([]byte(password))).
+				InDBaaS(aruba.URI(dbaasURI)),
+		)
+		return CheckResponseErrAsError("create", "DBaaSUser", err)
+	}, "DBaaSUser", username, timeout)
+	return user, err
+}
+
+// waitForUser blocks until the user can be fetched. Users don't have a
+// status field, so a successful Get is the only readiness signal.
+func (r *DBaaSUserResource) waitForUser(ctx context.Context, data *DBaaSUserResourceModel, timeout time.Duration) error {
+	checker := func(ctx context.Context) (string, error) {
+		_, getErr := r.client.Client.FromDatabase().Users().Get(ctx, dbaasUserRef(data))
+		if provErr := CheckResponseErr("get", "DBaaSUser", getErr); provErr != nil {
+			return "Unknown", provErr
+		}
+		return "Active", nil
+	}
+	return WaitForResourceActive(ctx, checker, "DBaaSUser", data.Id.ValueString(), timeout)
+}
+
+func (r *DBaaSUserResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	r.client.planProviderDefaults(ctx, req, resp,
+		providerDefault{attr: "project_id", replace: true},
+	)
 }
 
 func (r *DBaaSUserResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	ctx = withCorrelationID(ctx)
 	var data DBaaSUserResourceModel
 	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	timeout := r.client.operationTimeout(ctx, data.Timeouts.Create, data.Timeout, &resp.Diagnostics)
 	if resp.Diagnostics.HasError() {
 		return
 	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	if !r.client.checkGuardrails("create", "DBaaSUser", data.ProjectID, &resp.Diagnostics) {
+		return
+	}
 
 	projectID := data.ProjectID.ValueString()
 	dbaasID := data.DBaaSID.ValueString()
 	username := data.Username.ValueString()
-	passwordBase64 := base64.StdEncoding.EncodeToString([]byte(data.Password.ValueString()))
-
-	dbaasURI := "/projects/" + projectID + "/providers/Aruba.Database/dbaas/" + dbaasID
+	password, diags := writeOnlyOrDeprecated(ctx, req.Config, path.Root("password_wo"), data.Password)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	dbaasURI := dbaasParentURI(projectID, dbaasID)
 
 	// Poll until the DBaaS user management API is ready to accept requests.
 	// After WaitUntilReady the DBaaS may still reject user creation with a
@@ -160,21 +237,12 @@ func (r *DBaaSUserResource) Create(ctx context.Context, req resource.CreateReque
 		case <-ctx.Done():
 			resp.Diagnostics.AddError("Context cancelled", "Cancelled while waiting for DBaaS user API readiness")
 			return
-		case <-time.After(dbaasUserReadyInterval):
+		case <-time.After(retryDelay(dbaasUserReadyInterval)):
 		}
 	}
 
-	var user *aruba.User
-	if createErr := CreateWithTransientRetry(ctx, func() error {
-		var err error
-		user, err = r.client.Client.FromDatabase().Users().Create(ctx,
-			aruba.NewUser().
-				WithUsername(username).
-				WithPassword(passwordBase64).
-				InDBaaS(aruba.URI(dbaasURI)),
-		)
-		return CheckResponseErrAsError("create", "DBaaSUser", err)
-	}, "DBaaSUser", username, effectiveTimeout(data.Timeout, r.client.ResourceTimeout)); createErr != nil {
+	user, createErr := r.createUser(ctx, dbaasURI, username, password.ValueString(), timeout)
+	if createErr != nil {
 		resp.Diagnostics.AddError("Error creating DBaaS user", createErr.Error())
 		return
 	}
@@ -187,15 +255,7 @@ func (r *DBaaSUserResource) Create(ctx context.Context, req resource.CreateReque
 		return
 	}
 
-	// Users don't have a status field; wait until we can successfully Get the user.
-	checker := func(ctx context.Context) (string, error) {
-		_, getErr := r.client.Client.FromDatabase().Users().Get(ctx, dbaasUserRef(&data))
-		if provErr := CheckResponseErr("get", "DBaaSUser", getErr); provErr != nil {
-			return "Unknown", provErr
-		}
-		return "Active", nil
-	}
-	if err := WaitForResourceActive(ctx, checker, "DBaaSUser", username, effectiveTimeout(data.Timeout, r.client.ResourceTimeout)); err != nil {
+	if err := r.waitForUser(ctx, &data, timeout); err != nil {
 		ReportWaitResult(&resp.Diagnostics, err, "DBaaSUser", username)
 		return
 	}
@@ -208,11 +268,18 @@ func (r *DBaaSUserResource) Create(ctx context.Context, req resource.CreateReque
 }
 
 func (r *DBaaSUserResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	ctx = withCorrelationID(ctx)
 	var data DBaaSUserResourceModel
 	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	timeout := r.client.operationTimeout(ctx, data.Timeouts.Read, data.Timeout, &resp.Diagnostics)
 	if resp.Diagnostics.HasError() {
 		return
 	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	if !r.client.checkGuardrails("read", "DBaaSUser", data.ProjectID, &resp.Diagnostics) {
+		return
+	}
 	if data.Id.IsNull() || data.Id.ValueString() == "" {
 		resp.State.RemoveResource(ctx)
 		return
@@ -224,7 +291,7 @@ func (r *DBaaSUserResource) Read(ctx context.Context, req resource.ReadRequest,
 			resp.State.RemoveResource(ctx)
 			return
 		}
-		resp.Diagnostics.AddError("API Error", provErr.Error())
+		AppendAPIError(&resp.Diagnostics, provErr)
 		return
 	}
 
@@ -236,24 +303,125 @@ func (r *DBaaSUserResource) Read(ctx context.Context, req resource.ReadRequest,
 	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
 }
 
-// Update is unreachable: all attributes carry RequiresReplace so Terraform
-// will never call Update — any change triggers a destroy + create cycle.
-func (r *DBaaSUserResource) Update(_ context.Context, _ resource.UpdateRequest, resp *resource.UpdateResponse) {
-	resp.Diagnostics.AddError(
-		"Update Not Supported",
-		"The ArubaCloud DBaaS user API does not support in-place updates. "+
-			"All attribute changes force a new resource (RequiresReplace).",
-	)
+// Update rotates the user's password. The DBaaS user API has no update
+// endpoint, so the rotation is a swap: the user's grants are recorded, the
+// user is deleted and re-created under the same username with the new
+// password, and the grants are restored and verified. Grants are read before
+// anything is deleted, so a failure at that stage leaves the user untouched.
+func (r *DBaaSUserResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	ctx = withCorrelationID(ctx)
+	var data, state DBaaSUserResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	timeout := r.client.operationTimeout(ctx, data.Timeouts.Update, data.Timeout, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	if !r.client.checkGuardrails("update", "DBaaSUser", data.ProjectID, &resp.Diagnostics) {
+		return
+	}
+
+	data.Id = state.Id
+	data.Uri = state.Uri
+	username := state.Id.ValueString()
+
+	_, err := r.client.Client.FromDatabase().Users().Get(ctx, dbaasUserRef(&state))
+	if provErr := CheckResponseErr("read", "DBaaSUser", err); provErr != nil {
+		AppendAPIError(&resp.Diagnostics, provErr)
+		return
+	}
+
+	if data.Password.Equal(state.Password) && data.PasswordWOVersion.Equal(state.PasswordWOVersion) {
+		resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+		return
+	}
+
+	password, diags := writeOnlyOrDeprecated(ctx, req.Config, path.Root("password_wo"), data.Password)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	grants, err := r.userGrants(ctx, &state)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error reading DBaaS user grants",
+			fmt.Sprintf("Could not record the grants of user %s before rotating its password; the user was not modified: %s", username, err),
+		)
+		return
+	}
+	tflog.Info(ctx, "rotating DBaaS user password", map[string]interface{}{
+		"user_id": username,
+		"grants":  len(grants),
+	})
+
+	opStart := time.Now()
+	if err := r.deleteUser(ctx, &state, timeout); err != nil {
+		resp.Diagnostics.AddError("Error rotating DBaaS user password", err.Error())
+		return
+	}
+
+	dbaasURI := dbaasParentURI(data.ProjectID.ValueString(), data.DBaaSID.ValueString())
+	user, err := r.createUser(ctx, dbaasURI, username, password.ValueString(), remainingTimeout(opStart, timeout))
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error rotating DBaaS user password",
+			fmt.Sprintf("User %s was deleted but could not be re-created with the new password: %s\n\n%s", username, err, describeGrants(grants)),
+		)
+		resp.State.RemoveResource(ctx)
+		return
+	}
+	data.Uri = strVal(user.URI())
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if err := r.waitForUser(ctx, &data, remainingTimeout(opStart, timeout)); err != nil {
+		resp.Diagnostics.AddError(
+			"Error restoring DBaaS user grants",
+			fmt.Sprintf("User %s was re-created with the new password but did not become available, so its grants were not restored: %s\n\n%s", username, err, describeGrants(grants)),
+		)
+		return
+	}
+
+	if err := r.restoreGrants(ctx, &data, grants); err != nil {
+		resp.Diagnostics.AddError(
+			"Error restoring DBaaS user grants",
+			fmt.Sprintf("The password of user %s was rotated but its grants could not be restored: %s\n\n%s", username, err, describeGrants(grants)),
+		)
+		return
+	}
+
+	tflog.Trace(ctx, "rotated a DBaaS User password", map[string]interface{}{"user_id": username})
 }
 
 func (r *DBaaSUserResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	ctx = withCorrelationID(ctx)
 	var data DBaaSUserResourceModel
 	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	timeout := r.client.operationTimeout(ctx, data.Timeouts.Delete, data.Timeout, &resp.Diagnostics)
 	if resp.Diagnostics.HasError() {
 		return
 	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	if !r.client.checkGuardrails("delete", "DBaaSUser", data.ProjectID, &resp.Diagnostics) {
+		return
+	}
 
-	ref := dbaasUserRef(&data)
+	if err := r.deleteUser(ctx, &data, timeout); err != nil {
+		resp.Diagnostics.AddError("Error deleting DBaaS user", err.Error())
+		return
+	}
+	tflog.Trace(ctx, "deleted a DBaaS User resource", map[string]interface{}{"user_id": data.Id.ValueString()})
+}
+
+// deleteUser deletes the user and waits until the API no longer returns it.
+func (r *DBaaSUserResource) deleteUser(ctx context.Context, data *DBaaSUserResourceModel, timeout time.Duration) error {
+	ref := dbaasUserRef(data)
 	username := data.Id.ValueString()
 
 	deletionChecker := func(ctx context.Context) (bool, error) {
@@ -271,16 +439,100 @@ func (r *DBaaSUserResource) Delete(ctx context.Context, req resource.DeleteReque
 	err := DeleteResourceWithRetry(ctx, func() error {
 		return CheckResponseErrAsError("delete", "DBaaSUser",
 			r.client.Client.FromDatabase().Users().Delete(ctx, ref))
-	}, "DBaaSUser", username, effectiveTimeout(data.Timeout, r.client.ResourceTimeout), deletionChecker)
+	}, "DBaaSUser", username, timeout, deletionChecker)
 	if err != nil {
-		resp.Diagnostics.AddError("Error deleting DBaaS user", err.Error())
-		return
+		return err
 	}
-	if waitErr := WaitForResourceDeleted(ctx, deletionChecker, "DBaaSUser", username, remainingTimeout(deleteStart, effectiveTimeout(data.Timeout, r.client.ResourceTimeout))); waitErr != nil {
-		resp.Diagnostics.AddError("Error waiting for DBaaSUser deletion", waitErr.Error())
-		return
+	if waitErr := WaitForResourceDeleted(ctx, deletionChecker, "DBaaSUser", username, remainingTimeout(deleteStart, timeout)); waitErr != nil {
+		return fmt.Errorf("waiting for DBaaSUser deletion: %w", waitErr)
+	}
+	return nil
+}
+
+// dbaasUserGrant is a database role held by a DBaaS user, recorded before a
+// password rotation so it can be restored afterwards.
+type dbaasUserGrant struct {
+	Database string
+	Role     string
+}
+
+// userGrants lists the databases of the parent DBaaS and returns the role the
+// user holds on each of them. Databases without a grant for the user are
+// skipped.
+func (r *DBaaSUserResource) userGrants(ctx context.Context, data *DBaaSUserResourceModel) ([]dbaasUserGrant, error) {
+	projectID := data.ProjectID.ValueString()
+	dbaasID := data.DBaaSID.ValueString()
+	userID := data.Id.ValueString()
+
+	dbList, err := r.client.Client.FromDatabase().Databases().List(ctx, aruba.URI(dbaasParentURI(projectID, dbaasID)))
+	if provErr := CheckResponseErr("list", "Database", err); provErr != nil {
+		return nil, provErr
+	}
+
+	var grants []dbaasUserGrant
+	var iterErr error
+	listErr := dbList.All(ctx, func(db *aruba.Database) bool {
+		grant, getErr := r.client.Client.FromDatabase().Grants().Get(ctx, grantCompositeRef(projectID, dbaasID, db.Name(), userID))
+		if provErr := CheckResponseErr("get", "DatabaseGrant", getErr); provErr != nil {
+			if IsNotFound(provErr) {
+				return true
+			}
+			iterErr = provErr
+			return false
+		}
+		grants = append(grants, dbaasUserGrant{Database: db.Name(), Role: grant.RoleName()})
+		return true
+	})
+	if iterErr != nil {
+		return nil, iterErr
+	}
+	if provErr := CheckResponseErr("list", "Database", listErr); provErr != nil {
+		return nil, provErr
+	}
+	return grants, nil
+}
+
+// restoreGrants re-creates the given grants for the user and verifies each of
+// them with a Get, so a rotation only succeeds once every role is back.
+func (r *DBaaSUserResource) restoreGrants(ctx context.Context, data *DBaaSUserResourceModel, grants []dbaasUserGrant) error {
+	projectID := data.ProjectID.ValueString()
+	dbaasID := data.DBaaSID.ValueString()
+	userID := data.Id.ValueString()
+
+	for _, g := range grants {
+		_, err := r.client.Client.FromDatabase().Grants().Create(ctx,
+			aruba.NewGrant().
+				InDatabase(newResourceURI(uriKindDatabase, projectID, g.Database, dbaasID).Ref()).
+				ForUser(userID).
+				OfRole(g.Role),
+		)
+		if provErr := CheckResponseErr("create", "DatabaseGrant", err); provErr != nil {
+			return fmt.Errorf("grant on database %s: %w", g.Database, provErr)
+		}
+
+		grant, err := r.client.Client.FromDatabase().Grants().Get(ctx, grantCompositeRef(projectID, dbaasID, g.Database, userID))
+		if provErr := CheckResponseErr("get", "DatabaseGrant", err); provErr != nil {
+			return fmt.Errorf("verifying grant on database %s: %w", g.Database, provErr)
+		}
+		if grant.RoleName() != g.Role {
+			return fmt.Errorf("grant on database %s has role %q, expected %q", g.Database, grant.RoleName(), g.Role)
+		}
+	}
+	return nil
+}
+
+// describeGrants lists the grants held before a rotation so they can be
+// restored by hand when the swap fails part-way.
+func describeGrants(grants []dbaasUserGrant) string {
+	if len(grants) == 0 {
+		return "The user held no database grants before the rotation."
+	}
+	var b strings.Builder
+	b.WriteString("Grants held before the rotation:")
+	for _, g := range grants {
+		fmt.Fprintf(&b, "\n  - database %q: role %q", g.Database, g.Role)
 	}
-	tflog.Trace(ctx, "deleted a DBaaS User resource", map[string]interface{}{"user_id": username})
+	return b.String()
 }
 
 func (r *DBaaSUserResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
@@ -2,9 +2,17 @@ package provider
 
 import (
 	"context"
+	"encoding/base64"
+	"io"
 	"net/http"
+	"strings"
+	"sync"
 	"testing"
 	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
 )
 
 // dbaasUserCreateSuccessJSON provides a top-level "username" field so that
@@ -93,3 +101,228 @@ func TestDBaaSUserRead_WithUsername(t *testing.T) {
 	// The response format may or may not match exactly; what matters is coverage.
 	_ = resp
 }
+
+// dbaasUserRotateReq builds an UpdateRequest whose state holds oldPassword and
+// whose plan holds newPassword, with every other string set to "test-<name>".
+func dbaasUserRotateReq(ctx context.Context, t *testing.T, r resource.Resource, oldPassword, newPassword string) (resource.UpdateRequest, *resource.UpdateResponse) {
+	t.Helper()
+
+	schemaResp := &resource.SchemaResponse{}
+	r.Schema(ctx, resource.SchemaRequest{}, schemaResp)
+
+	objType, ok := schemaResp.Schema.Type().TerraformType(ctx).(tftypes.Object)
+	if !ok {
+		t.Fatalf("dbaasUserRotateReq: schema root is not an object type")
+	}
+	build := func(password string) tftypes.Value {
+		attrs := make(map[string]tftypes.Value, len(objType.AttributeTypes))
+		for name, ty := range objType.AttributeTypes {
+			switch {
+			case name == "password":
+				attrs[name] = tftypes.NewValue(ty, password)
+			case name == "password_wo" || !ty.Is(tftypes.String):
+				attrs[name] = tftypes.NewValue(ty, nil)
+			default:
+				attrs[name] = tftypes.NewValue(ty, "test-"+name)
+			}
+		}
+		return tftypes.NewValue(objType, attrs)
+	}
+
+	req := resource.UpdateRequest{
+		Plan:  tfsdk.Plan{Raw: build(newPassword), Schema: schemaResp.Schema},
+		State: tfsdk.State{Raw: build(oldPassword), Schema: schemaResp.Schema},
+	}
+	resp := &resource.UpdateResponse{State: tfsdk.State{Schema: schemaResp.Schema}}
+	return req, resp
+}
+
+// TestDBaaSUserUpdate_RotatesPassword verifies that a password change is
+// applied in place by deleting and re-creating the user with the new
+// password, after the user's grants have been read.
+func TestDBaaSUserUpdate_RotatesPassword(t *testing.T) {
+	oldActivePoll, oldDeletedPoll := waitForActivePollInterval, waitForDeletedPollInterval
+	waitForActivePollInterval, waitForDeletedPollInterval = time.Millisecond, time.Millisecond
+	t.Cleanup(func() {
+		waitForActivePollInterval, waitForDeletedPollInterval = oldActivePoll, oldDeletedPoll
+	})
+
+	ctx := context.Background()
+
+	var mu sync.Mutex
+	var calls []string
+	var createdBody string
+	deleted := false
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		calls = append(calls, r.Method+" "+r.URL.Path)
+
+		if strings.HasSuffix(r.URL.Path, "/databases") {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(`{"total":0,"values":[]}`)) //nolint:errcheck
+			return
+		}
+		switch r.Method {
+		case http.MethodDelete:
+			deleted = true
+			w.WriteHeader(http.StatusNoContent)
+			return
+		case http.MethodPost:
+			body, _ := io.ReadAll(r.Body)
+			createdBody = string(body)
+			deleted = false
+		case http.MethodGet:
+			if deleted {
+				apiError(w, http.StatusNotFound)
+				return
+			}
+		}
+		dbaasUserCreateSuccessHandler(w, r)
+	}
+	_, mockClient := newMockArubaClient(t, handler)
+
+	res := NewDBaaSUserResource()
+	configureResource(ctx, t, res, mockClient)
+
+	req, resp := dbaasUserRotateReq(ctx, t, res, "old-secret", "new-secret")
+	res.Update(ctx, req, resp)
+
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("DBaaS User Update() reported error: %v", resp.Diagnostics)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	deleteAt, createAt, listAt := -1, -1, -1
+	for i, c := range calls {
+		switch {
+		case strings.HasPrefix(c, http.MethodDelete) && deleteAt < 0:
+			deleteAt = i
+		case strings.HasPrefix(c, http.MethodPost) && createAt < 0:
+			createAt = i
+		case strings.HasSuffix(c, "/databases") && listAt < 0:
+			listAt = i
+		}
+	}
+	if listAt < 0 || deleteAt < 0 || createAt < 0 || !(listAt < deleteAt && deleteAt < createAt) {
+		t.Fatalf("expected list grants, then delete, then create; got calls %v", calls)
+	}
+	if want := base64.StdEncoding.EncodeToString([]byte("new-secret")); !strings.Contains(createdBody, want) {
+		t.Errorf("re-created user body %q does not carry the new password", createdBody)
+	}
+}
+
+// TestDBaaSUserUpdate_GrantListFailureKeepsUser verifies that the user is
+// never deleted when its grants cannot be recorded before a rotation.
+func TestDBaaSUserUpdate_GrantListFailureKeepsUser(t *testing.T) {
+	ctx := context.Background()
+
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodDelete || r.Method == http.MethodPost {
+			t.Errorf("unexpected %s %s after grant listing failed", r.Method, r.URL.Path)
+		}
+		if strings.HasSuffix(r.URL.Path, "/databases") {
+			apiError(w, http.StatusInternalServerError)
+			return
+		}
+		dbaasUserCreateSuccessHandler(w, r)
+	}
+	_, mockClient := newMockArubaClient(t, handler)
+
+	res := NewDBaaSUserResource()
+	configureResource(ctx, t, res, mockClient)
+
+	req, resp := dbaasUserRotateReq(ctx, t, res, "old-secret", "new-secret")
+	res.Update(ctx, req, resp)
+
+	if !resp.Diagnostics.HasError() {
+		t.Fatal("DBaaS User Update() should fail when grants cannot be listed")
+	}
+	if got := resp.Diagnostics.Errors()[0].Summary(); got != "Error reading DBaaS user grants" {
+		t.Errorf("unexpected error summary %q", got)
+	}
+}
+
+// TestDBaaSUserUpdate_RestoresGrants verifies that the grants recorded before
+// a rotation are re-created on the new user and verified afterwards.
+func TestDBaaSUserUpdate_RestoresGrants(t *testing.T) {
+	oldActivePoll, oldDeletedPoll := waitForActivePollInterval, waitForDeletedPollInterval
+	waitForActivePollInterval, waitForDeletedPollInterval = time.Millisecond, time.Millisecond
+	t.Cleanup(func() {
+		waitForActivePollInterval, waitForDeletedPollInterval = oldActivePoll, oldDeletedPoll
+	})
+
+	ctx := context.Background()
+
+	const grantJSON = `{"user":{"username":"test-user"},"database":{"name":"app"},"role":{"name":"readwrite"}}`
+	var mu sync.Mutex
+	var calls []string
+	deleted := false
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		calls = append(calls, r.Method+" "+r.URL.Path)
+
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/databases"):
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(`{"total":1,"values":[{"name":"app"}]}`)) //nolint:errcheck
+			return
+		case strings.Contains(r.URL.Path, "/grants"):
+			w.Header().Set("Content-Type", "application/json")
+			if r.Method == http.MethodPost {
+				w.WriteHeader(http.StatusCreated)
+			} else {
+				w.WriteHeader(http.StatusOK)
+			}
+			w.Write([]byte(grantJSON)) //nolint:errcheck
+			return
+		}
+		switch r.Method {
+		case http.MethodDelete:
+			deleted = true
+			w.WriteHeader(http.StatusNoContent)
+			return
+		case http.MethodPost:
+			deleted = false
+		case http.MethodGet:
+			if deleted {
+				apiError(w, http.StatusNotFound)
+				return
+			}
+		}
+		dbaasUserCreateSuccessHandler(w, r)
+	}
+	_, mockClient := newMockArubaClient(t, handler)
+
+	res := NewDBaaSUserResource()
+	configureResource(ctx, t, res, mockClient)
+
+	req, resp := dbaasUserRotateReq(ctx, t, res, "old-secret", "new-secret")
+	res.Update(ctx, req, resp)
+
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("DBaaS User Update() reported error: %v", resp.Diagnostics)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	userCreateAt, grantCreateAt, grantVerifyAt := -1, -1, -1
+	for i, c := range calls {
+		isGrant := strings.Contains(c, "/databases/app/grants")
+		switch {
+		case strings.HasPrefix(c, http.MethodPost) && !isGrant && userCreateAt < 0:
+			userCreateAt = i
+		case strings.HasPrefix(c, http.MethodPost) && isGrant && grantCreateAt < 0:
+			grantCreateAt = i
+		case strings.HasPrefix(c, http.MethodGet) && isGrant && grantCreateAt >= 0 && grantVerifyAt < 0:
+			grantVerifyAt = i
+		}
+	}
+	if userCreateAt < 0 || grantCreateAt < userCreateAt || grantVerifyAt < grantCreateAt {
+		t.Fatalf("expected the user to be re-created, then the grant on app to be created and verified; got calls %v", calls)
+	}
+}
@@ -0,0 +1,131 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+// deletionProtectionAttribute returns the schema of the deletion_protection
+// attribute shared by resources that hold data. It is enforced by
+// planDeletionProtection at plan time and by checkDeletionProtection in
+// Delete.
+func deletionProtectionAttribute() schema.BoolAttribute {
+	return schema.BoolAttribute{
+		MarkdownDescription: "Whether Terraform is prevented from destroying the resource. While `true`, a plan that destroys " +
+			"the resource or replaces it fails, and so does `Delete`. Set it to `false` and apply before destroying. " +
+			"It is updated in place; changing only this attribute does not call the API. Default: `false`.",
+		Optional: true,
+		Computed: true,
+		Default:  booldefault.StaticBool(false),
+	}
+}
+
+// planDeletionProtection fails the plan when it would destroy or replace a
+// resource whose prior state has deletion_protection = true. It is called
+// from ModifyPlan after every other plan modification, so that replacements
+// requested there are seen too; replaceAttrs is passed to replacedAttributes.
+// The prior state is checked rather than the plan: the replacement deletes
+// the existing resource with its prior state, so turning the protection off
+// must be applied on its own first.
+func planDeletionProtection(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse, resourceType string, replaceAttrs ...path.Path) {
+	if req.State.Raw.IsNull() {
+		return
+	}
+	var protected types.Bool
+	var id types.String
+	resp.Diagnostics.Append(req.State.GetAttribute(ctx, path.Root("deletion_protection"), &protected)...)
+	resp.Diagnostics.Append(req.State.GetAttribute(ctx, path.Root("id"), &id)...)
+	if resp.Diagnostics.HasError() || !protected.ValueBool() {
+		return
+	}
+
+	if req.Plan.Raw.IsNull() {
+		resp.Diagnostics.AddAttributeError(path.Root("deletion_protection"), "Deletion protection enabled",
+			fmt.Sprintf("%s %q has deletion_protection = true, so Terraform will not destroy it. "+
+				"To destroy it, set deletion_protection = false and apply that change first.", resourceType, id.ValueString()))
+		return
+	}
+
+	attrs := replacedAttributes(ctx, req, resp, replaceAttrs...)
+	if len(attrs) == 0 {
+		return
+	}
+	resp.Diagnostics.AddAttributeError(path.Root("deletion_protection"), "Deletion protection enabled",
+		fmt.Sprintf("%s %q has deletion_protection = true, but changing %s forces Terraform to destroy and re-create it. "+
+			"To replace it, set deletion_protection = false and apply that change first.",
+			resourceType, id.ValueString(), strings.Join(attrs, ", ")))
+}
+
+// replacedAttributes returns the sorted attributes whose change forces the
+// replacement of the resource: those added to resp.RequiresReplace by
+// ModifyPlan and those of replaceAttrs whose planned value differs from the
+// prior state. replaceAttrs mirrors the attributes with a RequiresReplace
+// plan modifier, whose replacements ModifyPlan cannot see.
+func replacedAttributes(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse, replaceAttrs ...path.Path) []string {
+	replaced := make(map[string]bool)
+	for _, p := range resp.RequiresReplace {
+		replaced[p.String()] = true
+	}
+	for _, p := range replaceAttrs {
+		var prior, planned attr.Value
+		resp.Diagnostics.Append(req.State.GetAttribute(ctx, p, &prior)...)
+		resp.Diagnostics.Append(resp.Plan.GetAttribute(ctx, p, &planned)...)
+		if resp.Diagnostics.HasError() {
+			return nil
+		}
+		if !prior.Equal(planned) {
+			replaced[p.String()] = true
+		}
+	}
+	attrs := make([]string, 0, len(replaced))
+	for p := range replaced {
+		attrs = append(attrs, p)
+	}
+	slices.Sort(attrs)
+	return attrs
+}
+
+// checkDeletionProtection reports whether a resource may be deleted. When its
+// state has deletion_protection = true it adds an error and returns false.
+func checkDeletionProtection(protected types.Bool, resourceType, id string, diags *diag.Diagnostics) bool {
+	if !protected.ValueBool() {
+		return true
+	}
+	diags.AddAttributeError(path.Root("deletion_protection"), "Deletion protection enabled",
+		fmt.Sprintf("Cannot delete %s %q: deletion_protection is true. Set deletion_protection = false and apply "+
+			"that change first, then destroy the resource.", resourceType, id))
+	return false
+}
+
+// deletionProtectionOnlyChange reports whether an update changes nothing but
+// deletion_protection, so that Update can record the new value without
+// calling the API.
+func deletionProtectionOnlyChange(plan tfsdk.Plan, state tfsdk.State) bool {
+	dp := tftypes.NewAttributePath().WithAttributeName("deletion_protection")
+	strip := func(v tftypes.Value) (tftypes.Value, error) {
+		return tftypes.Transform(v, func(p *tftypes.AttributePath, v tftypes.Value) (tftypes.Value, error) {
+			if p.Equal(dp) {
+				return tftypes.NewValue(tftypes.Bool, nil), nil
+			}
+			return v, nil
+		})
+	}
+	planned, err := strip(plan.Raw)
+	if err != nil {
+		return false
+	}
+	prior, err := strip(state.Raw)
+	return err == nil && planned.Equal(prior) && !plan.Raw.Equal(state.Raw)
+}
@@ -0,0 +1,162 @@
+package provider
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+func TestPlanDeletionProtection(t *testing.T) {
+	ctx := context.Background()
+
+	s := schema.Schema{
+		Attributes: map[string]schema.Attribute{
+			"id":                  schema.StringAttribute{Computed: true},
+			"name":                schema.StringAttribute{Required: true},
+			"engine_id":           schema.StringAttribute{Required: true},
+			"deletion_protection": deletionProtectionAttribute(),
+		},
+	}
+	objType := s.Type().TerraformType(ctx).(tftypes.Object)
+	obj := func(name, engineID string, protected bool) tftypes.Value {
+		return tftypes.NewValue(objType, map[string]tftypes.Value{
+			"id":                  tftypes.NewValue(tftypes.String, "dbaas-1"),
+			"name":                tftypes.NewValue(tftypes.String, name),
+			"engine_id":           tftypes.NewValue(tftypes.String, engineID),
+			"deletion_protection": tftypes.NewValue(tftypes.Bool, protected),
+		})
+	}
+	null := tftypes.NewValue(objType, nil)
+
+	cases := []struct {
+		name            string
+		state, plan     tftypes.Value
+		requiresReplace path.Paths
+		wantErr         bool
+	}{
+		{"create", null, obj("db", "mysql-8.0", true), nil, false},
+		{"in-place update of a protected resource", obj("db", "mysql-8.0", true), obj("db-renamed", "mysql-8.0", true), nil, false},
+		{"replacement of a protected resource", obj("db", "mysql-8.0", true), obj("db", "mysql-8.4", true), nil, true},
+		{"replacement while turning protection off", obj("db", "mysql-8.0", true), obj("db", "mysql-8.4", false), nil, true},
+		{"replacement requested by ModifyPlan", obj("db", "mysql-8.0", true), obj("db", "mysql-8.0", true), path.Paths{path.Root("project_id")}, true},
+		{"replacement of an unprotected resource", obj("db", "mysql-8.0", false), obj("db", "mysql-8.4", false), nil, false},
+		{"destroy of a protected resource", obj("db", "mysql-8.0", true), null, nil, true},
+		{"destroy of an unprotected resource", obj("db", "mysql-8.0", false), null, nil, false},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := resource.ModifyPlanRequest{
+				State: tfsdk.State{Raw: tc.state, Schema: s},
+				Plan:  tfsdk.Plan{Raw: tc.plan, Schema: s},
+			}
+			resp := &resource.ModifyPlanResponse{Plan: tfsdk.Plan{Raw: tc.plan, Schema: s}, RequiresReplace: tc.requiresReplace}
+			planDeletionProtection(ctx, req, resp, "DBaaS", path.Root("engine_id"))
+
+			if got := resp.Diagnostics.HasError(); got != tc.wantErr {
+				t.Fatalf("HasError() = %v, want %v: %v", got, tc.wantErr, resp.Diagnostics)
+			}
+			if tc.wantErr {
+				if d, ok := resp.Diagnostics.Errors()[0].(diag.DiagnosticWithPath); !ok || !d.Path().Equal(path.Root("deletion_protection")) {
+					t.Errorf("error is not on deletion_protection: %v", resp.Diagnostics)
+				}
+			}
+		})
+	}
+}
+
+func TestDeletionProtectionOnlyChange(t *testing.T) {
+	ctx := context.Background()
+	s := schema.Schema{
+		Attributes: map[string]schema.Attribute{
+			"name":                schema.StringAttribute{Required: true},
+			"deletion_protection": deletionProtectionAttribute(),
+		},
+	}
+	objType := s.Type().TerraformType(ctx).(tftypes.Object)
+	obj := func(name string, protected bool) tftypes.Value {
+		return tftypes.NewValue(objType, map[string]tftypes.Value{
+			"name":                tftypes.NewValue(tftypes.String, name),
+			"deletion_protection": tftypes.NewValue(tftypes.Bool, protected),
+		})
+	}
+
+	if !deletionProtectionOnlyChange(tfsdk.Plan{Raw: obj("a", true), Schema: s}, tfsdk.State{Raw: obj("a", false), Schema: s}) {
+		t.Error("toggling deletion_protection alone should not need the API")
+	}
+	if deletionProtectionOnlyChange(tfsdk.Plan{Raw: obj("b", true), Schema: s}, tfsdk.State{Raw: obj("a", false), Schema: s}) {
+		t.Error("a rename together with deletion_protection needs the API")
+	}
+	if deletionProtectionOnlyChange(tfsdk.Plan{Raw: obj("a", true), Schema: s}, tfsdk.State{Raw: obj("a", true), Schema: s}) {
+		t.Error("an update without any change is not a deletion_protection change")
+	}
+}
+
+// protectedResources lists the resources that support deletion_protection.
+var protectedResources = []struct {
+	name string
+	newR func() resource.Resource
+}{
+	{"dbaas", NewDBaaSResource},
+	{"database", NewDatabaseResource},
+	{"blockstorage", NewBlockStorageResource},
+	{"kaas", NewKaaSResource},
+	{"containerregistry", NewContainerRegistryResource},
+	{"kms", NewKMSResource},
+	{"backup", NewBackupResource},
+}
+
+// TestResourceDelete_DeletionProtection verifies that Delete() of a protected
+// resource fails before any API call.
+func TestResourceDelete_DeletionProtection(t *testing.T) {
+	ctx := context.Background()
+
+	for _, tc := range protectedResources {
+		t.Run(tc.name, func(t *testing.T) {
+			res := tc.newR()
+			configureResource(ctx, t, res, guardedClient(t, func(*ArubaCloudClient) {}))
+
+			req, resp := resourceDeleteReq(ctx, t, res)
+			resp.Diagnostics.Append(req.State.SetAttribute(ctx, path.Root("deletion_protection"), types.BoolValue(true))...)
+			res.Delete(ctx, req, resp)
+
+			errs := resp.Diagnostics.Errors()
+			if len(errs) != 1 || errs[0].Summary() != "Deletion protection enabled" {
+				t.Errorf("expected one deletion protection error, got %v", resp.Diagnostics)
+			}
+		})
+	}
+}
+
+// TestResourceUpdate_DeletionProtectionOnly verifies that toggling
+// deletion_protection is recorded in state without any API call.
+func TestResourceUpdate_DeletionProtectionOnly(t *testing.T) {
+	ctx := context.Background()
+
+	for _, tc := range protectedResources {
+		t.Run(tc.name, func(t *testing.T) {
+			res := tc.newR()
+			configureResource(ctx, t, res, guardedClient(t, func(*ArubaCloudClient) {}))
+
+			req, resp := resourceUpdateReq(ctx, t, res)
+			resp.Diagnostics.Append(req.State.SetAttribute(ctx, path.Root("deletion_protection"), types.BoolValue(false))...)
+			resp.Diagnostics.Append(req.Plan.SetAttribute(ctx, path.Root("deletion_protection"), types.BoolValue(true))...)
+			res.Update(ctx, req, resp)
+			if resp.Diagnostics.HasError() {
+				t.Fatalf("Update() reported error: %v", resp.Diagnostics)
+			}
+
+			var protected types.Bool
+			resp.State.GetAttribute(ctx, path.Root("deletion_protection"), &protected)
+			if !protected.ValueBool() {
+				t.Errorf("deletion_protection = %s, want true", protected)
+			}
+		})
+	}
+}
@@ -4,7 +4,6 @@ import (
 	"context"
 	"fmt"
 
-	aruba "github.com/Arubacloud/sdk-go/pkg/aruba"
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
 	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/types"
@@ -93,11 +92,15 @@ func (d *ElasticIPDataSource) Configure(ctx context.Context, req datasource.Conf
 }
 
 func (d *ElasticIPDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	ctx = withCorrelationID(ctx)
 	var data ElasticIPDataSourceModel
 	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
+	if !d.client.checkGuardrails("read", "ElasticIP", data.ProjectId, &resp.Diagnostics) {
+		return
+	}
 
 	projectID := data.ProjectId.ValueString()
 	eipID := data.Id.ValueString()
@@ -107,9 +110,9 @@ func (d *ElasticIPDataSource) Read(ctx context.Context, req datasource.ReadReque
 	}
 
 	eip, err := d.client.Client.FromNetwork().ElasticIPs().Get(ctx,
-		aruba.URI("/projects/"+projectID+"/network/elasticIps/"+eipID))
+		newResourceURI(uriKindElasticIP, projectID, eipID).Ref())
 	if provErr := CheckResponseErr("read", "ElasticIP", err); provErr != nil {
-		resp.Diagnostics.AddError("API Error", provErr.Error())
+		AppendAPIError(&resp.Diagnostics, provErr)
 		return
 	}
 
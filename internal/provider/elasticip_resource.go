@@ -3,10 +3,10 @@ package provider
 import (
 	"context"
 	"fmt"
-	"strings"
 	"time"
 
 	aruba "github.com/Arubacloud/sdk-go/pkg/aruba"
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
 	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
@@ -19,6 +19,7 @@ import (
 )
 
 var _ resource.Resource = &ElasticIPResource{}
+var _ resource.ResourceWithModifyPlan = &ElasticIPResource{}
 var _ resource.ResourceWithImportState = &ElasticIPResource{}
 
 func NewElasticIPResource() resource.Resource {
@@ -30,15 +31,17 @@ type ElasticIPResource struct {
 }
 
 type ElasticIPResourceModel struct {
-	Id            types.String `tfsdk:"id"`
-	Uri           types.String `tfsdk:"uri"`
-	Name          types.String `tfsdk:"name"`
-	Location      types.String `tfsdk:"location"`
-	Tags          types.List   `tfsdk:"tags"`
-	BillingPeriod types.String `tfsdk:"billing_period"`
-	Address       types.String `tfsdk:"address"`
-	ProjectId     types.String `tfsdk:"project_id"`
-	Timeout       types.String `tfsdk:"timeout"`
+	Id            types.String   `tfsdk:"id"`
+	Uri           types.String   `tfsdk:"uri"`
+	Name          types.String   `tfsdk:"name"`
+	Location      types.String   `tfsdk:"location"`
+	Tags          types.List     `tfsdk:"tags"`
+	TagsAll       types.List     `tfsdk:"tags_all"`
+	BillingPeriod types.String   `tfsdk:"billing_period"`
+	Address       types.String   `tfsdk:"address"`
+	ProjectId     types.String   `tfsdk:"project_id"`
+	Timeout       types.String   `tfsdk:"timeout"`
+	Timeouts      timeouts.Value `tfsdk:"timeouts"`
 }
 
 func (r *ElasticIPResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
@@ -64,13 +67,18 @@ func (r *ElasticIPResource) Schema(ctx context.Context, req resource.SchemaReque
 				Required:            true,
 			},
 			"location": schema.StringAttribute{
-				MarkdownDescription: "Region identifier (e.g., `ITBG-Bergamo`). Changing this value forces a new resource.",
-				Required:            true,
-				PlanModifiers:       []planmodifier.String{stringplanmodifier.RequiresReplace()},
+				MarkdownDescription: defaultedDescription("Region identifier (e.g., `ITBG-Bergamo`). Changing this value forces a new resource.", "location"),
+				Optional:            true,
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+					stringplanmodifier.RequiresReplace(),
+				},
 			},
 			"tags": schema.ListAttribute{
 				ElementType: types.StringType, MarkdownDescription: "List of string tags.", Optional: true,
 			},
+			"tags_all": tagsAllAttribute(),
 			"billing_period": schema.StringAttribute{
 				MarkdownDescription: "Billing cycle. Accepted values: `Hour`, `Month`, `Year`.",
 				Optional:            true,
@@ -84,15 +92,23 @@ func (r *ElasticIPResource) Schema(ctx context.Context, req resource.SchemaReque
 				PlanModifiers:       []planmodifier.String{stringplanmodifier.UseStateForUnknown()},
 			},
 			"project_id": schema.StringAttribute{
-				MarkdownDescription: "ID of the project that owns this resource. Changing this value forces a new resource.",
-				Required:            true,
-				PlanModifiers:       []planmodifier.String{stringplanmodifier.RequiresReplace()},
+				MarkdownDescription: defaultedDescription("ID of the project that owns this resource. Changing this value forces a new resource.", "project_id"),
+				Optional:            true,
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+					stringplanmodifier.RequiresReplace(),
+				},
 			},
 			"timeout": schema.StringAttribute{
-				MarkdownDescription: "Per-resource timeout override (e.g. `\"15m\"`, `\"1h\"`). Overrides the provider-level `resource_timeout` for this resource's Create and Delete operations. Uses Go duration syntax.",
+				MarkdownDescription: "Per-resource timeout override (e.g. `\"15m\"`, `\"1h\"`) applied to every operation not set in the `timeouts` block. Overrides the provider-level `resource_timeout`. Uses Go duration syntax. Deprecated — use the `timeouts` block instead.",
+				DeprecationMessage:  "Use the timeouts block, which sets the create, read, update and delete timeouts individually.",
 				Optional:            true,
 			},
 		},
+		Blocks: map[string]schema.Block{
+			"timeouts": timeouts.BlockAll(ctx),
+		},
 	}
 }
 
@@ -108,14 +124,29 @@ func (r *ElasticIPResource) Configure(ctx context.Context, req resource.Configur
 	r.client = client
 }
 
+func (r *ElasticIPResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	r.client.planTagsAll(ctx, req, resp, true)
+	r.client.planProviderDefaults(ctx, req, resp,
+		providerDefault{attr: "project_id", replace: true},
+		providerDefault{attr: "location", replace: true},
+	)
+}
+
 func (r *ElasticIPResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	ctx = withCorrelationID(ctx)
 	var data ElasticIPResourceModel
 	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	timeout := r.client.operationTimeout(ctx, data.Timeouts.Create, data.Timeout, &resp.Diagnostics)
 	if resp.Diagnostics.HasError() {
 		return
 	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	if !r.client.checkGuardrails("create", "ElasticIP", data.ProjectId, &resp.Diagnostics) {
+		return
+	}
 
-	tags := ListToTags(ctx, data.Tags, &resp.Diagnostics)
+	tags := r.client.requestTags(ctx, data.Tags, &data.TagsAll, &resp.Diagnostics)
 	if resp.Diagnostics.HasError() {
 		return
 	}
@@ -128,13 +159,13 @@ func (r *ElasticIPResource) Create(ctx context.Context, req resource.CreateReque
 	eip, err := r.client.Client.FromNetwork().ElasticIPs().Create(ctx,
 		aruba.NewElasticIP().
 			Named(data.Name.ValueString()).
-			InProject(aruba.URI("/projects/"+data.ProjectId.ValueString())).
+			InProject(newResourceURI(uriKindProject, data.ProjectId.ValueString(), "").Ref()).
 			InRegion(aruba.Region(data.Location.ValueString())).
 			BilledBy(aruba.BillingPeriod(billingPeriod)).
 			Tagged(tags...),
 	)
 	if provErr := CheckResponseErr("create", "ElasticIP", err); provErr != nil {
-		resp.Diagnostics.AddError("API Error", provErr.Error())
+		AppendAPIError(&resp.Diagnostics, provErr)
 		return
 	}
 
@@ -146,7 +177,7 @@ func (r *ElasticIPResource) Create(ctx context.Context, req resource.CreateReque
 		return
 	}
 
-	if waitErr := eip.WaitUntilReady(ctx, sdkWaitOptions(effectiveTimeout(data.Timeout, r.client.ResourceTimeout))...); waitErr != nil {
+	if waitErr := eip.WaitUntilReady(ctx, sdkWaitOptions(timeout)...); waitErr != nil {
 		ReportWaitResult(&resp.Diagnostics, waitErr, "ElasticIP", data.Id.ValueString())
 		// Resolve unknown computed fields so Terraform doesn't error with
 		// "Provider returned invalid result object after apply".
@@ -172,21 +203,17 @@ func (r *ElasticIPResource) Create(ctx context.Context, req resource.CreateReque
 }
 
 func eipRef(data *ElasticIPResourceModel) aruba.Ref {
-	if !data.Uri.IsNull() && data.Uri.ValueString() != "" {
-		// The API returns URIs with /network/elasticIPs/ (capital P), but the SDK
-		// path parser expects /network/elasticIps/ (lowercase p). Normalise before
-		// passing to the SDK so Get/Delete work regardless of where the URI came from.
-		uri := strings.ReplaceAll(data.Uri.ValueString(), "/network/elasticIPs/", "/network/elasticIps/")
-		return aruba.URI(uri)
-	}
-	return aruba.URI("/projects/" + data.ProjectId.ValueString() + "/network/elasticIps/" + data.Id.ValueString())
+	// The API returns URIs with elasticIPs (capital P), but the SDK path parser
+	// expects elasticIps (lowercase p). uriRef re-renders stored URIs with the
+	// SDK spelling so Get/Delete work regardless of where the URI came from.
+	return uriRef(data.Uri, newResourceURI(uriKindElasticIP, data.ProjectId.ValueString(), data.Id.ValueString()))
 }
 
 func applyEIPToModel(eip *aruba.ElasticIP, data *ElasticIPResourceModel) {
 	data.Id = types.StringValue(eip.ID())
 	data.Uri = strVal(eip.URI())
 	data.Name = types.StringValue(eip.Name())
-	data.Tags = TagsToListPreserveNull(eip.Tags(), data.Tags)
+	data.Tags, data.TagsAll = flattenTags(eip.Tags(), data.Tags, data.TagsAll)
 	data.BillingPeriod = strVal(string(eip.BillingPeriod()))
 	data.Address = strVal(eip.Address())
 	raw := eip.Raw()
@@ -196,11 +223,18 @@ func applyEIPToModel(eip *aruba.ElasticIP, data *ElasticIPResourceModel) {
 }
 
 func (r *ElasticIPResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	ctx = withCorrelationID(ctx)
 	var data ElasticIPResourceModel
 	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	timeout := r.client.operationTimeout(ctx, data.Timeouts.Read, data.Timeout, &resp.Diagnostics)
 	if resp.Diagnostics.HasError() {
 		return
 	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	if !r.client.checkGuardrails("read", "ElasticIP", data.ProjectId, &resp.Diagnostics) {
+		return
+	}
 	if data.Id.IsNull() || data.Id.ValueString() == "" {
 		resp.State.RemoveResource(ctx)
 		return
@@ -212,7 +246,7 @@ func (r *ElasticIPResource) Read(ctx context.Context, req resource.ReadRequest,
 			resp.State.RemoveResource(ctx)
 			return
 		}
-		resp.Diagnostics.AddError("API Error", provErr.Error())
+		AppendAPIError(&resp.Diagnostics, provErr)
 		return
 	}
 
@@ -221,13 +255,13 @@ func (r *ElasticIPResource) Read(ctx context.Context, req resource.ReadRequest,
 		resp.Diagnostics.AddWarning("Resource in Failed State",
 			fmt.Sprintf("ElasticIP %q is in a terminal failure state (%s).", data.Id.ValueString(), st))
 	} else if IsCreatingState(st) {
-		if waitErr := eip.WaitUntilReady(ctx, sdkWaitOptions(r.client.ResourceTimeout)...); waitErr != nil {
+		if waitErr := eip.WaitUntilReady(ctx, sdkWaitOptions(timeout)...); waitErr != nil {
 			ReportWaitResult(&resp.Diagnostics, waitErr, "ElasticIP", data.Id.ValueString())
 			return
 		}
 		eip, err = r.client.Client.FromNetwork().ElasticIPs().Get(ctx, eipRef(&data))
 		if provErr := CheckResponseErr("read", "ElasticIP", err); provErr != nil {
-			resp.Diagnostics.AddError("API Error", provErr.Error())
+			AppendAPIError(&resp.Diagnostics, provErr)
 			return
 		}
 	}
@@ -239,22 +273,29 @@ func (r *ElasticIPResource) Read(ctx context.Context, req resource.ReadRequest,
 }
 
 func (r *ElasticIPResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	ctx = withCorrelationID(ctx)
 	var data ElasticIPResourceModel
 	var state ElasticIPResourceModel
 	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
 	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	timeout := r.client.operationTimeout(ctx, data.Timeouts.Update, data.Timeout, &resp.Diagnostics)
 	if resp.Diagnostics.HasError() {
 		return
 	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	if !r.client.checkGuardrails("update", "ElasticIP", data.ProjectId, &resp.Diagnostics) {
+		return
+	}
 
-	tags := ListToTags(ctx, data.Tags, &resp.Diagnostics)
+	tags := r.client.requestTags(ctx, data.Tags, &data.TagsAll, &resp.Diagnostics)
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
 	eip, err := r.client.Client.FromNetwork().ElasticIPs().Get(ctx, eipRef(&state))
 	if provErr := CheckResponseErr("read", "ElasticIP", err); provErr != nil {
-		resp.Diagnostics.AddError("API Error", provErr.Error())
+		AppendAPIError(&resp.Diagnostics, provErr)
 		return
 	}
 
@@ -270,7 +311,7 @@ func (r *ElasticIPResource) Update(ctx context.Context, req resource.UpdateReque
 
 	updated, err := r.client.Client.FromNetwork().ElasticIPs().Update(ctx, eip)
 	if provErr := CheckResponseErr("update", "ElasticIP", err); provErr != nil {
-		resp.Diagnostics.AddError("API Error", provErr.Error())
+		AppendAPIError(&resp.Diagnostics, provErr)
 		return
 	}
 
@@ -280,18 +321,25 @@ func (r *ElasticIPResource) Update(ctx context.Context, req resource.UpdateReque
 	data.Location = state.Location
 	data.Address = state.Address
 	data.Name = types.StringValue(updated.Name())
-	data.Tags = TagsToListPreserveNull(updated.Tags(), data.Tags)
+	data.Tags, data.TagsAll = flattenTags(updated.Tags(), data.Tags, data.TagsAll)
 	data.BillingPeriod = strVal(string(updated.BillingPeriod()))
 
 	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
 }
 
 func (r *ElasticIPResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	ctx = withCorrelationID(ctx)
 	var data ElasticIPResourceModel
 	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	timeout := r.client.operationTimeout(ctx, data.Timeouts.Delete, data.Timeout, &resp.Diagnostics)
 	if resp.Diagnostics.HasError() {
 		return
 	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	if !r.client.checkGuardrails("delete", "ElasticIP", data.ProjectId, &resp.Diagnostics) {
+		return
+	}
 
 	ref := eipRef(&data)
 	eipID := data.Id.ValueString()
@@ -311,12 +359,12 @@ func (r *ElasticIPResource) Delete(ctx context.Context, req resource.DeleteReque
 	err := DeleteResourceWithRetry(ctx, func() error {
 		delErr := r.client.Client.FromNetwork().ElasticIPs().Delete(ctx, ref)
 		return CheckResponseErrAsError("delete", "ElasticIP", delErr)
-	}, "ElasticIP", eipID, effectiveTimeout(data.Timeout, r.client.ResourceTimeout), deletionChecker)
+	}, "ElasticIP", eipID, timeout, deletionChecker)
 	if err != nil {
 		resp.Diagnostics.AddError("Error deleting ElasticIP", err.Error())
 		return
 	}
-	if waitErr := WaitForResourceDeleted(ctx, deletionChecker, "ElasticIP", eipID, remainingTimeout(deleteStart, effectiveTimeout(data.Timeout, r.client.ResourceTimeout))); waitErr != nil {
+	if waitErr := WaitForResourceDeleted(ctx, deletionChecker, "ElasticIP", eipID, remainingTimeout(deleteStart, timeout)); waitErr != nil {
 		resp.Diagnostics.AddError("Error waiting for ElasticIP deletion", waitErr.Error())
 		return
 	}
@@ -2,6 +2,7 @@ package provider
 
 import (
 	"context"
+	"strings"
 
 	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
@@ -11,13 +12,28 @@ import (
 // failure is visible under TF_LOG=DEBUG, then appends the error to diags as a
 // user-facing diagnostic. `fields` should carry identifying IDs (project_id,
 // vpc_id, etc.) for log correlation — err.Error() is added automatically under
-// the "error" key.
+// the "error" key. Validation errors of a *ProviderError that map to attributes
+// are appended as attribute errors, as by AppendAPIError.
+//
+// The diagnostic carries the operation's correlation ID and the request ID
+// returned by the API, if any, for Aruba support.
 func LogAndAppendAPIError(ctx context.Context, diags *diag.Diagnostics, summary string, err error, fields map[string]any) {
-	logFields := make(map[string]any, len(fields)+1)
+	logFields := make(map[string]any, len(fields)+2)
 	for k, v := range fields {
 		logFields[k] = v
 	}
 	logFields["error"] = err.Error()
+	provErr, isProvErr := asProviderError(err)
+	if isProvErr && provErr.ServerRequestID != "" {
+		logFields["server_request_id"] = provErr.ServerRequestID
+	}
 	tflog.Error(ctx, summary, logFields)
-	diags.AddError(summary, err.Error())
+	if isProvErr && appendAttributeErrors(diags, summary, provErr) {
+		return
+	}
+	detail := err.Error()
+	if id := correlationID(ctx); id != "" && !strings.Contains(detail, id) {
+		detail += " (request_id: " + id + ")"
+	}
+	diags.AddError(summary, detail)
 }
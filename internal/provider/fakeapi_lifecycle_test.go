@@ -0,0 +1,244 @@
+package provider
+
+import (
+	"context"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Arubacloud/terraform-provider-arubacloud/internal/cassette"
+	"github.com/Arubacloud/terraform-provider-arubacloud/internal/fakeapi"
+	providerframe "github.com/hashicorp/terraform-plugin-framework/provider"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+// Paths of the database built by the request helpers in the fake API: every
+// string attribute is "test-<name>". Create names the database after its
+// name attribute; the other requests hold the id attribute.
+const (
+	fakeDatabasePath   = "/projects/test-project_id/providers/Aruba.Database/dbaas/test-dbaas_id/databases/test-name"
+	fakeDatabaseIDPath = "/projects/test-project_id/providers/Aruba.Database/dbaas/test-dbaas_id/databases/test-id"
+)
+
+// seedDatabaseParents seeds the project and DBaaS cluster the database of
+// resourceCreateReq belongs to.
+func seedDatabaseParents(t *testing.T, srv *fakeapi.Server) {
+	t.Helper()
+	for _, p := range []string{"/projects/test-project_id", "/projects/test-project_id/providers/Aruba.Database/dbaas/test-dbaas_id"} {
+		if err := srv.Seed(p, map[string]any{"metadata": map[string]any{"name": "fixture"}}); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+// TestFakeAPI_DatabaseLifecycle runs Create, Read and Delete of a database
+// against the fake API, then a Read after the database is gone.
+func TestFakeAPI_DatabaseLifecycle(t *testing.T) {
+	ctx := context.Background()
+	srv, client := newFakeArubaClient(t, fakeapi.WithActivateAfter(2))
+	seedDatabaseParents(t, srv)
+
+	res := NewDatabaseResource()
+	configureResource(ctx, t, res, client)
+
+	createReq, createResp := resourceCreateReq(ctx, t, res)
+	res.Create(ctx, createReq, createResp)
+	if createResp.Diagnostics.HasError() {
+		t.Fatalf("Create() reported error: %v", createResp.Diagnostics)
+	}
+	if body, ok := srv.Get(fakeDatabasePath); !ok || body["status"].(map[string]any)["state"] != fakeapi.StateActive {
+		t.Fatalf("database after Create() = %v, %v; want it Active", body, ok)
+	}
+
+	readResp := &resource.ReadResponse{State: createResp.State}
+	res.Read(ctx, resource.ReadRequest{State: createResp.State}, readResp)
+	if readResp.Diagnostics.HasError() || readResp.State.Raw.IsNull() {
+		t.Fatalf("Read() = %v, state null: %v", readResp.Diagnostics, readResp.State.Raw.IsNull())
+	}
+
+	deleteResp := &resource.DeleteResponse{State: readResp.State}
+	res.Delete(ctx, resource.DeleteRequest{State: readResp.State}, deleteResp)
+	if deleteResp.Diagnostics.HasError() {
+		t.Fatalf("Delete() reported error: %v", deleteResp.Diagnostics)
+	}
+	if _, ok := srv.Get(fakeDatabasePath); ok {
+		t.Error("database still exists after Delete()")
+	}
+
+	// A refresh once the database is gone drops it from state.
+	readResp = &resource.ReadResponse{State: createResp.State}
+	res.Read(ctx, resource.ReadRequest{State: createResp.State}, readResp)
+	if readResp.Diagnostics.HasError() || !readResp.State.Raw.IsNull() {
+		t.Errorf("Read() of a deleted database = %v, state null: %v", readResp.Diagnostics, readResp.State.Raw.IsNull())
+	}
+}
+
+// TestFakeAPI_DatabaseDeleteConflict verifies that a database with a grant
+// nested under it is not deleted, and is once the grant is gone.
+func TestFakeAPI_DatabaseDeleteConflict(t *testing.T) {
+	ctx := context.Background()
+	srv, client := newFakeArubaClient(t, fakeapi.WithActivateAfter(0))
+	client.ResourceTimeout = 200 * time.Millisecond
+	seedDatabaseParents(t, srv)
+	_ = srv.Seed(fakeDatabaseIDPath, map[string]any{"name": "test-id"})
+	_ = srv.Seed(fakeDatabaseIDPath+"/grants/test-user", map[string]any{"metadata": map[string]any{"name": "test-user"}})
+
+	res := NewDatabaseResource()
+	configureResource(ctx, t, res, client)
+
+	req, resp := resourceDeleteReq(ctx, t, res)
+	res.Delete(ctx, req, resp)
+	if !resp.Diagnostics.HasError() {
+		t.Fatal("Delete() of a database with a grant succeeded")
+	}
+	if _, ok := srv.Get(fakeDatabaseIDPath); !ok {
+		t.Fatal("database deleted despite the conflict")
+	}
+
+	srv.Remove(fakeDatabaseIDPath + "/grants/test-user")
+	req, resp = resourceDeleteReq(ctx, t, res)
+	res.Delete(ctx, req, resp)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("Delete() after removing the grant reported error: %v", resp.Diagnostics)
+	}
+}
+
+// TestFakeAPI_ReadRidesOutFaults verifies that reads survive a 5xx response,
+// a dropped connection and throttling injected into the fake API.
+func TestFakeAPI_ReadRidesOutFaults(t *testing.T) {
+	ctx := context.Background()
+	srv, client := newFakeArubaClient(t)
+	seedDatabaseParents(t, srv)
+	_ = srv.Seed(fakeDatabaseIDPath, map[string]any{"name": "test-id"})
+
+	res := NewDatabaseResource()
+	configureResource(ctx, t, res, client)
+
+	faults := []fakeapi.Fault{
+		{Status: http.StatusBadGateway},
+		{EOF: true},
+		{Status: http.StatusTooManyRequests, RetryAfter: "1"},
+		{Latency: 20 * time.Millisecond},
+	}
+	for _, fault := range faults {
+		fault.Method, fault.Path, fault.Times = http.MethodGet, "/databases/", 1
+		srv.Inject(fault)
+
+		req, resp := resourceReadReq(ctx, t, res)
+		res.Read(ctx, req, resp)
+		if resp.Diagnostics.HasError() || resp.State.Raw.IsNull() {
+			t.Errorf("Read() with fault %+v = %v", fault, resp.Diagnostics)
+		}
+	}
+
+	var gets int
+	for _, r := range srv.Requests() {
+		if strings.HasPrefix(r, "GET ") && strings.HasSuffix(r, "/databases/test-id") {
+			gets++
+		}
+	}
+	if gets < len(faults)+3 {
+		t.Errorf("database GETs = %d, want each failed attempt retried", gets)
+	}
+}
+
+// createDatabaseThrough configures p against srv and creates the database of
+// resourceCreateReq with the resulting client.
+func createDatabaseThrough(t *testing.T, p *ArubaCloudProvider, srv *fakeapi.Server) *resource.CreateResponse {
+	t.Helper()
+	ctx := context.Background()
+	config := buildProviderConfig(t, p, map[string]tftypes.Value{
+		"client_id":        tftypes.NewValue(tftypes.String, "test-key"),
+		"client_secret":    tftypes.NewValue(tftypes.String, "test-secret"),
+		"base_url":         tftypes.NewValue(tftypes.String, srv.URL),
+		"token_issuer_url": tftypes.NewValue(tftypes.String, srv.TokenURL()),
+	})
+	configureResp := &providerframe.ConfigureResponse{}
+	p.Configure(ctx, providerframe.ConfigureRequest{Config: config}, configureResp)
+	if configureResp.Diagnostics.HasError() {
+		t.Fatalf("unexpected error from Configure(): %v", configureResp.Diagnostics)
+	}
+	client, ok := configureResp.ResourceData.(*ArubaCloudClient)
+	if !ok {
+		t.Fatalf("ResourceData is %T, want *ArubaCloudClient", configureResp.ResourceData)
+	}
+
+	res := NewDatabaseResource()
+	configureResource(ctx, t, res, client)
+	req, resp := resourceCreateReq(ctx, t, res)
+	res.Create(ctx, req, resp)
+	return resp
+}
+
+// TestFakeAPI_ReplayedCreateSkipsWaits records a database create against the
+// fake API, with a transient 400 on the first POST and two reads in
+// InCreation, and replays it with WithReplayWaits. The replay keeps the
+// default intervals, which would retry the POST after 5s and poll after
+// another 5s, so it only finishes in time because the option shortens them.
+func TestFakeAPI_ReplayedCreateSkipsWaits(t *testing.T) {
+	srv := fakeapi.New(fakeapi.WithActivateAfter(2))
+	t.Cleanup(srv.Close)
+	seedDatabaseParents(t, srv)
+	srv.Inject(fakeapi.Fault{Method: http.MethodPost, Path: "/databases", Status: http.StatusBadRequest, Times: 1})
+
+	dir := t.TempDir()
+	recorded, replayed := filepath.Join(dir, "record.jsonl"), filepath.Join(dir, "replay.jsonl")
+	rec, err := cassette.Load(recorded, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	oldActivePoll, oldCreateRetry := waitForActivePollInterval, createRetryBaseWait
+	waitForActivePollInterval, createRetryBaseWait = time.Millisecond, time.Millisecond
+	p := newTestProvider(t)
+	p.wrapTransport = func(base http.RoundTripper) (http.RoundTripper, error) {
+		return rec.Transport(base), nil
+	}
+	resp := createDatabaseThrough(t, p, srv)
+	waitForActivePollInterval, createRetryBaseWait = oldActivePoll, oldCreateRetry
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("recorded Create() reported error: %v", resp.Diagnostics)
+	}
+	var posts int
+	for _, r := range srv.Requests() {
+		if r == "POST "+strings.TrimSuffix(fakeDatabasePath, "/test-name") {
+			posts++
+		}
+	}
+	if posts != 2 {
+		t.Fatalf("recorded %d database POSTs, want the transient 400 and its retry", posts)
+	}
+	srv.Close()
+
+	data, err := os.ReadFile(recorded)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(replayed, data, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	play, err := cassette.Load(replayed, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { replayDelay.Store(0) })
+	p, ok := NewWithTransport("test", func(http.RoundTripper) (http.RoundTripper, error) {
+		return play.Transport(nil), nil
+	}, WithReplayWaits(time.Millisecond))().(*ArubaCloudProvider)
+	if !ok {
+		t.Fatal("NewWithTransport() did not return *ArubaCloudProvider")
+	}
+
+	start := time.Now()
+	resp = createDatabaseThrough(t, p, srv)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("replayed Create() reported error: %v", resp.Diagnostics)
+	}
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Errorf("replayed Create() took %v, want the waits shortened", elapsed)
+	}
+}
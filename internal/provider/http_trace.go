@@ -0,0 +1,298 @@
+package provider
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// harTrace writes the HTTP exchanges of the SDK to a HAR 1.2 file.
+//
+// The file is a valid HAR document after every entry: entries are written
+// one per line and each write replaces the closing brackets, so a trace can
+// be attached to a support ticket even while Terraform is still running.
+// An existing trace is appended to, so the plan and apply of one pipeline
+// end up in one file.
+type harTrace struct {
+	redact *redactor
+
+	mu      sync.Mutex
+	file    *os.File
+	entries int
+	// end is the offset of the closing brackets.
+	end int64
+}
+
+const harTrailer = "\n]}}\n"
+
+var (
+	harTracesMu sync.Mutex
+	harTraces   = map[string]*harTrace{}
+)
+
+// openHARTrace returns the trace writing to path, shared by every provider
+// instance of the process, creating the file with 0600 permissions. A
+// non-empty file that is not a complete HAR trace written by the provider,
+// such as one cut off by a crash, is left untouched and reported as an error.
+func openHARTrace(path, version string, redactFields []string) (*harTrace, error) {
+	path, err := expandHome(path)
+	if err != nil {
+		return nil, err
+	}
+	if abs, err := filepath.Abs(path); err == nil {
+		path = abs
+	}
+	harTracesMu.Lock()
+	defer harTracesMu.Unlock()
+	if t, ok := harTraces[path]; ok {
+		return t, nil
+	}
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_RDWR, 0o600)
+	if err != nil {
+		return nil, fmt.Errorf("cannot open HTTP trace file: %w", err)
+	}
+	t := &harTrace{redact: newRedactor(redactFields), file: f}
+	if err := t.resume(version); err != nil {
+		_ = f.Close()
+		return nil, fmt.Errorf("cannot use HTTP trace file: %w", err)
+	}
+	harTraces[path] = t
+	return t, nil
+}
+
+// resume positions t after the last entry of the trace in its file, or
+// starts a new trace in an empty file.
+func (t *harTrace) resume(version string) error {
+	data, err := io.ReadAll(t.file)
+	if err != nil {
+		return err
+	}
+	var existing struct {
+		Log struct {
+			Version string            `json:"version"`
+			Entries []json.RawMessage `json:"entries"`
+		} `json:"log"`
+	}
+	if bytes.HasSuffix(data, []byte(harTrailer)) && json.Unmarshal(data, &existing) == nil && existing.Log.Version == "1.2" {
+		t.entries = len(existing.Log.Entries)
+		t.end = int64(len(data) - len(harTrailer))
+		return nil
+	}
+	if len(data) > 0 {
+		return fmt.Errorf("%s is not a complete HAR trace written by the provider; delete it or choose another file", t.file.Name())
+	}
+	creator, err := json.Marshal(map[string]string{"name": "terraform-provider-arubacloud", "version": version})
+	if err != nil {
+		return err
+	}
+	header := `{"log":{"version":"1.2","creator":` + string(creator) + `,"entries":[`
+	if _, err := t.file.WriteAt([]byte(header+harTrailer), 0); err != nil {
+		return err
+	}
+	t.end = int64(len(header))
+	return nil
+}
+
+// add writes entry to the trace.
+func (t *harTrace) add(entry harEntry) error {
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	sep := "\n"
+	if t.entries > 0 {
+		sep = ",\n"
+	}
+	chunk := append([]byte(sep), line...)
+	if _, err := t.file.WriteAt(append(chunk, harTrailer...), t.end); err != nil {
+		return err
+	}
+	t.end += int64(len(chunk))
+	t.entries++
+	return nil
+}
+
+// transport returns a RoundTripper that sends requests with base and traces
+// every exchange, including failed ones.
+func (t *harTrace) transport(base http.RoundTripper) http.RoundTripper {
+	return &harTransport{base: base, trace: t}
+}
+
+type harTransport struct {
+	base  http.RoundTripper
+	trace *harTrace
+}
+
+func (h *harTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	reqBody, err := readRequestBody(req)
+	if err != nil {
+		return nil, err
+	}
+	start := timeNow()
+	resp, err := h.base.RoundTrip(req)
+	var respBody []byte
+	if err == nil {
+		respBody, err = io.ReadAll(resp.Body)
+		_ = resp.Body.Close()
+		resp.Body = io.NopCloser(bytes.NewReader(respBody))
+		if err != nil {
+			resp = nil
+		}
+	}
+	entry := h.trace.entry(req, reqBody, resp, respBody, err, start, timeNow().Sub(start))
+	// Tracing is a troubleshooting aid and never fails a request.
+	if traceErr := h.trace.add(entry); traceErr != nil {
+		tflog.Warn(req.Context(), "Cannot write to the HTTP trace file", map[string]interface{}{"error": traceErr.Error()})
+	}
+	return resp, err
+}
+
+// harEntry is an entry of a HAR 1.2 log.
+type harEntry struct {
+	StartedDateTime string      `json:"startedDateTime"`
+	Time            float64     `json:"time"`
+	Request         harRequest  `json:"request"`
+	Response        harResponse `json:"response"`
+	Cache           struct{}    `json:"cache"`
+	Timings         harTimings  `json:"timings"`
+	// Error is the transport error of a request that got no response.
+	Error string `json:"_error,omitempty"`
+}
+
+type harRequest struct {
+	Method      string         `json:"method"`
+	URL         string         `json:"url"`
+	HTTPVersion string         `json:"httpVersion"`
+	Cookies     []harNameValue `json:"cookies"`
+	Headers     []harNameValue `json:"headers"`
+	QueryString []harNameValue `json:"queryString"`
+	PostData    *harPostData   `json:"postData,omitempty"`
+	HeadersSize int            `json:"headersSize"`
+	BodySize    int            `json:"bodySize"`
+}
+
+type harResponse struct {
+	Status      int            `json:"status"`
+	StatusText  string         `json:"statusText"`
+	HTTPVersion string         `json:"httpVersion"`
+	Cookies     []harNameValue `json:"cookies"`
+	Headers     []harNameValue `json:"headers"`
+	Content     harContent     `json:"content"`
+	RedirectURL string         `json:"redirectURL"`
+	HeadersSize int            `json:"headersSize"`
+	BodySize    int            `json:"bodySize"`
+}
+
+type harNameValue struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+type harPostData struct {
+	MimeType string `json:"mimeType"`
+	Text     string `json:"text"`
+}
+
+type harContent struct {
+	Size     int    `json:"size"`
+	MimeType string `json:"mimeType"`
+	Text     string `json:"text,omitempty"`
+	Comment  string `json:"comment,omitempty"`
+}
+
+type harTimings struct {
+	Send    float64 `json:"send"`
+	Wait    float64 `json:"wait"`
+	Receive float64 `json:"receive"`
+}
+
+// entry builds the redacted HAR entry of one exchange.
+func (t *harTrace) entry(req *http.Request, reqBody []byte, resp *http.Response, respBody []byte, err error, start time.Time, elapsed time.Duration) harEntry {
+	ms := float64(elapsed) / float64(time.Millisecond)
+	e := harEntry{
+		StartedDateTime: start.UTC().Format(time.RFC3339Nano),
+		Time:            ms,
+		Request: harRequest{
+			Method:      req.Method,
+			URL:         t.redact.url(req.URL),
+			HTTPVersion: "HTTP/1.1",
+			Cookies:     []harNameValue{},
+			Headers:     harHeaders(t.redact.header(req.Header)),
+			QueryString: []harNameValue{},
+			HeadersSize: -1,
+			BodySize:    len(reqBody),
+		},
+		Timings: harTimings{Wait: ms},
+	}
+	query := t.redact.values(req.URL.Query())
+	for _, name := range sortedKeys(query) {
+		for _, v := range query[name] {
+			e.Request.QueryString = append(e.Request.QueryString, harNameValue{Name: name, Value: v})
+		}
+	}
+	if len(reqBody) > 0 {
+		text, ok := t.redact.body(req.Header.Get("Content-Type"), reqBody)
+		if !ok {
+			text = "(binary body omitted)"
+		}
+		e.Request.PostData = &harPostData{MimeType: req.Header.Get("Content-Type"), Text: text}
+	}
+
+	e.Response = harResponse{Cookies: []harNameValue{}, Headers: []harNameValue{}, HeadersSize: -1, BodySize: -1}
+	if err != nil || resp == nil {
+		if err == nil {
+			err = errors.New("no response")
+		}
+		e.Error = err.Error()
+		e.Response.Content.Comment = "no response: " + err.Error()
+		return e
+	}
+	e.Response.Status = resp.StatusCode
+	e.Response.StatusText = http.StatusText(resp.StatusCode)
+	if resp.Proto != "" {
+		e.Response.HTTPVersion = resp.Proto
+		e.Request.HTTPVersion = resp.Proto
+	}
+	e.Response.Headers = harHeaders(t.redact.header(resp.Header))
+	e.Response.RedirectURL = resp.Header.Get("Location")
+	e.Response.BodySize = len(respBody)
+	e.Response.Content = harContent{Size: len(respBody), MimeType: resp.Header.Get("Content-Type")}
+	if text, ok := t.redact.body(resp.Header.Get("Content-Type"), respBody); ok {
+		e.Response.Content.Text = text
+	} else {
+		e.Response.Content.Comment = "binary body omitted"
+	}
+	return e
+}
+
+func harHeaders(h http.Header) []harNameValue {
+	out := []harNameValue{}
+	for _, name := range sortedKeys(h) {
+		for _, v := range h[name] {
+			out = append(out, harNameValue{Name: name, Value: v})
+		}
+	}
+	return out
+}
+
+func sortedKeys(m map[string][]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,222 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	providerframe "github.com/hashicorp/terraform-plugin-framework/provider"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+// harFile is the part of a HAR file the tests check.
+type harFile struct {
+	Log struct {
+		Version string `json:"version"`
+		Creator struct {
+			Name    string `json:"name"`
+			Version string `json:"version"`
+		} `json:"creator"`
+		Entries []harEntry `json:"entries"`
+	} `json:"log"`
+}
+
+// roundTripFunc adapts a function to http.RoundTripper.
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) { return f(req) }
+
+func readHAR(t *testing.T, path string) harFile {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var har harFile
+	if err := json.Unmarshal(data, &har); err != nil {
+		t.Fatalf("trace is not valid JSON: %v\n%s", err, data)
+	}
+	return har
+}
+
+// TestHTTPTrace_WritesRedactedHAR verifies that exchanges are written as a
+// HAR 1.2 log with credentials and secret fields redacted.
+func TestHTTPTrace_WritesRedactedHAR(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write(body)
+	}))
+	defer srv.Close()
+	path := filepath.Join(t.TempDir(), "trace.har")
+	trace, err := openHARTrace(path, "1.2.3", defaultRedactFields)
+	if err != nil {
+		t.Fatal(err)
+	}
+	client := &http.Client{Transport: trace.transport(http.DefaultTransport)}
+
+	req, _ := http.NewRequest(http.MethodPost, srv.URL+"/projects/p1/providers/Aruba.Database/dbaas/d1/users?api=1",
+		strings.NewReader(`{"username":"app","password":"Sup3r-secret"}`))
+	req.Header.Set("Authorization", "Bearer eyJhbGciOi")
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := client.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if string(body) != `{"username":"app","password":"Sup3r-secret"}` {
+		t.Errorf("response body seen by the caller = %s, want it unredacted", body)
+	}
+
+	data, _ := os.ReadFile(path)
+	if strings.Contains(string(data), "Sup3r-secret") || strings.Contains(string(data), "eyJhbGciOi") {
+		t.Fatalf("trace contains secrets:\n%s", data)
+	}
+	har := readHAR(t, path)
+	if har.Log.Version != "1.2" || har.Log.Creator.Name != "terraform-provider-arubacloud" || har.Log.Creator.Version != "1.2.3" {
+		t.Errorf("log = %+v, want a HAR 1.2 log created by the provider", har.Log)
+	}
+	if len(har.Log.Entries) != 1 {
+		t.Fatalf("trace has %d entries, want 1", len(har.Log.Entries))
+	}
+	e := har.Log.Entries[0]
+	if e.Request.Method != http.MethodPost || e.Response.Status != http.StatusCreated || e.StartedDateTime == "" {
+		t.Errorf("entry = %+v", e)
+	}
+	if e.Request.PostData == nil || !strings.Contains(e.Request.PostData.Text, `"username":"app"`) {
+		t.Errorf("postData = %+v, want the redacted body", e.Request.PostData)
+	}
+	if !strings.Contains(e.Response.Content.Text, `"password":"REDACTED"`) {
+		t.Errorf("response content = %s, want the password redacted", e.Response.Content.Text)
+	}
+	if len(e.Request.QueryString) != 1 || e.Request.QueryString[0].Name != "api" {
+		t.Errorf("queryString = %+v", e.Request.QueryString)
+	}
+}
+
+// TestHTTPTrace_FailedExchange verifies that an exchange without response is
+// traced with its error.
+func TestHTTPTrace_FailedExchange(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "trace.har")
+	trace, err := openHARTrace(path, "test", defaultRedactFields)
+	if err != nil {
+		t.Fatal(err)
+	}
+	failing := roundTripFunc(func(*http.Request) (*http.Response, error) { return nil, io.ErrUnexpectedEOF })
+	client := &http.Client{Transport: trace.transport(failing)}
+	if _, err := client.Get("https://api.example.com/projects"); err == nil {
+		t.Fatal("request through a failing transport succeeded")
+	}
+	har := readHAR(t, path)
+	if len(har.Log.Entries) != 1 || !strings.Contains(har.Log.Entries[0].Error, "unexpected EOF") {
+		t.Errorf("entries = %+v, want one with the transport error", har.Log.Entries)
+	}
+}
+
+// TestHTTPTrace_AppendsAcrossRuns verifies that a later run appends to the
+// trace of an earlier one, and that concurrent exchanges leave a valid file.
+func TestHTTPTrace_AppendsAcrossRuns(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer srv.Close()
+	path := filepath.Join(t.TempDir(), "trace.har")
+
+	send := func(n int) {
+		trace, err := openHARTrace(path, "test", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		client := &http.Client{Transport: trace.transport(http.DefaultTransport)}
+		var wg sync.WaitGroup
+		for i := 0; i < n; i++ {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				resp, err := client.Get(fmt.Sprintf("%s/projects/p%d", srv.URL, i))
+				if err != nil {
+					t.Error(err)
+					return
+				}
+				resp.Body.Close()
+			}(i)
+		}
+		wg.Wait()
+		// A new provider process opens the file afresh.
+		harTracesMu.Lock()
+		delete(harTraces, trace.file.Name())
+		harTracesMu.Unlock()
+		_ = trace.file.Close()
+	}
+	send(20)
+	send(5)
+	if har := readHAR(t, path); len(har.Log.Entries) != 25 {
+		t.Errorf("trace has %d entries, want 25", len(har.Log.Entries))
+	}
+}
+
+// TestHTTPTrace_KeepsOtherFiles verifies that a file that is not a complete
+// trace, whether it belongs to something else or was cut off by a crash, is
+// reported and left as it is.
+func TestHTTPTrace_KeepsOtherFiles(t *testing.T) {
+	for name, content := range map[string]string{
+		"other file":    "important notes\n",
+		"cut off trace": `{"log":{"version":"1.2","creator":{"name":"terraform-provider-arubacloud","version":"test"},"entries":[` + "\n" + `{"startedDateTime":"2026-01-02T15:04:05Z"`,
+	} {
+		t.Run(name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "trace.har")
+			if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+				t.Fatal(err)
+			}
+			if _, err := openHARTrace(path, "test", nil); err == nil || !strings.Contains(err.Error(), "not a complete HAR trace") {
+				t.Errorf("openHARTrace() error = %v, want the file reported", err)
+			}
+			if got, _ := os.ReadFile(path); string(got) != content {
+				t.Errorf("file was modified: %q", got)
+			}
+		})
+	}
+}
+
+// TestProviderConfigure_HTTPTraceFile verifies that Configure() opens the
+// trace file and reports one it cannot open.
+func TestProviderConfigure_HTTPTraceFile(t *testing.T) {
+	ctx := context.Background()
+	p := newTestProvider(t)
+	creds := map[string]tftypes.Value{
+		"client_id":     tftypes.NewValue(tftypes.String, "test-key"),
+		"client_secret": tftypes.NewValue(tftypes.String, "test-secret"),
+		"http_trace_redact": tftypes.NewValue(tftypes.List{ElementType: tftypes.String}, []tftypes.Value{
+			tftypes.NewValue(tftypes.String, "password"),
+		}),
+	}
+
+	file := filepath.Join(t.TempDir(), "trace.har")
+	t.Setenv("ARUBACLOUD_HTTP_TRACE_FILE", file)
+	resp := &providerframe.ConfigureResponse{}
+	p.Configure(ctx, providerframe.ConfigureRequest{Config: buildProviderConfig(t, p, creds)}, resp)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("unexpected error from Configure(): %v", resp.Diagnostics)
+	}
+	if har := readHAR(t, file); har.Log.Version != "1.2" {
+		t.Errorf("trace version = %q, want 1.2", har.Log.Version)
+	}
+
+	creds["http_trace_file"] = tftypes.NewValue(tftypes.String, filepath.Join(t.TempDir(), "missing", "trace.har"))
+	resp = &providerframe.ConfigureResponse{}
+	p.Configure(ctx, providerframe.ConfigureRequest{Config: buildProviderConfig(t, p, creds)}, resp)
+	errs := resp.Diagnostics.Errors()
+	if len(errs) != 1 || errs[0].Summary() != "Invalid http_trace_file" {
+		t.Errorf("expected one Invalid http_trace_file error, got %v", resp.Diagnostics)
+	}
+}
@@ -0,0 +1,244 @@
+package provider
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// apiTransport is the RoundTripper behind every request the SDK sends, for
+// both the API and the token issuer. It enforces the shared requestLimiter
+// and the throttleGate: a request first waits out any Retry-After the API
+// has asked for, then takes a limiter slot.
+//
+// A 429 response with a short Retry-After is replayed here, so that every
+// SDK call — including those made outside the provider's retry helpers —
+// honours it. Longer delays, and requests that cannot be replayed, are
+// returned to the caller with the delay recorded by annotateRetryAfter, and
+// the retry helpers decide against the operation's remaining timeout.
+//
+// Reads and updates that fail with a technical error are retried according
+// to the provider's retry policy, so every resource and data source shares
+// one implementation. A read or update throttled without a Retry-After
+// header backs off like a technical failure.
+//
+// When the provider authenticates with an access token, apiTransport answers
+// the SDK's token requests itself and sends the token on every API request.
+// Otherwise token requests may be answered from the on-disk token cache.
+//
+// With an audit log, every attempt of a create, update or delete request is
+// recorded in it.
+//
+// Every request carries the X-Request-Id correlation ID of its operation.
+// Error responses are annotated with it and with the server's request ID,
+// and transport failures carry it as a *requestError, so that
+// CheckResponseErr can include both in its diagnostics.
+type apiTransport struct {
+	base       http.RoundTripper
+	limiter    *requestLimiter
+	throttle   *throttleGate
+	retry      retryPolicy
+	token      *accessToken
+	tokenCache *tokenCache
+	audit      *auditLog
+}
+
+// maxThrottleReplays is how many times apiTransport replays a throttled
+// request before returning the 429 to the caller.
+const maxThrottleReplays = 2
+
+// maxThrottleReplayWait is the longest Retry-After apiTransport waits out
+// on its own; longer delays are left to the caller.
+var maxThrottleReplayWait = 30 * time.Second
+
+func (t *apiTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = setRequestID(req)
+	resp, err := t.roundTrip(req)
+	if err != nil {
+		return nil, &requestError{err: err, requestID: req.Header.Get(requestIDHeader)}
+	}
+	return resp, nil
+}
+
+func (t *apiTransport) roundTrip(req *http.Request) (*http.Response, error) {
+	ctx := req.Context()
+	if t.token != nil {
+		if isTokenRequest(req) {
+			if req.Body != nil {
+				_ = req.Body.Close()
+			}
+			return t.token.tokenResponse(req), nil
+		}
+		if t.token.expiresBefore(timeNow()) {
+			tflog.Warn(ctx, "ArubaCloud access token has expired", map[string]interface{}{
+				"expiry": t.token.expiry.Format(time.RFC3339),
+			})
+		}
+		req = req.Clone(ctx)
+		req.Header.Set("Authorization", "Bearer "+t.token.value)
+	} else if t.tokenCache != nil && isTokenRequest(req) {
+		return t.tokenCache.roundTrip(req.Clone(ctx), t.send)
+	}
+	return t.send(req)
+}
+
+// send sends req through the throttle gate and the limiter, replaying it on
+// short Retry-After delays and retrying technical failures.
+func (t *apiTransport) send(req *http.Request) (*http.Response, error) {
+	ctx := req.Context()
+	attempt, throttled := 1, 0
+	for {
+		if err := t.throttle.wait(ctx); err != nil {
+			return nil, err
+		}
+		release, err := t.limiter.acquire(ctx)
+		if err != nil {
+			return nil, err
+		}
+		start := timeNow()
+		resp, err := t.base.RoundTrip(req)
+
+		// Decide whether to send the request again. A throttled request is
+		// held back by the throttle gate on the next iteration; a technical
+		// failure backs off here.
+		var (
+			retryAfter    time.Duration
+			hasRetryAfter bool
+			backoff       time.Duration
+			retry         bool
+			technical     bool
+			msg           string
+		)
+		fields := map[string]interface{}{
+			"method":  req.Method,
+			"url":     req.URL.Path,
+			"attempt": attempt,
+		}
+		if err == nil && resp.StatusCode == http.StatusTooManyRequests {
+			retryAfter, hasRetryAfter = parseRetryAfter(resp.Header.Get("Retry-After"), timeNow())
+		}
+		if hasRetryAfter {
+			t.throttle.hold(ctx, retryAfter)
+			throttled++
+			retry = throttled <= maxThrottleReplays && retryAfter <= maxThrottleReplayWait
+			msg = "API throttled the request, retrying after Retry-After"
+			fields["retry_after"] = retryAfter.String()
+		} else if t.retry.shouldRetry(req, resp, err, attempt) {
+			backoff = t.retry.delay(attempt)
+			retry, technical = true, true
+			msg = "API request failed with a technical error, retrying"
+			fields["max_attempts"] = t.retry.maxAttempts
+			fields["retry_in"] = backoff.String()
+			if err != nil {
+				fields["error"] = err.Error()
+			} else {
+				fields["status_code"] = resp.StatusCode
+				if resp.StatusCode == http.StatusTooManyRequests {
+					msg = "API throttled the request without Retry-After, retrying"
+				}
+			}
+		}
+
+		var next *http.Request
+		if retry {
+			next, retry = rewind(ctx, req, retryAfter+backoff)
+		}
+		if t.audit != nil {
+			t.audit.record(req, resp, err, start, retry)
+		}
+		if retry {
+			if resp != nil && resp.Body != nil {
+				_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, maxProblemBodySize))
+				_ = resp.Body.Close()
+			}
+			release()
+			tflog.Warn(ctx, msg, fields)
+			if !sleepCtx(ctx, backoff) {
+				return nil, ctx.Err()
+			}
+			if technical {
+				attempt++
+			}
+			req = next
+			continue
+		}
+
+		if err != nil || resp.Body == nil {
+			release()
+			return resp, err
+		}
+		if hasRetryAfter {
+			annotateRetryAfter(resp, retryAfter)
+		}
+		if resp.StatusCode >= 400 {
+			annotateRequestIDs(req, resp)
+		}
+		// The limiter slot is held until the body is closed, so a request
+		// counts as in flight while its body is still being read.
+		resp.Body = &releaseOnClose{ReadCloser: resp.Body, release: release}
+		return resp, nil
+	}
+}
+
+// rewind returns a copy of req to send again after wait, or false when the
+// wait would run past the context deadline or the request body cannot be
+// replayed.
+func rewind(ctx context.Context, req *http.Request, wait time.Duration) (*http.Request, bool) {
+	if deadline, ok := ctx.Deadline(); ok && timeNow().Add(wait).After(deadline) {
+		return nil, false
+	}
+	next := req.Clone(ctx)
+	if req.Body != nil && req.Body != http.NoBody {
+		if req.GetBody == nil {
+			return nil, false
+		}
+		body, err := req.GetBody()
+		if err != nil {
+			return nil, false
+		}
+		next.Body = body
+	}
+	return next, true
+}
+
+// sleepCtx waits for d, returning false if ctx is done first.
+func sleepCtx(ctx context.Context, d time.Duration) bool {
+	if d <= 0 {
+		return true
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+// releaseOnClose releases the limiter slot of a response when its body is closed.
+type releaseOnClose struct {
+	io.ReadCloser
+	release func()
+}
+
+func (b *releaseOnClose) Close() error {
+	defer b.release()
+	return b.ReadCloser.Close()
+}
+
+// newAPITransport returns the transport behind the HTTP client handed to the
+// SDK, so that all API and token-issuer traffic — CRUD calls and wait-loop
+// polls alike — goes through the shared limiter, throttle gate and retry
+// policy. limiter may be nil.
+func newAPITransport(limiter *requestLimiter, retry retryPolicy) *apiTransport {
+	return &apiTransport{base: http.DefaultTransport, limiter: limiter, throttle: &throttleGate{}, retry: retry}
+}
+
+// newAPIHTTPClient returns an HTTP client using newAPITransport.
+func newAPIHTTPClient(limiter *requestLimiter, retry retryPolicy) *http.Client {
+	return &http.Client{Transport: newAPITransport(limiter, retry)}
+}
@@ -0,0 +1,44 @@
+package provider
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-framework/function"
+)
+
+var _ function.Function = &IsURIFunction{}
+
+// IsURIFunction implements provider::arubacloud::is_uri.
+type IsURIFunction struct{}
+
+func NewIsURIFunction() function.Function {
+	return &IsURIFunction{}
+}
+
+func (f *IsURIFunction) Metadata(ctx context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "is_uri"
+}
+
+func (f *IsURIFunction) Definition(ctx context.Context, req function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary: "Check whether a string is an ArubaCloud resource URI",
+		MarkdownDescription: "Returns `true` when the argument is a well-formed ArubaCloud resource URI that `parse_uri` accepts, " +
+			"and `false` otherwise. Useful in variable `validation` blocks.",
+		Parameters: []function.Parameter{
+			function.StringParameter{
+				Name:                "value",
+				MarkdownDescription: "String to check.",
+			},
+		},
+		Return: function.BoolReturn{},
+	}
+}
+
+func (f *IsURIFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var value string
+	resp.Error = function.ConcatFuncErrors(resp.Error, req.Arguments.Get(ctx, &value))
+	if resp.Error != nil {
+		return
+	}
+	resp.Error = function.ConcatFuncErrors(resp.Error, resp.Result.Set(ctx, IsResourceURI(value)))
+}
@@ -4,7 +4,6 @@ import (
 	"context"
 	"fmt"
 
-	aruba "github.com/Arubacloud/sdk-go/pkg/aruba"
 	"github.com/hashicorp/terraform-plugin-framework/attr"
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
 	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
@@ -189,11 +188,15 @@ func (d *KaaSDataSource) Configure(ctx context.Context, req datasource.Configure
 }
 
 func (d *KaaSDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	ctx = withCorrelationID(ctx)
 	var data KaaSDataSourceModel
 	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
+	if !d.client.checkGuardrails("read", "KaaS", data.ProjectID, &resp.Diagnostics) {
+		return
+	}
 
 	projectID := data.ProjectID.ValueString()
 	kaasID := data.Id.ValueString()
@@ -205,10 +208,10 @@ func (d *KaaSDataSource) Read(ctx context.Context, req datasource.ReadRequest, r
 		return
 	}
 
-	ref := aruba.URI("/projects/" + projectID + "/providers/Aruba.Container/kaas/" + kaasID)
+	ref := newResourceURI(uriKindKaaS, projectID, kaasID).Ref()
 	kaas, err := d.client.Client.FromContainer().KaaS().Get(ctx, ref)
 	if provErr := CheckResponseErr("read", "KaaS", err); provErr != nil {
-		resp.Diagnostics.AddError("API Error", provErr.Error())
+		AppendAPIError(&resp.Diagnostics, provErr)
 		return
 	}
 
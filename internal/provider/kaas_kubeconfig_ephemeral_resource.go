@@ -0,0 +1,162 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/ephemeral"
+	"github.com/hashicorp/terraform-plugin-framework/ephemeral/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+var _ ephemeral.EphemeralResource = &KaaSKubeconfigEphemeralResource{}
+var _ ephemeral.EphemeralResourceWithConfigure = &KaaSKubeconfigEphemeralResource{}
+
+func NewKaaSKubeconfigEphemeralResource() ephemeral.EphemeralResource {
+	return &KaaSKubeconfigEphemeralResource{}
+}
+
+type KaaSKubeconfigEphemeralResource struct {
+	client *ArubaCloudClient
+}
+
+type KaaSKubeconfigEphemeralResourceModel struct {
+	Id                   types.String `tfsdk:"id"`
+	ProjectID            types.String `tfsdk:"project_id"`
+	Kubeconfig           types.String `tfsdk:"kubeconfig"`
+	Host                 types.String `tfsdk:"host"`
+	ClusterCACertificate types.String `tfsdk:"cluster_ca_certificate"`
+	ClientCertificate    types.String `tfsdk:"client_certificate"`
+	ClientKey            types.String `tfsdk:"client_key"`
+	Token                types.String `tfsdk:"token"`
+}
+
+func (e *KaaSKubeconfigEphemeralResource) Metadata(ctx context.Context, req ephemeral.MetadataRequest, resp *ephemeral.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_kaas_kubeconfig"
+}
+
+func (e *KaaSKubeconfigEphemeralResource) Schema(ctx context.Context, req ephemeral.SchemaRequest, resp *ephemeral.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Fetches the kubeconfig of an active ArubaCloud KaaS cluster on demand. " +
+			"Values are never stored in Terraform plan or state, which makes this the preferred way to configure " +
+			"the `kubernetes` and `helm` providers. Requires Terraform 1.10 or later.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				MarkdownDescription: "Unique identifier of the KaaS cluster.",
+				Required:            true,
+			},
+			"project_id": schema.StringAttribute{
+				MarkdownDescription: "ID of the project that owns the KaaS cluster.",
+				Required:            true,
+			},
+			"kubeconfig": schema.StringAttribute{
+				MarkdownDescription: "Raw kubeconfig YAML for `kubectl` access.",
+				Computed:            true,
+				Sensitive:           true,
+			},
+			"host": schema.StringAttribute{
+				MarkdownDescription: "Kubernetes API server endpoint of the current context.",
+				Computed:            true,
+			},
+			"cluster_ca_certificate": schema.StringAttribute{
+				MarkdownDescription: "PEM-encoded CA certificate of the cluster.",
+				Computed:            true,
+			},
+			"client_certificate": schema.StringAttribute{
+				MarkdownDescription: "PEM-encoded client certificate, when the kubeconfig uses certificate authentication.",
+				Computed:            true,
+				Sensitive:           true,
+			},
+			"client_key": schema.StringAttribute{
+				MarkdownDescription: "PEM-encoded client private key, when the kubeconfig uses certificate authentication.",
+				Computed:            true,
+				Sensitive:           true,
+			},
+			"token": schema.StringAttribute{
+				MarkdownDescription: "Bearer token, when the kubeconfig uses token authentication.",
+				Computed:            true,
+				Sensitive:           true,
+			},
+		},
+	}
+}
+
+func (e *KaaSKubeconfigEphemeralResource) Configure(ctx context.Context, req ephemeral.ConfigureRequest, resp *ephemeral.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+	client, ok := req.ProviderData.(*ArubaCloudClient)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Ephemeral Resource Configure Type",
+			fmt.Sprintf("Expected *ArubaCloudClient, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+		return
+	}
+	e.client = client
+}
+
+func (e *KaaSKubeconfigEphemeralResource) Open(ctx context.Context, req ephemeral.OpenRequest, resp *ephemeral.OpenResponse) {
+	ctx = withCorrelationID(ctx)
+	var data KaaSKubeconfigEphemeralResourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	if !e.client.checkGuardrails("read", "KaaS", data.ProjectID, &resp.Diagnostics) {
+		return
+	}
+
+	projectID := data.ProjectID.ValueString()
+	kaasID := data.Id.ValueString()
+	if projectID == "" || kaasID == "" {
+		resp.Diagnostics.AddError(
+			"Missing Required Fields",
+			"Project ID and KaaS ID (id) are required to fetch the kubeconfig",
+		)
+		return
+	}
+
+	ref := newResourceURI(uriKindKaaS, projectID, kaasID).Ref()
+	kaas, err := e.client.Client.FromContainer().KaaS().Get(ctx, ref)
+	if provErr := CheckResponseErr("read", "KaaS", err); provErr != nil {
+		AppendAPIError(&resp.Diagnostics, provErr)
+		return
+	}
+
+	raw := kaas.Raw()
+	if raw == nil || raw.Properties.ManagementIP == nil || *raw.Properties.ManagementIP == "" {
+		resp.Diagnostics.AddError(
+			"Kubeconfig Not Available",
+			fmt.Sprintf("KaaS cluster %s has no management IP yet; the kubeconfig can only be fetched once the cluster is active.", kaasID),
+		)
+		return
+	}
+
+	data.Kubeconfig = downloadKubeconfig(ctx, kaas)
+	if data.Kubeconfig.IsNull() {
+		resp.Diagnostics.AddError(
+			"Kubeconfig Not Available",
+			fmt.Sprintf("The API returned no kubeconfig for KaaS cluster %s.", kaasID),
+		)
+		return
+	}
+
+	creds, err := ParseKubeconfig(data.Kubeconfig.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Invalid Kubeconfig",
+			fmt.Sprintf("Could not parse the kubeconfig returned for KaaS cluster %s: %s", kaasID, err),
+		)
+		return
+	}
+	data.Host = strVal(creds.Host)
+	data.ClusterCACertificate = strVal(creds.ClusterCACertificate)
+	data.ClientCertificate = strVal(creds.ClientCertificate)
+	data.ClientKey = strVal(creds.ClientKey)
+	data.Token = strVal(creds.Token)
+
+	tflog.Trace(ctx, "opened a KaaS kubeconfig ephemeral resource", map[string]interface{}{"kaas_id": kaasID})
+	resp.Diagnostics.Append(resp.Result.Set(ctx, &data)...)
+}
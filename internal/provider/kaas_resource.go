@@ -7,6 +7,7 @@ import (
 	"time"
 
 	aruba "github.com/Arubacloud/sdk-go/pkg/aruba"
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
 	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
 	"github.com/hashicorp/terraform-plugin-framework/attr"
 	"github.com/hashicorp/terraform-plugin-framework/path"
@@ -25,6 +26,7 @@ type KaaSResource struct {
 }
 
 var _ resource.Resource = &KaaSResource{}
+var _ resource.ResourceWithModifyPlan = &KaaSResource{}
 var _ resource.ResourceWithImportState = &KaaSResource{}
 
 func NewKaaSResource() resource.Resource {
@@ -32,18 +34,22 @@ func NewKaaSResource() resource.Resource {
 }
 
 type KaaSResourceModel struct {
-	Id            types.String `tfsdk:"id"`
-	Uri           types.String `tfsdk:"uri"`
-	Name          types.String `tfsdk:"name"`
-	Location      types.String `tfsdk:"location"`
-	Tags          types.List   `tfsdk:"tags"`
-	ProjectID     types.String `tfsdk:"project_id"`
-	BillingPeriod types.String `tfsdk:"billing_period"`
-	ManagementIP  types.String `tfsdk:"management_ip"`
-	Kubeconfig    types.String `tfsdk:"kubeconfig"`
-	Network       types.Object `tfsdk:"network"`
-	Settings      types.Object `tfsdk:"settings"`
-	Timeout       types.String `tfsdk:"timeout"`
+	Id                 types.String   `tfsdk:"id"`
+	Uri                types.String   `tfsdk:"uri"`
+	Name               types.String   `tfsdk:"name"`
+	Location           types.String   `tfsdk:"location"`
+	Tags               types.List     `tfsdk:"tags"`
+	TagsAll            types.List     `tfsdk:"tags_all"`
+	ProjectID          types.String   `tfsdk:"project_id"`
+	BillingPeriod      types.String   `tfsdk:"billing_period"`
+	ManagementIP       types.String   `tfsdk:"management_ip"`
+	Kubeconfig         types.String   `tfsdk:"kubeconfig"`
+	PersistKubeconfig  types.Bool     `tfsdk:"persist_kubeconfig"`
+	Network            types.Object   `tfsdk:"network"`
+	Settings           types.Object   `tfsdk:"settings"`
+	DeletionProtection types.Bool     `tfsdk:"deletion_protection"`
+	Timeout            types.String   `tfsdk:"timeout"`
+	Timeouts           timeouts.Value `tfsdk:"timeouts"`
 }
 
 type KaaSNodeCIDRModel struct {
@@ -98,19 +104,28 @@ func (r *KaaSResource) Schema(ctx context.Context, req resource.SchemaRequest, r
 				Required:            true,
 			},
 			"location": schema.StringAttribute{
-				MarkdownDescription: "Region identifier (e.g., `ITBG-Bergamo`). See the [available locations and zones](https://api.arubacloud.com/docs/metadata/#location-and-data-center). (Immutable — changing this value forces the resource to be destroyed and re-created.)",
-				Required:            true,
-				PlanModifiers:       []planmodifier.String{stringplanmodifier.RequiresReplace()},
+				MarkdownDescription: defaultedDescription("Region identifier (e.g., `ITBG-Bergamo`). See the [available locations and zones](https://api.arubacloud.com/docs/metadata/#location-and-data-center). (Immutable — changing this value forces the resource to be destroyed and re-created.)", "location"),
+				Optional:            true,
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+					stringplanmodifier.RequiresReplace(),
+				},
 			},
 			"tags": schema.ListAttribute{
 				ElementType:         types.StringType,
 				MarkdownDescription: "List of string tags attached to the resource for filtering and organisation.",
 				Optional:            true,
 			},
+			"tags_all": tagsAllAttribute(),
 			"project_id": schema.StringAttribute{
-				MarkdownDescription: "ID of the project that owns this resource. (Immutable — changing this value forces the resource to be destroyed and re-created.)",
-				Required:            true,
-				PlanModifiers:       []planmodifier.String{stringplanmodifier.RequiresReplace()},
+				MarkdownDescription: defaultedDescription("ID of the project that owns this resource. (Immutable — changing this value forces the resource to be destroyed and re-created.)", "project_id"),
+				Optional:            true,
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+					stringplanmodifier.RequiresReplace(),
+				},
 			},
 			"billing_period": schema.StringAttribute{
 				MarkdownDescription: "Billing cycle. Accepted values: `Hour`, `Month`, `Year`.",
@@ -123,10 +138,14 @@ func (r *KaaSResource) Schema(ctx context.Context, req resource.SchemaRequest, r
 				PlanModifiers:       []planmodifier.String{stringplanmodifier.UseStateForUnknown()},
 			},
 			"kubeconfig": schema.StringAttribute{
-				MarkdownDescription: "Kubeconfig YAML for `kubectl` access. Populated automatically when the cluster becomes active. Sensitive — stored in Terraform state but redacted from plan output.",
+				MarkdownDescription: "Kubeconfig YAML for `kubectl` access. Populated automatically when the cluster becomes active. Sensitive — stored in Terraform state but redacted from plan output. Always null when `persist_kubeconfig` is `false`; use the `arubacloud_kaas_kubeconfig` ephemeral resource instead.",
 				Computed:            true,
 				Sensitive:           true,
 			},
+			"persist_kubeconfig": schema.BoolAttribute{
+				MarkdownDescription: "Whether to store the cluster kubeconfig in the `kubeconfig` attribute (and therefore in Terraform state). Set to `false` to keep credentials out of state and fetch them on demand with the `arubacloud_kaas_kubeconfig` ephemeral resource. Default: `true`.",
+				Optional:            true,
+			},
 			"network": schema.SingleNestedAttribute{
 				MarkdownDescription: "Network configuration for the KaaS cluster.",
 				Required:            true,
@@ -165,8 +184,10 @@ func (r *KaaSResource) Schema(ctx context.Context, req resource.SchemaRequest, r
 					},
 				},
 			},
+			"deletion_protection": deletionProtectionAttribute(),
 			"timeout": schema.StringAttribute{
-				MarkdownDescription: "Per-resource timeout override (e.g. `\"15m\"`, `\"1h\"`). Overrides the provider-level `resource_timeout` for this resource's Create and Delete operations. Uses Go duration syntax.",
+				MarkdownDescription: "Per-resource timeout override (e.g. `\"15m\"`, `\"1h\"`) applied to every operation not set in the `timeouts` block. Overrides the provider-level `resource_timeout`. Uses Go duration syntax. Deprecated — use the `timeouts` block instead.",
+				DeprecationMessage:  "Use the timeouts block, which sets the create, read, update and delete timeouts individually.",
 				Optional:            true,
 			},
 			"settings": schema.SingleNestedAttribute{
@@ -220,6 +241,9 @@ func (r *KaaSResource) Schema(ctx context.Context, req resource.SchemaRequest, r
 				},
 			},
 		},
+		Blocks: map[string]schema.Block{
+			"timeouts": timeouts.BlockAll(ctx),
+		},
 	}
 }
 
@@ -239,11 +263,7 @@ func (r *KaaSResource) Configure(ctx context.Context, req resource.ConfigureRequ
 }
 
 func kaasRef(data *KaaSResourceModel) aruba.Ref {
-	if !data.Uri.IsNull() && data.Uri.ValueString() != "" {
-		return aruba.URI(data.Uri.ValueString())
-	}
-	return aruba.URI("/projects/" + data.ProjectID.ValueString() +
-		"/providers/Aruba.Container/kaas/" + data.Id.ValueString())
+	return uriRef(data.Uri, newResourceURI(uriKindKaaS, data.ProjectID.ValueString(), data.Id.ValueString()))
 }
 
 // buildNodePools converts Terraform node pool models to aruba.NewNodePool() builders.
@@ -277,6 +297,12 @@ func nodePoolAttrTypes() map[string]attr.Type {
 	}
 }
 
+// persistKubeconfig reports whether the kubeconfig should be stored in state.
+// A null persist_kubeconfig keeps the historical behaviour of persisting it.
+func (m *KaaSResourceModel) persistKubeconfig() bool {
+	return m.PersistKubeconfig.IsNull() || m.PersistKubeconfig.ValueBool()
+}
+
 // downloadKubeconfig downloads and decodes the kubeconfig from the KaaS wrapper.
 func downloadKubeconfig(ctx context.Context, kaas *aruba.KaaS) types.String {
 	kb, kerr := kaas.DownloadKubeconfig(ctx)
@@ -345,15 +371,38 @@ func buildNodePoolAttrValues(raw *aruba.KaaS, originalNodePools types.List) (typ
 	return list, true
 }
 
+func (r *KaaSResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	r.client.planTagsAll(ctx, req, resp, true)
+	r.client.planProviderDefaults(ctx, req, resp,
+		providerDefault{attr: "project_id", replace: true},
+		providerDefault{attr: "location", replace: true},
+	)
+	r.client.planDataLoss(ctx, req, resp, "KaaS",
+		"the cluster with its node pools and the workloads running on it",
+		path.Root("project_id"),
+		path.Root("location"),
+		path.Root("network").AtName("vpc_uri_ref"),
+		path.Root("network").AtName("subnet_uri_ref"),
+	)
+}
+
 func (r *KaaSResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	ctx = withCorrelationID(ctx)
 	var data KaaSResourceModel
 	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	timeout := r.client.operationTimeout(ctx, data.Timeouts.Create, data.Timeout, &resp.Diagnostics)
 	if resp.Diagnostics.HasError() {
 		return
 	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	if !r.client.checkGuardrails("create", "KaaS", data.ProjectID, &resp.Diagnostics) {
+		return
+	}
+	r.client.warnAccessTokenExpiry(timeNow().Add(timeout), fmt.Sprintf("creating KaaS cluster %q", data.Name.ValueString()), &resp.Diagnostics)
 
 	projectID := data.ProjectID.ValueString()
-	tags := ListToTags(ctx, data.Tags, &resp.Diagnostics)
+	tags := r.client.requestTags(ctx, data.Tags, &data.TagsAll, &resp.Diagnostics)
 	if resp.Diagnostics.HasError() {
 		return
 	}
@@ -390,7 +439,7 @@ func (r *KaaSResource) Create(ctx context.Context, req resource.CreateRequest, r
 
 	builder := aruba.NewKaaS().
 		Named(data.Name.ValueString()).
-		InProject(aruba.URI("/projects/"+projectID)).
+		InProject(newResourceURI(uriKindProject, projectID, "").Ref()).
 		InRegion(aruba.Region(data.Location.ValueString())).
 		WithKubernetesVersion(aruba.KubernetesVersion(settingsModel.KubernetesVersion.ValueString())).
 		WithVPC(aruba.URI(networkModel.VpcUriRef.ValueString())).
@@ -412,7 +461,7 @@ func (r *KaaSResource) Create(ctx context.Context, req resource.CreateRequest, r
 
 	kaas, err := r.client.Client.FromContainer().KaaS().Create(ctx, builder)
 	if provErr := CheckResponseErr("create", "KaaS", err); provErr != nil {
-		resp.Diagnostics.AddError("API Error", provErr.Error())
+		AppendAPIError(&resp.Diagnostics, provErr)
 		return
 	}
 
@@ -473,7 +522,7 @@ func (r *KaaSResource) Create(ctx context.Context, req resource.CreateRequest, r
 		return
 	}
 
-	if waitErr := kaas.WaitUntilReady(ctx, sdkWaitOptions(effectiveTimeout(data.Timeout, r.client.ResourceTimeout))...); waitErr != nil {
+	if waitErr := kaas.WaitUntilReady(ctx, sdkWaitOptions(timeout)...); waitErr != nil {
 		ReportWaitResult(&resp.Diagnostics, waitErr, "KaaS", data.Id.ValueString())
 		data.Kubeconfig = types.StringNull()
 		data.ManagementIP = types.StringNull()
@@ -490,7 +539,11 @@ func (r *KaaSResource) Create(ctx context.Context, req resource.CreateRequest, r
 		} else {
 			data.ManagementIP = types.StringNull()
 		}
-		data.Kubeconfig = downloadKubeconfig(ctx, fresh)
+		if data.persistKubeconfig() {
+			data.Kubeconfig = downloadKubeconfig(ctx, fresh)
+		} else {
+			data.Kubeconfig = types.StringNull()
+		}
 	} else {
 		data.ManagementIP = types.StringNull()
 		data.Kubeconfig = types.StringNull()
@@ -504,11 +557,22 @@ func (r *KaaSResource) Create(ctx context.Context, req resource.CreateRequest, r
 }
 
 func (r *KaaSResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	ctx = withCorrelationID(ctx)
 	var data KaaSResourceModel
 	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	timeout := r.client.operationTimeout(ctx, data.Timeouts.Read, data.Timeout, &resp.Diagnostics)
 	if resp.Diagnostics.HasError() {
 		return
 	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	if !r.client.checkGuardrails("read", "KaaS", data.ProjectID, &resp.Diagnostics) {
+		return
+	}
+	if data.DeletionProtection.IsNull() {
+		// Imported, or written by a provider version without the attribute.
+		data.DeletionProtection = types.BoolValue(false)
+	}
 	var originalState KaaSResourceModel
 	resp.Diagnostics.Append(req.State.Get(ctx, &originalState)...)
 
@@ -523,7 +587,7 @@ func (r *KaaSResource) Read(ctx context.Context, req resource.ReadRequest, resp
 			resp.State.RemoveResource(ctx)
 			return
 		}
-		resp.Diagnostics.AddError("API Error", provErr.Error())
+		AppendAPIError(&resp.Diagnostics, provErr)
 		return
 	}
 
@@ -534,13 +598,13 @@ func (r *KaaSResource) Read(ctx context.Context, req resource.ReadRequest, resp
 			fmt.Sprintf("KaaS %q is in a terminal failure state (%s). "+
 				"Run `terraform destroy` to clean it up, or `terraform apply -replace=<address>` to recreate it.", data.Id.ValueString(), st))
 	case IsCreatingState(st):
-		if waitErr := kaas.WaitUntilReady(ctx, sdkWaitOptions(r.client.ResourceTimeout)...); waitErr != nil {
+		if waitErr := kaas.WaitUntilReady(ctx, sdkWaitOptions(timeout)...); waitErr != nil {
 			ReportWaitResult(&resp.Diagnostics, waitErr, "KaaS", data.Id.ValueString())
 			return
 		}
 		kaas, err = r.client.Client.FromContainer().KaaS().Get(ctx, kaasRef(&data))
 		if provErr := CheckResponseErr("read", "KaaS", err); provErr != nil {
-			resp.Diagnostics.AddError("API Error", provErr.Error())
+			AppendAPIError(&resp.Diagnostics, provErr)
 			return
 		}
 	}
@@ -554,7 +618,7 @@ func (r *KaaSResource) Read(ctx context.Context, req resource.ReadRequest, resp
 	data.Id = types.StringValue(kaas.ID())
 	data.Uri = strVal(kaas.URI())
 	data.Name = types.StringValue(kaas.Name())
-	data.Tags = TagsToListPreserveNull(kaas.Tags(), data.Tags)
+	data.Tags, data.TagsAll = flattenTags(kaas.Tags(), data.Tags, data.TagsAll)
 	if kaas.Region() != "" {
 		data.Location = types.StringValue(string(kaas.Region()))
 	}
@@ -666,7 +730,9 @@ func (r *KaaSResource) Read(ctx context.Context, req resource.ReadRequest, resp
 	}
 
 	// Refresh kubeconfig when cluster has management IP.
-	if raw.Properties.ManagementIP != nil && *raw.Properties.ManagementIP != "" {
+	if !data.persistKubeconfig() {
+		data.Kubeconfig = types.StringNull()
+	} else if raw.Properties.ManagementIP != nil && *raw.Properties.ManagementIP != "" {
 		kc := downloadKubeconfig(ctx, kaas)
 		if kc.IsNull() && !originalState.Kubeconfig.IsNull() {
 			data.Kubeconfig = originalState.Kubeconfig
@@ -683,23 +749,34 @@ func (r *KaaSResource) Read(ctx context.Context, req resource.ReadRequest, resp
 }
 
 func (r *KaaSResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	ctx = withCorrelationID(ctx)
 	var data KaaSResourceModel
 	var state KaaSResourceModel
 
 	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
 	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	timeout := r.client.operationTimeout(ctx, data.Timeouts.Update, data.Timeout, &resp.Diagnostics)
 	if resp.Diagnostics.HasError() {
 		return
 	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	if !r.client.checkGuardrails("update", "KaaS", data.ProjectID, &resp.Diagnostics) {
+		return
+	}
+	if deletionProtectionOnlyChange(req.Plan, req.State) {
+		resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+		return
+	}
 
-	tags := ListToTags(ctx, data.Tags, &resp.Diagnostics)
+	tags := r.client.requestTags(ctx, data.Tags, &data.TagsAll, &resp.Diagnostics)
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
 	kaas, err := r.client.Client.FromContainer().KaaS().Get(ctx, kaasRef(&state))
 	if provErr := CheckResponseErr("read", "KaaS", err); provErr != nil {
-		resp.Diagnostics.AddError("API Error", provErr.Error())
+		AppendAPIError(&resp.Diagnostics, provErr)
 		return
 	}
 
@@ -733,7 +810,7 @@ func (r *KaaSResource) Update(ctx context.Context, req resource.UpdateRequest, r
 
 	updated, err := r.client.Client.FromContainer().KaaS().Update(ctx, kaas)
 	if provErr := CheckResponseErr("update", "KaaS", err); provErr != nil {
-		resp.Diagnostics.AddError("API Error", provErr.Error())
+		AppendAPIError(&resp.Diagnostics, provErr)
 		return
 	}
 
@@ -742,7 +819,7 @@ func (r *KaaSResource) Update(ctx context.Context, req resource.UpdateRequest, r
 	data.Uri = strVal(updated.URI())
 	data.Network = state.Network // Network is immutable
 	data.Name = types.StringValue(updated.Name())
-	data.Tags = TagsToListPreserveNull(updated.Tags(), data.Tags)
+	data.Tags, data.TagsAll = flattenTags(updated.Tags(), data.Tags, data.TagsAll)
 
 	// Re-read for full state.
 	fresh, freshErr := r.client.Client.FromContainer().KaaS().Get(ctx, kaasRef(&data))
@@ -758,8 +835,9 @@ func (r *KaaSResource) Update(ctx context.Context, req resource.UpdateRequest, r
 				data.BillingPeriod = types.StringValue(bp)
 			}
 		}
-		kc := downloadKubeconfig(ctx, fresh)
-		if kc.IsNull() && !state.Kubeconfig.IsNull() {
+		if !data.persistKubeconfig() {
+			data.Kubeconfig = types.StringNull()
+		} else if kc := downloadKubeconfig(ctx, fresh); kc.IsNull() && !state.Kubeconfig.IsNull() {
 			data.Kubeconfig = state.Kubeconfig
 		} else {
 			data.Kubeconfig = kc
@@ -790,7 +868,11 @@ func (r *KaaSResource) Update(ctx context.Context, req resource.UpdateRequest, r
 		}
 	} else {
 		data.ManagementIP = state.ManagementIP
-		data.Kubeconfig = state.Kubeconfig
+		if data.persistKubeconfig() {
+			data.Kubeconfig = state.Kubeconfig
+		} else {
+			data.Kubeconfig = types.StringNull()
+		}
 		data.Settings = state.Settings
 	}
 
@@ -798,11 +880,22 @@ func (r *KaaSResource) Update(ctx context.Context, req resource.UpdateRequest, r
 }
 
 func (r *KaaSResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	ctx = withCorrelationID(ctx)
 	var data KaaSResourceModel
 	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	timeout := r.client.operationTimeout(ctx, data.Timeouts.Delete, data.Timeout, &resp.Diagnostics)
 	if resp.Diagnostics.HasError() {
 		return
 	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	if !r.client.checkGuardrails("delete", "KaaS", data.ProjectID, &resp.Diagnostics) {
+		return
+	}
+	if !checkDeletionProtection(data.DeletionProtection, "KaaS", data.Id.ValueString(), &resp.Diagnostics) {
+		return
+	}
+	r.client.warnAccessTokenExpiry(timeNow().Add(timeout), fmt.Sprintf("deleting KaaS cluster %q", data.Name.ValueString()), &resp.Diagnostics)
 
 	ref := kaasRef(&data)
 	kaasID := data.Id.ValueString()
@@ -822,12 +915,12 @@ func (r *KaaSResource) Delete(ctx context.Context, req resource.DeleteRequest, r
 	err := DeleteResourceWithRetry(ctx, func() error {
 		return CheckResponseErrAsError("delete", "KaaS",
 			r.client.Client.FromContainer().KaaS().Delete(ctx, ref))
-	}, "KaaS", kaasID, effectiveTimeout(data.Timeout, r.client.ResourceTimeout), deletionChecker)
+	}, "KaaS", kaasID, timeout, deletionChecker)
 	if err != nil {
 		resp.Diagnostics.AddError("Error deleting KaaS cluster", err.Error())
 		return
 	}
-	if waitErr := WaitForResourceDeleted(ctx, deletionChecker, "KaaS", kaasID, remainingTimeout(deleteStart, effectiveTimeout(data.Timeout, r.client.ResourceTimeout))); waitErr != nil {
+	if waitErr := WaitForResourceDeleted(ctx, deletionChecker, "KaaS", kaasID, remainingTimeout(deleteStart, timeout)); waitErr != nil {
 		resp.Diagnostics.AddError("Error waiting for KaaS cluster deletion", waitErr.Error())
 		return
 	}
@@ -4,7 +4,6 @@ import (
 	"context"
 	"fmt"
 
-	aruba "github.com/Arubacloud/sdk-go/pkg/aruba"
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
 	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/types"
@@ -88,11 +87,15 @@ func (d *KeypairDataSource) Configure(ctx context.Context, req datasource.Config
 }
 
 func (d *KeypairDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	ctx = withCorrelationID(ctx)
 	var data KeypairDataSourceModel
 	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
+	if !d.client.checkGuardrails("read", "Keypair", data.ProjectID, &resp.Diagnostics) {
+		return
+	}
 
 	projectID := data.ProjectID.ValueString()
 	keypairID := data.Id.ValueString()
@@ -101,10 +104,10 @@ func (d *KeypairDataSource) Read(ctx context.Context, req datasource.ReadRequest
 		return
 	}
 
-	ref := aruba.URI("/projects/" + projectID + "/compute/keyPairs/" + keypairID)
+	ref := newResourceURI(uriKindKeypair, projectID, keypairID).Ref()
 	kp, err := d.client.Client.FromCompute().KeyPairs().Get(ctx, ref)
 	if provErr := CheckResponseErr("read", "Keypair", err); provErr != nil {
-		resp.Diagnostics.AddError("API Error", provErr.Error())
+		AppendAPIError(&resp.Diagnostics, provErr)
 		return
 	}
 
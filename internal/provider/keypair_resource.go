@@ -6,24 +6,33 @@ import (
 	"time"
 
 	aruba "github.com/Arubacloud/sdk-go/pkg/aruba"
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
 )
 
 type KeypairResourceModel struct {
-	Id        types.String `tfsdk:"id"`
-	Uri       types.String `tfsdk:"uri"`
-	Name      types.String `tfsdk:"name"`
-	Location  types.String `tfsdk:"location"`
-	ProjectID types.String `tfsdk:"project_id"`
-	Value     types.String `tfsdk:"value"`
-	Tags      types.List   `tfsdk:"tags"`
-	Timeout   types.String `tfsdk:"timeout"`
+	Id             types.String   `tfsdk:"id"`
+	Uri            types.String   `tfsdk:"uri"`
+	Name           types.String   `tfsdk:"name"`
+	Location       types.String   `tfsdk:"location"`
+	ProjectID      types.String   `tfsdk:"project_id"`
+	Value          types.String   `tfsdk:"value"`
+	ValueWO        types.String   `tfsdk:"value_wo"`
+	ValueWOVersion types.Int64    `tfsdk:"value_wo_version"`
+	Tags           types.List     `tfsdk:"tags"`
+	TagsAll        types.List     `tfsdk:"tags_all"`
+	Timeout        types.String   `tfsdk:"timeout"`
+	Timeouts       timeouts.Value `tfsdk:"timeouts"`
 }
 
 type KeypairResource struct {
@@ -31,6 +40,7 @@ type KeypairResource struct {
 }
 
 var _ resource.Resource = &KeypairResource{}
+var _ resource.ResourceWithModifyPlan = &KeypairResource{}
 var _ resource.ResourceWithImportState = &KeypairResource{}
 
 func NewKeypairResource() resource.Resource {
@@ -67,37 +77,66 @@ func (r *KeypairResource) Schema(ctx context.Context, req resource.SchemaRequest
 				},
 			},
 			"location": schema.StringAttribute{
-				MarkdownDescription: "Region identifier for the resource (e.g., `ITBG-Bergamo`). See the [available locations and zones](https://api.arubacloud.com/docs/metadata/#location-and-data-center). (Immutable — changing this value forces the resource to be destroyed and re-created.)",
-				Required:            true,
+				MarkdownDescription: defaultedDescription("Region identifier for the resource (e.g., `ITBG-Bergamo`). See the [available locations and zones](https://api.arubacloud.com/docs/metadata/#location-and-data-center). (Immutable — changing this value forces the resource to be destroyed and re-created.)", "location"),
+				Optional:            true,
+				Computed:            true,
 				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
 					stringplanmodifier.RequiresReplace(),
 				},
 			},
 			"project_id": schema.StringAttribute{
-				MarkdownDescription: "ID of the project that owns this resource. (Immutable — changing this value forces the resource to be destroyed and re-created.)",
-				Required:            true,
+				MarkdownDescription: defaultedDescription("ID of the project that owns this resource. (Immutable — changing this value forces the resource to be destroyed and re-created.)", "project_id"),
+				Optional:            true,
+				Computed:            true,
 				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
 					stringplanmodifier.RequiresReplace(),
 				},
 			},
 			"value": schema.StringAttribute{
-				MarkdownDescription: "OpenSSH-format public key string (e.g., `ssh-rsa AAAA...`). The provider uploads this to ArubaCloud; the corresponding private key is never stored. Write-only — this value is sent to the API but is not returned in subsequent read responses. (Immutable — changing this value forces the resource to be destroyed and re-created.)",
-				Required:            true,
+				MarkdownDescription: "OpenSSH-format public key string (e.g., `ssh-rsa AAAA...`). The provider uploads this to ArubaCloud; the corresponding private key is never stored. Deprecated — the value is stored in Terraform state; use `value_wo` instead. Exactly one of `value` or `value_wo` must be set. (Immutable — changing this value forces the resource to be destroyed and re-created.)",
+				Optional:            true,
 				Sensitive:           true,
+				DeprecationMessage:  "Use value_wo (Terraform 1.11+) so the public key is never stored in state.",
 				PlanModifiers: []planmodifier.String{
 					stringplanmodifier.RequiresReplace(),
 				},
+				Validators: []validator.String{
+					stringvalidator.ExactlyOneOf(path.MatchRoot("value_wo")),
+				},
+			},
+			"value_wo": schema.StringAttribute{
+				MarkdownDescription: "OpenSSH-format public key string (e.g., `ssh-rsa AAAA...`). Write-only (Terraform 1.11+) — the value is sent to the API but never stored in plan or state. Bump `value_wo_version` to upload a new key.",
+				Optional:            true,
+				Sensitive:           true,
+				WriteOnly:           true,
+			},
+			"value_wo_version": schema.Int64Attribute{
+				MarkdownDescription: "Version of `value_wo`. Terraform cannot detect changes to write-only values, so change this number whenever `value_wo` changes. (Changing this value forces the resource to be destroyed and re-created.)",
+				Optional:            true,
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.RequiresReplace(),
+				},
+				Validators: []validator.Int64{
+					int64validator.AlsoRequires(path.MatchRoot("value_wo")),
+				},
 			},
 			"tags": schema.ListAttribute{
 				ElementType:         types.StringType,
 				MarkdownDescription: "List of string tags attached to the resource for filtering and organisation.",
 				Optional:            true,
 			},
+			"tags_all": tagsAllAttribute(),
 			"timeout": schema.StringAttribute{
-				MarkdownDescription: "Per-resource timeout override (e.g. `\"15m\"`, `\"1h\"`). Overrides the provider-level `resource_timeout` for this resource's Create and Delete operations. Uses Go duration syntax.",
+				MarkdownDescription: "Per-resource timeout override (e.g. `\"15m\"`, `\"1h\"`) applied to every operation not set in the `timeouts` block. Overrides the provider-level `resource_timeout`. Uses Go duration syntax. Deprecated — use the `timeouts` block instead.",
+				DeprecationMessage:  "Use the timeouts block, which sets the create, read, update and delete timeouts individually.",
 				Optional:            true,
 			},
 		},
+		Blocks: map[string]schema.Block{
+			"timeouts": timeouts.BlockAll(ctx),
+		},
 	}
 }
 
@@ -116,12 +155,27 @@ func (r *KeypairResource) Configure(ctx context.Context, req resource.ConfigureR
 	r.client = client
 }
 
+func (r *KeypairResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	r.client.planTagsAll(ctx, req, resp, false)
+	r.client.planProviderDefaults(ctx, req, resp,
+		providerDefault{attr: "project_id", replace: true},
+		providerDefault{attr: "location", replace: true},
+	)
+}
+
 func (r *KeypairResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	ctx = withCorrelationID(ctx)
 	var data KeypairResourceModel
 	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	timeout := r.client.operationTimeout(ctx, data.Timeouts.Create, data.Timeout, &resp.Diagnostics)
 	if resp.Diagnostics.HasError() {
 		return
 	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	if !r.client.checkGuardrails("create", "Keypair", data.ProjectID, &resp.Diagnostics) {
+		return
+	}
 
 	projectID := data.ProjectID.ValueString()
 	if projectID == "" {
@@ -129,21 +183,27 @@ func (r *KeypairResource) Create(ctx context.Context, req resource.CreateRequest
 		return
 	}
 
-	tags := ListToTags(ctx, data.Tags, &resp.Diagnostics)
+	tags := r.client.requestTags(ctx, data.Tags, &data.TagsAll, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	publicKey, diags := writeOnlyOrDeprecated(ctx, req.Config, path.Root("value_wo"), data.Value)
+	resp.Diagnostics.Append(diags...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
 	builder := aruba.NewKeyPair().
 		Named(data.Name.ValueString()).
-		InProject(aruba.URI("/projects/" + projectID)).
+		InProject(newResourceURI(uriKindProject, projectID, "").Ref()).
 		InRegion(aruba.Region(data.Location.ValueString())).
-		WithPublicKey(data.Value.ValueString()).
+		WithPublicKey(publicKey.ValueString()).
 		Tagged(tags...)
 
 	kp, err := r.client.Client.FromCompute().KeyPairs().Create(ctx, builder)
 	if provErr := CheckResponseErr("create", "Keypair", err); provErr != nil {
-		resp.Diagnostics.AddError("API Error", provErr.Error())
+		AppendAPIError(&resp.Diagnostics, provErr)
 		return
 	}
 
@@ -157,7 +217,7 @@ func (r *KeypairResource) Create(ctx context.Context, req resource.CreateRequest
 	keypairID := data.Id.ValueString()
 
 	// KeyPairs may go through a short provisioning phase; wait until ready.
-	if err := kp.WaitUntilReady(ctx, sdkWaitOptions(effectiveTimeout(data.Timeout, r.client.ResourceTimeout))...); err != nil {
+	if err := kp.WaitUntilReady(ctx, sdkWaitOptions(timeout)...); err != nil {
 		ReportWaitResult(&resp.Diagnostics, err, "Keypair", keypairID)
 		resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
 		return
@@ -174,18 +234,22 @@ func (r *KeypairResource) Create(ctx context.Context, req resource.CreateRequest
 // keypairRef returns the Ref to use for Get/Update/Delete.
 // Uses the stored URI when available (normal flow); falls back to a constructed URI for the import flow.
 func keypairRef(data *KeypairResourceModel) aruba.Ref {
-	if !data.Uri.IsNull() && data.Uri.ValueString() != "" {
-		return aruba.URI(data.Uri.ValueString())
-	}
-	return aruba.URI("/projects/" + data.ProjectID.ValueString() + "/compute/keyPairs/" + data.Id.ValueString())
+	return uriRef(data.Uri, newResourceURI(uriKindKeypair, data.ProjectID.ValueString(), data.Id.ValueString()))
 }
 
 func (r *KeypairResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	ctx = withCorrelationID(ctx)
 	var data KeypairResourceModel
 	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	timeout := r.client.operationTimeout(ctx, data.Timeouts.Read, data.Timeout, &resp.Diagnostics)
 	if resp.Diagnostics.HasError() {
 		return
 	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	if !r.client.checkGuardrails("read", "Keypair", data.ProjectID, &resp.Diagnostics) {
+		return
+	}
 
 	if data.Id.IsUnknown() || data.Id.IsNull() || data.Id.ValueString() == "" {
 		resp.State.RemoveResource(ctx)
@@ -204,7 +268,7 @@ func (r *KeypairResource) Read(ctx context.Context, req resource.ReadRequest, re
 			resp.State.RemoveResource(ctx)
 			return
 		}
-		resp.Diagnostics.AddError("API Error", provErr.Error())
+		AppendAPIError(&resp.Diagnostics, provErr)
 		return
 	}
 
@@ -225,7 +289,7 @@ func (r *KeypairResource) Read(ctx context.Context, req resource.ReadRequest, re
 		data.Location = types.StringValue(string(raw.Metadata.LocationResponse.Value))
 	}
 
-	data.Tags = TagsToListPreserveNull(kp.Tags(), data.Tags)
+	data.Tags, data.TagsAll = flattenTags(kp.Tags(), data.Tags, data.TagsAll)
 
 	data.ProjectID = projectIDFromState
 	data.Value = valueFromState
@@ -234,13 +298,20 @@ func (r *KeypairResource) Read(ctx context.Context, req resource.ReadRequest, re
 }
 
 func (r *KeypairResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	ctx = withCorrelationID(ctx)
 	var data KeypairResourceModel
 	var state KeypairResourceModel
 
 	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	timeout := r.client.operationTimeout(ctx, data.Timeouts.Update, data.Timeout, &resp.Diagnostics)
 	if resp.Diagnostics.HasError() {
 		return
 	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	if !r.client.checkGuardrails("update", "Keypair", data.ProjectID, &resp.Diagnostics) {
+		return
+	}
 	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
 	if resp.Diagnostics.HasError() {
 		return
@@ -255,7 +326,7 @@ func (r *KeypairResource) Update(ctx context.Context, req resource.UpdateRequest
 			resp.State.RemoveResource(ctx)
 			return
 		}
-		resp.Diagnostics.AddError("API Error", provErr.Error())
+		AppendAPIError(&resp.Diagnostics, provErr)
 		return
 	}
 
@@ -269,16 +340,24 @@ func (r *KeypairResource) Update(ctx context.Context, req resource.UpdateRequest
 	} else {
 		data.Uri = state.Uri
 	}
+	r.client.resolveTagsAll(data.Tags, &data.TagsAll)
 
 	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
 }
 
 func (r *KeypairResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	ctx = withCorrelationID(ctx)
 	var data KeypairResourceModel
 	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	timeout := r.client.operationTimeout(ctx, data.Timeouts.Delete, data.Timeout, &resp.Diagnostics)
 	if resp.Diagnostics.HasError() {
 		return
 	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	if !r.client.checkGuardrails("delete", "Keypair", data.ProjectID, &resp.Diagnostics) {
+		return
+	}
 
 	keypairID := data.Id.ValueString()
 	if keypairID == "" {
@@ -308,7 +387,7 @@ func (r *KeypairResource) Delete(ctx context.Context, req resource.DeleteRequest
 		},
 		"Keypair",
 		keypairID,
-		effectiveTimeout(data.Timeout, r.client.ResourceTimeout),
+		timeout,
 		deletionChecker,
 	)
 	if err != nil {
@@ -316,7 +395,7 @@ func (r *KeypairResource) Delete(ctx context.Context, req resource.DeleteRequest
 		return
 	}
 
-	if waitErr := WaitForResourceDeleted(ctx, deletionChecker, "Keypair", keypairID, remainingTimeout(deleteStart, effectiveTimeout(data.Timeout, r.client.ResourceTimeout))); waitErr != nil {
+	if waitErr := WaitForResourceDeleted(ctx, deletionChecker, "Keypair", keypairID, remainingTimeout(deleteStart, timeout)); waitErr != nil {
 		resp.Diagnostics.AddError("Error waiting for Keypair deletion", waitErr.Error())
 		return
 	}
@@ -2,8 +2,13 @@ package provider
 
 import (
 	"context"
+	"io"
 	"net/http"
+	"strings"
 	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
 )
 
 // TestKeypairRead_WithProperties covers keypair Read() path where the API
@@ -35,3 +40,55 @@ func TestKeypairRead_WithProperties(t *testing.T) {
 		t.Errorf("Keypair Read() reported error with properties: %v", resp.Diagnostics)
 	}
 }
+
+// TestKeypairCreate_ValueWriteOnly verifies that Create() reads value_wo from
+// the configuration when the deprecated value attribute is unset, and that
+// the public key never reaches state.
+func TestKeypairCreate_ValueWriteOnly(t *testing.T) {
+	ctx := context.Background()
+	const publicKey = "ssh-ed25519 AAAAC3NzaC1lZDI1NTE5AAAAIWriteOnly test@test"
+
+	var posted string
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPost {
+			body, _ := io.ReadAll(r.Body)
+			posted = string(body)
+		}
+		createSuccessHandler(w, r)
+	}
+	_, mockClient := newMockArubaClient(t, handler)
+
+	res := NewKeypairResource()
+	configureResource(ctx, t, res, mockClient)
+
+	req, resp := resourceCreateReq(ctx, t, res)
+	objType := req.Plan.Raw.Type().(tftypes.Object)
+	planAttrs := map[string]tftypes.Value{}
+	if err := req.Plan.Raw.As(&planAttrs); err != nil {
+		t.Fatalf("unpack plan: %v", err)
+	}
+	planAttrs["value"] = tftypes.NewValue(tftypes.String, nil)
+	planAttrs["value_wo"] = tftypes.NewValue(tftypes.String, nil)
+	req.Plan.Raw = tftypes.NewValue(objType, planAttrs)
+
+	configAttrs := map[string]tftypes.Value{}
+	for k, v := range planAttrs {
+		configAttrs[k] = v
+	}
+	configAttrs["value_wo"] = tftypes.NewValue(tftypes.String, publicKey)
+	req.Config = tfsdk.Config{Raw: tftypes.NewValue(objType, configAttrs), Schema: req.Plan.Schema}
+
+	res.Create(ctx, req, resp)
+
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("Keypair Create() reported error: %v", resp.Diagnostics)
+	}
+	if !strings.Contains(posted, publicKey) {
+		t.Errorf("create request body does not contain value_wo; got %s", posted)
+	}
+	var state KeypairResourceModel
+	resp.Diagnostics.Append(resp.State.Get(ctx, &state)...)
+	if !state.Value.IsNull() || !state.ValueWO.IsNull() {
+		t.Errorf("public key leaked into state: value=%s value_wo=%s", state.Value, state.ValueWO)
+	}
+}
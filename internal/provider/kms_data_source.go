@@ -4,7 +4,6 @@ import (
 	"context"
 	"fmt"
 
-	aruba "github.com/Arubacloud/sdk-go/pkg/aruba"
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
 	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/types"
@@ -82,11 +81,15 @@ func (d *KMSDataSource) Configure(ctx context.Context, req datasource.ConfigureR
 }
 
 func (d *KMSDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	ctx = withCorrelationID(ctx)
 	var data KMSDataSourceModel
 	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
+	if !d.client.checkGuardrails("read", "KMS", data.ProjectID, &resp.Diagnostics) {
+		return
+	}
 
 	projectID := data.ProjectID.ValueString()
 	kmsID := data.Id.ValueString()
@@ -95,10 +98,10 @@ func (d *KMSDataSource) Read(ctx context.Context, req datasource.ReadRequest, re
 		return
 	}
 
-	ref := aruba.URI("/projects/" + projectID + "/providers/Aruba.Security/kms/" + kmsID)
+	ref := newResourceURI(uriKindKMS, projectID, kmsID).Ref()
 	kms, err := d.client.Client.FromSecurity().KMS().Get(ctx, ref)
 	if provErr := CheckResponseErr("read", "KMS", err); provErr != nil {
-		resp.Diagnostics.AddError("API Error", provErr.Error())
+		AppendAPIError(&resp.Diagnostics, provErr)
 		return
 	}
 
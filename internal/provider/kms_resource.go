@@ -6,6 +6,7 @@ import (
 	"time"
 
 	aruba "github.com/Arubacloud/sdk-go/pkg/aruba"
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
 	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
@@ -18,14 +19,17 @@ import (
 )
 
 type KMSResourceModel struct {
-	Id            types.String `tfsdk:"id"`
-	Uri           types.String `tfsdk:"uri"`
-	Name          types.String `tfsdk:"name"`
-	ProjectID     types.String `tfsdk:"project_id"`
-	Location      types.String `tfsdk:"location"`
-	Tags          types.List   `tfsdk:"tags"`
-	BillingPeriod types.String `tfsdk:"billing_period"`
-	Timeout       types.String `tfsdk:"timeout"`
+	Id                 types.String   `tfsdk:"id"`
+	Uri                types.String   `tfsdk:"uri"`
+	Name               types.String   `tfsdk:"name"`
+	ProjectID          types.String   `tfsdk:"project_id"`
+	Location           types.String   `tfsdk:"location"`
+	Tags               types.List     `tfsdk:"tags"`
+	TagsAll            types.List     `tfsdk:"tags_all"`
+	BillingPeriod      types.String   `tfsdk:"billing_period"`
+	DeletionProtection types.Bool     `tfsdk:"deletion_protection"`
+	Timeout            types.String   `tfsdk:"timeout"`
+	Timeouts           timeouts.Value `tfsdk:"timeouts"`
 }
 
 type KMSResource struct {
@@ -33,6 +37,7 @@ type KMSResource struct {
 }
 
 var _ resource.Resource = &KMSResource{}
+var _ resource.ResourceWithModifyPlan = &KMSResource{}
 var _ resource.ResourceWithImportState = &KMSResource{}
 
 func NewKMSResource() resource.Resource {
@@ -62,11 +67,13 @@ func (r *KMSResource) Schema(ctx context.Context, req resource.SchemaRequest, re
 				Required:            true,
 			},
 			"project_id": schema.StringAttribute{
-				MarkdownDescription: "ID of the project that owns this resource.",
-				Required:            true,
+				MarkdownDescription: defaultedDescription("ID of the project that owns this resource.", "project_id"),
+				Optional:            true,
+				Computed:            true,
+				PlanModifiers:       []planmodifier.String{stringplanmodifier.UseStateForUnknown()},
 			},
 			"location": schema.StringAttribute{
-				MarkdownDescription: "Region identifier (e.g., `ITBG-Bergamo`). See the [available locations and zones](https://api.arubacloud.com/docs/metadata/#location-and-data-center).",
+				MarkdownDescription: defaultedDescription("Region identifier (e.g., `ITBG-Bergamo`). See the [available locations and zones](https://api.arubacloud.com/docs/metadata/#location-and-data-center).", "location"),
 				Optional:            true,
 				Computed:            true,
 				PlanModifiers:       []planmodifier.String{stringplanmodifier.UseStateForUnknown()},
@@ -76,6 +83,7 @@ func (r *KMSResource) Schema(ctx context.Context, req resource.SchemaRequest, re
 				MarkdownDescription: "List of string tags attached to the resource for filtering and organisation.",
 				Optional:            true,
 			},
+			"tags_all": tagsAllAttribute(),
 			"billing_period": schema.StringAttribute{
 				MarkdownDescription: "Billing cycle. Accepted values: `Hour`, `Month`, `Year`.",
 				Optional:            true,
@@ -83,11 +91,16 @@ func (r *KMSResource) Schema(ctx context.Context, req resource.SchemaRequest, re
 				Computed:            true,
 				PlanModifiers:       []planmodifier.String{stringplanmodifier.UseStateForUnknown()},
 			},
+			"deletion_protection": deletionProtectionAttribute(),
 			"timeout": schema.StringAttribute{
-				MarkdownDescription: "Per-resource timeout override (e.g. `\"15m\"`, `\"1h\"`). Overrides the provider-level `resource_timeout` for this resource's Create and Delete operations. Uses Go duration syntax.",
+				MarkdownDescription: "Per-resource timeout override (e.g. `\"15m\"`, `\"1h\"`) applied to every operation not set in the `timeouts` block. Overrides the provider-level `resource_timeout`. Uses Go duration syntax. Deprecated — use the `timeouts` block instead.",
+				DeprecationMessage:  "Use the timeouts block, which sets the create, read, update and delete timeouts individually.",
 				Optional:            true,
 			},
 		},
+		Blocks: map[string]schema.Block{
+			"timeouts": timeouts.BlockAll(ctx),
+		},
 	}
 }
 
@@ -107,11 +120,7 @@ func (r *KMSResource) Configure(ctx context.Context, req resource.ConfigureReque
 }
 
 func kmsRef(data *KMSResourceModel) aruba.Ref {
-	if !data.Uri.IsNull() && data.Uri.ValueString() != "" {
-		return aruba.URI(data.Uri.ValueString())
-	}
-	return aruba.URI("/projects/" + data.ProjectID.ValueString() +
-		"/providers/Aruba.Security/kms/" + data.Id.ValueString())
+	return uriRef(data.Uri, newResourceURI(uriKindKMS, data.ProjectID.ValueString(), data.Id.ValueString()))
 }
 
 func applyKMSToModel(kms *aruba.KMS, data *KMSResourceModel) {
@@ -122,7 +131,7 @@ func applyKMSToModel(kms *aruba.KMS, data *KMSResourceModel) {
 		data.Uri = types.StringNull()
 	}
 	data.Name = types.StringValue(kms.Name())
-	data.Tags = TagsToListPreserveNull(kms.Tags(), data.Tags)
+	data.Tags, data.TagsAll = flattenTags(kms.Tags(), data.Tags, data.TagsAll)
 	if r := string(kms.Region()); r != "" {
 		data.Location = types.StringValue(r)
 	}
@@ -131,12 +140,29 @@ func applyKMSToModel(kms *aruba.KMS, data *KMSResourceModel) {
 	}
 }
 
+func (r *KMSResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	r.client.planTagsAll(ctx, req, resp, true)
+	r.client.planProviderDefaults(ctx, req, resp,
+		providerDefault{attr: "project_id", replace: false},
+		providerDefault{attr: "location", replace: false},
+	)
+	r.client.planDataLoss(ctx, req, resp, "KMS",
+		"the KMS instance with its keys; data encrypted with those keys can no longer be decrypted")
+}
+
 func (r *KMSResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	ctx = withCorrelationID(ctx)
 	var data KMSResourceModel
 	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	timeout := r.client.operationTimeout(ctx, data.Timeouts.Create, data.Timeout, &resp.Diagnostics)
 	if resp.Diagnostics.HasError() {
 		return
 	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	if !r.client.checkGuardrails("create", "KMS", data.ProjectID, &resp.Diagnostics) {
+		return
+	}
 
 	projectID := data.ProjectID.ValueString()
 	if projectID == "" {
@@ -144,14 +170,14 @@ func (r *KMSResource) Create(ctx context.Context, req resource.CreateRequest, re
 		return
 	}
 
-	tags := ListToTags(ctx, data.Tags, &resp.Diagnostics)
+	tags := r.client.requestTags(ctx, data.Tags, &data.TagsAll, &resp.Diagnostics)
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
 	builder := aruba.NewKMS().
 		Named(data.Name.ValueString()).
-		InProject(aruba.URI("/projects/" + projectID)).
+		InProject(newResourceURI(uriKindProject, projectID, "").Ref()).
 		Tagged(tags...)
 
 	if !data.Location.IsNull() && !data.Location.IsUnknown() {
@@ -163,7 +189,7 @@ func (r *KMSResource) Create(ctx context.Context, req resource.CreateRequest, re
 
 	kms, err := r.client.Client.FromSecurity().KMS().Create(ctx, builder)
 	if provErr := CheckResponseErr("create", "KMS", err); provErr != nil {
-		resp.Diagnostics.AddError("API Error", provErr.Error())
+		AppendAPIError(&resp.Diagnostics, provErr)
 		return
 	}
 
@@ -174,7 +200,7 @@ func (r *KMSResource) Create(ctx context.Context, req resource.CreateRequest, re
 		return
 	}
 
-	if waitErr := kms.WaitUntilReady(ctx, sdkWaitOptions(effectiveTimeout(data.Timeout, r.client.ResourceTimeout))...); waitErr != nil {
+	if waitErr := kms.WaitUntilReady(ctx, sdkWaitOptions(timeout)...); waitErr != nil {
 		ReportWaitResult(&resp.Diagnostics, waitErr, "KMS", data.Id.ValueString())
 		resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
 		return
@@ -188,11 +214,22 @@ func (r *KMSResource) Create(ctx context.Context, req resource.CreateRequest, re
 }
 
 func (r *KMSResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	ctx = withCorrelationID(ctx)
 	var data KMSResourceModel
 	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	timeout := r.client.operationTimeout(ctx, data.Timeouts.Read, data.Timeout, &resp.Diagnostics)
 	if resp.Diagnostics.HasError() {
 		return
 	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	if !r.client.checkGuardrails("read", "KMS", data.ProjectID, &resp.Diagnostics) {
+		return
+	}
+	if data.DeletionProtection.IsNull() {
+		// Imported, or written by a provider version without the attribute.
+		data.DeletionProtection = types.BoolValue(false)
+	}
 
 	if data.Id.IsUnknown() || data.Id.IsNull() || data.Id.ValueString() == "" {
 		resp.State.RemoveResource(ctx)
@@ -205,7 +242,7 @@ func (r *KMSResource) Read(ctx context.Context, req resource.ReadRequest, resp *
 			resp.State.RemoveResource(ctx)
 			return
 		}
-		resp.Diagnostics.AddError("API Error", provErr.Error())
+		AppendAPIError(&resp.Diagnostics, provErr)
 		return
 	}
 
@@ -216,13 +253,13 @@ func (r *KMSResource) Read(ctx context.Context, req resource.ReadRequest, resp *
 			fmt.Sprintf("KMS %q is in a terminal failure state (%s). "+
 				"Run `terraform destroy` to clean it up, or `terraform apply -replace=<address>` to recreate it.", data.Id.ValueString(), st))
 	case IsCreatingState(st):
-		if waitErr := kms.WaitUntilReady(ctx, sdkWaitOptions(r.client.ResourceTimeout)...); waitErr != nil {
+		if waitErr := kms.WaitUntilReady(ctx, sdkWaitOptions(timeout)...); waitErr != nil {
 			ReportWaitResult(&resp.Diagnostics, waitErr, "KMS", data.Id.ValueString())
 			return
 		}
 		kms, err = r.client.Client.FromSecurity().KMS().Get(ctx, kmsRef(&data))
 		if provErr := CheckResponseErr("read", "KMS", err); provErr != nil {
-			resp.Diagnostics.AddError("API Error", provErr.Error())
+			AppendAPIError(&resp.Diagnostics, provErr)
 			return
 		}
 	}
@@ -232,23 +269,34 @@ func (r *KMSResource) Read(ctx context.Context, req resource.ReadRequest, resp *
 }
 
 func (r *KMSResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	ctx = withCorrelationID(ctx)
 	var data KMSResourceModel
 	var state KMSResourceModel
 
 	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
 	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	timeout := r.client.operationTimeout(ctx, data.Timeouts.Update, data.Timeout, &resp.Diagnostics)
 	if resp.Diagnostics.HasError() {
 		return
 	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	if !r.client.checkGuardrails("update", "KMS", data.ProjectID, &resp.Diagnostics) {
+		return
+	}
+	if deletionProtectionOnlyChange(req.Plan, req.State) {
+		resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+		return
+	}
 
-	tags := ListToTags(ctx, data.Tags, &resp.Diagnostics)
+	tags := r.client.requestTags(ctx, data.Tags, &data.TagsAll, &resp.Diagnostics)
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
 	kms, err := r.client.Client.FromSecurity().KMS().Get(ctx, kmsRef(&state))
 	if provErr := CheckResponseErr("read", "KMS", err); provErr != nil {
-		resp.Diagnostics.AddError("API Error", provErr.Error())
+		AppendAPIError(&resp.Diagnostics, provErr)
 		return
 	}
 
@@ -264,7 +312,7 @@ func (r *KMSResource) Update(ctx context.Context, req resource.UpdateRequest, re
 
 	updated, err := r.client.Client.FromSecurity().KMS().Update(ctx, kms)
 	if provErr := CheckResponseErr("update", "KMS", err); provErr != nil {
-		resp.Diagnostics.AddError("API Error", provErr.Error())
+		AppendAPIError(&resp.Diagnostics, provErr)
 		return
 	}
 
@@ -278,11 +326,21 @@ func (r *KMSResource) Update(ctx context.Context, req resource.UpdateRequest, re
 }
 
 func (r *KMSResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	ctx = withCorrelationID(ctx)
 	var data KMSResourceModel
 	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	timeout := r.client.operationTimeout(ctx, data.Timeouts.Delete, data.Timeout, &resp.Diagnostics)
 	if resp.Diagnostics.HasError() {
 		return
 	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	if !r.client.checkGuardrails("delete", "KMS", data.ProjectID, &resp.Diagnostics) {
+		return
+	}
+	if !checkDeletionProtection(data.DeletionProtection, "KMS", data.Id.ValueString(), &resp.Diagnostics) {
+		return
+	}
 
 	if data.Id.IsUnknown() || data.Id.IsNull() || data.Id.ValueString() == "" {
 		return
@@ -306,12 +364,12 @@ func (r *KMSResource) Delete(ctx context.Context, req resource.DeleteRequest, re
 	err := DeleteResourceWithRetry(ctx, func() error {
 		return CheckResponseErrAsError("delete", "KMS",
 			r.client.Client.FromSecurity().KMS().Delete(ctx, ref))
-	}, "KMS", kmsID, effectiveTimeout(data.Timeout, r.client.ResourceTimeout), deletionChecker)
+	}, "KMS", kmsID, timeout, deletionChecker)
 	if err != nil {
 		resp.Diagnostics.AddError("Error deleting KMS", err.Error())
 		return
 	}
-	if waitErr := WaitForResourceDeleted(ctx, deletionChecker, "KMS", kmsID, remainingTimeout(deleteStart, effectiveTimeout(data.Timeout, r.client.ResourceTimeout))); waitErr != nil {
+	if waitErr := WaitForResourceDeleted(ctx, deletionChecker, "KMS", kmsID, remainingTimeout(deleteStart, timeout)); waitErr != nil {
 		resp.Diagnostics.AddError("Error waiting for KMS deletion", waitErr.Error())
 		return
 	}
@@ -0,0 +1,115 @@
+package provider
+
+import (
+	"encoding/base64"
+	"fmt"
+
+	"gopkg.in/yaml.v3"
+)
+
+// kubeconfigFile is the subset of the kubectl config format the provider
+// needs to expose connection details for the current context.
+type kubeconfigFile struct {
+	CurrentContext string `yaml:"current-context"`
+	Clusters       []struct {
+		Name    string `yaml:"name"`
+		Cluster struct {
+			Server                   string `yaml:"server"`
+			CertificateAuthorityData string `yaml:"certificate-authority-data"`
+		} `yaml:"cluster"`
+	} `yaml:"clusters"`
+	Users []struct {
+		Name string `yaml:"name"`
+		User struct {
+			ClientCertificateData string `yaml:"client-certificate-data"`
+			ClientKeyData         string `yaml:"client-key-data"`
+			Token                 string `yaml:"token"`
+		} `yaml:"user"`
+	} `yaml:"users"`
+	Contexts []struct {
+		Name    string `yaml:"name"`
+		Context struct {
+			Cluster string `yaml:"cluster"`
+			User    string `yaml:"user"`
+		} `yaml:"context"`
+	} `yaml:"contexts"`
+}
+
+// KubeconfigCredentials holds the connection details of the current context
+// of a kubeconfig. Certificate and key fields are PEM-encoded.
+type KubeconfigCredentials struct {
+	Host                 string
+	ClusterCACertificate string
+	ClientCertificate    string
+	ClientKey            string
+	Token                string
+}
+
+// ParseKubeconfig extracts the cluster endpoint and client credentials of the
+// current context. When current-context is unset or does not match a context
+// entry, the first cluster and user are used.
+func ParseKubeconfig(raw string) (KubeconfigCredentials, error) {
+	var creds KubeconfigCredentials
+
+	var kc kubeconfigFile
+	if err := yaml.Unmarshal([]byte(raw), &kc); err != nil {
+		return creds, fmt.Errorf("invalid kubeconfig: %w", err)
+	}
+	if len(kc.Clusters) == 0 {
+		return creds, fmt.Errorf("invalid kubeconfig: no clusters defined")
+	}
+
+	clusterName, userName := "", ""
+	for _, c := range kc.Contexts {
+		if c.Name == kc.CurrentContext {
+			clusterName, userName = c.Context.Cluster, c.Context.User
+			break
+		}
+	}
+
+	cluster := kc.Clusters[0].Cluster
+	for _, c := range kc.Clusters {
+		if c.Name == clusterName {
+			cluster = c.Cluster
+			break
+		}
+	}
+	creds.Host = cluster.Server
+
+	var err error
+	if creds.ClusterCACertificate, err = decodeKubeconfigData("certificate-authority-data", cluster.CertificateAuthorityData); err != nil {
+		return creds, err
+	}
+
+	if len(kc.Users) == 0 {
+		return creds, nil
+	}
+	user := kc.Users[0].User
+	for _, u := range kc.Users {
+		if u.Name == userName {
+			user = u.User
+			break
+		}
+	}
+	if creds.ClientCertificate, err = decodeKubeconfigData("client-certificate-data", user.ClientCertificateData); err != nil {
+		return creds, err
+	}
+	if creds.ClientKey, err = decodeKubeconfigData("client-key-data", user.ClientKeyData); err != nil {
+		return creds, err
+	}
+	creds.Token = user.Token
+
+	return creds, nil
+}
+
+// decodeKubeconfigData decodes a base64 *-data field into its PEM content.
+func decodeKubeconfigData(field, value string) (string, error) {
+	if value == "" {
+		return "", nil
+	}
+	decoded, err := base64.StdEncoding.DecodeString(value)
+	if err != nil {
+		return "", fmt.Errorf("invalid kubeconfig: %s is not valid base64: %w", field, err)
+	}
+	return string(decoded), nil
+}
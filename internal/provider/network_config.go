@@ -0,0 +1,124 @@
+package provider
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// networkSettings holds the provider attributes that shape the connection to
+// the API and the token issuer: an outbound proxy and TLS trust and client
+// authentication.
+type networkSettings struct {
+	HTTPProxy          types.String
+	CACertFile         types.String
+	CACertPEM          types.String
+	ClientCertPEM      types.String
+	ClientKeyPEM       types.String
+	InsecureSkipVerify types.Bool
+}
+
+// configured reports whether any network setting is set, so that the default
+// transport is kept untouched otherwise.
+func (s networkSettings) configured() bool {
+	for _, v := range []types.String{s.HTTPProxy, s.CACertFile, s.CACertPEM, s.ClientCertPEM, s.ClientKeyPEM} {
+		if stringSet(v) {
+			return true
+		}
+	}
+	return s.InsecureSkipVerify.ValueBool()
+}
+
+// stringSet reports whether v holds a non-empty known value.
+func stringSet(v types.String) bool {
+	return !v.IsNull() && !v.IsUnknown() && v.ValueString() != ""
+}
+
+// newBaseTransport returns the transport apiTransport sends requests with.
+// Without network settings it is http.DefaultTransport, which honours the
+// HTTPS_PROXY, HTTP_PROXY and NO_PROXY environment variables. Invalid
+// settings are reported as attribute errors.
+func newBaseTransport(s networkSettings, diags *diag.Diagnostics) http.RoundTripper {
+	if !s.configured() {
+		return http.DefaultTransport
+	}
+	defaultTransport, ok := http.DefaultTransport.(*http.Transport)
+	if !ok {
+		diags.AddError("Unexpected HTTP transport", fmt.Sprintf("http.DefaultTransport is %T, want *http.Transport.", http.DefaultTransport))
+		return http.DefaultTransport
+	}
+	transport := defaultTransport.Clone()
+
+	if stringSet(s.HTTPProxy) {
+		proxy, err := url.Parse(s.HTTPProxy.ValueString())
+		switch {
+		case err != nil:
+			// Report the cause only: url.Error quotes the URL, password included.
+			var urlErr *url.Error
+			if errors.As(err, &urlErr) {
+				err = urlErr.Err
+			}
+			diags.AddAttributeError(path.Root("http_proxy"), "Invalid http_proxy", fmt.Sprintf("Could not parse the proxy URL: %s.", err))
+		case (proxy.Scheme != "http" && proxy.Scheme != "https" && proxy.Scheme != "socks5") || proxy.Host == "":
+			diags.AddAttributeError(path.Root("http_proxy"), "Invalid http_proxy",
+				"The proxy URL must have the form scheme://[user:password@]host:port with an http, https or socks5 scheme.")
+		default:
+			// Credentials in the URL are sent as Proxy-Authorization.
+			transport.Proxy = http.ProxyURL(proxy)
+		}
+	}
+
+	tlsConfig := &tls.Config{MinVersion: tls.VersionTLS12}
+	if stringSet(s.CACertFile) || stringSet(s.CACertPEM) {
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			pool = x509.NewCertPool()
+		}
+		if stringSet(s.CACertFile) {
+			pemData, err := os.ReadFile(s.CACertFile.ValueString())
+			if err != nil {
+				diags.AddAttributeError(path.Root("ca_cert_file"), "Invalid ca_cert_file", fmt.Sprintf("Could not read the CA bundle: %s.", err))
+			} else if !pool.AppendCertsFromPEM(pemData) {
+				diags.AddAttributeError(path.Root("ca_cert_file"), "Invalid ca_cert_file",
+					fmt.Sprintf("%s does not contain any PEM-encoded certificate.", s.CACertFile.ValueString()))
+			}
+		}
+		if stringSet(s.CACertPEM) && !pool.AppendCertsFromPEM([]byte(s.CACertPEM.ValueString())) {
+			diags.AddAttributeError(path.Root("ca_cert_pem"), "Invalid ca_cert_pem", "The value does not contain any PEM-encoded certificate.")
+		}
+		tlsConfig.RootCAs = pool
+	}
+
+	switch {
+	case stringSet(s.ClientCertPEM) && stringSet(s.ClientKeyPEM):
+		cert, err := tls.X509KeyPair([]byte(s.ClientCertPEM.ValueString()), []byte(s.ClientKeyPEM.ValueString()))
+		if err != nil {
+			diags.AddAttributeError(path.Root("client_cert_pem"), "Invalid client certificate",
+				fmt.Sprintf("Could not load the client certificate and key: %s.", err))
+		} else {
+			tlsConfig.Certificates = []tls.Certificate{cert}
+		}
+	case stringSet(s.ClientCertPEM):
+		diags.AddAttributeError(path.Root("client_key_pem"), "Missing client_key_pem", "client_cert_pem requires client_key_pem.")
+	case stringSet(s.ClientKeyPEM):
+		diags.AddAttributeError(path.Root("client_cert_pem"), "Missing client_cert_pem", "client_key_pem requires client_cert_pem.")
+	}
+
+	if s.InsecureSkipVerify.ValueBool() {
+		tlsConfig.InsecureSkipVerify = true //nolint:gosec // explicitly requested by the user
+		diags.AddAttributeWarning(path.Root("insecure_skip_verify"), "TLS certificate verification is disabled",
+			"insecure_skip_verify is true, so the provider does not verify the certificates of the ArubaCloud API and token issuer. "+
+				"Credentials and tokens can be intercepted. Use ca_cert_file or ca_cert_pem to trust a private CA instead.")
+	}
+
+	transport.TLSClientConfig = tlsConfig
+	return transport
+}
@@ -0,0 +1,97 @@
+package provider
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+var _ function.Function = &ParseURIFunction{}
+
+// ParseURIFunction implements provider::arubacloud::parse_uri.
+type ParseURIFunction struct{}
+
+// uriSegmentAttrTypes describes one element of the parse_uri "parents" list.
+var uriSegmentAttrTypes = map[string]attr.Type{
+	"kind": types.StringType,
+	"id":   types.StringType,
+}
+
+// parsedURIAttrTypes describes the object returned by parse_uri.
+var parsedURIAttrTypes = map[string]attr.Type{
+	"project_id": types.StringType,
+	"provider":   types.StringType,
+	"kind":       types.StringType,
+	"id":         types.StringType,
+	"parents":    types.ListType{ElemType: types.ObjectType{AttrTypes: uriSegmentAttrTypes}},
+}
+
+func NewParseURIFunction() function.Function {
+	return &ParseURIFunction{}
+}
+
+func (f *ParseURIFunction) Metadata(ctx context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "parse_uri"
+}
+
+func (f *ParseURIFunction) Definition(ctx context.Context, req function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary: "Split an ArubaCloud resource URI into its components",
+		MarkdownDescription: "Parses an ArubaCloud resource URI such as `/projects/<project_id>/providers/Aruba.Network/vpcs/<vpc_id>` " +
+			"and returns an object with `project_id`, `provider` (the provider namespace, e.g. `Aruba.Network`; null for project URIs), " +
+			"`kind` (e.g. `vpc`, matching the `arubacloud_<kind>` resource type), `id`, and `parents` — a list of `{ kind, id }` objects " +
+			"for the enclosing resources, outermost first. Legacy short URIs (`/projects/<p>/network/...`) are also accepted. " +
+			"URIs of kinds the provider does not know are still parsed; `kind` then holds the raw collection segment.",
+		Parameters: []function.Parameter{
+			function.StringParameter{
+				Name:                "uri",
+				MarkdownDescription: "ArubaCloud resource URI to parse.",
+			},
+		},
+		Return: function.ObjectReturn{AttributeTypes: parsedURIAttrTypes},
+	}
+}
+
+func (f *ParseURIFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var uri string
+	resp.Error = function.ConcatFuncErrors(resp.Error, req.Arguments.Get(ctx, &uri))
+	if resp.Error != nil {
+		return
+	}
+
+	parsed, err := ParseResourceURI(uri)
+	if err != nil {
+		resp.Error = function.NewArgumentFuncError(0, err.Error())
+		return
+	}
+
+	parents := make([]attr.Value, 0, len(parsed.Parents))
+	for _, p := range parsed.Parents {
+		parent, diags := types.ObjectValue(uriSegmentAttrTypes, map[string]attr.Value{
+			"kind": types.StringValue(p.Kind),
+			"id":   types.StringValue(p.ID),
+		})
+		resp.Error = function.ConcatFuncErrors(resp.Error, function.FuncErrorFromDiags(ctx, diags))
+		parents = append(parents, parent)
+	}
+	parentList, diags := types.ListValue(types.ObjectType{AttrTypes: uriSegmentAttrTypes}, parents)
+	resp.Error = function.ConcatFuncErrors(resp.Error, function.FuncErrorFromDiags(ctx, diags))
+	if resp.Error != nil {
+		return
+	}
+
+	result, diags := types.ObjectValue(parsedURIAttrTypes, map[string]attr.Value{
+		"project_id": types.StringValue(parsed.ProjectID),
+		"provider":   strVal(parsed.Namespace),
+		"kind":       types.StringValue(parsed.Kind),
+		"id":         types.StringValue(parsed.ID),
+		"parents":    parentList,
+	})
+	resp.Error = function.ConcatFuncErrors(resp.Error, function.FuncErrorFromDiags(ctx, diags))
+	if resp.Error != nil {
+		return
+	}
+	resp.Error = function.ConcatFuncErrors(resp.Error, resp.Result.Set(ctx, result))
+}
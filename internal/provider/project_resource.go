@@ -6,6 +6,7 @@ import (
 	"time"
 
 	aruba "github.com/Arubacloud/sdk-go/pkg/aruba"
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
@@ -16,6 +17,7 @@ import (
 )
 
 var _ resource.Resource = &ProjectResource{}
+var _ resource.ResourceWithModifyPlan = &ProjectResource{}
 var _ resource.ResourceWithImportState = &ProjectResource{}
 
 func NewProjectResource() resource.Resource {
@@ -27,11 +29,13 @@ type ProjectResource struct {
 }
 
 type ProjectResourceModel struct {
-	Name        types.String `tfsdk:"name"`
-	Description types.String `tfsdk:"description"`
-	Tags        types.List   `tfsdk:"tags"`
-	Id          types.String `tfsdk:"id"`
-	Timeout     types.String `tfsdk:"timeout"`
+	Name        types.String   `tfsdk:"name"`
+	Description types.String   `tfsdk:"description"`
+	Tags        types.List     `tfsdk:"tags"`
+	TagsAll     types.List     `tfsdk:"tags_all"`
+	Id          types.String   `tfsdk:"id"`
+	Timeout     types.String   `tfsdk:"timeout"`
+	Timeouts    timeouts.Value `tfsdk:"timeouts"`
 }
 
 func (r *ProjectResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
@@ -55,16 +59,21 @@ func (r *ProjectResource) Schema(ctx context.Context, req resource.SchemaRequest
 				MarkdownDescription: "List of string tags attached to the resource for filtering and organisation.",
 				Optional:            true,
 			},
+			"tags_all": tagsAllAttribute(),
 			"id": schema.StringAttribute{
 				MarkdownDescription: "Computed by the API. Unique identifier for the resource.",
 				Computed:            true,
 				PlanModifiers:       []planmodifier.String{stringplanmodifier.UseStateForUnknown()},
 			},
 			"timeout": schema.StringAttribute{
-				MarkdownDescription: "Per-resource timeout override (e.g. `\"15m\"`, `\"1h\"`). Overrides the provider-level `resource_timeout` for this resource's Create and Delete operations. Uses Go duration syntax.",
+				MarkdownDescription: "Per-resource timeout override (e.g. `\"15m\"`, `\"1h\"`) applied to every operation not set in the `timeouts` block. Overrides the provider-level `resource_timeout`. Uses Go duration syntax. Deprecated — use the `timeouts` block instead.",
+				DeprecationMessage:  "Use the timeouts block, which sets the create, read, update and delete timeouts individually.",
 				Optional:            true,
 			},
 		},
+		Blocks: map[string]schema.Block{
+			"timeouts": timeouts.BlockAll(ctx),
+		},
 	}
 }
 
@@ -84,7 +93,7 @@ func (r *ProjectResource) Configure(ctx context.Context, req resource.ConfigureR
 }
 
 func projectRef(data *ProjectResourceModel) aruba.Ref {
-	return aruba.URI("/projects/" + data.Id.ValueString())
+	return newResourceURI(uriKindProject, data.Id.ValueString(), "").Ref()
 }
 
 func applyProjectToModel(project *aruba.Project, data *ProjectResourceModel) {
@@ -95,17 +104,28 @@ func applyProjectToModel(project *aruba.Project, data *ProjectResourceModel) {
 	} else {
 		data.Description = types.StringNull()
 	}
-	data.Tags = TagsToListPreserveNull(project.Tags(), data.Tags)
+	data.Tags, data.TagsAll = flattenTags(project.Tags(), data.Tags, data.TagsAll)
+}
+
+func (r *ProjectResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	r.client.planTagsAll(ctx, req, resp, true)
 }
 
 func (r *ProjectResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	ctx = withCorrelationID(ctx)
 	var data ProjectResourceModel
 	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	timeout := r.client.operationTimeout(ctx, data.Timeouts.Create, data.Timeout, &resp.Diagnostics)
 	if resp.Diagnostics.HasError() {
 		return
 	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	if !r.client.checkGuardrailsAt("create", "Project", path.Root("id"), data.Id, &resp.Diagnostics) {
+		return
+	}
 
-	tags := ListToTags(ctx, data.Tags, &resp.Diagnostics)
+	tags := r.client.requestTags(ctx, data.Tags, &data.TagsAll, &resp.Diagnostics)
 	if resp.Diagnostics.HasError() {
 		return
 	}
@@ -120,7 +140,7 @@ func (r *ProjectResource) Create(ctx context.Context, req resource.CreateRequest
 
 	project, err := r.client.Client.FromProject().Create(ctx, builder)
 	if provErr := CheckResponseErr("create", "Project", err); provErr != nil {
-		resp.Diagnostics.AddError("API Error", provErr.Error())
+		AppendAPIError(&resp.Diagnostics, provErr)
 		return
 	}
 
@@ -134,11 +154,18 @@ func (r *ProjectResource) Create(ctx context.Context, req resource.CreateRequest
 }
 
 func (r *ProjectResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	ctx = withCorrelationID(ctx)
 	var data ProjectResourceModel
 	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	timeout := r.client.operationTimeout(ctx, data.Timeouts.Read, data.Timeout, &resp.Diagnostics)
 	if resp.Diagnostics.HasError() {
 		return
 	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	if !r.client.checkGuardrailsAt("read", "Project", path.Root("id"), data.Id, &resp.Diagnostics) {
+		return
+	}
 
 	if data.Id.IsUnknown() || data.Id.IsNull() || data.Id.ValueString() == "" {
 		resp.State.RemoveResource(ctx)
@@ -151,7 +178,7 @@ func (r *ProjectResource) Read(ctx context.Context, req resource.ReadRequest, re
 			resp.State.RemoveResource(ctx)
 			return
 		}
-		resp.Diagnostics.AddError("API Error", provErr.Error())
+		AppendAPIError(&resp.Diagnostics, provErr)
 		return
 	}
 
@@ -160,23 +187,30 @@ func (r *ProjectResource) Read(ctx context.Context, req resource.ReadRequest, re
 }
 
 func (r *ProjectResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	ctx = withCorrelationID(ctx)
 	var data ProjectResourceModel
 	var state ProjectResourceModel
 
 	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
 	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	timeout := r.client.operationTimeout(ctx, data.Timeouts.Update, data.Timeout, &resp.Diagnostics)
 	if resp.Diagnostics.HasError() {
 		return
 	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	if !r.client.checkGuardrailsAt("update", "Project", path.Root("id"), state.Id, &resp.Diagnostics) {
+		return
+	}
 
-	tags := ListToTags(ctx, data.Tags, &resp.Diagnostics)
+	tags := r.client.requestTags(ctx, data.Tags, &data.TagsAll, &resp.Diagnostics)
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
 	project, err := r.client.Client.FromProject().Get(ctx, projectRef(&state))
 	if provErr := CheckResponseErr("read", "Project", err); provErr != nil {
-		resp.Diagnostics.AddError("API Error", provErr.Error())
+		AppendAPIError(&resp.Diagnostics, provErr)
 		return
 	}
 
@@ -194,7 +228,7 @@ func (r *ProjectResource) Update(ctx context.Context, req resource.UpdateRequest
 
 	updated, err := r.client.Client.FromProject().Update(ctx, project)
 	if provErr := CheckResponseErr("update", "Project", err); provErr != nil {
-		resp.Diagnostics.AddError("API Error", provErr.Error())
+		AppendAPIError(&resp.Diagnostics, provErr)
 		return
 	}
 
@@ -205,11 +239,18 @@ func (r *ProjectResource) Update(ctx context.Context, req resource.UpdateRequest
 }
 
 func (r *ProjectResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	ctx = withCorrelationID(ctx)
 	var data ProjectResourceModel
 	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	timeout := r.client.operationTimeout(ctx, data.Timeouts.Delete, data.Timeout, &resp.Diagnostics)
 	if resp.Diagnostics.HasError() {
 		return
 	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	if !r.client.checkGuardrailsAt("delete", "Project", path.Root("id"), data.Id, &resp.Diagnostics) {
+		return
+	}
 
 	projectID := data.Id.ValueString()
 	if projectID == "" {
@@ -234,13 +275,13 @@ func (r *ProjectResource) Delete(ctx context.Context, req resource.DeleteRequest
 	err := DeleteResourceWithRetry(ctx, func() error {
 		return CheckResponseErrAsError("delete", "Project",
 			r.client.Client.FromProject().Delete(ctx, ref))
-	}, "Project", projectID, effectiveTimeout(data.Timeout, r.client.ResourceTimeout), deletionChecker)
+	}, "Project", projectID, timeout, deletionChecker)
 	if err != nil {
 		resp.Diagnostics.AddError("Error deleting project", err.Error())
 		return
 	}
 
-	if waitErr := WaitForResourceDeleted(ctx, deletionChecker, "Project", projectID, remainingTimeout(deleteStart, effectiveTimeout(data.Timeout, r.client.ResourceTimeout))); waitErr != nil {
+	if waitErr := WaitForResourceDeleted(ctx, deletionChecker, "Project", projectID, remainingTimeout(deleteStart, timeout)); waitErr != nil {
 		resp.Diagnostics.AddError("Error waiting for Project deletion", waitErr.Error())
 		return
 	}
@@ -3,10 +3,15 @@ package provider
 import (
 	"context"
 	"fmt"
+	"net/http"
 	"os"
 	"time"
 
 	"github.com/Arubacloud/sdk-go/pkg/aruba"
+	"github.com/hashicorp/terraform-plugin-framework-validators/float64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/listvalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
 	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
@@ -17,6 +22,7 @@ import (
 	"github.com/hashicorp/terraform-plugin-framework/provider"
 	"github.com/hashicorp/terraform-plugin-framework/provider/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 )
 
@@ -31,16 +37,54 @@ type ArubaCloudProvider struct {
 	// provider is built and ran locally, and "test" when running acceptance
 	// testing.
 	version string
+	// wrapTransport, set only by the acceptance tests through
+	// NewWithTransport, wraps the transport every SDK request is sent over.
+	wrapTransport func(http.RoundTripper) (http.RoundTripper, error)
+	// replayWait, set only by the acceptance tests through WithReplayWaits,
+	// replaces the delay of every wait loop and retry once configured.
+	replayWait time.Duration
 }
 
 // ArubaCloudProviderModel describes the provider data model.
 type ArubaCloudProviderModel struct {
-	ClientID        types.String `tfsdk:"client_id"`
-	ClientSecret    types.String `tfsdk:"client_secret"`
-	ResourceTimeout types.String `tfsdk:"resource_timeout"`
-	BaseURL         types.String `tfsdk:"base_url"`
-	TokenIssuerURL  types.String `tfsdk:"token_issuer_url"`
-	LogLevel        types.String `tfsdk:"log_level"`
+	ClientID         types.String `tfsdk:"client_id"`
+	ClientSecret     types.String `tfsdk:"client_secret"`
+	ResourceTimeout  types.String `tfsdk:"resource_timeout"`
+	BaseURL          types.String `tfsdk:"base_url"`
+	TokenIssuerURL   types.String `tfsdk:"token_issuer_url"`
+	LogLevel         types.String `tfsdk:"log_level"`
+	DefaultTags      types.List   `tfsdk:"default_tags"`
+	DefaultProjectID types.String `tfsdk:"default_project_id"`
+	DefaultLocation  types.String `tfsdk:"default_location"`
+	DefaultZone      types.String `tfsdk:"default_zone"`
+
+	MaxConcurrentRequests types.Int64   `tfsdk:"max_concurrent_requests"`
+	RequestsPerSecond     types.Float64 `tfsdk:"requests_per_second"`
+
+	Retry *ProviderRetryModel `tfsdk:"retry"`
+
+	Profile               types.String `tfsdk:"profile"`
+	SharedCredentialsFile types.String `tfsdk:"shared_credentials_file"`
+	CredentialProcess     types.String `tfsdk:"credential_process"`
+	AccessToken           types.String `tfsdk:"access_token"`
+
+	HTTPProxy          types.String `tfsdk:"http_proxy"`
+	CACertFile         types.String `tfsdk:"ca_cert_file"`
+	CACertPEM          types.String `tfsdk:"ca_cert_pem"`
+	ClientCertPEM      types.String `tfsdk:"client_cert_pem"`
+	ClientKeyPEM       types.String `tfsdk:"client_key_pem"`
+	InsecureSkipVerify types.Bool   `tfsdk:"insecure_skip_verify"`
+
+	TokenCacheDir types.String `tfsdk:"token_cache_dir"`
+
+	AllowedProjectIDs        types.List `tfsdk:"allowed_project_ids"`
+	ForbiddenProjectIDs      types.List `tfsdk:"forbidden_project_ids"`
+	ReadOnly                 types.Bool `tfsdk:"read_only"`
+	FailOnDestructiveReplace types.Bool `tfsdk:"fail_on_destructive_replace"`
+
+	AuditLogPath    types.String `tfsdk:"audit_log_path"`
+	HTTPTraceFile   types.String `tfsdk:"http_trace_file"`
+	HTTPTraceRedact types.List   `tfsdk:"http_trace_redact"`
 }
 
 func (p *ArubaCloudProvider) Metadata(ctx context.Context, req provider.MetadataRequest, resp *provider.MetadataResponse) {
@@ -60,12 +104,47 @@ func (p *ArubaCloudProvider) Schema(ctx context.Context, req provider.SchemaRequ
 				Optional:            true,
 				Sensitive:           true,
 			},
+			"profile": schema.StringAttribute{
+				MarkdownDescription: "(Optional) Name of the shared credentials file profile to read `client_id`, `client_secret`, " +
+					"`base_url`, `token_issuer_url` and the `default_*` settings from. Provider attributes and environment variables " +
+					"take precedence over the profile. Can also be set via the `ARUBACLOUD_PROFILE` environment variable. " +
+					"Default: `default` (ignored when it does not exist).",
+				Optional: true,
+			},
+			"shared_credentials_file": schema.StringAttribute{
+				MarkdownDescription: "(Optional) Path of the shared credentials file, in INI or YAML format. " +
+					"Can also be set via the `ARUBACLOUD_SHARED_CREDENTIALS_FILE` environment variable. " +
+					"Default: `~/.arubacloud/credentials`.",
+				Optional: true,
+			},
+			"credential_process": schema.StringAttribute{
+				MarkdownDescription: "(Optional) Command that prints the client credentials as a JSON object with `client_id`, " +
+					"`client_secret` and an optional RFC 3339 `expiry`. It is run without a shell, only when `client_id` or " +
+					"`client_secret` is not set by the provider configuration, the environment or the profile, and its output " +
+					"is cached for the life of the provider process. Can also be set in a shared credentials profile.",
+				Optional: true,
+			},
+			"access_token": schema.StringAttribute{
+				MarkdownDescription: "(Optional) Bearer token used to authenticate API calls instead of the OAuth2 client-credentials flow, " +
+					"for pipelines that obtain short-lived tokens from a central broker. Conflicts with `client_id`, `client_secret` " +
+					"and `credential_process`, including those of the shared credentials profile. Can also be set via the `ARUBACLOUD_ACCESS_TOKEN` environment variable. " +
+					"The token is not refreshed; when it is a JWT, the provider warns if it expires before a long-running operation can finish.",
+				Optional:  true,
+				Sensitive: true,
+				Validators: []validator.String{
+					stringvalidator.ConflictsWith(
+						path.MatchRoot("client_id"),
+						path.MatchRoot("client_secret"),
+						path.MatchRoot("credential_process"),
+					),
+				},
+			},
 			"resource_timeout": schema.StringAttribute{
-				MarkdownDescription: "Timeout for waiting for resources to become active after creation (e.g., \"10m\", \"20m\", \"30m\"). This timeout applies to all resources that need to wait for active state. Default: \"30m\"",
+				MarkdownDescription: "Default timeout for each resource operation, covering every API call, wait and retry it makes (e.g., \"10m\", \"20m\", \"30m\"). A resource's `timeouts` block overrides it per operation. Default: \"30m\"",
 				Optional:            true,
 			},
 			"base_url": schema.StringAttribute{
-				MarkdownDescription: "(Optional) Override the ArubaCloud API base URL (advanced use only).",
+				MarkdownDescription: "(Optional) Override the ArubaCloud API base URL (advanced use only). Can also be set via the `ARUBACLOUD_BASE_URL` environment variable.",
 				Optional:            true,
 			},
 			"token_issuer_url": schema.StringAttribute{
@@ -80,20 +159,188 @@ func (p *ArubaCloudProvider) Schema(ctx context.Context, req provider.SchemaRequ
 					"Can also be set via the `ARUBACLOUD_LOG_LEVEL` environment variable; the HCL attribute takes precedence. " +
 					"SDK messages are emitted under the `arubacloud-sdk` subsystem and are subject to the `TF_LOG` filter — " +
 					"both `log_level` and `TF_LOG` must permit a message for it to appear. " +
-					"Note: the SDK already redacts `Authorization` headers, but request/response bodies " +
-					"may contain sensitive data — avoid committing debug logs to version control.",
+					"Credentials and the values of sensitive fields (`password`, `secret`, `psk`, `user_data`, `value`, " +
+					"`kubeconfig`, tokens and the client secret) are redacted from SDK messages, but logs may still " +
+					"contain other data about your infrastructure — avoid committing debug logs to version control.",
+				Optional: true,
+			},
+			"default_tags": schema.ListAttribute{
+				ElementType: types.StringType,
+				MarkdownDescription: "(Optional) Tags added to every taggable resource, on top of the resource's own `tags`. " +
+					"A resource tag overrides a default tag with the same key (the part before the first `:`). " +
+					"The merged list is exposed on each resource as the computed `tags_all` attribute; " +
+					"tags that come only from `default_tags` never appear as drift on `tags`.",
+				Optional: true,
+			},
+			"default_project_id": schema.StringAttribute{
+				MarkdownDescription: "(Optional) Project ID used by every resource that omits `project_id`. " +
+					"Can also be set via the `ARUBACLOUD_PROJECT_ID` environment variable; the HCL attribute takes precedence.",
+				Optional: true,
+			},
+			"default_location": schema.StringAttribute{
+				MarkdownDescription: "(Optional) Location (e.g. `ITBG-Bergamo`) used by every resource that omits `location`. " +
+					"Can also be set via the `ARUBACLOUD_LOCATION` environment variable; the HCL attribute takes precedence.",
+				Optional: true,
+			},
+			"default_zone": schema.StringAttribute{
+				MarkdownDescription: "(Optional) Zone (e.g. `ITBG-1`) used by every resource with a required `zone` that omits it. " +
+					"Can also be set via the `ARUBACLOUD_ZONE` environment variable; the HCL attribute takes precedence.",
+				Optional: true,
+			},
+			"max_concurrent_requests": schema.Int64Attribute{
+				MarkdownDescription: "(Optional) Maximum number of API requests in flight at once, shared by every resource, " +
+					"data source and wait loop of this provider instance. " +
+					"Use it to stay under API throttling when running with a high `-parallelism`. Default: `0` (unlimited).",
+				Optional: true,
+				Validators: []validator.Int64{
+					int64validator.AtLeast(0),
+				},
+			},
+			"requests_per_second": schema.Float64Attribute{
+				MarkdownDescription: "(Optional) Maximum rate of API requests, shared by every resource, " +
+					"data source and wait loop of this provider instance. Fractional values such as `0.5` are allowed. " +
+					"Requests above the rate are delayed, not rejected. Default: `0` (unlimited).",
+				Optional: true,
+				Validators: []validator.Float64{
+					float64validator.AtLeast(0),
+				},
+			},
+			"http_proxy": schema.StringAttribute{
+				MarkdownDescription: "(Optional) Proxy for all API and token issuer traffic, as `http://[user:password@]host:port` " +
+					"(`https` and `socks5` schemes are also accepted). Credentials in the URL are sent as `Proxy-Authorization`. " +
+					"Default: the `HTTPS_PROXY`, `HTTP_PROXY` and `NO_PROXY` environment variables.",
+				Optional:  true,
+				Sensitive: true,
+			},
+			"ca_cert_file": schema.StringAttribute{
+				MarkdownDescription: "(Optional) Path of a PEM bundle of additional root CAs trusted for the API and the token issuer, " +
+					"e.g. a corporate TLS-inspection CA. The system roots remain trusted.",
+				Optional: true,
+			},
+			"ca_cert_pem": schema.StringAttribute{
+				MarkdownDescription: "(Optional) PEM-encoded additional root CAs, like `ca_cert_file` but inline. Both may be set.",
+				Optional:            true,
+			},
+			"client_cert_pem": schema.StringAttribute{
+				MarkdownDescription: "(Optional) PEM-encoded client certificate presented to gateways that require mutual TLS. Requires `client_key_pem`.",
+				Optional:            true,
+				Validators: []validator.String{
+					stringvalidator.AlsoRequires(path.MatchRoot("client_key_pem")),
+				},
+			},
+			"client_key_pem": schema.StringAttribute{
+				MarkdownDescription: "(Optional) PEM-encoded private key of `client_cert_pem`.",
+				Optional:            true,
+				Sensitive:           true,
+				Validators: []validator.String{
+					stringvalidator.AlsoRequires(path.MatchRoot("client_cert_pem")),
+				},
+			},
+			"insecure_skip_verify": schema.BoolAttribute{
+				MarkdownDescription: "(Optional) Skip verification of the API and token issuer TLS certificates. " +
+					"Intended for troubleshooting only; prefer `ca_cert_file` or `ca_cert_pem`. Default: `false`.",
+				Optional: true,
+			},
+			"token_cache_dir": schema.StringAttribute{
+				MarkdownDescription: "(Optional) Directory where OAuth2 tokens are cached between provider processes, keyed by client ID " +
+					"and token issuer, so that validate, plan and apply reuse one token. Files are created with `0600` permissions. " +
+					"A cached token is refreshed 5 minutes before it expires, and is still used while the token issuer is unavailable " +
+					"until it actually expires. Can also be set via the `ARUBACLOUD_TOKEN_CACHE_DIR` environment variable. " +
+					"Default: no cache.",
+				Optional: true,
+			},
+			"allowed_project_ids": schema.ListAttribute{
+				ElementType: types.StringType,
+				MarkdownDescription: "(Optional) Project IDs the provider may operate on. Every resource and data source targeting " +
+					"another project fails before calling the API, and so does the plan of a resource whose `project_id`, set or " +
+					"defaulted, is not listed. Conflicts with `forbidden_project_ids`.",
+				Optional: true,
+				Validators: []validator.List{
+					listvalidator.SizeAtLeast(1),
+					listvalidator.ConflictsWith(path.MatchRoot("forbidden_project_ids")),
+				},
+			},
+			"forbidden_project_ids": schema.ListAttribute{
+				ElementType: types.StringType,
+				MarkdownDescription: "(Optional) Project IDs the provider must never operate on. Every resource and data source " +
+					"targeting one of them fails before calling the API. Conflicts with `allowed_project_ids`.",
+				Optional: true,
+				Validators: []validator.List{
+					listvalidator.SizeAtLeast(1),
+				},
+			},
+			"read_only": schema.BoolAttribute{
+				MarkdownDescription: "(Optional) Reject every create, update and delete with an error before calling the API. " +
+					"Plans, refreshes, imports and data sources keep working. Default: `false`.",
+				Optional: true,
+			},
+			"fail_on_destructive_replace": schema.BoolAttribute{
+				MarkdownDescription: "(Optional) Fail the plan instead of warning when it replaces a resource that holds data, " +
+					"such as a block storage volume, a DBaaS cluster, a database or a backup, because an attribute that forces " +
+					"replacement changed. Default: `false`.",
+				Optional: true,
+			},
+			"audit_log_path": schema.StringAttribute{
+				MarkdownDescription: "(Optional) File to which every create, update and delete request sent to the API, including " +
+					"each retry, is appended as a JSON line with the timestamp, operation, resource type and name, URI, HTTP status, " +
+					"duration and attempt number. The file is created with `0600` permissions and is safe to share between " +
+					"concurrent operations and provider processes. Can also be set via the `ARUBACLOUD_AUDIT_LOG_PATH` " +
+					"environment variable. Default: no audit log.",
+				Optional: true,
+			},
+			"http_trace_file": schema.StringAttribute{
+				MarkdownDescription: "(Optional) File to which every HTTP exchange with the API and the token issuer is written as " +
+					"a HAR 1.2 archive, with request and response headers and bodies, for attaching to support tickets. The " +
+					"`Authorization` header, cookies, OAuth2 tokens, the client secret and the fields listed in `http_trace_redact` " +
+					"are redacted. The file is created with `0600` permissions, is a valid HAR document after every exchange, and " +
+					"later runs append to it. Can also be set via the `ARUBACLOUD_HTTP_TRACE_FILE` environment variable. " +
+					"Default: no trace.",
+				Optional: true,
+			},
+			"http_trace_redact": schema.ListAttribute{
+				ElementType: types.StringType,
+				MarkdownDescription: "(Optional) Fields whose values are replaced with `REDACTED` in `http_trace_file`, in JSON " +
+					"bodies, form bodies and query strings. A field matches when its name, ignoring case, `_` and `-`, ends with " +
+					"an entry, so `password` also matches `adminPassword`. Replaces the default list: `password`, `secret`, `psk`, " +
+					"`user_data`, `value`, `kubeconfig`.",
 				Optional: true,
 			},
 		},
+		Blocks: map[string]schema.Block{
+			"retry": schema.SingleNestedBlock{
+				MarkdownDescription: "(Optional) Retry policy for reads (`GET`, including list calls and wait-loop polls) and updates " +
+					"that fail with a technical error: an HTTP 5xx response or a network failure with no response, " +
+					"or with a 429 response without a Retry-After header. " +
+					"Create and delete keep their own retry loops. Without this block, requests are tried up to 3 times " +
+					"with a 1s base delay, a 30s maximum delay and jitter.",
+				Attributes: map[string]schema.Attribute{
+					"max_attempts": schema.Int64Attribute{
+						MarkdownDescription: "Total number of attempts per request, including the first. `1` disables retries. Default: `3`.",
+						Optional:            true,
+						Validators: []validator.Int64{
+							int64validator.AtLeast(1),
+						},
+					},
+					"base_delay": schema.StringAttribute{
+						MarkdownDescription: "Delay before the first retry; it doubles on every further retry. Uses Go duration syntax. Default: `\"1s\"`.",
+						Optional:            true,
+					},
+					"max_delay": schema.StringAttribute{
+						MarkdownDescription: "Upper bound for the delay between two attempts. Uses Go duration syntax. Default: `\"30s\"`.",
+						Optional:            true,
+					},
+					"jitter": schema.BoolAttribute{
+						MarkdownDescription: "Randomise each delay between half and all of its nominal value, so that parallel operations do not retry in lockstep. Default: `true`.",
+						Optional:            true,
+					},
+				},
+			},
+		},
 	}
 }
 
 func (p *ArubaCloudProvider) Configure(ctx context.Context, req provider.ConfigureRequest, resp *provider.ConfigureResponse) {
 
-	// Default values to environment variables, but override with Terraform configuration value if set.
-	clientID := os.Getenv("ARUBACLOUD_CLIENT_ID")
-	clientSecret := os.Getenv("ARUBACLOUD_CLIENT_SECRET")
-	tokenIssuerURL := os.Getenv("ARUBACLOUD_TOKEN_ISSUER_URL")
 	logLevelStr := os.Getenv("ARUBACLOUD_LOG_LEVEL")
 
 	// Retrieve provider data from configuration
@@ -104,37 +351,136 @@ func (p *ArubaCloudProvider) Configure(ctx context.Context, req provider.Configu
 		return
 	}
 
-	if !config.ClientID.IsNull() {
-		clientID = config.ClientID.ValueString()
+	if !config.LogLevel.IsNull() && config.LogLevel.ValueString() != "" {
+		logLevelStr = config.LogLevel.ValueString()
 	}
 
-	if !config.ClientSecret.IsNull() {
-		clientSecret = config.ClientSecret.ValueString()
+	// Select the shared credentials profile. Only an explicitly selected
+	// profile has to exist; the implicit "default" profile is optional.
+	profileName, profileExplicit := defaultProfileName, false
+	if v := os.Getenv("ARUBACLOUD_PROFILE"); v != "" {
+		profileName, profileExplicit = v, true
+	}
+	if !config.Profile.IsNull() && config.Profile.ValueString() != "" {
+		profileName, profileExplicit = config.Profile.ValueString(), true
+	}
+	credentialsFile := defaultCredentialsFile
+	if v := os.Getenv("ARUBACLOUD_SHARED_CREDENTIALS_FILE"); v != "" {
+		credentialsFile = v
+	}
+	if !config.SharedCredentialsFile.IsNull() && config.SharedCredentialsFile.ValueString() != "" {
+		credentialsFile = config.SharedCredentialsFile.ValueString()
+	}
+	profile, err := loadCredentialsProfile(credentialsFile, profileName, profileExplicit)
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("profile"),
+			"Invalid ArubaCloud Credentials Profile",
+			fmt.Sprintf("The provider cannot load the shared credentials profile: %s.", err),
+		)
+		return
 	}
 
-	if !config.TokenIssuerURL.IsNull() && config.TokenIssuerURL.ValueString() != "" {
-		tokenIssuerURL = config.TokenIssuerURL.ValueString()
+	// Each setting comes from the provider configuration, then its
+	// environment variable, then the selected profile.
+	settings := settingResolver{profile: profile}
+	clientID, clientIDSource := settings.resolve("client_id", config.ClientID, "ARUBACLOUD_CLIENT_ID")
+	clientSecret, clientSecretSource := settings.resolve("client_secret", config.ClientSecret, "ARUBACLOUD_CLIENT_SECRET")
+	baseURL, _ := settings.resolve("base_url", config.BaseURL, "ARUBACLOUD_BASE_URL")
+	tokenIssuerURL, _ := settings.resolve("token_issuer_url", config.TokenIssuerURL, "ARUBACLOUD_TOKEN_ISSUER_URL")
+	defaultProjectID, _ := settings.resolve("default_project_id", config.DefaultProjectID, "ARUBACLOUD_PROJECT_ID")
+	defaultLocation, _ := settings.resolve("default_location", config.DefaultLocation, "ARUBACLOUD_LOCATION")
+	defaultZone, _ := settings.resolve("default_zone", config.DefaultZone, "ARUBACLOUD_ZONE")
+
+	credentialProcess, credentialProcessOrigin := settings.resolve("credential_process", config.CredentialProcess, "")
+
+	// An access token replaces the client-credentials flow, so client_id,
+	// client_secret and credential_process must not be set by any source,
+	// the shared credentials profile included.
+	accessTokenValue, accessTokenSource := settings.resolve("access_token", config.AccessToken, "ARUBACLOUD_ACCESS_TOKEN")
+	var token *accessToken
+	if accessTokenValue != "" {
+		for _, cred := range []struct {
+			attr, value, source string
+		}{
+			{"client_id", clientID, clientIDSource},
+			{"client_secret", clientSecret, clientSecretSource},
+			{"credential_process", credentialProcess, credentialProcessOrigin},
+		} {
+			if cred.value != "" {
+				resp.Diagnostics.AddAttributeError(
+					path.Root(cred.attr),
+					"Conflicting ArubaCloud Credentials",
+					fmt.Sprintf("An access token is supplied by %s, but %s is also set, by %s. "+
+						"Use either access_token or client credentials, and remove the other from the provider configuration, "+
+						"the environment and the shared credentials profile.", accessTokenSource, cred.attr, cred.source),
+				)
+			}
+		}
+		if resp.Diagnostics.HasError() {
+			return
+		}
+
+		token = newAccessToken(accessTokenValue)
+		if token.expiresBefore(timeNow()) {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("access_token"),
+				"Expired ArubaCloud Access Token",
+				fmt.Sprintf("The access token supplied by %s expired at %s. Request a new token.", accessTokenSource, token.expiry.Format(time.RFC3339)),
+			)
+			return
+		}
 	}
 
-	if !config.LogLevel.IsNull() && config.LogLevel.ValueString() != "" {
-		logLevelStr = config.LogLevel.ValueString()
+	// credential_process only supplies the credentials no other source set.
+	if token == nil && credentialProcess != "" && (clientID == "" || clientSecret == "") {
+		creds, err := runCredentialProcess(ctx, credentialProcess)
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("credential_process"),
+				"ArubaCloud credential_process Failed",
+				fmt.Sprintf("The provider cannot read credentials from the credential_process command set by %s: %s", credentialProcessOrigin, err),
+			)
+			return
+		}
+		source := fmt.Sprintf("the credential_process command set by %s", credentialProcessOrigin)
+		if clientID == "" && creds.ClientID != "" {
+			clientID, clientIDSource = creds.ClientID, source
+		}
+		if clientSecret == "" {
+			clientSecret, clientSecretSource = creds.ClientSecret, source
+		}
+		if creds.Expiry != nil {
+			tflog.Info(ctx, "credential_process credentials expire", map[string]interface{}{
+				"expiry": creds.Expiry.Format(time.RFC3339),
+			})
+		}
+	}
+
+	profileSource := fmt.Sprintf("the shared credentials file %s (no %q profile)", credentialsFile, profileName)
+	if profile != nil {
+		profileSource = profile.source()
 	}
 
-	if clientID == "" {
+	if token == nil && clientID == "" {
 		resp.Diagnostics.AddAttributeError(
 			path.Root("client_id"),
 			"Unknown ArubaCloud Client ID",
 			"The provider cannot create the ArubaCloud API client as there is an unknown configuration value for the client ID. "+
-				"Either target apply the source of the value first, set the value statically in the configuration, or use the ARUBACLOUD_CLIENT_ID environment variable.",
+				"Either target apply the source of the value first, set the value statically in the configuration, use the ARUBACLOUD_CLIENT_ID environment variable, "+
+				"set client_id in a shared credentials profile, or print it from credential_process.\n\n"+
+				credentialSourcesDetail("client ID", "client_id", "ARUBACLOUD_CLIENT_ID", profileSource, "client secret", clientSecretSource),
 		)
 	}
 
-	if clientSecret == "" {
+	if token == nil && clientSecret == "" {
 		resp.Diagnostics.AddAttributeError(
 			path.Root("client_secret"),
 			"Unknown ArubaCloud Client Secret",
 			"The provider cannot create the ArubaCloud API client as there is an unknown configuration value for the client secret. "+
-				"Either target apply the source of the value first, set the value statically in the configuration, or use the ARUBACLOUD_CLIENT_SECRET environment variable.",
+				"Either target apply the source of the value first, set the value statically in the configuration, use the ARUBACLOUD_CLIENT_SECRET environment variable, "+
+				"set client_secret in a shared credentials profile, or print it from credential_process.\n\n"+
+				credentialSourcesDetail("client secret", "client_secret", "ARUBACLOUD_CLIENT_SECRET", profileSource, "client ID", clientIDSource),
 		)
 	}
 
@@ -142,6 +488,17 @@ func (p *ArubaCloudProvider) Configure(ctx context.Context, req provider.Configu
 		return
 	}
 
+	if token != nil {
+		tflog.Info(ctx, "Authenticating with an ArubaCloud access token", map[string]interface{}{
+			"access_token_source": accessTokenSource,
+		})
+	} else {
+		tflog.Info(ctx, "Resolved ArubaCloud credentials", map[string]interface{}{
+			"client_id_source":     clientIDSource,
+			"client_secret_source": clientSecretSource,
+		})
+	}
+
 	// Parse log level — invalid values produce a warning and fall back to Off (no SDK logging).
 	logLevel, err := ParseLogLevel(logLevelStr)
 	if err != nil {
@@ -155,43 +512,151 @@ func (p *ArubaCloudProvider) Configure(ctx context.Context, req provider.Configu
 	// Register the arubacloud-sdk tflog subsystem so its output can be filtered
 	// independently via TF_LOG_PROVIDER_ARUBACLOUD_SDK.
 	ctx = tflog.NewSubsystem(ctx, subsystemName)
-
-	// Create SDK client with credentials using DefaultOptions
-	options := aruba.DefaultOptions(clientID, clientSecret)
+	ctx = maskSensitiveSDKLogFields(ctx)
+
+	// Create SDK client with credentials using DefaultOptions. With an access
+	// token the SDK gets a placeholder client, and apiTransport answers its
+	// token requests with the supplied token.
+	sdkClientID, sdkClientSecret := clientID, clientSecret
+	if token != nil {
+		sdkClientID, sdkClientSecret = accessTokenClientID, accessTokenClientID
+	}
+	options := aruba.DefaultOptions(sdkClientID, sdkClientSecret)
 	options = options.WithCustomLogger(newSDKLogAdapter(ctx, logLevel))
 	options = options.WithUserAgent(fmt.Sprintf("terraform-provider-arubacloud@%s", p.version))
 
 	// Optionally override base URL and token issuer
-	if !config.BaseURL.IsNull() && config.BaseURL.ValueString() != "" {
-		options = options.WithBaseURL(config.BaseURL.ValueString())
+	if baseURL != "" {
+		options = options.WithBaseURL(baseURL)
 	}
 
 	if tokenIssuerURL != "" {
 		options = options.WithTokenIssuerURL(tokenIssuerURL)
 	}
 
+	// Route every SDK request, including wait-loop polls and token requests,
+	// through a single limiter, throttle gate and retry policy shared by all
+	// resources and data sources, over the configured proxy and TLS settings.
+	limiter := newRequestLimiter(config.MaxConcurrentRequests.ValueInt64(), config.RequestsPerSecond.ValueFloat64())
+	retry := newRetryPolicy(config.Retry, &resp.Diagnostics)
+	base := newBaseTransport(networkSettings{
+		HTTPProxy:          config.HTTPProxy,
+		CACertFile:         config.CACertFile,
+		CACertPEM:          config.CACertPEM,
+		ClientCertPEM:      config.ClientCertPEM,
+		ClientKeyPEM:       config.ClientKeyPEM,
+		InsecureSkipVerify: config.InsecureSkipVerify,
+	}, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	if p.wrapTransport != nil {
+		wrapped, err := p.wrapTransport(base)
+		if err != nil {
+			resp.Diagnostics.AddError("Cannot set up the HTTP transport", err.Error())
+			return
+		}
+		base = wrapped
+	}
+	if p.replayWait > 0 {
+		replayDelay.Store(int64(p.replayWait))
+	}
+
+	httpTraceFile := os.Getenv("ARUBACLOUD_HTTP_TRACE_FILE")
+	if !config.HTTPTraceFile.IsNull() && config.HTTPTraceFile.ValueString() != "" {
+		httpTraceFile = config.HTTPTraceFile.ValueString()
+	}
+	if httpTraceFile != "" {
+		redactFields := defaultRedactFields
+		if !config.HTTPTraceRedact.IsNull() && !config.HTTPTraceRedact.IsUnknown() {
+			redactFields = nil
+			resp.Diagnostics.Append(config.HTTPTraceRedact.ElementsAs(ctx, &redactFields, false)...)
+		}
+		trace, err := openHARTrace(httpTraceFile, p.version, redactFields)
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(path.Root("http_trace_file"), "Invalid http_trace_file", err.Error())
+			return
+		}
+		base = trace.transport(base)
+	}
+	transport := newAPITransport(limiter, retry)
+	transport.base = base
+	transport.token = token
+
+	tokenCacheDir := os.Getenv("ARUBACLOUD_TOKEN_CACHE_DIR")
+	if !config.TokenCacheDir.IsNull() && config.TokenCacheDir.ValueString() != "" {
+		tokenCacheDir = config.TokenCacheDir.ValueString()
+	}
+	if tokenCacheDir != "" && token == nil {
+		cache, err := newTokenCache(tokenCacheDir)
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(path.Root("token_cache_dir"), "Invalid token_cache_dir", err.Error())
+			return
+		}
+		transport.tokenCache = cache
+	}
+
+	auditLogPath := os.Getenv("ARUBACLOUD_AUDIT_LOG_PATH")
+	if !config.AuditLogPath.IsNull() && config.AuditLogPath.ValueString() != "" {
+		auditLogPath = config.AuditLogPath.ValueString()
+	}
+	if auditLogPath != "" {
+		audit, err := openAuditLog(auditLogPath)
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(path.Root("audit_log_path"), "Invalid audit_log_path", err.Error())
+			return
+		}
+		transport.audit = audit
+	}
+	options = options.WithCustomHTTPClient(&http.Client{Transport: transport})
+
 	sdkClient, err := aruba.NewClient(options)
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Failed to create ArubaCloud SDK client",
-			NewTransportError("create", "Provider.go", err).Error(),
+			fmt.Sprintf("%s\n\nThe client ID was supplied by %s and the client secret by %s.",
+				NewTransportError("create", "Provider.go", err), clientIDSource, clientSecretSource),
 		)
 		return
 	}
 
+	defaultTags := ListToTags(ctx, config.DefaultTags, &resp.Diagnostics)
+	var allowedProjectIDs, forbiddenProjectIDs []string
+	if !config.AllowedProjectIDs.IsNull() {
+		resp.Diagnostics.Append(config.AllowedProjectIDs.ElementsAs(ctx, &allowedProjectIDs, false)...)
+	}
+	if !config.ForbiddenProjectIDs.IsNull() {
+		resp.Diagnostics.Append(config.ForbiddenProjectIDs.ElementsAs(ctx, &forbiddenProjectIDs, false)...)
+	}
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
 	// Parse timeout configuration with default (30 minutes - covers long-running resources like KaaS and ContainerRegistry)
 	resourceTimeout := parseTimeout(config.ResourceTimeout, 30*time.Minute, resp.Diagnostics)
 
 	// Create a new ArubaCloud client using the SDK client
 	client := &ArubaCloudClient{
-		ClientID:        clientID,
-		ClientSecret:    clientSecret,
-		Client:          sdkClient,
-		ResourceTimeout: resourceTimeout,
+		ClientID:                 clientID,
+		ClientSecret:             clientSecret,
+		Client:                   sdkClient,
+		ResourceTimeout:          resourceTimeout,
+		DefaultTags:              defaultTags,
+		DefaultProjectID:         defaultProjectID,
+		DefaultLocation:          defaultLocation,
+		DefaultZone:              defaultZone,
+		AllowedProjectIDs:        allowedProjectIDs,
+		ForbiddenProjectIDs:      forbiddenProjectIDs,
+		ReadOnly:                 config.ReadOnly.ValueBool(),
+		FailOnDestructiveReplace: config.FailOnDestructiveReplace.ValueBool(),
+		limiter:                  limiter,
+		accessToken:              token,
 	}
+	client.warnAccessTokenExpiry(timeNow().Add(resourceTimeout), "an operation using the provider resource_timeout", &resp.Diagnostics)
 
 	resp.DataSourceData = client
 	resp.ResourceData = client
+	resp.EphemeralResourceData = client
 }
 
 // parseTimeout parses a timeout string (e.g., "5m", "10m") and returns the duration.
@@ -216,10 +681,32 @@ func parseTimeout(timeoutStr types.String, defaultDuration time.Duration, diags
 
 // ArubaCloudClient wraps the SDK client with API credentials and timeout configuration.
 type ArubaCloudClient struct {
-	ClientID        string
-	ClientSecret    string
-	Client          aruba.Client
-	ResourceTimeout time.Duration
+	ClientID         string
+	ClientSecret     string
+	Client           aruba.Client
+	ResourceTimeout  time.Duration
+	DefaultTags      []string
+	DefaultProjectID string
+	DefaultLocation  string
+	DefaultZone      string
+
+	// AllowedProjectIDs, ForbiddenProjectIDs and ReadOnly are the guardrails
+	// enforced by checkGuardrails before every API call.
+	AllowedProjectIDs   []string
+	ForbiddenProjectIDs []string
+	ReadOnly            bool
+
+	// FailOnDestructiveReplace turns the planDataLoss warning about a
+	// replacement that deletes data into an error.
+	FailOnDestructiveReplace bool
+
+	// limiter enforces max_concurrent_requests and requests_per_second; nil
+	// when neither is set.
+	limiter *requestLimiter
+
+	// accessToken is set when the provider authenticates with access_token
+	// instead of client credentials.
+	accessToken *accessToken
 }
 
 func (p *ArubaCloudProvider) Resources(ctx context.Context) []func() resource.Resource {
@@ -255,7 +742,9 @@ func (p *ArubaCloudProvider) Resources(ctx context.Context) []func() resource.Re
 }
 
 func (p *ArubaCloudProvider) EphemeralResources(ctx context.Context) []func() ephemeral.EphemeralResource {
-	return []func() ephemeral.EphemeralResource{}
+	return []func() ephemeral.EphemeralResource{
+		NewKaaSKubeconfigEphemeralResource,
+	}
 }
 
 func (p *ArubaCloudProvider) DataSources(ctx context.Context) []func() datasource.DataSource {
@@ -289,7 +778,11 @@ func (p *ArubaCloudProvider) DataSources(ctx context.Context) []func() datasourc
 }
 
 func (p *ArubaCloudProvider) Functions(ctx context.Context) []func() function.Function {
-	return []func() function.Function{}
+	return []func() function.Function{
+		NewParseURIFunction,
+		NewBuildURIFunction,
+		NewIsURIFunction,
+	}
 }
 
 func New(version string) func() provider.Provider {
@@ -299,3 +792,32 @@ func New(version string) func() provider.Provider {
 		}
 	}
 }
+
+// NewWithTransport is New for the acceptance tests: wrap is called on every
+// Configure with the network transport, before the HTTP trace, so that the
+// tests can record the API traffic or replay it without network access.
+func NewWithTransport(version string, wrap func(http.RoundTripper) (http.RoundTripper, error), opts ...Option) func() provider.Provider {
+	return func() provider.Provider {
+		p := &ArubaCloudProvider{
+			version:       version,
+			wrapTransport: wrap,
+		}
+		for _, opt := range opts {
+			opt(p)
+		}
+		return p
+	}
+}
+
+// Option adjusts a provider built by NewWithTransport.
+type Option func(*ArubaCloudProvider)
+
+// WithReplayWaits makes the provider wait d, instead of its usual poll and
+// back-off intervals, between the attempts of every wait loop and retry. It
+// is meant for tests replaying recorded traffic, whose responses are already
+// final, and applies to the whole process once the provider is configured.
+func WithReplayWaits(d time.Duration) Option {
+	return func(p *ArubaCloudProvider) {
+		p.replayWait = d
+	}
+}
@@ -2,16 +2,30 @@ package provider
 
 import (
 	"context"
+	"errors"
+	"net/http"
+	"path/filepath"
 	"testing"
+	"time"
 
 	providerframe "github.com/hashicorp/terraform-plugin-framework/provider"
 	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
 	"github.com/hashicorp/terraform-plugin-go/tftypes"
 )
 
-// newTestProvider instantiates ArubaCloudProvider for unit tests.
+// newTestProvider instantiates ArubaCloudProvider for unit tests. It points
+// the shared credentials file at an empty temporary directory and clears
+// ARUBACLOUD_ACCESS_TOKEN and ARUBACLOUD_TOKEN_CACHE_DIR so that a
+// developer's own credentials never leak into the tests.
 func newTestProvider(t *testing.T) *ArubaCloudProvider {
 	t.Helper()
+	t.Setenv("ARUBACLOUD_SHARED_CREDENTIALS_FILE", filepath.Join(t.TempDir(), "credentials"))
+	t.Setenv("ARUBACLOUD_PROFILE", "")
+	t.Setenv("ARUBACLOUD_ACCESS_TOKEN", "")
+	t.Setenv("ARUBACLOUD_TOKEN_CACHE_DIR", "")
+	t.Setenv("ARUBACLOUD_BASE_URL", "")
+	t.Setenv("ARUBACLOUD_AUDIT_LOG_PATH", "")
+	t.Setenv("ARUBACLOUD_HTTP_TRACE_FILE", "")
 	p, ok := New("test")().(*ArubaCloudProvider)
 	if !ok {
 		t.Fatal("New() did not return *ArubaCloudProvider")
@@ -227,3 +241,114 @@ func TestProviderConfigure_ValidResourceTimeout(t *testing.T) {
 		t.Errorf("ResourceTimeout = %v, want 5m", client.ResourceTimeout)
 	}
 }
+
+// TestProviderConfigure_RequestLimits verifies that max_concurrent_requests
+// and requests_per_second install a shared limiter on the client, and that
+// leaving both unset keeps requests unlimited.
+func TestProviderConfigure_RequestLimits(t *testing.T) {
+	ctx := context.Background()
+	p := newTestProvider(t)
+
+	config := buildProviderConfig(t, p, map[string]tftypes.Value{
+		"client_id":               tftypes.NewValue(tftypes.String, "test-key"),
+		"client_secret":           tftypes.NewValue(tftypes.String, "test-secret"),
+		"max_concurrent_requests": tftypes.NewValue(tftypes.Number, 4),
+		"requests_per_second":     tftypes.NewValue(tftypes.Number, 2.5),
+	})
+	resp := &providerframe.ConfigureResponse{}
+	p.Configure(ctx, providerframe.ConfigureRequest{Config: config}, resp)
+
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("unexpected error from Configure(): %v", resp.Diagnostics)
+	}
+	client, ok := resp.ResourceData.(*ArubaCloudClient)
+	if !ok {
+		t.Fatalf("ResourceData is %T, want *ArubaCloudClient", resp.ResourceData)
+	}
+	if client.limiter == nil {
+		t.Fatal("limiter is nil, want a configured limiter")
+	}
+	if cap(client.limiter.slots) != 4 {
+		t.Errorf("concurrency cap = %d, want 4", cap(client.limiter.slots))
+	}
+	if client.limiter.interval != 400*time.Millisecond {
+		t.Errorf("interval = %s, want 400ms", client.limiter.interval)
+	}
+
+	config = buildProviderConfig(t, p, map[string]tftypes.Value{
+		"client_id":     tftypes.NewValue(tftypes.String, "test-key"),
+		"client_secret": tftypes.NewValue(tftypes.String, "test-secret"),
+	})
+	resp = &providerframe.ConfigureResponse{}
+	p.Configure(ctx, providerframe.ConfigureRequest{Config: config}, resp)
+	if client, ok := resp.ResourceData.(*ArubaCloudClient); !ok || client.limiter != nil {
+		t.Errorf("limiter should be nil when no limits are configured")
+	}
+}
+
+// TestProviderConfigure_InvalidRetryDelay verifies that an unparseable
+// duration in the retry block is reported as an error on that attribute.
+func TestProviderConfigure_InvalidRetryDelay(t *testing.T) {
+	ctx := context.Background()
+	p := newTestProvider(t)
+
+	retryType := tftypes.Object{AttributeTypes: map[string]tftypes.Type{
+		"max_attempts": tftypes.Number,
+		"base_delay":   tftypes.String,
+		"max_delay":    tftypes.String,
+		"jitter":       tftypes.Bool,
+	}}
+	config := buildProviderConfig(t, p, map[string]tftypes.Value{
+		"client_id":     tftypes.NewValue(tftypes.String, "test-key"),
+		"client_secret": tftypes.NewValue(tftypes.String, "test-secret"),
+		"retry": tftypes.NewValue(retryType, map[string]tftypes.Value{
+			"max_attempts": tftypes.NewValue(tftypes.Number, 5),
+			"base_delay":   tftypes.NewValue(tftypes.String, "soon"),
+			"max_delay":    tftypes.NewValue(tftypes.String, nil),
+			"jitter":       tftypes.NewValue(tftypes.Bool, nil),
+		}),
+	})
+	resp := &providerframe.ConfigureResponse{}
+	p.Configure(ctx, providerframe.ConfigureRequest{Config: config}, resp)
+
+	if !resp.Diagnostics.HasError() {
+		t.Fatal("expected an error for an invalid retry base_delay")
+	}
+	if resp.ResourceData != nil {
+		t.Error("ResourceData should not be set when the retry block is invalid")
+	}
+}
+
+// TestProviderConfigure_WrapTransport verifies that the transport hook of
+// NewWithTransport is called on Configure and that its error is reported.
+func TestProviderConfigure_WrapTransport(t *testing.T) {
+	ctx := context.Background()
+	p := newTestProvider(t)
+	config := buildProviderConfig(t, p, map[string]tftypes.Value{
+		"client_id":     tftypes.NewValue(tftypes.String, "test-key"),
+		"client_secret": tftypes.NewValue(tftypes.String, "test-secret"),
+	})
+
+	var wrapped http.RoundTripper
+	p.wrapTransport = func(base http.RoundTripper) (http.RoundTripper, error) {
+		wrapped = base
+		return base, nil
+	}
+	resp := &providerframe.ConfigureResponse{}
+	p.Configure(ctx, providerframe.ConfigureRequest{Config: config}, resp)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("unexpected error from Configure(): %v", resp.Diagnostics)
+	}
+	if wrapped == nil {
+		t.Error("Configure() did not call the transport hook")
+	}
+
+	p.wrapTransport = func(http.RoundTripper) (http.RoundTripper, error) {
+		return nil, errors.New("no cassette")
+	}
+	resp = &providerframe.ConfigureResponse{}
+	p.Configure(ctx, providerframe.ConfigureRequest{Config: config}, resp)
+	if errs := resp.Diagnostics.Errors(); len(errs) != 1 || errs[0].Summary() != "Cannot set up the HTTP transport" {
+		t.Errorf("expected one transport error, got %v", resp.Diagnostics)
+	}
+}
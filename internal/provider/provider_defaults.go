@@ -0,0 +1,109 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// providerDefault describes a root attribute that falls back to a
+// provider-level default (default_project_id, default_location or
+// default_zone) when it is omitted from the resource configuration. The
+// attribute must be Optional and Computed with UseStateForUnknown ahead of any
+// RequiresReplace: otherwise the framework marks it unknown whenever another
+// attribute changes, and RequiresReplace replaces the resource before
+// planProviderDefaults can resolve it.
+type providerDefault struct {
+	attr string
+	// replace mirrors the attribute's RequiresReplace plan modifier: a change
+	// to the resolved value forces a new resource.
+	replace bool
+}
+
+// providerDefaultSettings maps each defaultable attribute to the provider
+// attribute and environment variable that supply its default.
+var providerDefaultSettings = map[string]struct{ setting, envVar string }{
+	"project_id": {"default_project_id", "ARUBACLOUD_PROJECT_ID"},
+	"location":   {"default_location", "ARUBACLOUD_LOCATION"},
+	"zone":       {"default_zone", "ARUBACLOUD_ZONE"},
+}
+
+// providerDefaultValue returns the provider default for attr, or "" when
+// none is configured.
+func (c *ArubaCloudClient) providerDefaultValue(attr string) string {
+	switch attr {
+	case "project_id":
+		return c.DefaultProjectID
+	case "location":
+		return c.DefaultLocation
+	case "zone":
+		return c.DefaultZone
+	}
+	return ""
+}
+
+// defaultedDescription appends the provider default note to the description
+// of an attribute resolved by planProviderDefaults.
+func defaultedDescription(description, attr string) string {
+	s := providerDefaultSettings[attr]
+	return fmt.Sprintf("%s Defaults to the provider `%s` (or `%s`) when omitted.", description, s.setting, s.envVar)
+}
+
+// planProviderDefaults resolves omitted attributes to the provider defaults
+// at plan time. It is called from ModifyPlan so that the plan, and therefore
+// the state, always records the resolved value: changing a provider default
+// later shows up as a diff on every resource that relied on it, and forces a
+// replacement where the attribute is immutable. An attribute that is neither
+// configured nor defaulted is reported as an error on that attribute, and a
+// project_id excluded by allowed_project_ids or forbidden_project_ids fails
+// the plan rather than the apply.
+func (c *ArubaCloudClient) planProviderDefaults(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse, defaults ...providerDefault) {
+	if c == nil || req.Plan.Raw.IsNull() {
+		return
+	}
+
+	for _, d := range defaults {
+		p := path.Root(d.attr)
+
+		var configured types.String
+		diags := req.Config.GetAttribute(ctx, p, &configured)
+		resp.Diagnostics.Append(diags...)
+		if diags.HasError() {
+			continue
+		}
+		if !configured.IsNull() {
+			if d.attr == "project_id" {
+				c.checkProjectAllowed(p, configured, &resp.Diagnostics)
+			}
+			continue
+		}
+
+		value := c.providerDefaultValue(d.attr)
+		if value == "" {
+			s := providerDefaultSettings[d.attr]
+			resp.Diagnostics.AddAttributeError(p,
+				"Missing "+d.attr,
+				fmt.Sprintf("The %q attribute is not set and the provider has no default for it. "+
+					"Set %q on the resource, or set %q in the provider configuration or the %s environment variable.",
+					d.attr, d.attr, s.setting, s.envVar),
+			)
+			continue
+		}
+		resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, p, types.StringValue(value))...)
+		if d.attr == "project_id" {
+			c.checkProjectAllowed(p, types.StringValue(value), &resp.Diagnostics)
+		}
+
+		if !d.replace || req.State.Raw.IsNull() {
+			continue
+		}
+		var prior types.String
+		resp.Diagnostics.Append(req.State.GetAttribute(ctx, p, &prior)...)
+		if !prior.IsNull() && prior.ValueString() != value {
+			resp.RequiresReplace = append(resp.RequiresReplace, p)
+		}
+	}
+}
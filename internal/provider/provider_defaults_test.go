@@ -0,0 +1,294 @@
+package provider
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/providerserver"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+func TestPlanProviderDefaults(t *testing.T) {
+	ctx := context.Background()
+
+	s := schema.Schema{
+		Attributes: map[string]schema.Attribute{
+			"project_id": schema.StringAttribute{Optional: true, Computed: true},
+			"location":   schema.StringAttribute{Optional: true, Computed: true},
+		},
+	}
+	objType := s.Type().TerraformType(ctx).(tftypes.Object)
+	str := func(v interface{}) tftypes.Value { return tftypes.NewValue(tftypes.String, v) }
+	obj := func(projectID, location tftypes.Value) tftypes.Value {
+		return tftypes.NewValue(objType, map[string]tftypes.Value{"project_id": projectID, "location": location})
+	}
+	unknown := str(tftypes.UnknownValue)
+	noState := tftypes.NewValue(objType, nil)
+
+	cases := []struct {
+		name         string
+		client       *ArubaCloudClient
+		config       tftypes.Value
+		state        tftypes.Value
+		plan         tftypes.Value
+		replace      bool
+		wantProject  types.String
+		wantLocation types.String
+		wantReplace  bool
+		wantErrPath  *path.Path
+	}{
+		{
+			name:         "omitted values resolve to the defaults",
+			client:       &ArubaCloudClient{DefaultProjectID: "proj-default", DefaultLocation: "ITBG-Bergamo"},
+			config:       obj(str(nil), str(nil)),
+			state:        noState,
+			plan:         obj(unknown, unknown),
+			replace:      true,
+			wantProject:  types.StringValue("proj-default"),
+			wantLocation: types.StringValue("ITBG-Bergamo"),
+		},
+		{
+			name:         "configured values win over the defaults",
+			client:       &ArubaCloudClient{DefaultProjectID: "proj-default", DefaultLocation: "ITBG-Bergamo"},
+			config:       obj(str("proj-explicit"), str(nil)),
+			state:        noState,
+			plan:         obj(str("proj-explicit"), unknown),
+			replace:      true,
+			wantProject:  types.StringValue("proj-explicit"),
+			wantLocation: types.StringValue("ITBG-Bergamo"),
+		},
+		{
+			name:         "changed default forces replacement of immutable attributes",
+			client:       &ArubaCloudClient{DefaultProjectID: "proj-new", DefaultLocation: "ITBG-Bergamo"},
+			config:       obj(str(nil), str(nil)),
+			state:        obj(str("proj-old"), str("ITBG-Bergamo")),
+			plan:         obj(str("proj-old"), str("ITBG-Bergamo")),
+			replace:      true,
+			wantProject:  types.StringValue("proj-new"),
+			wantLocation: types.StringValue("ITBG-Bergamo"),
+			wantReplace:  true,
+		},
+		{
+			name:         "changed default is an in-place update for mutable attributes",
+			client:       &ArubaCloudClient{DefaultProjectID: "proj-new", DefaultLocation: "ITBG-Bergamo"},
+			config:       obj(str(nil), str(nil)),
+			state:        obj(str("proj-old"), str("ITBG-Bergamo")),
+			plan:         obj(str("proj-old"), str("ITBG-Bergamo")),
+			replace:      false,
+			wantProject:  types.StringValue("proj-new"),
+			wantLocation: types.StringValue("ITBG-Bergamo"),
+		},
+		{
+			name:         "missing default is an attribute error",
+			client:       &ArubaCloudClient{DefaultLocation: "ITBG-Bergamo"},
+			config:       obj(str(nil), str(nil)),
+			state:        noState,
+			plan:         obj(unknown, unknown),
+			replace:      true,
+			wantProject:  types.StringUnknown(),
+			wantLocation: types.StringValue("ITBG-Bergamo"),
+			wantErrPath:  func() *path.Path { p := path.Root("project_id"); return &p }(),
+		},
+		{
+			name:         "defaulted project outside allowed_project_ids fails the plan",
+			client:       &ArubaCloudClient{DefaultProjectID: "proj-prod", DefaultLocation: "ITBG-Bergamo", AllowedProjectIDs: []string{"proj-staging"}},
+			config:       obj(str(nil), str(nil)),
+			state:        noState,
+			plan:         obj(unknown, unknown),
+			replace:      true,
+			wantProject:  types.StringValue("proj-prod"),
+			wantLocation: types.StringValue("ITBG-Bergamo"),
+			wantErrPath:  func() *path.Path { p := path.Root("project_id"); return &p }(),
+		},
+		{
+			name:         "configured project in forbidden_project_ids fails the plan",
+			client:       &ArubaCloudClient{DefaultLocation: "ITBG-Bergamo", ForbiddenProjectIDs: []string{"proj-prod"}},
+			config:       obj(str("proj-prod"), str(nil)),
+			state:        noState,
+			plan:         obj(str("proj-prod"), unknown),
+			replace:      true,
+			wantProject:  types.StringValue("proj-prod"),
+			wantLocation: types.StringValue("ITBG-Bergamo"),
+			wantErrPath:  func() *path.Path { p := path.Root("project_id"); return &p }(),
+		},
+		{
+			name:         "unconfigured provider leaves values unknown",
+			client:       nil,
+			config:       obj(str(nil), str(nil)),
+			state:        noState,
+			plan:         obj(unknown, unknown),
+			replace:      true,
+			wantProject:  types.StringUnknown(),
+			wantLocation: types.StringUnknown(),
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := resource.ModifyPlanRequest{
+				Config: tfsdk.Config{Raw: tc.config, Schema: s},
+				State:  tfsdk.State{Raw: tc.state, Schema: s},
+				Plan:   tfsdk.Plan{Raw: tc.plan, Schema: s},
+			}
+			resp := &resource.ModifyPlanResponse{Plan: tfsdk.Plan{Raw: tc.plan, Schema: s}}
+			tc.client.planProviderDefaults(ctx, req, resp,
+				providerDefault{attr: "project_id", replace: tc.replace},
+				providerDefault{attr: "location", replace: tc.replace},
+			)
+
+			if tc.wantErrPath != nil {
+				if !resp.Diagnostics.HasError() {
+					t.Fatal("expected an error diagnostic")
+				}
+				if got := resp.Diagnostics.Errors()[0].(interface{ Path() path.Path }).Path(); !got.Equal(*tc.wantErrPath) {
+					t.Errorf("error path = %s, want %s", got, *tc.wantErrPath)
+				}
+			} else if resp.Diagnostics.HasError() {
+				t.Fatalf("unexpected error: %v", resp.Diagnostics)
+			}
+
+			var projectID, location types.String
+			resp.Plan.GetAttribute(ctx, path.Root("project_id"), &projectID)
+			resp.Plan.GetAttribute(ctx, path.Root("location"), &location)
+			if !projectID.Equal(tc.wantProject) {
+				t.Errorf("project_id = %s, want %s", projectID, tc.wantProject)
+			}
+			if !location.Equal(tc.wantLocation) {
+				t.Errorf("location = %s, want %s", location, tc.wantLocation)
+			}
+			if got := resp.RequiresReplace.Contains(path.Root("project_id")); got != tc.wantReplace {
+				t.Errorf("project_id requires replace = %v, want %v", got, tc.wantReplace)
+			}
+		})
+	}
+}
+
+// TestPlanProviderDefaults_PlanResourceChange plans an update of an existing
+// VPC through the provider server, so that the attribute plan modifiers run
+// before ModifyPlan exactly as they do under Terraform.
+func TestPlanProviderDefaults_PlanResourceChange(t *testing.T) {
+	ctx := context.Background()
+	str := func(v interface{}) tftypes.Value { return tftypes.NewValue(tftypes.String, v) }
+
+	schemaResp := &resource.SchemaResponse{}
+	NewVPCResource().Schema(ctx, resource.SchemaRequest{}, schemaResp)
+	objType := schemaResp.Schema.Type().TerraformType(ctx).(tftypes.Object)
+	obj := func(values map[string]tftypes.Value) *tfprotov6.DynamicValue {
+		attrs := make(map[string]tftypes.Value, len(objType.AttributeTypes))
+		for name, ty := range objType.AttributeTypes {
+			attrs[name] = tftypes.NewValue(ty, nil)
+		}
+		for name, v := range values {
+			attrs[name] = v
+		}
+		dv, err := tfprotov6.NewDynamicValue(objType, tftypes.NewValue(objType, attrs))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return &dv
+	}
+
+	prior := map[string]tftypes.Value{
+		"id":         str("vpc-1"),
+		"uri":        str("/projects/proj-1/providers/Aruba.Network/vpcs/vpc-1"),
+		"name":       str("vpc-old"),
+		"project_id": str("proj-1"),
+		"location":   str("ITBG-Bergamo"),
+	}
+	// Terraform proposes the prior value for computed attributes that are
+	// not configured.
+	proposed := map[string]tftypes.Value{}
+	for name, v := range prior {
+		proposed[name] = v
+	}
+	proposed["name"] = str("vpc-new")
+
+	cases := []struct {
+		name           string
+		defaultProject string
+		wantProject    string
+		wantReplace    bool
+	}{
+		{"renaming keeps the defaulted attributes", "proj-1", "proj-1", false},
+		{"changed default forces replacement", "proj-2", "proj-2", true},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Setenv("ARUBACLOUD_PROJECT_ID", "")
+			t.Setenv("ARUBACLOUD_LOCATION", "")
+			p := newTestProvider(t)
+			providerConfig := buildProviderConfig(t, p, map[string]tftypes.Value{
+				"client_id":          str("test-key"),
+				"client_secret":      str("test-secret"),
+				"default_project_id": str(tc.defaultProject),
+				"default_location":   str("ITBG-Bergamo"),
+			})
+			server, err := providerserver.NewProtocol6WithError(p)()
+			if err != nil {
+				t.Fatal(err)
+			}
+			cfg, err := tfprotov6.NewDynamicValue(providerConfig.Raw.Type(), providerConfig.Raw)
+			if err != nil {
+				t.Fatal(err)
+			}
+			configureResp, err := server.ConfigureProvider(ctx, &tfprotov6.ConfigureProviderRequest{Config: &cfg})
+			if err != nil {
+				t.Fatal(err)
+			}
+			failOnErrors(t, configureResp.Diagnostics)
+
+			planResp, err := server.PlanResourceChange(ctx, &tfprotov6.PlanResourceChangeRequest{
+				TypeName:         "arubacloud_vpc",
+				PriorState:       obj(prior),
+				ProposedNewState: obj(proposed),
+				Config:           obj(map[string]tftypes.Value{"name": str("vpc-new")}),
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			failOnErrors(t, planResp.Diagnostics)
+
+			projectPath := tftypes.NewAttributePath().WithAttributeName("project_id")
+			var gotReplace bool
+			for _, ap := range planResp.RequiresReplace {
+				if ap.Equal(projectPath) {
+					gotReplace = true
+				} else {
+					t.Errorf("unexpected replacement of %s", ap)
+				}
+			}
+			if gotReplace != tc.wantReplace {
+				t.Errorf("project_id requires replace = %v, want %v", gotReplace, tc.wantReplace)
+			}
+
+			planned, err := planResp.PlannedState.Unmarshal(objType)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var attrs map[string]tftypes.Value
+			if err := planned.As(&attrs); err != nil {
+				t.Fatal(err)
+			}
+			if want := str(tc.wantProject); !attrs["project_id"].Equal(want) {
+				t.Errorf("planned project_id = %s, want %s", attrs["project_id"], want)
+			}
+			if want := str("ITBG-Bergamo"); !attrs["location"].Equal(want) {
+				t.Errorf("planned location = %s, want %s", attrs["location"], want)
+			}
+		})
+	}
+}
+
+func failOnErrors(t *testing.T, diags []*tfprotov6.Diagnostic) {
+	t.Helper()
+	for _, d := range diags {
+		if d.Severity == tfprotov6.DiagnosticSeverityError {
+			t.Fatalf("%s: %s", d.Summary, d.Detail)
+		}
+	}
+}
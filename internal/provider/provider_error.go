@@ -4,13 +4,15 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"net/http"
 	"sort"
 	"strings"
+	"time"
 
 	"github.com/Arubacloud/sdk-go/pkg/aruba"
 )
 
-// ProviderErrorCategory classifies an API error as semantic, transient, or technical.
+// ProviderErrorCategory classifies an API error as semantic, transient, technical, or throttled.
 type ProviderErrorCategory int
 
 const (
@@ -24,6 +26,9 @@ const (
 	// ProviderErrorCategoryTechnical represents infrastructure or transient errors (network failures,
 	// HTTP 5xx). These are candidates for retry.
 	ProviderErrorCategoryTechnical
+	// ProviderErrorCategoryThrottled represents HTTP 429 Too Many Requests. The request was not
+	// processed and can be retried once the delay in ProviderError.RetryAfter has passed.
+	ProviderErrorCategoryThrottled
 )
 
 func (c ProviderErrorCategory) String() string {
@@ -34,6 +39,8 @@ func (c ProviderErrorCategory) String() string {
 		return "transient"
 	case ProviderErrorCategoryTechnical:
 		return "technical"
+	case ProviderErrorCategoryThrottled:
+		return "throttled"
 	default:
 		return "unknown"
 	}
@@ -51,12 +58,26 @@ type ProviderError struct {
 	Operation  string
 	Resource   string
 	Cause      error
+	// RetryAfter is the delay requested by the API's Retry-After header on a
+	// throttled response; zero when the header was absent or unparseable.
+	RetryAfter time.Duration
+	// FieldErrors are the field-level validation errors of a Semantic error.
+	FieldErrors []FieldError
+	// RequestID is the correlation ID the provider sent as X-Request-Id, and
+	// ServerRequestID the request ID the API returned, if any. Aruba support
+	// asks for them to trace a failed request.
+	RequestID       string
+	ServerRequestID string
 }
 
 // Error implements the error interface.
 func (e *ProviderError) Error() string {
 	if e.Cause != nil {
-		return fmt.Sprintf("failed to %s %q: %v", e.Operation, e.Resource, e.Cause)
+		msg := fmt.Sprintf("failed to %s %q: %v", e.Operation, e.Resource, e.Cause)
+		if ids := e.requestIDs(); len(ids) > 0 {
+			msg += " (" + strings.Join(ids, ", ") + ")"
+		}
+		return msg
 	}
 	parts := []string{
 		fmt.Sprintf("failed to %s %q", e.Operation, e.Resource),
@@ -72,9 +93,25 @@ func (e *ProviderError) Error() string {
 	if e.Instance != "" {
 		parts = append(parts, "instance: "+e.Instance)
 	}
+	if e.RetryAfter > 0 {
+		parts = append(parts, "retry_after: "+e.RetryAfter.String())
+	}
+	parts = append(parts, e.requestIDs()...)
 	return strings.Join(parts, ", ")
 }
 
+// requestIDs returns the request IDs of e formatted for Error().
+func (e *ProviderError) requestIDs() []string {
+	var ids []string
+	if e.RequestID != "" {
+		ids = append(ids, "request_id: "+e.RequestID)
+	}
+	if e.ServerRequestID != "" {
+		ids = append(ids, "server_request_id: "+e.ServerRequestID)
+	}
+	return ids
+}
+
 // Unwrap returns the underlying transport-level error, enabling errors.Is / errors.As chains.
 func (e *ProviderError) Unwrap() error {
 	return e.Cause
@@ -146,11 +183,14 @@ func NewTransportError(operation, resource string, err error) *ProviderError {
 }
 
 // newResponseError creates a ProviderError from pre-extracted HTTP response fields.
-// For HTTP 4xx: Semantic when hasValidationErrors is true (field-level validation
-// failures), Transient otherwise. For everything else: Technical.
+// HTTP 429 is Throttled. For other HTTP 4xx: Semantic when hasValidationErrors is
+// true (field-level validation failures), Transient otherwise. For everything
+// else: Technical.
 func newResponseError(operation, resource string, statusCode int, title, detail, instance string, hasValidationErrors bool) *ProviderError {
 	category := ProviderErrorCategoryTechnical
-	if statusCode >= 400 && statusCode < 500 {
+	if statusCode == http.StatusTooManyRequests {
+		category = ProviderErrorCategoryThrottled
+	} else if statusCode >= 400 && statusCode < 500 {
 		if hasValidationErrors {
 			category = ProviderErrorCategorySemantic
 		} else {
@@ -180,6 +220,7 @@ func CheckResponseErr(operation, resource string, err error) *ProviderError {
 	if errors.As(err, &httpErr) {
 		title, detail, instance := "", "", ""
 		hasValidationErrors := false
+		var fieldErrors []FieldError
 		if httpErr.ErrResp != nil {
 			if httpErr.ErrResp.Title != nil {
 				title = sanitizeAPIString(*httpErr.ErrResp.Title)
@@ -194,6 +235,9 @@ func CheckResponseErr(operation, resource string, err error) *ProviderError {
 				hasValidationErrors = true
 				parts := make([]string, 0, len(httpErr.ErrResp.Errors))
 				for _, ve := range httpErr.ErrResp.Errors {
+					if ve.Field != "" {
+						fieldErrors = append(fieldErrors, FieldError{Field: ve.Field, Message: sanitizeAPIString(ve.Message)})
+					}
 					switch {
 					case ve.Field != "" && ve.Message != "":
 						parts = append(parts, ve.Field+": "+ve.Message)
@@ -211,6 +255,7 @@ func CheckResponseErr(operation, resource string, err error) *ProviderError {
 						detail = "Validation: " + validationDetail
 					}
 				} else {
+					fieldErrors = rawFieldErrors(httpErr.Body)
 					rawDetail := formatRawValidationErrors(httpErr.Body)
 					if rawDetail != "" {
 						if detail != "" {
@@ -222,9 +267,20 @@ func CheckResponseErr(operation, resource string, err error) *ProviderError {
 				}
 			}
 		}
-		return newResponseError(operation, resource, httpErr.StatusCode, title, detail, instance, hasValidationErrors)
+		provErr := newResponseError(operation, resource, httpErr.StatusCode, title, detail, instance, hasValidationErrors)
+		provErr.FieldErrors = fieldErrors
+		provErr.RequestID, provErr.ServerRequestID = requestIDsFromBody(httpErr.Body)
+		if provErr.Category == ProviderErrorCategoryThrottled {
+			provErr.RetryAfter = retryAfterFromBody(httpErr.Body)
+		}
+		return provErr
 	}
-	return NewTransportError(operation, resource, err)
+	provErr := NewTransportError(operation, resource, err)
+	var reqErr *requestError
+	if errors.As(err, &reqErr) {
+		provErr.RequestID = reqErr.requestID
+	}
+	return provErr
 }
 
 // CheckResponseErrAsError is like CheckResponseErr but returns a plain error
@@ -263,6 +319,22 @@ func ErrorIsTechnical(err error) bool {
 	return errors.As(err, &provErr) && provErr != nil && provErr.Category == ProviderErrorCategoryTechnical
 }
 
+// ErrorIsThrottled reports whether err is a *ProviderError with category Throttled (HTTP 429).
+func ErrorIsThrottled(err error) bool {
+	var provErr *ProviderError
+	return errors.As(err, &provErr) && provErr != nil && provErr.Category == ProviderErrorCategoryThrottled
+}
+
+// retryAfter returns the Retry-After delay carried by a throttled error, or
+// zero when err is not throttled or the API sent no usable delay.
+func retryAfter(err error) time.Duration {
+	var provErr *ProviderError
+	if errors.As(err, &provErr) && provErr != nil && provErr.Category == ProviderErrorCategoryThrottled {
+		return provErr.RetryAfter
+	}
+	return 0
+}
+
 // ErrorIsTransportFailure reports whether err is a network-level failure with no
 // HTTP status code (e.g. EOF, connection reset). StatusCode == 0 means no response
 // was received, so the server almost certainly never processed the request and
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"strings"
 	"testing"
+	"time"
 
 	aruba "github.com/Arubacloud/sdk-go/pkg/aruba"
 	sdktypes "github.com/Arubacloud/sdk-go/pkg/types"
@@ -192,6 +193,32 @@ func TestCheckResponseErr_NilErrResp(t *testing.T) {
 	_ = err.Error()
 }
 
+func TestCheckResponseErr_ThrottledRetryAfter(t *testing.T) {
+	body := []byte(`{"title":"Too Many Requests","status":429,"retryAfter":2.5}`)
+	err := CheckResponseErr("create", "Resource", &aruba.HTTPError{StatusCode: 429, Body: body})
+	if !ErrorIsThrottled(err) {
+		t.Fatalf("expected throttled, got %v", err.Category)
+	}
+	if err.RetryAfter != 2500*time.Millisecond {
+		t.Errorf("RetryAfter = %s, want 2.5s", err.RetryAfter)
+	}
+	if got := retryAfter(err); got != err.RetryAfter {
+		t.Errorf("retryAfter() = %s, want %s", got, err.RetryAfter)
+	}
+	if !contains(err.Error(), "retry_after: 2.5s") {
+		t.Errorf("Error() should mention the delay, got %q", err.Error())
+	}
+	if ErrorIsTransient(err) || ErrorIsTechnical(err) {
+		t.Error("a throttled error must not be classified as transient or technical")
+	}
+
+	// Without a recorded delay RetryAfter stays zero.
+	err = CheckResponseErr("create", "Resource", &aruba.HTTPError{StatusCode: 429})
+	if err.RetryAfter != 0 {
+		t.Errorf("RetryAfter = %s, want 0", err.RetryAfter)
+	}
+}
+
 func makeHTTPErr(statusCode int, errResp *sdktypes.ErrorResponse) error {
 	return &aruba.HTTPError{StatusCode: statusCode, ErrResp: errResp}
 }
@@ -231,6 +258,11 @@ func TestCheckResponseErr(t *testing.T) {
 			err:          makeHTTPErr(500, nil),
 			wantCategory: ProviderErrorCategoryTechnical,
 		},
+		{
+			name:         "429 Too Many Requests → throttled",
+			err:          makeHTTPErr(429, nil),
+			wantCategory: ProviderErrorCategoryThrottled,
+		},
 		{
 			name:         "non-HTTP transport error → technical",
 			err:          fmt.Errorf("dial tcp: connection refused"),
@@ -336,6 +368,7 @@ func TestProviderErrorCategory_String(t *testing.T) {
 		{ProviderErrorCategorySemantic, "semantic"},
 		{ProviderErrorCategoryTransient, "transient"},
 		{ProviderErrorCategoryTechnical, "technical"},
+		{ProviderErrorCategoryThrottled, "throttled"},
 		{ProviderErrorCategory(99), "unknown"},
 	}
 	for _, tc := range cases {
@@ -0,0 +1,62 @@
+package provider
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// checkGuardrails enforces the provider guardrails before a resource or data
+// source operation calls the API: read_only rejects every operation other
+// than "read", and allowed_project_ids and forbidden_project_ids restrict the
+// project the operation targets. It reports whether the operation may
+// proceed; when it may not, the reason has been added to diags.
+func (c *ArubaCloudClient) checkGuardrails(operation, resourceType string, projectID types.String, diags *diag.Diagnostics) bool {
+	return c.checkGuardrailsAt(operation, resourceType, path.Root("project_id"), projectID, diags)
+}
+
+// checkGuardrailsAt is checkGuardrails for resources whose project ID is held
+// by an attribute other than project_id, such as the id of arubacloud_project.
+func (c *ArubaCloudClient) checkGuardrailsAt(operation, resourceType string, projectAttr path.Path, projectID types.String, diags *diag.Diagnostics) bool {
+	if c == nil {
+		return true
+	}
+	if c.ReadOnly && operation != "read" {
+		diags.AddError(
+			"Provider is read-only",
+			fmt.Sprintf("Cannot %s %s: the provider is configured with read_only = true, which allows plans, refreshes "+
+				"and data sources but no changes. Remove read_only from the provider configuration to apply changes.",
+				operation, resourceType),
+		)
+		return false
+	}
+	return c.checkProjectAllowed(projectAttr, projectID, diags)
+}
+
+// checkProjectAllowed reports an error on attr when projectID is excluded by
+// forbidden_project_ids or not listed in allowed_project_ids. Unknown and
+// empty IDs are not checked; they are checked again once known.
+func (c *ArubaCloudClient) checkProjectAllowed(attr path.Path, projectID types.String, diags *diag.Diagnostics) bool {
+	if c == nil || projectID.IsNull() || projectID.IsUnknown() || projectID.ValueString() == "" {
+		return true
+	}
+	id := projectID.ValueString()
+	switch {
+	case slices.Contains(c.ForbiddenProjectIDs, id):
+		diags.AddAttributeError(attr, "Forbidden project",
+			fmt.Sprintf("Project %q is listed in the provider forbidden_project_ids, so the provider does not call the API for it. "+
+				"Check the project_id of the resource, the provider default_project_id and the ARUBACLOUD_PROJECT_ID environment variable.", id))
+		return false
+	case len(c.AllowedProjectIDs) > 0 && !slices.Contains(c.AllowedProjectIDs, id):
+		diags.AddAttributeError(attr, "Project not allowed",
+			fmt.Sprintf("Project %q is not listed in the provider allowed_project_ids (%s), so the provider does not call the API for it. "+
+				"Check the project_id of the resource, the provider default_project_id and the ARUBACLOUD_PROJECT_ID environment variable.",
+				id, strings.Join(c.AllowedProjectIDs, ", ")))
+		return false
+	}
+	return true
+}
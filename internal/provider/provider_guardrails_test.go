@@ -0,0 +1,150 @@
+package provider
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestCheckGuardrails(t *testing.T) {
+	cases := []struct {
+		name      string
+		client    *ArubaCloudClient
+		operation string
+		projectID types.String
+		want      string
+	}{
+		{"no guardrails", &ArubaCloudClient{}, "delete", types.StringValue("proj-a"), ""},
+		{"unconfigured provider", nil, "delete", types.StringValue("proj-a"), ""},
+		{"read-only allows reads", &ArubaCloudClient{ReadOnly: true}, "read", types.StringValue("proj-a"), ""},
+		{"read-only rejects creates", &ArubaCloudClient{ReadOnly: true}, "create", types.StringValue("proj-a"), "Provider is read-only"},
+		{"read-only rejects deletes", &ArubaCloudClient{ReadOnly: true}, "delete", types.StringNull(), "Provider is read-only"},
+		{"allowed project", &ArubaCloudClient{AllowedProjectIDs: []string{"proj-a", "proj-b"}}, "update", types.StringValue("proj-b"), ""},
+		{"project not allowed", &ArubaCloudClient{AllowedProjectIDs: []string{"proj-a"}}, "read", types.StringValue("proj-b"), "Project not allowed"},
+		{"forbidden project", &ArubaCloudClient{ForbiddenProjectIDs: []string{"proj-b"}}, "read", types.StringValue("proj-b"), "Forbidden project"},
+		{"other project", &ArubaCloudClient{ForbiddenProjectIDs: []string{"proj-b"}}, "read", types.StringValue("proj-a"), ""},
+		{"unknown project", &ArubaCloudClient{AllowedProjectIDs: []string{"proj-a"}}, "create", types.StringUnknown(), ""},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			var diags diag.Diagnostics
+			ok := tc.client.checkGuardrails(tc.operation, "VPC", tc.projectID, &diags)
+			if ok != (tc.want == "") {
+				t.Errorf("checkGuardrails() = %v, want %v", ok, tc.want == "")
+			}
+			switch errs := diags.Errors(); {
+			case tc.want == "" && len(errs) != 0:
+				t.Errorf("unexpected diagnostics: %v", diags)
+			case tc.want != "" && (len(errs) != 1 || errs[0].Summary() != tc.want):
+				t.Errorf("expected one %q error, got %v", tc.want, diags)
+			}
+		})
+	}
+}
+
+// guardedClient returns a mock client with the given guardrails whose API
+// fails the test on any request: guardrails must stop operations before the
+// first API call.
+func guardedClient(t *testing.T, guard func(*ArubaCloudClient)) *ArubaCloudClient {
+	t.Helper()
+	_, client := newMockArubaClient(t, func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("unexpected API call %s %s", r.Method, r.URL.Path)
+		apiError(w, http.StatusInternalServerError)
+	})
+	guard(client)
+	return client
+}
+
+// TestGuardrails_ReadOnly verifies that read_only rejects Create, Update and
+// Delete of every resource before any API call.
+func TestGuardrails_ReadOnly(t *testing.T) {
+	ctx := context.Background()
+	// Neither resource calls the API on update.
+	noUpdateCall := map[string]bool{"databasebackup": true, "databasegrant": true}
+
+	for _, tc := range allResources25 {
+		t.Run(tc.name, func(t *testing.T) {
+			client := guardedClient(t, func(c *ArubaCloudClient) { c.ReadOnly = true })
+			res := tc.newR()
+			configureResource(ctx, t, res, client)
+
+			var diags []diag.Diagnostics
+			createReq, createResp := resourceCreateReq(ctx, t, res)
+			res.Create(ctx, createReq, createResp)
+			diags = append(diags, createResp.Diagnostics)
+			if !noUpdateCall[tc.name] {
+				updateReq, updateResp := resourceUpdateReq(ctx, t, res)
+				res.Update(ctx, updateReq, updateResp)
+				diags = append(diags, updateResp.Diagnostics)
+			}
+			deleteReq, deleteResp := resourceDeleteReq(ctx, t, res)
+			res.Delete(ctx, deleteReq, deleteResp)
+			diags = append(diags, deleteResp.Diagnostics)
+
+			for _, d := range diags {
+				if errs := d.Errors(); len(errs) != 1 || errs[0].Summary() != "Provider is read-only" {
+					t.Errorf("expected one read-only error, got %v", d)
+				}
+			}
+		})
+	}
+}
+
+// TestGuardrails_ProjectIDs verifies that allowed_project_ids and
+// forbidden_project_ids stop the Read of every resource and data source
+// before any API call.
+func TestGuardrails_ProjectIDs(t *testing.T) {
+	ctx := context.Background()
+	guards := map[string]func(*ArubaCloudClient){
+		"Forbidden project": func(c *ArubaCloudClient) {
+			// The request helpers set project_id to "test-project_id", and
+			// the id of arubacloud_project to "test-id".
+			c.ForbiddenProjectIDs = []string{"test-project_id", "test-id"}
+		},
+		"Project not allowed": func(c *ArubaCloudClient) { c.AllowedProjectIDs = []string{"proj-staging"} },
+	}
+	wantPath := func(name string) path.Path {
+		if name == "project" {
+			return path.Root("id")
+		}
+		return path.Root("project_id")
+	}
+	checkErr := func(t *testing.T, diags diag.Diagnostics, summary string, attr path.Path) {
+		t.Helper()
+		errs := diags.Errors()
+		if len(errs) != 1 || errs[0].Summary() != summary {
+			t.Fatalf("expected one %q error, got %v", summary, diags)
+		}
+		if d, ok := errs[0].(diag.DiagnosticWithPath); !ok || !d.Path().Equal(attr) {
+			t.Errorf("error is not on %s: %v", attr, errs[0])
+		}
+	}
+
+	for summary, guard := range guards {
+		for _, tc := range allResources25 {
+			t.Run(summary+"/resource/"+tc.name, func(t *testing.T) {
+				res := tc.newR()
+				configureResource(ctx, t, res, guardedClient(t, guard))
+				req, resp := resourceReadReq(ctx, t, res)
+				res.Read(ctx, req, resp)
+				checkErr(t, resp.Diagnostics, summary, wantPath(tc.name))
+			})
+		}
+		for _, newDS := range (&ArubaCloudProvider{}).DataSources(ctx) {
+			ds := newDS()
+			metaResp := &datasource.MetadataResponse{}
+			ds.Metadata(ctx, datasource.MetadataRequest{ProviderTypeName: "arubacloud"}, metaResp)
+			t.Run(summary+"/data-source/"+metaResp.TypeName, func(t *testing.T) {
+				configureDatasource(ctx, t, ds, guardedClient(t, guard))
+				resp := &datasource.ReadResponse{}
+				ds.Read(ctx, dsReadReq(ctx, t, ds, nil), resp)
+				checkErr(t, resp.Diagnostics, summary, wantPath(metaResp.TypeName[len("arubacloud_"):]))
+			})
+		}
+	}
+}
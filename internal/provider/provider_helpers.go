@@ -4,7 +4,10 @@ import (
 	"context"
 	"time"
 
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 )
 
@@ -49,3 +52,31 @@ func effectiveTimeout(resourceTimeout types.String, providerTimeout time.Duratio
 	}
 	return d
 }
+
+// operationTimeout resolves the timeout of a single CRUD operation. The
+// matching entry of the resource's timeouts block wins; otherwise the
+// deprecated timeout attribute applies, then the provider-level
+// resource_timeout. op is the timeouts accessor for the operation, e.g.
+// data.Timeouts.Create.
+func (c *ArubaCloudClient) operationTimeout(ctx context.Context, op func(context.Context, time.Duration) (time.Duration, diag.Diagnostics), legacy types.String, diags *diag.Diagnostics) time.Duration {
+	var providerTimeout time.Duration
+	if c != nil {
+		providerTimeout = c.ResourceTimeout
+	}
+	timeout, d := op(ctx, effectiveTimeout(legacy, providerTimeout))
+	diags.Append(d...)
+	return timeout
+}
+
+// writeOnlyOrDeprecated returns the secret to send to the API. The deprecated
+// state-persisted attribute wins when set; otherwise the write-only attribute
+// at woPath is read from the configuration, since Terraform always nulls
+// write-only values in the plan and state.
+func writeOnlyOrDeprecated(ctx context.Context, config tfsdk.Config, woPath path.Path, deprecated types.String) (types.String, diag.Diagnostics) {
+	if !deprecated.IsNull() && !deprecated.IsUnknown() {
+		return deprecated, nil
+	}
+	var wo types.String
+	diags := config.GetAttribute(ctx, woPath, &wo)
+	return wo, diags
+}
@@ -93,6 +93,30 @@ func TestAllDataSourceSchemas(t *testing.T) {
 	}
 }
 
+// TestAllEphemeralResourceSchemas validates that all ephemeral resources are registered.
+func TestAllEphemeralResourceSchemas(t *testing.T) {
+	t.Parallel()
+
+	p := New("test")()
+	provider, ok := p.(*ArubaCloudProvider)
+	if !ok {
+		t.Fatal("provider is not of type *ArubaCloudProvider")
+	}
+
+	ephemeralResources := provider.EphemeralResources(context.TODO())
+
+	expectedCount := 1 // arubacloud_kaas_kubeconfig
+	if len(ephemeralResources) != expectedCount {
+		t.Errorf("expected %d ephemeral resources, got %d", expectedCount, len(ephemeralResources))
+	}
+
+	for _, ephemeralFunc := range ephemeralResources {
+		if ephemeralFunc() == nil {
+			t.Error("ephemeral resource function returned nil")
+		}
+	}
+}
+
 // TestProviderSchemaAttributes validates the auth attribute rename from v0.1.x to v0.2.0.
 func TestProviderSchemaAttributes(t *testing.T) {
 	t.Parallel()
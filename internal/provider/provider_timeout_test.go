@@ -1,11 +1,18 @@
 package provider
 
 import (
+	"context"
+	"net/http"
 	"testing"
 	"time"
 
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
 	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
 )
 
 func TestParseTimeout(t *testing.T) {
@@ -37,3 +44,243 @@ func TestParseTimeout(t *testing.T) {
 		})
 	}
 }
+
+func timeoutsValue(values map[string]string) timeouts.Value {
+	attrTypes := map[string]attr.Type{
+		"create": types.StringType,
+		"read":   types.StringType,
+		"update": types.StringType,
+		"delete": types.StringType,
+	}
+	attrs := make(map[string]attr.Value, len(attrTypes))
+	for name := range attrTypes {
+		if v, ok := values[name]; ok {
+			attrs[name] = types.StringValue(v)
+		} else {
+			attrs[name] = types.StringNull()
+		}
+	}
+	return timeouts.Value{Object: types.ObjectValueMust(attrTypes, attrs)}
+}
+
+func TestOperationTimeout(t *testing.T) {
+	ctx := context.Background()
+	client := &ArubaCloudClient{ResourceTimeout: 30 * time.Minute}
+
+	block := timeoutsValue(map[string]string{"create": "45m", "read": "2m"})
+	cases := []struct {
+		name   string
+		client *ArubaCloudClient
+		op     func(context.Context, time.Duration) (time.Duration, diag.Diagnostics)
+		legacy types.String
+		want   time.Duration
+	}{
+		{"unset block uses provider timeout", client, timeouts.Value{}.Create, types.StringNull(), 30 * time.Minute},
+		{"unset block uses legacy timeout", client, timeouts.Value{}.Delete, types.StringValue("15m"), 15 * time.Minute},
+		{"block entry wins over legacy", client, block.Create, types.StringValue("15m"), 45 * time.Minute},
+		{"read entry", client, block.Read, types.StringNull(), 2 * time.Minute},
+		{"missing entry uses legacy", client, block.Update, types.StringValue("15m"), 15 * time.Minute},
+		{"missing entry uses provider", client, block.Delete, types.StringNull(), 30 * time.Minute},
+		{"invalid legacy uses provider", client, block.Delete, types.StringValue("soon"), 30 * time.Minute},
+		{"nil client uses legacy", nil, block.Update, types.StringValue("5m"), 5 * time.Minute},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			var diags diag.Diagnostics
+			got := tc.client.operationTimeout(ctx, tc.op, tc.legacy, &diags)
+			if diags.HasError() {
+				t.Fatalf("unexpected diagnostics: %v", diags)
+			}
+			if got != tc.want {
+				t.Errorf("operationTimeout() = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestOperationTimeout_InvalidBlockEntry(t *testing.T) {
+	var diags diag.Diagnostics
+	client := &ArubaCloudClient{ResourceTimeout: 30 * time.Minute}
+	block := timeoutsValue(map[string]string{"create": "soon"})
+
+	got := client.operationTimeout(context.Background(), block.Create, types.StringNull(), &diags)
+	if !diags.HasError() {
+		t.Error("expected an error for an unparseable timeouts entry")
+	}
+	if got != 30*time.Minute {
+		t.Errorf("operationTimeout() = %v, want the 30m fallback", got)
+	}
+}
+
+// withTimeouts returns raw with its timeouts block set to values. Operations
+// missing from values are left null.
+func withTimeouts(t *testing.T, raw tftypes.Value, values map[string]string) tftypes.Value {
+	t.Helper()
+
+	objType, ok := raw.Type().(tftypes.Object)
+	if !ok {
+		t.Fatalf("withTimeouts: value is not an object")
+	}
+	attrs := make(map[string]tftypes.Value, len(objType.AttributeTypes))
+	if err := raw.As(&attrs); err != nil {
+		t.Fatalf("withTimeouts: failed to unpack value: %v", err)
+	}
+	blockType, ok := objType.AttributeTypes["timeouts"].(tftypes.Object)
+	if !ok {
+		t.Fatalf("withTimeouts: schema has no timeouts block")
+	}
+	block := make(map[string]tftypes.Value, len(blockType.AttributeTypes))
+	for name := range blockType.AttributeTypes {
+		if v, ok := values[name]; ok {
+			block[name] = tftypes.NewValue(tftypes.String, v)
+		} else {
+			block[name] = tftypes.NewValue(tftypes.String, nil)
+		}
+	}
+	attrs["timeouts"] = tftypes.NewValue(blockType, block)
+	return tftypes.NewValue(objType, attrs)
+}
+
+// TestResourceTimeoutsBlock verifies that the per-operation timeouts bound
+// the Create, Read and Delete waits and the API calls of an Update, even when
+// the provider-level resource_timeout is much longer.
+func TestResourceTimeoutsBlock(t *testing.T) {
+	oldActivePoll, oldDeletedPoll := waitForActivePollInterval, waitForDeletedPollInterval
+	waitForActivePollInterval = time.Millisecond
+	waitForDeletedPollInterval = time.Millisecond
+	t.Cleanup(func() {
+		waitForActivePollInterval = oldActivePoll
+		waitForDeletedPollInterval = oldDeletedPoll
+	})
+
+	ctx := context.Background()
+	const limit = 5 * time.Second
+
+	t.Run("create", func(t *testing.T) {
+		_, mockClient := newMockArubaClient(t, alwaysCreatingHandler)
+		r := NewVPCResource()
+		configureResource(ctx, t, r, mockClient)
+
+		req, resp := resourceCreateReq(ctx, t, r)
+		req.Plan.Raw = withTimeouts(t, req.Plan.Raw, map[string]string{"create": "50ms"})
+
+		start := time.Now()
+		r.Create(ctx, req, resp)
+		if elapsed := time.Since(start); elapsed > limit {
+			t.Fatalf("Create() took %v; timeouts.create was not honoured", elapsed)
+		}
+		if len(resp.Diagnostics) == 0 {
+			t.Error("expected a wait timeout diagnostic")
+		}
+	})
+
+	t.Run("read", func(t *testing.T) {
+		_, mockClient := newMockArubaClient(t, alwaysCreatingHandler)
+		r := NewSubnetResource()
+		configureResource(ctx, t, r, mockClient)
+
+		req, resp := resourceReadReq(ctx, t, r)
+		req.State.Raw = withTimeouts(t, req.State.Raw, map[string]string{"read": "50ms"})
+
+		start := time.Now()
+		r.Read(ctx, req, resp)
+		if elapsed := time.Since(start); elapsed > limit {
+			t.Fatalf("Read() took %v; timeouts.read was not honoured", elapsed)
+		}
+		if len(resp.Diagnostics) == 0 {
+			t.Error("expected a wait timeout diagnostic")
+		}
+	})
+
+	t.Run("update", func(t *testing.T) {
+		_, mockClient := newMockArubaClient(t, func(w http.ResponseWriter, r *http.Request) {
+			if r.Method == http.MethodGet {
+				updateSuccessHandler(w, r)
+				return
+			}
+			// Hold the write until the client gives up on it.
+			select {
+			case <-r.Context().Done():
+			case <-time.After(2 * limit):
+			}
+		})
+		r := NewVPCResource()
+		configureResource(ctx, t, r, mockClient)
+
+		req, resp := resourceUpdateReq(ctx, t, r)
+		req.Plan.Raw = withTimeouts(t, req.Plan.Raw, map[string]string{"update": "50ms"})
+
+		start := time.Now()
+		r.Update(ctx, req, resp)
+		if elapsed := time.Since(start); elapsed > limit {
+			t.Fatalf("Update() took %v; timeouts.update did not bound the API call", elapsed)
+		}
+		if !resp.Diagnostics.HasError() {
+			t.Error("expected an error when the API does not answer within timeouts.update")
+		}
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		_, mockClient := newMockArubaClient(t, func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(minimalActiveJSON)) //nolint:errcheck
+		})
+		r := NewVPCResource()
+		configureResource(ctx, t, r, mockClient)
+
+		req, resp := resourceDeleteReq(ctx, t, r)
+		req.State.Raw = withTimeouts(t, req.State.Raw, map[string]string{"delete": "50ms"})
+		resp.State = req.State
+
+		start := time.Now()
+		r.Delete(ctx, req, resp)
+		if elapsed := time.Since(start); elapsed > limit {
+			t.Fatalf("Delete() took %v; timeouts.delete was not honoured", elapsed)
+		}
+		if !resp.Diagnostics.HasError() {
+			t.Error("expected an error when the resource is not deleted within timeouts.delete")
+		}
+	})
+}
+
+// TestResourceRead_PreservesTimeouts verifies that Read keeps the configured
+// timeouts block in state; the API never returns it.
+func TestResourceRead_PreservesTimeouts(t *testing.T) {
+	ctx := context.Background()
+
+	for name, newR := range map[string]func() resource.Resource{
+		"vpc":         NewVPCResource,
+		"cloudserver": NewCloudServerResource,
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, mockClient := newMockArubaClient(t, func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(http.StatusOK)
+				w.Write([]byte(minimalActiveJSON)) //nolint:errcheck
+			})
+			r := newR()
+			configureResource(ctx, t, r, mockClient)
+
+			req, resp := resourceReadReqFull(ctx, t, r)
+			req.State.Raw = withTimeouts(t, req.State.Raw, map[string]string{"create": "45m", "delete": "20m"})
+			r.Read(ctx, req, resp)
+			if resp.Diagnostics.HasError() {
+				t.Fatalf("Read() error: %v", resp.Diagnostics)
+			}
+
+			var got timeouts.Value
+			resp.Diagnostics.Append(resp.State.GetAttribute(ctx, path.Root("timeouts"), &got)...)
+			if resp.Diagnostics.HasError() {
+				t.Fatalf("GetAttribute(timeouts) error: %v", resp.Diagnostics)
+			}
+			if d, _ := got.Create(ctx, 0); d != 45*time.Minute {
+				t.Errorf("timeouts.create = %v after Read, want 45m", d)
+			}
+			if d, _ := got.Delete(ctx, 0); d != 20*time.Minute {
+				t.Errorf("timeouts.delete = %v after Read, want 20m", d)
+			}
+		})
+	}
+}
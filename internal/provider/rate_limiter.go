@@ -0,0 +1,80 @@
+package provider
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// requestLimiter is the client-side limiter shared by every API call made
+// through an ArubaCloudClient. It caps the number of requests in flight
+// (max_concurrent_requests) and spaces request starts evenly so that no more
+// than requests_per_second are sent. A nil *requestLimiter imposes no limit.
+type requestLimiter struct {
+	// slots holds one token per in-flight request; nil means no concurrency cap.
+	slots chan struct{}
+	// interval is the minimum gap between two request starts; zero means no rate limit.
+	interval time.Duration
+
+	mu   sync.Mutex
+	next time.Time
+}
+
+// newRequestLimiter returns a limiter for the given settings, or nil when
+// both are zero (unlimited).
+func newRequestLimiter(maxConcurrent int64, requestsPerSecond float64) *requestLimiter {
+	if maxConcurrent <= 0 && requestsPerSecond <= 0 {
+		return nil
+	}
+	l := &requestLimiter{}
+	if maxConcurrent > 0 {
+		l.slots = make(chan struct{}, maxConcurrent)
+	}
+	if requestsPerSecond > 0 {
+		l.interval = time.Duration(float64(time.Second) / requestsPerSecond)
+	}
+	return l
+}
+
+// acquire blocks until a request may start, or until ctx is done. On success
+// the returned release func must be called once the request has finished;
+// it is safe to call more than once.
+func (l *requestLimiter) acquire(ctx context.Context) (func(), error) {
+	if l == nil {
+		return func() {}, nil
+	}
+
+	release := func() {}
+	if l.slots != nil {
+		select {
+		case l.slots <- struct{}{}:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+		var once sync.Once
+		release = func() { once.Do(func() { <-l.slots }) }
+	}
+
+	if l.interval > 0 {
+		l.mu.Lock()
+		now := time.Now()
+		start := l.next
+		if start.Before(now) {
+			start = now
+		}
+		l.next = start.Add(l.interval)
+		l.mu.Unlock()
+
+		if wait := time.Until(start); wait > 0 {
+			timer := time.NewTimer(wait)
+			defer timer.Stop()
+			select {
+			case <-timer.C:
+			case <-ctx.Done():
+				release()
+				return nil, ctx.Err()
+			}
+		}
+	}
+	return release, nil
+}
@@ -0,0 +1,214 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// inFlightHandler wraps h and records the peak number of concurrent
+// requests and the total request count. Each request is held for hold so
+// that unlimited clients visibly overlap.
+type inFlightHandler struct {
+	hold     time.Duration
+	h        http.HandlerFunc
+	current  int32
+	peak     int32
+	requests int32
+}
+
+func (f *inFlightHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	n := atomic.AddInt32(&f.current, 1)
+	defer atomic.AddInt32(&f.current, -1)
+	atomic.AddInt32(&f.requests, 1)
+	for {
+		peak := atomic.LoadInt32(&f.peak)
+		if n <= peak || atomic.CompareAndSwapInt32(&f.peak, peak, n) {
+			break
+		}
+	}
+	time.Sleep(f.hold)
+	f.h(w, r)
+}
+
+func TestNewRequestLimiter_Unlimited(t *testing.T) {
+	if l := newRequestLimiter(0, 0); l != nil {
+		t.Fatalf("newRequestLimiter(0, 0) = %+v, want nil", l)
+	}
+	var l *requestLimiter
+	release, err := l.acquire(context.Background())
+	if err != nil {
+		t.Fatalf("nil limiter acquire() error: %v", err)
+	}
+	release()
+}
+
+func TestRequestLimiter_ConcurrencyCap(t *testing.T) {
+	f := &inFlightHandler{hold: 20 * time.Millisecond, h: func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("ok")) //nolint:errcheck
+	}}
+	srv := httptest.NewServer(f)
+	t.Cleanup(srv.Close)
+
+	client := newAPIHTTPClient(newRequestLimiter(3, 0), noRetry)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			resp, err := client.Get(srv.URL)
+			if err != nil {
+				t.Errorf("GET error: %v", err)
+				return
+			}
+			_, _ = io.Copy(io.Discard, resp.Body)
+			resp.Body.Close()
+		}()
+	}
+	wg.Wait()
+
+	if got := atomic.LoadInt32(&f.peak); got > 3 {
+		t.Errorf("peak in-flight requests = %d, want <= 3", got)
+	}
+	if got := atomic.LoadInt32(&f.requests); got != 20 {
+		t.Errorf("requests = %d, want 20", got)
+	}
+}
+
+func TestRequestLimiter_Rate(t *testing.T) {
+	f := &inFlightHandler{h: func(w http.ResponseWriter, r *http.Request) {}}
+	srv := httptest.NewServer(f)
+	t.Cleanup(srv.Close)
+
+	client := newAPIHTTPClient(newRequestLimiter(0, 50), noRetry)
+
+	start := time.Now()
+	var wg sync.WaitGroup
+	for i := 0; i < 11; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			resp, err := client.Get(srv.URL)
+			if err != nil {
+				t.Errorf("GET error: %v", err)
+				return
+			}
+			resp.Body.Close()
+		}()
+	}
+	wg.Wait()
+
+	// 11 requests at 50/s: the last one may not start before 10 * 20ms.
+	if elapsed := time.Since(start); elapsed < 200*time.Millisecond {
+		t.Errorf("11 requests at 50 rps took %s, want >= 200ms", elapsed)
+	}
+}
+
+func TestRequestLimiter_AcquireHonoursContext(t *testing.T) {
+	l := newRequestLimiter(1, 0)
+	release, err := l.acquire(context.Background())
+	if err != nil {
+		t.Fatalf("acquire() error: %v", err)
+	}
+	defer release()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if _, err := l.acquire(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("acquire() with a full limiter = %v, want context.DeadlineExceeded", err)
+	}
+
+	release()
+	release() // a second release must not free another slot
+	if len(l.slots) != 0 {
+		t.Errorf("slots in use = %d, want 0", len(l.slots))
+	}
+}
+
+// TestResourceOperations_HonourSharedLimiter runs concurrent Reads of
+// provisioning resources — each polls WaitForResourceActive before
+// re-reading — against the mock server and checks that CRUD calls and wait
+// loops together never exceed max_concurrent_requests.
+func TestResourceOperations_HonourSharedLimiter(t *testing.T) {
+	oldActivePoll := waitForActivePollInterval
+	waitForActivePollInterval = 1 * time.Millisecond
+	t.Cleanup(func() { waitForActivePollInterval = oldActivePoll })
+
+	var gets int32
+	f := &inFlightHandler{hold: 5 * time.Millisecond, h: func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		state := "Active"
+		if atomic.AddInt32(&gets, 1) <= 16 {
+			state = "InCreation"
+		}
+		w.Write([]byte(`{"metadata":{"id":"test-id","name":"test-name"},"status":{"state":"` + state + `"}}`)) //nolint:errcheck
+	}}
+	_, mockClient := newMockArubaClientLimited(t, f.ServeHTTP, 2, 0)
+
+	ctx := context.Background()
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			res := NewVPCResource()
+			configureResource(ctx, t, res, mockClient)
+			req, resp := resourceReadReq(ctx, t, res)
+			res.Read(ctx, req, resp)
+			if resp.Diagnostics.HasError() {
+				t.Errorf("Read() reported error: %v", resp.Diagnostics)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if got := atomic.LoadInt32(&f.peak); got > 2 {
+		t.Errorf("peak in-flight API requests = %d, want <= 2", got)
+	}
+	if got := atomic.LoadInt32(&f.requests); got <= 16 {
+		t.Errorf("API requests = %d, want > 16 (reads plus wait-loop polls)", got)
+	}
+}
+
+// TestResourceOperations_HonourRequestsPerSecond checks that concurrent
+// resource Reads are spread out according to requests_per_second.
+func TestResourceOperations_HonourRequestsPerSecond(t *testing.T) {
+	f := &inFlightHandler{h: func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(minimalActiveJSON)) //nolint:errcheck
+	}}
+	_, mockClient := newMockArubaClientLimited(t, f.ServeHTTP, 0, 40)
+
+	ctx := context.Background()
+	start := time.Now()
+	var wg sync.WaitGroup
+	for i := 0; i < 6; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			res := NewSubnetResource()
+			configureResource(ctx, t, res, mockClient)
+			req, resp := resourceReadReq(ctx, t, res)
+			res.Read(ctx, req, resp)
+			if resp.Diagnostics.HasError() {
+				t.Errorf("Read() reported error: %v", resp.Diagnostics)
+			}
+		}()
+	}
+	wg.Wait()
+
+	// At least the 6 reads go through the limiter (the token request adds
+	// one more): at 40 rps the last may not start before 5 * 25ms.
+	if elapsed := time.Since(start); elapsed < 125*time.Millisecond {
+		t.Errorf("6 reads at 40 rps took %s, want >= 125ms", elapsed)
+	}
+}
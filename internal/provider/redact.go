@@ -0,0 +1,220 @@
+package provider
+
+import (
+	"bytes"
+	"encoding/json"
+	"mime"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+// redactedValue replaces every redacted value.
+const redactedValue = "REDACTED"
+
+// defaultRedactFields are the fields redacted when no list is configured.
+var defaultRedactFields = []string{"password", "secret", "psk", "user_data", "value", "kubeconfig"}
+
+// credentialFields are always redacted: the OAuth2 client secret and the
+// tokens of the token exchange.
+var credentialFields = []string{"client_secret", "access_token", "refresh_token", "id_token"}
+
+// credentialHeaders are always redacted.
+var credentialHeaders = []string{"Authorization", "Proxy-Authorization", "Cookie", "Set-Cookie"}
+
+// redactor replaces the values of sensitive fields in JSON bodies, form
+// bodies and query strings. A field matches an entry when its name, ignoring
+// case, "_" and "-", ends with it: "password" also matches "adminPassword"
+// and "user_data" matches "userData".
+type redactor struct {
+	fields []string
+}
+
+// newRedactor returns a redactor for fields and the credential fields.
+func newRedactor(fields []string) *redactor {
+	r := &redactor{}
+	for _, f := range append(append([]string{}, fields...), credentialFields...) {
+		if n := normalizeFieldName(f); n != "" {
+			r.fields = append(r.fields, n)
+		}
+	}
+	return r
+}
+
+func normalizeFieldName(s string) string {
+	return strings.ToLower(strings.NewReplacer("_", "", "-", "", " ", "").Replace(s))
+}
+
+// matches reports whether the value of field name is redacted.
+func (r *redactor) matches(name string) bool {
+	n := normalizeFieldName(name)
+	for _, f := range r.fields {
+		if strings.HasSuffix(n, f) {
+			return true
+		}
+	}
+	return false
+}
+
+// header returns a copy of h with credential headers redacted.
+func (r *redactor) header(h http.Header) http.Header {
+	out := h.Clone()
+	for _, name := range credentialHeaders {
+		if _, ok := out[name]; ok {
+			out[name] = []string{redactedValue}
+		}
+	}
+	return out
+}
+
+// values redacts the matching fields of values in place.
+func (r *redactor) values(values url.Values) url.Values {
+	for k := range values {
+		if r.matches(k) {
+			values[k] = []string{redactedValue}
+		}
+	}
+	return values
+}
+
+// url returns u with its credentials and matching query parameters redacted.
+func (r *redactor) url(u *url.URL) string {
+	c := *u
+	if c.User != nil {
+		c.User = url.User(redactedValue)
+	}
+	if c.RawQuery != "" {
+		c.RawQuery = r.values(c.Query()).Encode()
+	}
+	return c.String()
+}
+
+// body returns body as text with matching fields redacted. JSON and form
+// bodies are redacted field by field; other bodies are returned as they are
+// when they are text, and as false otherwise.
+func (r *redactor) body(contentType string, body []byte) (string, bool) {
+	if len(body) == 0 {
+		return "", true
+	}
+	if redacted, ok := r.json(body); ok {
+		return string(redacted), true
+	}
+	if mediaType, _, _ := mime.ParseMediaType(contentType); mediaType == "application/x-www-form-urlencoded" {
+		if values, err := url.ParseQuery(string(body)); err == nil {
+			return r.values(values).Encode(), true
+		}
+	}
+	if !utf8.Valid(body) {
+		return "", false
+	}
+	return string(body), true
+}
+
+// json returns the JSON document body with matching fields redacted, or
+// false when body is not JSON.
+func (r *redactor) json(body []byte) ([]byte, bool) {
+	dec := json.NewDecoder(bytes.NewReader(body))
+	dec.UseNumber()
+	var v interface{}
+	if err := dec.Decode(&v); err != nil || dec.More() {
+		return nil, false
+	}
+	out, err := json.Marshal(r.jsonValue(v))
+	if err != nil {
+		return nil, false
+	}
+	return out, true
+}
+
+func (r *redactor) jsonValue(v interface{}) interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		for k, item := range v {
+			if item != nil && r.matches(k) {
+				v[k] = redactedValue
+				continue
+			}
+			v[k] = r.jsonValue(item)
+		}
+	case []interface{}:
+		for i, item := range v {
+			v[i] = r.jsonValue(item)
+		}
+	}
+	return v
+}
+
+var (
+	// logJSONStringField matches a "key": "value" pair of JSON that could not
+	// be parsed, such as a truncated body.
+	logJSONStringField = regexp.MustCompile(`"((?:[^"\\]|\\.)*)"(\s*:\s*)"(?:[^"\\]|\\.)*"`)
+	// logQuotedJSONStringField matches the same pair in JSON quoted with %q.
+	logQuotedJSONStringField = regexp.MustCompile(`\\"([^"\\]*)\\"(\s*:\s*)\\"(?:[^"\\]|\\\\)*\\"`)
+	// logParam matches a query or form parameter.
+	logParam = regexp.MustCompile(`(^|[?&\s])([A-Za-z0-9_.\-]+)=([^&\s#"'\]]*)`)
+	// logCredential matches the credentials of an Authorization header.
+	logCredential = regexp.MustCompile(`\b(Bearer|bearer|BEARER|Basic)\s+[A-Za-z0-9\-._~+/]{8,}=*`)
+)
+
+// text returns the log message s with its secrets redacted: the matching
+// fields of the JSON documents in it, including truncated and quoted ones,
+// matching query and form parameters, and bearer and basic credentials.
+func (r *redactor) text(s string) string {
+	s = r.embeddedJSON(s)
+	for _, re := range []*regexp.Regexp{logJSONStringField, logQuotedJSONStringField} {
+		re := re
+		s = re.ReplaceAllStringFunc(s, func(m string) string {
+			sub := re.FindStringSubmatch(m)
+			if !r.matches(sub[1]) {
+				return m
+			}
+			if re == logQuotedJSONStringField {
+				return `\"` + sub[1] + `\"` + sub[2] + `\"` + redactedValue + `\"`
+			}
+			return `"` + sub[1] + `"` + sub[2] + `"` + redactedValue + `"`
+		})
+	}
+	s = logParam.ReplaceAllStringFunc(s, func(m string) string {
+		sub := logParam.FindStringSubmatch(m)
+		if !r.matches(sub[2]) || sub[3] == "" {
+			return m
+		}
+		return sub[1] + sub[2] + "=" + redactedValue
+	})
+	return logCredential.ReplaceAllString(s, "$1 "+redactedValue)
+}
+
+// embeddedJSON redacts the JSON documents in s. Documents without matching
+// fields are left as they are, formatting included.
+func (r *redactor) embeddedJSON(s string) string {
+	var b strings.Builder
+	for i := 0; i < len(s); {
+		j := strings.IndexAny(s[i:], "{[")
+		if j < 0 {
+			b.WriteString(s[i:])
+			break
+		}
+		j += i
+		b.WriteString(s[i:j])
+		dec := json.NewDecoder(strings.NewReader(s[j:]))
+		dec.UseNumber()
+		var v interface{}
+		if err := dec.Decode(&v); err == nil {
+			end := j + int(dec.InputOffset())
+			before, _ := json.Marshal(v)
+			after, err := json.Marshal(r.jsonValue(v))
+			if err == nil && !bytes.Equal(before, after) {
+				b.Write(after)
+			} else {
+				b.WriteString(s[j:end])
+			}
+			i = end
+			continue
+		}
+		b.WriteByte(s[j])
+		i = j + 1
+	}
+	return b.String()
+}
@@ -0,0 +1,86 @@
+package provider
+
+import (
+	"net/http"
+	"net/url"
+	"testing"
+)
+
+func TestRedactor_Body(t *testing.T) {
+	r := newRedactor(defaultRedactFields)
+	tests := []struct {
+		name        string
+		contentType string
+		body        string
+		want        string
+	}{
+		{
+			name:        "nested JSON fields",
+			contentType: "application/json",
+			body:        `{"username":"app","password":"p","settings":{"userData":"#cloud-config","vpnClientSettings":{"psk":"k"}}}`,
+			want:        `{"password":"REDACTED","settings":{"userData":"REDACTED","vpnClientSettings":{"psk":"REDACTED"}},"username":"app"}`,
+		},
+		{
+			name:        "suffix match and objects",
+			contentType: "application/json",
+			body:        `{"adminPassword":"p","clientSecret":"s","kubeconfig":{"clusters":[]},"items":[{"value":"v"}],"name":null}`,
+			want:        `{"adminPassword":"REDACTED","clientSecret":"REDACTED","items":[{"value":"REDACTED"}],"kubeconfig":"REDACTED","name":null}`,
+		},
+		{
+			name:        "token response",
+			contentType: "application/json",
+			body:        `{"access_token":"eyJ","expires_in":300,"token_type":"Bearer"}`,
+			want:        `{"access_token":"REDACTED","expires_in":300,"token_type":"Bearer"}`,
+		},
+		{
+			name:        "token request",
+			contentType: "application/x-www-form-urlencoded",
+			body:        "client_id=id&client_secret=s&grant_type=client_credentials",
+			want:        "client_id=id&client_secret=REDACTED&grant_type=client_credentials",
+		},
+		{
+			name:        "text",
+			contentType: "text/plain",
+			body:        "upstream timeout",
+			want:        "upstream timeout",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := r.body(tt.contentType, []byte(tt.body))
+			if !ok || got != tt.want {
+				t.Errorf("body() = %s, %v; want %s", got, ok, tt.want)
+			}
+		})
+	}
+
+	if _, ok := r.body("application/octet-stream", []byte{0xff, 0xfe, 0x00}); ok {
+		t.Error("body() of a binary body reported text")
+	}
+}
+
+func TestRedactor_CustomFields(t *testing.T) {
+	r := newRedactor([]string{"ssh_key"})
+	got, _ := r.body("application/json", []byte(`{"password":"p","sshKey":"k","value":"v","client_secret":"s"}`))
+	want := `{"client_secret":"REDACTED","password":"p","sshKey":"REDACTED","value":"v"}`
+	if got != want {
+		t.Errorf("body() = %s, want %s: a custom list replaces the defaults but keeps credentials", got, want)
+	}
+}
+
+func TestRedactor_URLAndHeaders(t *testing.T) {
+	r := newRedactor(defaultRedactFields)
+	u, _ := url.Parse("https://api.example.com/projects?filter=a&access_token=t&secret=s")
+	if got, want := r.url(u), "https://api.example.com/projects?access_token=REDACTED&filter=a&secret=REDACTED"; got != want {
+		t.Errorf("url() = %s, want %s", got, want)
+	}
+
+	h := http.Header{"Authorization": {"Bearer eyJ"}, "Content-Type": {"application/json"}, "Set-Cookie": {"s=1"}}
+	got := r.header(h)
+	if got.Get("Authorization") != redactedValue || got.Get("Set-Cookie") != redactedValue || got.Get("Content-Type") != "application/json" {
+		t.Errorf("header() = %v", got)
+	}
+	if h.Get("Authorization") != "Bearer eyJ" {
+		t.Error("header() modified its argument")
+	}
+}
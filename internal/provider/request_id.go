@@ -0,0 +1,134 @@
+package provider
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// requestIDHeader carries the correlation ID of every request the SDK sends.
+const requestIDHeader = "X-Request-Id"
+
+// requestIDLogField is the log field holding the correlation ID.
+const requestIDLogField = "request_id"
+
+// serverRequestIDHeaders are the response headers the API and its gateways
+// may return the request ID they assigned in, in order of preference.
+var serverRequestIDHeaders = []string{"X-Request-Id", "X-Correlation-Id", "Request-Id", "X-Trace-Id"}
+
+type correlationIDKey struct{}
+
+// withCorrelationID returns ctx with a new correlation ID, sent as the
+// X-Request-Id header of every SDK call made with it and added to its log
+// fields. Each resource and data source operation calls it first, so all
+// the requests of one operation, retries and wait-loop polls included,
+// share one ID that can be handed to Aruba support. The provider's
+// sensitive log fields are masked on the returned ctx; the SDK logs with the
+// Configure context instead, see maskSensitiveSDKLogFields. A ctx that
+// already has an ID is returned unchanged.
+func withCorrelationID(ctx context.Context) context.Context {
+	if correlationID(ctx) != "" {
+		return ctx
+	}
+	id := newCorrelationID()
+	ctx = context.WithValue(ctx, correlationIDKey{}, id)
+	ctx = maskSensitiveLogFields(ctx)
+	return tflog.SetField(ctx, requestIDLogField, id)
+}
+
+// correlationID returns the correlation ID of ctx, or "".
+func correlationID(ctx context.Context) string {
+	id, _ := ctx.Value(correlationIDKey{}).(string)
+	return id
+}
+
+// newCorrelationID returns a random (version 4) UUID.
+func newCorrelationID() string {
+	var b [16]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return fmt.Sprintf("%032x", timeNow().UnixNano())
+	}
+	b[6] = b[6]&0x0f | 0x40
+	b[8] = b[8]&0x3f | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:])
+}
+
+// setRequestID returns req with the X-Request-Id header of its context, or
+// of a new ID for a request made outside an operation. A header already set
+// is kept.
+func setRequestID(req *http.Request) *http.Request {
+	if req.Header.Get(requestIDHeader) != "" {
+		return req
+	}
+	id := correlationID(req.Context())
+	if id == "" {
+		id = newCorrelationID()
+	}
+	req = req.Clone(req.Context())
+	req.Header.Set(requestIDHeader, id)
+	return req
+}
+
+// serverRequestID returns the request ID the server assigned to resp, or ""
+// when it returned none besides the echo of the one sent.
+func serverRequestID(req *http.Request, resp *http.Response) string {
+	for _, name := range serverRequestIDHeaders {
+		if v := strings.TrimSpace(resp.Header.Get(name)); v != "" && v != req.Header.Get(requestIDHeader) {
+			return v
+		}
+	}
+	return ""
+}
+
+// annotateRequestIDs records the correlation ID and the server's request ID
+// of an error response in its problem-details body, as the "correlationId"
+// and "serverRequestId" extension members, so that they reach
+// CheckResponseErr as the Retry-After delay does.
+func annotateRequestIDs(req *http.Request, resp *http.Response) {
+	members := map[string]json.RawMessage{
+		"correlationId": json.RawMessage(strconv.Quote(req.Header.Get(requestIDHeader))),
+	}
+	if id := serverRequestID(req, resp); id != "" {
+		members["serverRequestId"] = json.RawMessage(strconv.Quote(id))
+	}
+	annotateProblem(resp, members)
+}
+
+// requestIDsFromBody returns the IDs recorded by annotateRequestIDs. When
+// the server returned no request ID header, the "traceId" or "requestId"
+// member of its problem details is used instead.
+func requestIDsFromBody(body []byte) (correlation, server string) {
+	var problem struct {
+		CorrelationID   string `json:"correlationId"`
+		ServerRequestID string `json:"serverRequestId"`
+		TraceID         string `json:"traceId"`
+		RequestID       string `json:"requestId"`
+	}
+	if len(body) == 0 || json.Unmarshal(body, &problem) != nil {
+		return "", ""
+	}
+	server = problem.ServerRequestID
+	for _, id := range []string{problem.TraceID, problem.RequestID} {
+		if server == "" && id != problem.CorrelationID {
+			server = id
+		}
+	}
+	return sanitizeAPIString(problem.CorrelationID), sanitizeAPIString(server)
+}
+
+// requestError is a transport failure of a request, carrying its
+// correlation ID to CheckResponseErr.
+type requestError struct {
+	err       error
+	requestID string
+}
+
+func (e *requestError) Error() string { return e.err.Error() }
+
+func (e *requestError) Unwrap() error { return e.err }
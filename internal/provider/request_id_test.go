@@ -0,0 +1,159 @@
+package provider
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"sync"
+	"testing"
+
+	aruba "github.com/Arubacloud/sdk-go/pkg/aruba"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/hashicorp/terraform-plugin-log/tflogtest"
+)
+
+var uuidPattern = regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`)
+
+func TestWithCorrelationID(t *testing.T) {
+	var buf bytes.Buffer
+	ctx := withCorrelationID(tflogtest.RootLogger(context.Background(), &buf))
+	id := correlationID(ctx)
+	if !uuidPattern.MatchString(id) {
+		t.Fatalf("correlation ID %q is not a version 4 UUID", id)
+	}
+	if got := correlationID(withCorrelationID(ctx)); got != id {
+		t.Errorf("withCorrelationID() replaced the ID of its context: %q, want %q", got, id)
+	}
+	if other := correlationID(withCorrelationID(context.Background())); other == id {
+		t.Error("two operations got the same correlation ID")
+	}
+
+	tflog.Info(ctx, "operation", map[string]interface{}{"password": "op-secret"})
+	if !strings.Contains(buf.String(), `"request_id":"`+id+`"`) {
+		t.Errorf("log entry has no request_id field: %s", buf.String())
+	}
+	if strings.Contains(buf.String(), "op-secret") {
+		t.Errorf("log entry contains a sensitive field: %s", buf.String())
+	}
+}
+
+// TestAPITransport_RequestIDHeader verifies that every attempt of the
+// requests of one operation carries its correlation ID, and that a request
+// made outside an operation gets an ID of its own.
+func TestAPITransport_RequestIDHeader(t *testing.T) {
+	var (
+		mu   sync.Mutex
+		seen []string
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		seen = append(seen, r.Header.Get("X-Request-Id"))
+		first := len(seen) == 1
+		mu.Unlock()
+		if first {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+	client := &http.Client{Transport: newAPITransport(nil, fastRetry)}
+
+	ctx := withCorrelationID(context.Background())
+	for i := 0; i < 2; i++ {
+		req, _ := http.NewRequestWithContext(ctx, http.MethodGet, srv.URL+"/projects/p1", nil)
+		resp, err := client.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+	}
+	resp, err := client.Get(srv.URL + "/projects")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+
+	id := correlationID(ctx)
+	if len(seen) != 4 || seen[0] != id || seen[1] != id || seen[2] != id {
+		t.Fatalf("X-Request-Id headers = %v, want the retried and the second request to carry %s", seen, id)
+	}
+	if seen[3] == id || !uuidPattern.MatchString(seen[3]) {
+		t.Errorf("request outside an operation sent X-Request-Id %q, want a new UUID", seen[3])
+	}
+}
+
+// TestCheckResponseErr_RequestIDs verifies that the IDs of a failed request
+// reach the error and its diagnostics.
+func TestCheckResponseErr_RequestIDs(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Request-Id", r.Header.Get("X-Request-Id"))
+		w.Header().Set("X-Correlation-Id", "srv-123")
+		w.Header().Set("Content-Type", "application/problem+json")
+		w.WriteHeader(http.StatusConflict)
+		_, _ = w.Write([]byte(`{"title":"Conflict","status":409}`))
+	}))
+	defer srv.Close()
+	client := &http.Client{Transport: newAPITransport(nil, fastRetry)}
+
+	ctx := withCorrelationID(context.Background())
+	id := correlationID(ctx)
+	req, _ := http.NewRequestWithContext(ctx, http.MethodDelete, srv.URL+"/projects/p1", nil)
+	resp, err := client.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+
+	provErr := CheckResponseErr("delete", "Project", &aruba.HTTPError{StatusCode: resp.StatusCode, Body: body})
+	if provErr.RequestID != id || provErr.ServerRequestID != "srv-123" {
+		t.Fatalf("RequestID, ServerRequestID = %q, %q; want %q, srv-123", provErr.RequestID, provErr.ServerRequestID, id)
+	}
+	var diags diag.Diagnostics
+	AppendAPIError(&diags, provErr)
+	if detail := diags[0].Detail(); !strings.Contains(detail, "request_id: "+id) || !strings.Contains(detail, "server_request_id: srv-123") {
+		t.Errorf("diagnostic detail %q lacks the request IDs", detail)
+	}
+
+	// Without a server header, the traceId of the problem details is used.
+	_, server := requestIDsFromBody([]byte(`{"title":"Bad Request","traceId":"00-abc-01"}`))
+	if server != "00-abc-01" {
+		t.Errorf("server request ID = %q, want the traceId", server)
+	}
+}
+
+// TestCheckResponseErr_TransportFailureRequestID verifies that a request
+// that got no response still reports its correlation ID.
+func TestCheckResponseErr_TransportFailureRequestID(t *testing.T) {
+	transport := newAPITransport(nil, noRetry)
+	transport.base = roundTripFunc(func(*http.Request) (*http.Response, error) { return nil, io.ErrUnexpectedEOF })
+	client := &http.Client{Transport: transport}
+
+	ctx := withCorrelationID(context.Background())
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, "https://api.example.com/projects/p1", nil)
+	_, err := client.Do(req)
+	if !errors.Is(err, io.ErrUnexpectedEOF) {
+		t.Fatalf("error = %v, want it to wrap the transport error", err)
+	}
+
+	provErr := CheckResponseErr("read", "Project", err)
+	if provErr.RequestID != correlationID(ctx) || !strings.Contains(provErr.Error(), "request_id: "+correlationID(ctx)) {
+		t.Errorf("error = %q, want it to carry request_id %s", provErr.Error(), correlationID(ctx))
+	}
+}
+
+func TestLogAndAppendAPIError_RequestID(t *testing.T) {
+	ctx := withCorrelationID(context.Background())
+	var diags diag.Diagnostics
+	LogAndAppendAPIError(ctx, &diags, "Read Error", errors.New("timeout"), nil)
+	if want := "timeout (request_id: " + correlationID(ctx) + ")"; diags[0].Detail() != want {
+		t.Errorf("detail = %q, want %q", diags[0].Detail(), want)
+	}
+}
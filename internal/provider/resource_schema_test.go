@@ -4,7 +4,9 @@ import (
 	"context"
 	"testing"
 
+	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
 )
 
 // TestResourceSchemas tests that all resources have valid schemas.
@@ -318,3 +320,78 @@ func TestSubnetResourceSchema(t *testing.T) {
 		}
 	}
 }
+
+// TestResourceSchemas_ValidateImplementation runs the framework's schema
+// implementation checks (e.g. WriteOnly placement rules) for every resource.
+func TestResourceSchemas_ValidateImplementation(t *testing.T) {
+	ctx := context.Background()
+
+	for _, rFunc := range New("test")().Resources(ctx) {
+		r := rFunc()
+		metadataResp := &resource.MetadataResponse{}
+		r.Metadata(ctx, resource.MetadataRequest{ProviderTypeName: "arubacloud"}, metadataResp)
+
+		schemaResp := &resource.SchemaResponse{}
+		r.Schema(ctx, resource.SchemaRequest{}, schemaResp)
+
+		if diags := schemaResp.Schema.ValidateImplementation(ctx); diags.HasError() {
+			t.Errorf("Resource %s schema implementation is invalid: %v", metadataResp.TypeName, diags)
+		}
+	}
+}
+
+// TestWriteOnlyAttributes verifies that every secret has a write-only
+// variant and that the state-persisted original is deprecated.
+func TestWriteOnlyAttributes(t *testing.T) {
+	ctx := context.Background()
+
+	pskPath := path.Root("properties").AtName("vpn_client_settings").AtName("psk")
+	testCases := []struct {
+		name       string
+		rFunc      func() resource.Resource
+		deprecated path.Path
+		writeOnly  path.Path
+		version    path.Path
+	}{
+		{"dbaasuser", NewDBaaSUserResource, path.Root("password"), path.Root("password_wo"), path.Root("password_wo_version")},
+		{"keypair", NewKeypairResource, path.Root("value"), path.Root("value_wo"), path.Root("value_wo_version")},
+		{"cloudserver", NewCloudServerResource, path.Root("settings").AtName("user_data"), path.Root("settings").AtName("user_data_wo"), path.Root("settings").AtName("user_data_wo_version")},
+		{"vpntunnel/secret", NewVPNTunnelResource, pskPath.AtName("secret"), pskPath.AtName("secret_wo"), path.Root("properties").AtName("vpn_client_settings").AtName("psk_wo_version")},
+		{"vpntunnel/cloud_site", NewVPNTunnelResource, pskPath.AtName("cloud_site"), pskPath.AtName("cloud_site_wo"), path.Root("properties").AtName("vpn_client_settings").AtName("psk_wo_version")},
+		{"vpntunnel/on_prem_site", NewVPNTunnelResource, pskPath.AtName("on_prem_site"), pskPath.AtName("on_prem_site_wo"), path.Root("properties").AtName("vpn_client_settings").AtName("psk_wo_version")},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			schemaResp := &resource.SchemaResponse{}
+			tc.rFunc().Schema(ctx, resource.SchemaRequest{}, schemaResp)
+
+			dep, diags := schemaResp.Schema.AttributeAtPath(ctx, tc.deprecated)
+			if diags.HasError() {
+				t.Fatalf("missing attribute %s: %v", tc.deprecated, diags)
+			}
+			if dep.GetDeprecationMessage() == "" {
+				t.Errorf("%s should be deprecated", tc.deprecated)
+			}
+			if !dep.IsSensitive() {
+				t.Errorf("%s should be sensitive", tc.deprecated)
+			}
+
+			wo, diags := schemaResp.Schema.AttributeAtPath(ctx, tc.writeOnly)
+			if diags.HasError() {
+				t.Fatalf("missing attribute %s: %v", tc.writeOnly, diags)
+			}
+			if !wo.IsWriteOnly() || !wo.IsSensitive() {
+				t.Errorf("%s should be write-only and sensitive", tc.writeOnly)
+			}
+
+			ver, diags := schemaResp.Schema.AttributeAtPath(ctx, tc.version)
+			if diags.HasError() {
+				t.Fatalf("missing attribute %s: %v", tc.version, diags)
+			}
+			if v, ok := ver.(schema.Int64Attribute); !ok || len(v.Validators) == 0 {
+				t.Errorf("%s should be validated against %s", tc.version, tc.writeOnly)
+			}
+		})
+	}
+}
@@ -222,14 +222,16 @@ func TestVPCPeeringRouteUpdate_RichMetadata(t *testing.T) {
 	}
 }
 
-// TestDBaaSUserUpdate_RichMetadata verifies that dbaasuser Update() returns an
-// error because the API does not support in-place updates (all fields carry
-// RequiresReplace; Terraform will never actually call Update in practice).
+// TestDBaaSUserUpdate_RichMetadata verifies that dbaasuser Update() with an
+// unchanged password only re-checks the user and never deletes or re-creates it.
 func TestDBaaSUserUpdate_RichMetadata(t *testing.T) {
 	ctx := context.Background()
 
 	_, mockClient := newMockArubaClient(t, func(w http.ResponseWriter, r *http.Request) {
-		w.WriteHeader(http.StatusOK)
+		if r.Method != http.MethodGet {
+			t.Errorf("unexpected %s %s for an unchanged password", r.Method, r.URL.Path)
+		}
+		dbaasUserCreateSuccessHandler(w, r)
 	})
 	res := NewDBaaSUserResource()
 	configureResource(ctx, t, res, mockClient)
@@ -237,8 +239,8 @@ func TestDBaaSUserUpdate_RichMetadata(t *testing.T) {
 	req, resp := resourceUpdateReqFull(ctx, t, res)
 	res.Update(ctx, req, resp)
 
-	if !resp.Diagnostics.HasError() {
-		t.Error("dbaasuser Update() should return an error because update is not supported")
+	if resp.Diagnostics.HasError() {
+		t.Errorf("dbaasuser Update() error with unchanged password: %v", resp.Diagnostics)
 	}
 }
 
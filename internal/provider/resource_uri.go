@@ -0,0 +1,348 @@
+package provider
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	aruba "github.com/Arubacloud/sdk-go/pkg/aruba"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Resource kinds understood by the shared URI model. Each name matches the
+// Terraform type suffix (arubacloud_<kind>) so the provider functions and the
+// resources speak the same vocabulary.
+const (
+	uriKindProject           = "project"
+	uriKindVPC               = "vpc"
+	uriKindSubnet            = "subnet"
+	uriKindSecurityGroup     = "securitygroup"
+	uriKindSecurityRule      = "securityrule"
+	uriKindElasticIP         = "elasticip"
+	uriKindVPCPeering        = "vpcpeering"
+	uriKindVPCPeeringRoute   = "vpcpeeringroute"
+	uriKindVPNTunnel         = "vpntunnel"
+	uriKindVPNRoute          = "vpnroute"
+	uriKindCloudServer       = "cloudserver"
+	uriKindKeypair           = "keypair"
+	uriKindBlockStorage      = "blockstorage"
+	uriKindSnapshot          = "snapshot"
+	uriKindBackup            = "backup"
+	uriKindRestore           = "restore"
+	uriKindKaaS              = "kaas"
+	uriKindContainerRegistry = "containerregistry"
+	uriKindDBaaS             = "dbaas"
+	uriKindDatabase          = "database"
+	uriKindDatabaseGrant     = "databasegrant"
+	uriKindDBaaSUser         = "dbaasuser"
+	uriKindDatabaseBackup    = "databasebackup"
+	uriKindScheduleJob       = "schedulejob"
+	uriKindKMS               = "kms"
+)
+
+// uriKindSpec describes where one resource kind lives in an ArubaCloud URI.
+type uriKindSpec struct {
+	// Namespace is the provider namespace segment, e.g. "Aruba.Network".
+	Namespace string
+	// Collection is the path segment the API uses in the URIs it returns.
+	Collection string
+	// Parents lists the kinds this resource is nested under, outermost first.
+	Parents []string
+	// SDKCollection replaces Collection in Refs handed to the SDK, whose ID
+	// extractor only recognises its own spelling of some segments.
+	SDKCollection string
+	// Aliases are additional collection spellings accepted when parsing.
+	Aliases []string
+}
+
+// uriKinds is the single source of truth for URI shapes. The *Ref helpers of
+// the resources and the parse_uri/build_uri/is_uri functions are all driven
+// by this table.
+var uriKinds = map[string]uriKindSpec{
+	uriKindVPC:             {Namespace: "Aruba.Network", Collection: "vpcs"},
+	uriKindSubnet:          {Namespace: "Aruba.Network", Collection: "subnets", Parents: []string{uriKindVPC}},
+	uriKindSecurityGroup:   {Namespace: "Aruba.Network", Collection: "securityGroups", Parents: []string{uriKindVPC}},
+	uriKindSecurityRule:    {Namespace: "Aruba.Network", Collection: "securityRules", Parents: []string{uriKindVPC, uriKindSecurityGroup}},
+	uriKindElasticIP:       {Namespace: "Aruba.Network", Collection: "elasticIPs", SDKCollection: "elasticIps"},
+	uriKindVPCPeering:      {Namespace: "Aruba.Network", Collection: "vpcPeerings", Parents: []string{uriKindVPC}},
+	uriKindVPCPeeringRoute: {Namespace: "Aruba.Network", Collection: "vpcPeeringRoutes", Parents: []string{uriKindVPC, uriKindVPCPeering}},
+	uriKindVPNTunnel:       {Namespace: "Aruba.Network", Collection: "vpnTunnels"},
+	uriKindVPNRoute:        {Namespace: "Aruba.Network", Collection: "vpnRoutes", Parents: []string{uriKindVPNTunnel}},
+	uriKindCloudServer:     {Namespace: "Aruba.Compute", Collection: "cloudServers"},
+	uriKindKeypair:         {Namespace: "Aruba.Compute", Collection: "keyPairs"},
+	uriKindBlockStorage:    {Namespace: "Aruba.Storage", Collection: "volumes", SDKCollection: "blockStorages", Aliases: []string{"blockStorages"}},
+	uriKindSnapshot:        {Namespace: "Aruba.Storage", Collection: "snapshots"},
+	uriKindBackup:          {Namespace: "Aruba.Storage", Collection: "backups"},
+	uriKindRestore:         {Namespace: "Aruba.Storage", Collection: "restores", Parents: []string{uriKindBackup}},
+	uriKindKaaS:            {Namespace: "Aruba.Container", Collection: "kaas"},
+	uriKindContainerRegistry: {
+		Namespace: "Aruba.Container", Collection: "containerRegistries", SDKCollection: "registries", Aliases: []string{"registries"},
+	},
+	uriKindDBaaS:          {Namespace: "Aruba.Database", Collection: "dbaas"},
+	uriKindDatabase:       {Namespace: "Aruba.Database", Collection: "databases", Parents: []string{uriKindDBaaS}},
+	uriKindDatabaseGrant:  {Namespace: "Aruba.Database", Collection: "grants", Parents: []string{uriKindDBaaS, uriKindDatabase}},
+	uriKindDBaaSUser:      {Namespace: "Aruba.Database", Collection: "users", Parents: []string{uriKindDBaaS}},
+	uriKindDatabaseBackup: {Namespace: "Aruba.Database", Collection: "backups"},
+	uriKindScheduleJob:    {Namespace: "Aruba.Schedule", Collection: "jobs"},
+	uriKindKMS:            {Namespace: "Aruba.Security", Collection: "kms"},
+}
+
+// legacyNamespaceSegments maps the short namespace segments still found in
+// older URIs (e.g. "/projects/p/network/vpcs/v") to their provider namespace.
+var legacyNamespaceSegments = map[string]string{
+	"network": "Aruba.Network",
+	"compute": "Aruba.Compute",
+}
+
+// ResourceURISegment is one parent kind/ID pair of a ResourceURI.
+type ResourceURISegment struct {
+	Kind string
+	ID   string
+}
+
+// ResourceURI is the structured form of an ArubaCloud resource URI such as
+// "/projects/<project>/providers/Aruba.Network/vpcs/<id>".
+//
+// Kind is one of the uriKind* constants when the URI matches a known shape.
+// For well-formed URIs of kinds the provider does not know yet, Kind (and the
+// Kind of each parent) holds the raw collection segment instead.
+type ResourceURI struct {
+	ProjectID string
+	Namespace string
+	Kind      string
+	ID        string
+	Parents   []ResourceURISegment
+}
+
+// newResourceURI assembles a ResourceURI for a known kind without validating
+// the identifiers. parentIDs are matched positionally against the kind's parents.
+func newResourceURI(kind, projectID, id string, parentIDs ...string) ResourceURI {
+	u := ResourceURI{ProjectID: projectID, Kind: kind, ID: id}
+	if kind == uriKindProject {
+		u.ID = projectID
+		return u
+	}
+	spec := uriKinds[kind]
+	u.Namespace = spec.Namespace
+	for i, parentKind := range spec.Parents {
+		parentID := ""
+		if i < len(parentIDs) {
+			parentID = parentIDs[i]
+		}
+		u.Parents = append(u.Parents, ResourceURISegment{Kind: parentKind, ID: parentID})
+	}
+	return u
+}
+
+// BuildResourceURI validates the identifiers and returns the canonical URI for
+// a resource of the given kind. parentIDs must list one ID per parent kind,
+// outermost first (e.g. vpc_id then security_group_id for a security rule).
+func BuildResourceURI(kind, projectID, id string, parentIDs ...string) (ResourceURI, error) {
+	if err := validateURISegment("project_id", projectID); err != nil {
+		return ResourceURI{}, err
+	}
+	if kind == uriKindProject {
+		if id != "" && id != projectID {
+			return ResourceURI{}, fmt.Errorf("id %q must be empty or equal to project_id %q for kind %q", id, projectID, kind)
+		}
+		if len(parentIDs) != 0 {
+			return ResourceURI{}, fmt.Errorf("kind %q takes no parent IDs, got %d", kind, len(parentIDs))
+		}
+		return newResourceURI(kind, projectID, projectID), nil
+	}
+	spec, ok := uriKinds[kind]
+	if !ok {
+		return ResourceURI{}, fmt.Errorf("unknown resource kind %q; expected one of: %s", kind, strings.Join(knownURIKinds(), ", "))
+	}
+	if err := validateURISegment("id", id); err != nil {
+		return ResourceURI{}, err
+	}
+	if len(parentIDs) != len(spec.Parents) {
+		return ResourceURI{}, fmt.Errorf("kind %q requires %d parent ID(s) (%s), got %d",
+			kind, len(spec.Parents), strings.Join(spec.Parents, ", "), len(parentIDs))
+	}
+	for i, parentID := range parentIDs {
+		if err := validateURISegment(spec.Parents[i]+" id", parentID); err != nil {
+			return ResourceURI{}, err
+		}
+	}
+	return newResourceURI(kind, projectID, id, parentIDs...), nil
+}
+
+// ParseResourceURI splits an ArubaCloud resource URI into its components.
+// Both the canonical "/projects/<p>/providers/<Namespace>/..." form and the
+// legacy short form ("/projects/<p>/network/...") are accepted. Collection
+// segments are matched case-insensitively.
+func ParseResourceURI(s string) (ResourceURI, error) {
+	trimmed := strings.Trim(strings.TrimSpace(s), "/")
+	parts := strings.Split(trimmed, "/")
+	if len(parts) < 2 || parts[0] != "projects" || parts[1] == "" {
+		return ResourceURI{}, fmt.Errorf("%q is not an ArubaCloud resource URI: expected it to start with /projects/<project_id>", s)
+	}
+	u := ResourceURI{ProjectID: parts[1]}
+	if len(parts) == 2 {
+		u.Kind = uriKindProject
+		u.ID = u.ProjectID
+		return u, nil
+	}
+
+	var rest []string
+	switch {
+	case parts[2] == "providers" && len(parts) > 3 && parts[3] != "":
+		u.Namespace = parts[3]
+		rest = parts[4:]
+	case legacyNamespaceSegments[parts[2]] != "":
+		u.Namespace = legacyNamespaceSegments[parts[2]]
+		rest = parts[3:]
+	default:
+		return ResourceURI{}, fmt.Errorf("%q is not an ArubaCloud resource URI: expected /providers/<namespace> after the project ID", s)
+	}
+	if len(rest) == 0 || len(rest)%2 != 0 {
+		return ResourceURI{}, fmt.Errorf("%q is not an ArubaCloud resource URI: expected <collection>/<id> pairs after the namespace", s)
+	}
+	for _, p := range rest {
+		if p == "" {
+			return ResourceURI{}, fmt.Errorf("%q is not an ArubaCloud resource URI: empty path segment", s)
+		}
+	}
+
+	collections := make([]string, 0, len(rest)/2)
+	ids := make([]string, 0, len(rest)/2)
+	for i := 0; i < len(rest); i += 2 {
+		collections = append(collections, rest[i])
+		ids = append(ids, rest[i+1])
+	}
+	last := len(collections) - 1
+	u.ID = ids[last]
+
+	if kind, spec, ok := matchURIKind(u.Namespace, collections); ok {
+		u.Namespace = spec.Namespace
+		u.Kind = kind
+		for i, parentKind := range spec.Parents {
+			u.Parents = append(u.Parents, ResourceURISegment{Kind: parentKind, ID: ids[i]})
+		}
+		return u, nil
+	}
+
+	u.Kind = collections[last]
+	for i := 0; i < last; i++ {
+		u.Parents = append(u.Parents, ResourceURISegment{Kind: collections[i], ID: ids[i]})
+	}
+	return u, nil
+}
+
+// IsResourceURI reports whether s is a well-formed ArubaCloud resource URI.
+func IsResourceURI(s string) bool {
+	_, err := ParseResourceURI(s)
+	return err == nil
+}
+
+// String renders the canonical URI, as returned by the API.
+func (u ResourceURI) String() string {
+	return u.render(false)
+}
+
+// Ref renders the URI with the collection spellings the SDK expects and wraps
+// it as an aruba.Ref.
+func (u ResourceURI) Ref() aruba.Ref {
+	return aruba.URI(u.render(true))
+}
+
+func (u ResourceURI) render(forSDK bool) string {
+	var b strings.Builder
+	b.WriteString("/projects/")
+	b.WriteString(u.ProjectID)
+	if u.Kind == uriKindProject {
+		return b.String()
+	}
+	b.WriteString("/providers/")
+	b.WriteString(u.Namespace)
+	for _, parent := range u.Parents {
+		b.WriteString("/" + uriCollection(parent.Kind, forSDK) + "/" + parent.ID)
+	}
+	b.WriteString("/" + uriCollection(u.Kind, forSDK) + "/" + u.ID)
+	return b.String()
+}
+
+// uriCollection returns the path segment for kind. Unknown kinds are rendered
+// verbatim so that URIs parsed generically round-trip unchanged.
+func uriCollection(kind string, forSDK bool) string {
+	spec, ok := uriKinds[kind]
+	if !ok {
+		return kind
+	}
+	if forSDK && spec.SDKCollection != "" {
+		return spec.SDKCollection
+	}
+	return spec.Collection
+}
+
+// matchURIKind finds the known kind whose namespace and full collection chain
+// match the parsed URI.
+func matchURIKind(namespace string, collections []string) (string, uriKindSpec, bool) {
+	for kind, spec := range uriKinds {
+		if !strings.EqualFold(spec.Namespace, namespace) || len(spec.Parents) != len(collections)-1 {
+			continue
+		}
+		if !collectionMatches(spec, collections[len(collections)-1]) {
+			continue
+		}
+		parentsMatch := true
+		for i, parentKind := range spec.Parents {
+			if !collectionMatches(uriKinds[parentKind], collections[i]) {
+				parentsMatch = false
+				break
+			}
+		}
+		if parentsMatch {
+			return kind, spec, true
+		}
+	}
+	return "", uriKindSpec{}, false
+}
+
+func collectionMatches(spec uriKindSpec, collection string) bool {
+	if strings.EqualFold(spec.Collection, collection) || strings.EqualFold(spec.SDKCollection, collection) {
+		return true
+	}
+	for _, alias := range spec.Aliases {
+		if strings.EqualFold(alias, collection) {
+			return true
+		}
+	}
+	return false
+}
+
+// knownURIKinds returns the supported kind names in a stable order for error messages.
+func knownURIKinds() []string {
+	kinds := []string{uriKindProject}
+	for kind := range uriKinds {
+		kinds = append(kinds, kind)
+	}
+	sort.Strings(kinds[1:])
+	return kinds
+}
+
+func validateURISegment(name, value string) error {
+	if value == "" {
+		return fmt.Errorf("%s must not be empty", name)
+	}
+	if strings.Contains(value, "/") {
+		return fmt.Errorf("%s %q must not contain \"/\"", name, value)
+	}
+	return nil
+}
+
+// uriRef returns a Ref for the URI recorded in state when present, otherwise
+// for the URI built from IDs. Stored URIs are re-rendered through the shared
+// model so API spellings the SDK cannot parse (e.g. "containerRegistries",
+// "elasticIPs") are normalised before reaching it.
+func uriRef(stored types.String, fallback ResourceURI) aruba.Ref {
+	if !stored.IsNull() && !stored.IsUnknown() && stored.ValueString() != "" {
+		if parsed, err := ParseResourceURI(stored.ValueString()); err == nil {
+			return parsed.Ref()
+		}
+		return aruba.URI(stored.ValueString())
+	}
+	return fallback.Ref()
+}
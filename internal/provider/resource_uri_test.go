@@ -0,0 +1,185 @@
+package provider
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestBuildResourceURI(t *testing.T) {
+	cases := []struct {
+		name      string
+		kind      string
+		projectID string
+		id        string
+		parents   []string
+		want      string
+		wantErr   bool
+	}{
+		{name: "project", kind: "project", projectID: "p1", want: "/projects/p1"},
+		{name: "project with matching id", kind: "project", projectID: "p1", id: "p1", want: "/projects/p1"},
+		{name: "vpc", kind: "vpc", projectID: "p1", id: "v1", want: "/projects/p1/providers/Aruba.Network/vpcs/v1"},
+		{name: "cloudserver", kind: "cloudserver", projectID: "p1", id: "s1", want: "/projects/p1/providers/Aruba.Compute/cloudServers/s1"},
+		{name: "subnet", kind: "subnet", projectID: "p1", id: "s1", parents: []string{"v1"}, want: "/projects/p1/providers/Aruba.Network/vpcs/v1/subnets/s1"},
+		{
+			name: "security rule", kind: "securityrule", projectID: "p1", id: "r1", parents: []string{"v1", "sg1"},
+			want: "/projects/p1/providers/Aruba.Network/vpcs/v1/securityGroups/sg1/securityRules/r1",
+		},
+		{
+			name: "database grant", kind: "databasegrant", projectID: "p1", id: "u1", parents: []string{"d1", "db1"},
+			want: "/projects/p1/providers/Aruba.Database/dbaas/d1/databases/db1/grants/u1",
+		},
+		{name: "blockstorage uses API collection", kind: "blockstorage", projectID: "p1", id: "b1", want: "/projects/p1/providers/Aruba.Storage/volumes/b1"},
+		{name: "unknown kind", kind: "nope", projectID: "p1", id: "x", wantErr: true},
+		{name: "missing parent", kind: "subnet", projectID: "p1", id: "s1", wantErr: true},
+		{name: "extra parent", kind: "vpc", projectID: "p1", id: "v1", parents: []string{"x"}, wantErr: true},
+		{name: "empty id", kind: "vpc", projectID: "p1", wantErr: true},
+		{name: "empty project", kind: "vpc", id: "v1", wantErr: true},
+		{name: "slash in id", kind: "vpc", projectID: "p1", id: "a/b", wantErr: true},
+		{name: "project with mismatched id", kind: "project", projectID: "p1", id: "p2", wantErr: true},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := BuildResourceURI(tc.kind, tc.projectID, tc.id, tc.parents...)
+			if tc.wantErr {
+				if err == nil {
+					t.Fatalf("expected error, got %q", got.String())
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got.String() != tc.want {
+				t.Errorf("got %q, want %q", got.String(), tc.want)
+			}
+		})
+	}
+}
+
+func TestParseResourceURI(t *testing.T) {
+	cases := []struct {
+		name    string
+		uri     string
+		want    ResourceURI
+		wantErr bool
+	}{
+		{
+			name: "project",
+			uri:  "/projects/p1",
+			want: ResourceURI{ProjectID: "p1", Kind: "project", ID: "p1"},
+		},
+		{
+			name: "vpc",
+			uri:  "/projects/p1/providers/Aruba.Network/vpcs/v1",
+			want: ResourceURI{ProjectID: "p1", Namespace: "Aruba.Network", Kind: "vpc", ID: "v1"},
+		},
+		{
+			name: "legacy short form",
+			uri:  "/projects/p1/network/vpcs/v1",
+			want: ResourceURI{ProjectID: "p1", Namespace: "Aruba.Network", Kind: "vpc", ID: "v1"},
+		},
+		{
+			name: "case-insensitive collection",
+			uri:  "/projects/p1/providers/Aruba.Network/elasticips/e1",
+			want: ResourceURI{ProjectID: "p1", Namespace: "Aruba.Network", Kind: "elasticip", ID: "e1"},
+		},
+		{
+			name: "SDK spelling alias",
+			uri:  "/projects/p1/providers/Aruba.Container/registries/r1",
+			want: ResourceURI{ProjectID: "p1", Namespace: "Aruba.Container", Kind: "containerregistry", ID: "r1"},
+		},
+		{
+			name: "nested",
+			uri:  "/projects/p1/providers/Aruba.Network/vpcs/v1/subnets/s1",
+			want: ResourceURI{
+				ProjectID: "p1", Namespace: "Aruba.Network", Kind: "subnet", ID: "s1",
+				Parents: []ResourceURISegment{{Kind: "vpc", ID: "v1"}},
+			},
+		},
+		{
+			name: "same collection in different namespace",
+			uri:  "/projects/p1/providers/Aruba.Database/backups/b1",
+			want: ResourceURI{ProjectID: "p1", Namespace: "Aruba.Database", Kind: "databasebackup", ID: "b1"},
+		},
+		{
+			name: "unknown kind is parsed generically",
+			uri:  "/projects/p1/providers/Aruba.Future/widgets/w1/gadgets/g1",
+			want: ResourceURI{
+				ProjectID: "p1", Namespace: "Aruba.Future", Kind: "gadgets", ID: "g1",
+				Parents: []ResourceURISegment{{Kind: "widgets", ID: "w1"}},
+			},
+		},
+		{name: "empty", uri: "", wantErr: true},
+		{name: "not a project URI", uri: "/foo/bar", wantErr: true},
+		{name: "missing namespace", uri: "/projects/p1/vpcs/v1", wantErr: true},
+		{name: "dangling collection", uri: "/projects/p1/providers/Aruba.Network/vpcs", wantErr: true},
+		{name: "empty segment", uri: "/projects/p1/providers/Aruba.Network/vpcs//subnets/s1", wantErr: true},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := ParseResourceURI(tc.uri)
+			if tc.wantErr {
+				if err == nil {
+					t.Fatalf("expected error, got %+v", got)
+				}
+				if IsResourceURI(tc.uri) {
+					t.Errorf("IsResourceURI(%q) = true, want false", tc.uri)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("got %+v, want %+v", got, tc.want)
+			}
+			if !IsResourceURI(tc.uri) {
+				t.Errorf("IsResourceURI(%q) = false, want true", tc.uri)
+			}
+		})
+	}
+}
+
+// TestResourceURI_RoundTrip verifies that every known kind survives
+// build → parse unchanged, so the functions and the *Ref helpers agree.
+func TestResourceURI_RoundTrip(t *testing.T) {
+	for kind, spec := range uriKinds {
+		parents := make([]string, len(spec.Parents))
+		for i := range parents {
+			parents[i] = "parent" + string(rune('a'+i))
+		}
+		built, err := BuildResourceURI(kind, "p1", "id1", parents...)
+		if err != nil {
+			t.Fatalf("%s: build: %v", kind, err)
+		}
+		parsed, err := ParseResourceURI(built.String())
+		if err != nil {
+			t.Fatalf("%s: parse %q: %v", kind, built.String(), err)
+		}
+		if !reflect.DeepEqual(parsed, built) {
+			t.Errorf("%s: round trip mismatch: built %+v, parsed %+v", kind, built, parsed)
+		}
+		// The SDK rendering must parse back to the same resource too.
+		sdkParsed, err := ParseResourceURI(built.render(true))
+		if err != nil || !reflect.DeepEqual(sdkParsed, built) {
+			t.Errorf("%s: SDK rendering %q does not parse back: %+v, %v", kind, built.render(true), sdkParsed, err)
+		}
+	}
+}
+
+func TestResourceURI_SDKRendering(t *testing.T) {
+	cases := []struct {
+		uri  ResourceURI
+		want string
+	}{
+		{newResourceURI(uriKindElasticIP, "p1", "e1"), "/projects/p1/providers/Aruba.Network/elasticIps/e1"},
+		{newResourceURI(uriKindContainerRegistry, "p1", "r1"), "/projects/p1/providers/Aruba.Container/registries/r1"},
+		{newResourceURI(uriKindBlockStorage, "p1", "b1"), "/projects/p1/providers/Aruba.Storage/blockStorages/b1"},
+		{newResourceURI(uriKindVPC, "p1", "v1"), "/projects/p1/providers/Aruba.Network/vpcs/v1"},
+	}
+	for _, tc := range cases {
+		if got := tc.uri.render(true); got != tc.want {
+			t.Errorf("render(true) = %q, want %q", got, tc.want)
+		}
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"sync/atomic"
 	"time"
 
 	aruba "github.com/Arubacloud/sdk-go/pkg/aruba"
@@ -57,6 +58,17 @@ func IsWaitTimeout(err error) bool {
 	return errors.Is(err, context.DeadlineExceeded)
 }
 
+// ctxDoneError returns the error for a wait or retry loop whose ctx is done.
+// CRUD methods bound ctx by the operation's timeout, so a passed deadline
+// returns timeoutErr and is reported like the loop's own timeout; any other
+// cancellation returns the error described by format and args.
+func ctxDoneError(ctx context.Context, timeoutErr error, format string, args ...interface{}) error {
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return timeoutErr
+	}
+	return fmt.Errorf(format, args...)
+}
+
 // IsCreatingState reports whether a resource status string indicates the resource
 // is still being provisioned. Read() uses this to resume WaitForResourceActive
 // after a Create timeout saved partial state.
@@ -102,7 +114,7 @@ type ResourceStateChecker func(ctx context.Context) (string, error)
 // It polls the resource status until it's not in a transitional state.
 func WaitForResourceActive(ctx context.Context, checker ResourceStateChecker, resourceType, resourceID string, timeout time.Duration) error {
 	deadline := time.Now().Add(timeout)
-	ticker := time.NewTicker(waitForActivePollInterval)
+	ticker := time.NewTicker(retryDelay(waitForActivePollInterval))
 	defer ticker.Stop()
 
 	tflog.Info(ctx, fmt.Sprintf("Waiting for %s %s to become active", resourceType, resourceID))
@@ -111,13 +123,26 @@ func WaitForResourceActive(ctx context.Context, checker ResourceStateChecker, re
 	for {
 		select {
 		case <-ctx.Done():
-			return fmt.Errorf("context cancelled while waiting for %s %s", resourceType, resourceID)
+			return ctxDoneError(ctx, &ErrWaitTimeout{ResourceType: resourceType, ResourceID: resourceID, Timeout: timeout, Operation: "become active"},
+				"context cancelled while waiting for %s %s", resourceType, resourceID)
 		case <-ticker.C:
 			if time.Now().After(deadline) {
 				return &ErrWaitTimeout{ResourceType: resourceType, ResourceID: resourceID, Timeout: timeout, Operation: "become active"}
 			}
 
 			state, err := checker(ctx)
+			if ErrorIsThrottled(err) {
+				// Throttling is not a failed check: back off without
+				// counting it towards the consecutive-error limit.
+				if !throttleBackoff(ctx, err, resourceType, resourceID, consecutiveErrors+1, deadline) {
+					timeoutErr := &ErrWaitTimeout{ResourceType: resourceType, ResourceID: resourceID, Timeout: timeout, Operation: "become active"}
+					if ctx.Err() != nil {
+						return ctxDoneError(ctx, timeoutErr, "context cancelled while waiting for %s %s", resourceType, resourceID)
+					}
+					return timeoutErr
+				}
+				continue
+			}
 			if err != nil {
 				consecutiveErrors++
 				tflog.Warn(ctx, fmt.Sprintf("Error checking %s %s status (attempt %d): %v", resourceType, resourceID, consecutiveErrors, err))
@@ -185,6 +210,62 @@ var waitForActivePollInterval = 5 * time.Second
 // Overridable in tests to avoid multi-second waits between retry attempts.
 var deleteRetryBaseWait = 5 * time.Second
 
+// createRetryBaseWait is the base per-attempt delay in CreateWithTransientRetry.
+// Overridable in tests to avoid multi-second waits between retry attempts.
+var createRetryBaseWait = 5 * time.Second
+
+// defaultThrottleWait is the back-off applied to an HTTP 429 response that
+// carries no usable Retry-After header. Overridable in tests.
+var defaultThrottleWait = 5 * time.Second
+
+// replayDelay, when set by a provider configured with WithReplayWaits,
+// replaces the delay of every wait loop and retry in the process.
+var replayDelay atomic.Int64
+
+// retryDelay returns d, the delay before the next attempt of a wait loop or
+// retry, or the replay delay when one is set.
+func retryDelay(d time.Duration) time.Duration {
+	if replay := time.Duration(replayDelay.Load()); replay > 0 {
+		return replay
+	}
+	return d
+}
+
+// throttleBackoff waits out a throttled (HTTP 429) error before the next
+// attempt, honouring the API's Retry-After delay or defaultThrottleWait when
+// there is none. Each back-off is logged. It returns false without waiting
+// when the delay would run past deadline, and false when ctx is cancelled,
+// so a throttled operation still ends within its timeout.
+func throttleBackoff(ctx context.Context, err error, resourceType, resourceID string, attempt int, deadline time.Time) bool {
+	wait := retryAfter(err)
+	if wait <= 0 {
+		wait = defaultThrottleWait
+	}
+	wait = retryDelay(wait)
+	remaining := time.Until(deadline)
+	fields := map[string]interface{}{
+		"resource_type": resourceType,
+		"resource_id":   resourceID,
+		"attempt":       attempt,
+		"retry_after":   wait.String(),
+		"remaining":     remaining.String(),
+	}
+	if wait > remaining {
+		tflog.Warn(ctx, "API throttled the request and the Retry-After delay exceeds the remaining timeout", fields)
+		return false
+	}
+	tflog.Warn(ctx, "API throttled the request, backing off", fields)
+
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return false
+	case <-timer.C:
+		return true
+	}
+}
+
 // WaitForResourceDeleted polls until the resource is confirmed deleted (checker returns true)
 // or the timeout elapses. Up to 3 consecutive checker errors are tolerated before giving up,
 // mirroring the behaviour of WaitForResourceActive.
@@ -198,9 +279,20 @@ func WaitForResourceDeleted(ctx context.Context, checker ResourceDeletedChecker,
 		"timeout":       timeout.String(),
 	})
 
+	deadline := time.Now().Add(timeout)
 	consecutiveErrors := 0
 	checkDeletion := func() (bool, error) {
 		deleted, err := checker(ctx)
+		if ErrorIsThrottled(err) {
+			if !throttleBackoff(ctx, err, resourceType, resourceID, consecutiveErrors+1, deadline) {
+				timeoutErr := &ErrWaitTimeout{ResourceType: resourceType, ResourceID: resourceID, Timeout: timeout, Operation: "be deleted"}
+				if ctx.Err() != nil {
+					return false, ctxDoneError(ctx, timeoutErr, "context cancelled while waiting for %s %s deletion", resourceType, resourceID)
+				}
+				return false, timeoutErr
+			}
+			return false, nil
+		}
 		if err != nil {
 			consecutiveErrors++
 			tflog.Warn(ctx, "deletion check error", map[string]interface{}{
@@ -236,7 +328,7 @@ func WaitForResourceDeleted(ctx context.Context, checker ResourceDeletedChecker,
 		return nil
 	}
 
-	ticker := time.NewTicker(waitForDeletedPollInterval)
+	ticker := time.NewTicker(retryDelay(waitForDeletedPollInterval))
 	defer ticker.Stop()
 
 	timeoutTimer := time.NewTimer(timeout)
@@ -245,7 +337,8 @@ func WaitForResourceDeleted(ctx context.Context, checker ResourceDeletedChecker,
 	for {
 		select {
 		case <-ctx.Done():
-			return fmt.Errorf("context cancelled while waiting for %s %s deletion", resourceType, resourceID)
+			return ctxDoneError(ctx, &ErrWaitTimeout{ResourceType: resourceType, ResourceID: resourceID, Timeout: timeout, Operation: "be deleted"},
+				"context cancelled while waiting for %s %s deletion", resourceType, resourceID)
 		case <-timeoutTimer.C:
 			return &ErrWaitTimeout{ResourceType: resourceType, ResourceID: resourceID, Timeout: timeout, Operation: "be deleted"}
 		case <-ticker.C:
@@ -277,6 +370,9 @@ func CreateWithTransientRetry(
 	deadline := time.Now().Add(timeout)
 	maxRetryInterval := 30 * time.Second
 	attempt := 0
+	timeoutErr := func() error {
+		return fmt.Errorf("timeout waiting to create %s %s (timeout: %v, attempts: %d)", resourceType, resourceID, timeout, attempt)
+	}
 
 	tflog.Info(ctx, "creating resource (with transient retry)", map[string]interface{}{
 		"resource_type": resourceType,
@@ -288,7 +384,7 @@ func CreateWithTransientRetry(
 		attempt++
 		select {
 		case <-ctx.Done():
-			return fmt.Errorf("context cancelled while creating %s %s", resourceType, resourceID)
+			return ctxDoneError(ctx, timeoutErr(), "context cancelled while creating %s %s", resourceType, resourceID)
 		default:
 		}
 
@@ -296,6 +392,18 @@ func CreateWithTransientRetry(
 		if err == nil {
 			return nil
 		}
+		// A throttled POST was not processed by the API; retry it once the
+		// Retry-After delay has passed.
+		if ErrorIsThrottled(err) {
+			if !throttleBackoff(ctx, err, resourceType, resourceID, attempt, deadline) {
+				if ctx.Err() != nil {
+					return ctxDoneError(ctx, timeoutErr(), "context cancelled while waiting to retry create %s %s", resourceType, resourceID)
+				}
+				return fmt.Errorf("timeout waiting to create %s %s: API is throttling requests (timeout: %v): %w",
+					resourceType, resourceID, timeout, err)
+			}
+			continue
+		}
 		// Retry transient 4xx (dependency not ready) and pure transport failures
 		// (EOF / connection reset — StatusCode == 0 means the server never processed
 		// the request so retrying the POST is safe).
@@ -308,7 +416,7 @@ func CreateWithTransientRetry(
 				resourceType, resourceID, attempt, timeout, err)
 		}
 
-		waitTime := time.Duration(5*attempt) * time.Second
+		waitTime := createRetryBaseWait * time.Duration(attempt)
 		if waitTime > maxRetryInterval {
 			waitTime = maxRetryInterval
 		}
@@ -322,16 +430,16 @@ func CreateWithTransientRetry(
 
 		select {
 		case <-ctx.Done():
-			return fmt.Errorf("context cancelled while waiting to retry create %s %s", resourceType, resourceID)
-		case <-time.After(waitTime):
+			return ctxDoneError(ctx, timeoutErr(), "context cancelled while waiting to retry create %s %s", resourceType, resourceID)
+		case <-time.After(retryDelay(waitTime)):
 		}
 	}
 }
 
 // minRemainingTimeout is the floor applied by remainingTimeout so that
-// WaitForResourceDeleted always has enough budget to run at least the
-// initial immediate check, even when DeleteResourceWithRetry has exhausted
-// nearly all of the ResourceTimeout budget through retries.
+// WaitForResourceDeleted is not handed a near-zero timeout when
+// DeleteResourceWithRetry has used up most of the budget through retries.
+// The deadline of the Delete context still ends the wait on time.
 const minRemainingTimeout = 10 * time.Second
 
 // remainingTimeout returns how much of the original timeout budget is left
@@ -365,6 +473,9 @@ func DeleteResourceWithRetry(
 	deadline := time.Now().Add(timeout)
 	maxRetryInterval := 30 * time.Second
 	attempt := 0
+	timeoutErr := func() error {
+		return fmt.Errorf("timeout waiting to delete %s %s (timeout: %v, attempts: %d)", resourceType, resourceID, timeout, attempt)
+	}
 
 	tflog.Info(ctx, fmt.Sprintf("Attempting to delete %s %s", resourceType, resourceID))
 
@@ -372,10 +483,10 @@ func DeleteResourceWithRetry(
 		attempt++
 		select {
 		case <-ctx.Done():
-			return fmt.Errorf("context cancelled while deleting %s %s", resourceType, resourceID)
+			return ctxDoneError(ctx, timeoutErr(), "context cancelled while deleting %s %s", resourceType, resourceID)
 		default:
 			if time.Now().After(deadline) {
-				return fmt.Errorf("timeout waiting to delete %s %s (timeout: %v, attempts: %d)", resourceType, resourceID, timeout, attempt)
+				return timeoutErr()
 			}
 
 			err := deleteFunc()
@@ -387,6 +498,16 @@ func DeleteResourceWithRetry(
 				tflog.Info(ctx, fmt.Sprintf("%s %s already deleted (404)", resourceType, resourceID))
 				return nil
 			}
+			if ErrorIsThrottled(err) {
+				if !throttleBackoff(ctx, err, resourceType, resourceID, attempt, deadline) {
+					if ctx.Err() != nil {
+						return ctxDoneError(ctx, timeoutErr(), "context cancelled while waiting to delete %s %s", resourceType, resourceID)
+					}
+					return fmt.Errorf("timeout waiting to delete %s %s: API is throttling requests (timeout: %v, attempts: %d): %w",
+						resourceType, resourceID, timeout, attempt, err)
+				}
+				continue
+			}
 			// Semantic errors are usually permanent (field validation failures such as
 			// "cannot delete default VPC"). Exception: the API also returns a semantic
 			// 400 with fieldName="Status" when the resource is in a transitional state
@@ -425,8 +546,8 @@ func DeleteResourceWithRetry(
 			}
 			select {
 			case <-ctx.Done():
-				return fmt.Errorf("context cancelled while waiting to delete %s %s", resourceType, resourceID)
-			case <-time.After(waitTime):
+				return ctxDoneError(ctx, timeoutErr(), "context cancelled while waiting to delete %s %s", resourceType, resourceID)
+			case <-time.After(retryDelay(waitTime)):
 			}
 		}
 	}
@@ -246,6 +246,25 @@ func TestWaitForResourceActive_ContextCancelledReturnsError(t *testing.T) {
 	}
 }
 
+// TestWaitForResourceActive_ContextDeadlineReturnsErrWaitTimeout checks that
+// the deadline CRUD methods set from the operation timeout is reported as a
+// wait timeout, not as a cancellation.
+func TestWaitForResourceActive_ContextDeadlineReturnsErrWaitTimeout(t *testing.T) {
+	withFastActivePoll(t)
+
+	checker := func(ctx context.Context) (string, error) {
+		return "InCreation", nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	err := WaitForResourceActive(ctx, checker, "kaas", "abc", time.Hour)
+	if !IsWaitTimeout(err) {
+		t.Fatalf("expected ErrWaitTimeout, got %T: %v", err, err)
+	}
+}
+
 func TestWaitForResourceActive_GivesUpAfterThreeConsecutiveErrors(t *testing.T) {
 	withFastActivePoll(t)
 
@@ -393,6 +412,21 @@ func TestDeleteResourceWithRetry_ContextCancelledReturnsError(t *testing.T) {
 	}
 }
 
+func TestDeleteResourceWithRetry_ContextDeadlineReturnsTimeout(t *testing.T) {
+	withFastDeleteRetry(t)
+
+	boom := newResponseError("delete", "kaas", 500, "", "", "", false)
+	deleteFunc := func() error { return boom }
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	err := DeleteResourceWithRetry(ctx, deleteFunc, "kaas", "abc", time.Hour)
+	if err == nil || !strings.Contains(err.Error(), "timeout waiting to delete") {
+		t.Fatalf("expected a timeout error, got %v", err)
+	}
+}
+
 func TestDeleteResourceWithRetry_ExistsCheckerShortCircuitsAfterFailedDelete(t *testing.T) {
 	withFastDeleteRetry(t)
 
@@ -729,3 +763,124 @@ func TestCreateWithTransientRetry_TransientErrorTimesOut(t *testing.T) {
 		t.Fatal("expected createFunc to be called at least once")
 	}
 }
+
+// throttledErr builds a 429 *ProviderError carrying the given Retry-After delay.
+func throttledErr(operation string, retry time.Duration) error {
+	provErr := newResponseError(operation, "vpc", 429, "Too Many Requests", "", "", false)
+	provErr.RetryAfter = retry
+	return provErr
+}
+
+func TestCreateWithTransientRetry_ThrottledHonoursRetryAfter(t *testing.T) {
+	var calls int32
+	createFunc := func() error {
+		if atomic.AddInt32(&calls, 1) == 1 {
+			return throttledErr("create", 30*time.Millisecond)
+		}
+		return nil
+	}
+
+	start := time.Now()
+	if err := CreateWithTransientRetry(context.Background(), createFunc, "vpc", "abc", time.Minute); err != nil {
+		t.Fatalf("expected nil after throttling, got %v", err)
+	}
+	if n := atomic.LoadInt32(&calls); n != 2 {
+		t.Fatalf("expected 2 calls, got %d", n)
+	}
+	if elapsed := time.Since(start); elapsed < 30*time.Millisecond {
+		t.Errorf("retried after %s, want at least the 30ms Retry-After", elapsed)
+	}
+}
+
+func TestCreateWithTransientRetry_ThrottledBeyondTimeoutFailsFast(t *testing.T) {
+	var calls int32
+	createFunc := func() error {
+		atomic.AddInt32(&calls, 1)
+		return throttledErr("create", time.Hour)
+	}
+
+	start := time.Now()
+	err := CreateWithTransientRetry(context.Background(), createFunc, "vpc", "abc", time.Second)
+	if err == nil {
+		t.Fatal("expected an error when Retry-After exceeds the timeout")
+	}
+	if !ErrorIsThrottled(err) {
+		t.Errorf("expected the throttled error to be wrapped, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Errorf("took %s, want an immediate failure instead of waiting", elapsed)
+	}
+	if n := atomic.LoadInt32(&calls); n != 1 {
+		t.Fatalf("expected 1 call, got %d", n)
+	}
+}
+
+func TestDeleteResourceWithRetry_ThrottledHonoursRetryAfter(t *testing.T) {
+	var calls int32
+	deleteFunc := func() error {
+		if atomic.AddInt32(&calls, 1) == 1 {
+			return throttledErr("delete", 20*time.Millisecond)
+		}
+		return nil
+	}
+
+	if err := DeleteResourceWithRetry(context.Background(), deleteFunc, "vpc", "abc", time.Minute); err != nil {
+		t.Fatalf("expected nil after throttling, got %v", err)
+	}
+	if n := atomic.LoadInt32(&calls); n != 2 {
+		t.Fatalf("expected 2 calls, got %d", n)
+	}
+}
+
+func TestWaitForResourceActive_ThrottlingIsNotAConsecutiveError(t *testing.T) {
+	withFastActivePoll(t)
+
+	// Five throttled checks in a row would exceed the 3-error limit if they
+	// counted as failures.
+	var calls int32
+	checker := func(ctx context.Context) (string, error) {
+		if atomic.AddInt32(&calls, 1) <= 5 {
+			return "", throttledErr("get", time.Millisecond)
+		}
+		return "Active", nil
+	}
+
+	if err := WaitForResourceActive(context.Background(), checker, "vpc", "abc", 5*time.Second); err != nil {
+		t.Fatalf("expected nil, got %v", err)
+	}
+	if got := atomic.LoadInt32(&calls); got != 6 {
+		t.Fatalf("expected 6 checker calls, got %d", got)
+	}
+}
+
+func TestWaitForResourceActive_ThrottledBeyondTimeoutReturnsErrWaitTimeout(t *testing.T) {
+	withFastActivePoll(t)
+
+	checker := func(ctx context.Context) (string, error) {
+		return "", throttledErr("get", time.Hour)
+	}
+
+	err := WaitForResourceActive(context.Background(), checker, "vpc", "abc", time.Second)
+	if !IsWaitTimeout(err) {
+		t.Fatalf("expected a wait timeout, got %v", err)
+	}
+}
+
+func TestWaitForResourceDeleted_ThrottledHonoursRetryAfter(t *testing.T) {
+	withFastPoll(t)
+
+	var calls int32
+	checker := func(ctx context.Context) (bool, error) {
+		if atomic.AddInt32(&calls, 1) <= 4 {
+			return false, throttledErr("get", time.Millisecond)
+		}
+		return true, nil
+	}
+
+	if err := WaitForResourceDeleted(context.Background(), checker, "vpc", "abc", 5*time.Second); err != nil {
+		t.Fatalf("expected nil, got %v", err)
+	}
+	if got := atomic.LoadInt32(&calls); got != 5 {
+		t.Fatalf("expected 5 checker calls, got %d", got)
+	}
+}
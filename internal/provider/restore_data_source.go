@@ -4,7 +4,6 @@ import (
 	"context"
 	"fmt"
 
-	aruba "github.com/Arubacloud/sdk-go/pkg/aruba"
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
 	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/types"
@@ -93,11 +92,15 @@ func (d *RestoreDataSource) Configure(ctx context.Context, req datasource.Config
 }
 
 func (d *RestoreDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	ctx = withCorrelationID(ctx)
 	var data RestoreDataSourceModel
 	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
+	if !d.client.checkGuardrails("read", "Restore", data.ProjectId, &resp.Diagnostics) {
+		return
+	}
 
 	projectID := data.ProjectId.ValueString()
 	backupID := data.BackupId.ValueString()
@@ -108,9 +111,9 @@ func (d *RestoreDataSource) Read(ctx context.Context, req datasource.ReadRequest
 	}
 
 	restore, err := d.client.Client.FromStorage().Restores().Get(ctx,
-		aruba.URI("/projects/"+projectID+"/providers/Aruba.Storage/backups/"+backupID+"/restores/"+restoreID))
+		newResourceURI(uriKindRestore, projectID, restoreID, backupID).Ref())
 	if provErr := CheckResponseErr("read", "Restore", err); provErr != nil {
-		resp.Diagnostics.AddError("API Error", provErr.Error())
+		AppendAPIError(&resp.Diagnostics, provErr)
 		return
 	}
 
@@ -6,6 +6,7 @@ import (
 	"time"
 
 	aruba "github.com/Arubacloud/sdk-go/pkg/aruba"
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
@@ -16,15 +17,17 @@ import (
 )
 
 type RestoreResourceModel struct {
-	Id        types.String `tfsdk:"id"`
-	Uri       types.String `tfsdk:"uri"`
-	Name      types.String `tfsdk:"name"`
-	Location  types.String `tfsdk:"location"`
-	Tags      types.List   `tfsdk:"tags"`
-	ProjectID types.String `tfsdk:"project_id"`
-	BackupID  types.String `tfsdk:"backup_id"`
-	VolumeID  types.String `tfsdk:"volume_id"`
-	Timeout   types.String `tfsdk:"timeout"`
+	Id        types.String   `tfsdk:"id"`
+	Uri       types.String   `tfsdk:"uri"`
+	Name      types.String   `tfsdk:"name"`
+	Location  types.String   `tfsdk:"location"`
+	Tags      types.List     `tfsdk:"tags"`
+	TagsAll   types.List     `tfsdk:"tags_all"`
+	ProjectID types.String   `tfsdk:"project_id"`
+	BackupID  types.String   `tfsdk:"backup_id"`
+	VolumeID  types.String   `tfsdk:"volume_id"`
+	Timeout   types.String   `tfsdk:"timeout"`
+	Timeouts  timeouts.Value `tfsdk:"timeouts"`
 }
 
 type RestoreResource struct {
@@ -32,6 +35,7 @@ type RestoreResource struct {
 }
 
 var _ resource.Resource = &RestoreResource{}
+var _ resource.ResourceWithModifyPlan = &RestoreResource{}
 var _ resource.ResourceWithImportState = &RestoreResource{}
 
 func NewRestoreResource() resource.Resource {
@@ -65,9 +69,11 @@ func (r *RestoreResource) Schema(ctx context.Context, req resource.SchemaRequest
 				Required:            true,
 			},
 			"location": schema.StringAttribute{
-				MarkdownDescription: "Region identifier (e.g., `ITBG-Bergamo`). See the [available locations and zones](https://api.arubacloud.com/docs/metadata/#location-and-data-center). (Immutable — changing this value forces the resource to be destroyed and re-created.)",
-				Required:            true,
+				MarkdownDescription: defaultedDescription("Region identifier (e.g., `ITBG-Bergamo`). See the [available locations and zones](https://api.arubacloud.com/docs/metadata/#location-and-data-center). (Immutable — changing this value forces the resource to be destroyed and re-created.)", "location"),
+				Optional:            true,
+				Computed:            true,
 				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
 					stringplanmodifier.RequiresReplace(),
 				},
 			},
@@ -76,10 +82,13 @@ func (r *RestoreResource) Schema(ctx context.Context, req resource.SchemaRequest
 				MarkdownDescription: "List of string tags attached to the resource for filtering and organisation.",
 				Optional:            true,
 			},
+			"tags_all": tagsAllAttribute(),
 			"project_id": schema.StringAttribute{
-				MarkdownDescription: "ID of the project that owns this resource. (Immutable — changing this value forces the resource to be destroyed and re-created.)",
-				Required:            true,
+				MarkdownDescription: defaultedDescription("ID of the project that owns this resource. (Immutable — changing this value forces the resource to be destroyed and re-created.)", "project_id"),
+				Optional:            true,
+				Computed:            true,
 				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
 					stringplanmodifier.RequiresReplace(),
 				},
 			},
@@ -98,10 +107,14 @@ func (r *RestoreResource) Schema(ctx context.Context, req resource.SchemaRequest
 				},
 			},
 			"timeout": schema.StringAttribute{
-				MarkdownDescription: "Per-resource timeout override (e.g. `\"15m\"`, `\"1h\"`). Overrides the provider-level `resource_timeout` for this resource's Create and Delete operations. Uses Go duration syntax.",
+				MarkdownDescription: "Per-resource timeout override (e.g. `\"15m\"`, `\"1h\"`) applied to every operation not set in the `timeouts` block. Overrides the provider-level `resource_timeout`. Uses Go duration syntax. Deprecated — use the `timeouts` block instead.",
+				DeprecationMessage:  "Use the timeouts block, which sets the create, read, update and delete timeouts individually.",
 				Optional:            true,
 			},
 		},
+		Blocks: map[string]schema.Block{
+			"timeouts": timeouts.BlockAll(ctx),
+		},
 	}
 }
 
@@ -121,33 +134,43 @@ func (r *RestoreResource) Configure(ctx context.Context, req resource.ConfigureR
 }
 
 func restoreRef(data *RestoreResourceModel) aruba.Ref {
-	if !data.Uri.IsNull() && data.Uri.ValueString() != "" {
-		return aruba.URI(data.Uri.ValueString())
-	}
-	return aruba.URI("/projects/" + data.ProjectID.ValueString() +
-		"/providers/Aruba.Storage/backups/" + data.BackupID.ValueString() +
-		"/restores/" + data.Id.ValueString())
+	return uriRef(data.Uri, newResourceURI(uriKindRestore, data.ProjectID.ValueString(), data.Id.ValueString(), data.BackupID.ValueString()))
+}
+
+func (r *RestoreResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	r.client.planTagsAll(ctx, req, resp, true)
+	r.client.planProviderDefaults(ctx, req, resp,
+		providerDefault{attr: "project_id", replace: true},
+		providerDefault{attr: "location", replace: true},
+	)
 }
 
 func (r *RestoreResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	ctx = withCorrelationID(ctx)
 	var data RestoreResourceModel
 	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	timeout := r.client.operationTimeout(ctx, data.Timeouts.Create, data.Timeout, &resp.Diagnostics)
 	if resp.Diagnostics.HasError() {
 		return
 	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	if !r.client.checkGuardrails("create", "Restore", data.ProjectID, &resp.Diagnostics) {
+		return
+	}
 
 	projectID := data.ProjectID.ValueString()
 	backupID := data.BackupID.ValueString()
 	volumeID := data.VolumeID.ValueString()
 
-	tags := ListToTags(ctx, data.Tags, &resp.Diagnostics)
+	tags := r.client.requestTags(ctx, data.Tags, &data.TagsAll, &resp.Diagnostics)
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
 	// Get volume URI from the volume ID.
 	vol, err := r.client.Client.FromStorage().Volumes().Get(ctx,
-		aruba.URI("/projects/"+projectID+"/providers/Aruba.Storage/blockStorages/"+volumeID))
+		newResourceURI(uriKindBlockStorage, projectID, volumeID).Ref())
 	if provErr := CheckResponseErr("read", "Volume", err); provErr != nil {
 		resp.Diagnostics.AddError("Error getting volume details", provErr.Error())
 		return
@@ -157,7 +180,7 @@ func (r *RestoreResource) Create(ctx context.Context, req resource.CreateRequest
 		return
 	}
 
-	backupRef := aruba.URI("/projects/" + projectID + "/providers/Aruba.Storage/backups/" + backupID)
+	backupRef := newResourceURI(uriKindBackup, projectID, backupID).Ref()
 	restore, err := r.client.Client.FromStorage().Restores().Create(ctx,
 		aruba.NewStorageRestore().
 			Named(data.Name.ValueString()).
@@ -167,7 +190,7 @@ func (r *RestoreResource) Create(ctx context.Context, req resource.CreateRequest
 			Tagged(tags...),
 	)
 	if provErr := CheckResponseErr("create", "Restore", err); provErr != nil {
-		resp.Diagnostics.AddError("API Error", provErr.Error())
+		AppendAPIError(&resp.Diagnostics, provErr)
 		return
 	}
 
@@ -179,7 +202,7 @@ func (r *RestoreResource) Create(ctx context.Context, req resource.CreateRequest
 		return
 	}
 
-	if waitErr := restore.WaitUntilReady(ctx, sdkWaitOptions(effectiveTimeout(data.Timeout, r.client.ResourceTimeout))...); waitErr != nil {
+	if waitErr := restore.WaitUntilReady(ctx, sdkWaitOptions(timeout)...); waitErr != nil {
 		ReportWaitResult(&resp.Diagnostics, waitErr, "Restore", data.Id.ValueString())
 		return
 	}
@@ -200,11 +223,18 @@ func (r *RestoreResource) Create(ctx context.Context, req resource.CreateRequest
 }
 
 func (r *RestoreResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	ctx = withCorrelationID(ctx)
 	var data RestoreResourceModel
 	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	timeout := r.client.operationTimeout(ctx, data.Timeouts.Read, data.Timeout, &resp.Diagnostics)
 	if resp.Diagnostics.HasError() {
 		return
 	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	if !r.client.checkGuardrails("read", "Restore", data.ProjectID, &resp.Diagnostics) {
+		return
+	}
 	if data.Id.IsNull() || data.Id.ValueString() == "" {
 		resp.State.RemoveResource(ctx)
 		return
@@ -216,7 +246,7 @@ func (r *RestoreResource) Read(ctx context.Context, req resource.ReadRequest, re
 			resp.State.RemoveResource(ctx)
 			return
 		}
-		resp.Diagnostics.AddError("API Error", provErr.Error())
+		AppendAPIError(&resp.Diagnostics, provErr)
 		return
 	}
 
@@ -227,13 +257,13 @@ func (r *RestoreResource) Read(ctx context.Context, req resource.ReadRequest, re
 			fmt.Sprintf("Restore %q is in a terminal failure state (%s). "+
 				"Run `terraform destroy` to clean it up, or `terraform apply -replace=<address>` to recreate it.", data.Id.ValueString(), st))
 	case IsCreatingState(st):
-		if waitErr := restore.WaitUntilReady(ctx, sdkWaitOptions(r.client.ResourceTimeout)...); waitErr != nil {
+		if waitErr := restore.WaitUntilReady(ctx, sdkWaitOptions(timeout)...); waitErr != nil {
 			ReportWaitResult(&resp.Diagnostics, waitErr, "Restore", data.Id.ValueString())
 			return
 		}
 		restore, err = r.client.Client.FromStorage().Restores().Get(ctx, restoreRef(&data))
 		if provErr := CheckResponseErr("read", "Restore", err); provErr != nil {
-			resp.Diagnostics.AddError("API Error", provErr.Error())
+			AppendAPIError(&resp.Diagnostics, provErr)
 			return
 		}
 	}
@@ -249,30 +279,37 @@ func applyRestoreToState(restore *aruba.StorageRestore, data *RestoreResourceMod
 	data.Id = types.StringValue(restore.ID())
 	data.Uri = strVal(restore.URI())
 	data.Name = types.StringValue(restore.Name())
-	data.Tags = TagsToListPreserveNull(restore.Tags(), data.Tags)
+	data.Tags, data.TagsAll = flattenTags(restore.Tags(), data.Tags, data.TagsAll)
 	if restore.Region() != "" {
 		data.Location = types.StringValue(string(restore.Region()))
 	}
 }
 
 func (r *RestoreResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	ctx = withCorrelationID(ctx)
 	var data RestoreResourceModel
 	var state RestoreResourceModel
 
 	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
 	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	timeout := r.client.operationTimeout(ctx, data.Timeouts.Update, data.Timeout, &resp.Diagnostics)
 	if resp.Diagnostics.HasError() {
 		return
 	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	if !r.client.checkGuardrails("update", "Restore", data.ProjectID, &resp.Diagnostics) {
+		return
+	}
 
-	tags := ListToTags(ctx, data.Tags, &resp.Diagnostics)
+	tags := r.client.requestTags(ctx, data.Tags, &data.TagsAll, &resp.Diagnostics)
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
 	restore, err := r.client.Client.FromStorage().Restores().Get(ctx, restoreRef(&state))
 	if provErr := CheckResponseErr("read", "Restore", err); provErr != nil {
-		resp.Diagnostics.AddError("API Error", provErr.Error())
+		AppendAPIError(&resp.Diagnostics, provErr)
 		return
 	}
 
@@ -285,7 +322,7 @@ func (r *RestoreResource) Update(ctx context.Context, req resource.UpdateRequest
 
 	updated, err := r.client.Client.FromStorage().Restores().Update(ctx, restore)
 	if provErr := CheckResponseErr("update", "Restore", err); provErr != nil {
-		resp.Diagnostics.AddError("API Error", provErr.Error())
+		AppendAPIError(&resp.Diagnostics, provErr)
 		return
 	}
 
@@ -296,17 +333,24 @@ func (r *RestoreResource) Update(ctx context.Context, req resource.UpdateRequest
 	data.Uri = state.Uri
 	data.Location = state.Location
 	data.Name = types.StringValue(updated.Name())
-	data.Tags = TagsToListPreserveNull(updated.Tags(), data.Tags)
+	data.Tags, data.TagsAll = flattenTags(updated.Tags(), data.Tags, data.TagsAll)
 
 	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
 }
 
 func (r *RestoreResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	ctx = withCorrelationID(ctx)
 	var data RestoreResourceModel
 	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	timeout := r.client.operationTimeout(ctx, data.Timeouts.Delete, data.Timeout, &resp.Diagnostics)
 	if resp.Diagnostics.HasError() {
 		return
 	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	if !r.client.checkGuardrails("delete", "Restore", data.ProjectID, &resp.Diagnostics) {
+		return
+	}
 
 	ref := restoreRef(&data)
 	restoreID := data.Id.ValueString()
@@ -326,12 +370,12 @@ func (r *RestoreResource) Delete(ctx context.Context, req resource.DeleteRequest
 	err := DeleteResourceWithRetry(ctx, func() error {
 		return CheckResponseErrAsError("delete", "Restore",
 			r.client.Client.FromStorage().Restores().Delete(ctx, ref))
-	}, "Restore", restoreID, effectiveTimeout(data.Timeout, r.client.ResourceTimeout), deletionChecker)
+	}, "Restore", restoreID, timeout, deletionChecker)
 	if err != nil {
 		resp.Diagnostics.AddError("Error deleting Restore", err.Error())
 		return
 	}
-	if waitErr := WaitForResourceDeleted(ctx, deletionChecker, "Restore", restoreID, remainingTimeout(deleteStart, effectiveTimeout(data.Timeout, r.client.ResourceTimeout))); waitErr != nil {
+	if waitErr := WaitForResourceDeleted(ctx, deletionChecker, "Restore", restoreID, remainingTimeout(deleteStart, timeout)); waitErr != nil {
 		resp.Diagnostics.AddError("Error waiting for Restore deletion", waitErr.Error())
 		return
 	}
@@ -0,0 +1,142 @@
+package provider
+
+import (
+	"fmt"
+	"math/rand"
+	"net/http"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// ProviderRetryModel describes the provider-level retry block.
+type ProviderRetryModel struct {
+	MaxAttempts types.Int64  `tfsdk:"max_attempts"`
+	BaseDelay   types.String `tfsdk:"base_delay"`
+	MaxDelay    types.String `tfsdk:"max_delay"`
+	Jitter      types.Bool   `tfsdk:"jitter"`
+}
+
+// retryPolicy controls how apiTransport retries reads and updates that fail
+// with a technical error (HTTP 5xx), a transport failure (no response) or a
+// 429 without a Retry-After header.
+// Create and delete keep their own retry loops (CreateWithTransientRetry and
+// DeleteResourceWithRetry), so POST and DELETE are never retried here.
+type retryPolicy struct {
+	// maxAttempts is the total number of attempts, including the first; 1
+	// disables retries.
+	maxAttempts int
+	baseDelay   time.Duration
+	maxDelay    time.Duration
+	// jitter randomises each delay between half and all of its nominal value
+	// so that parallel operations do not retry in lockstep.
+	jitter bool
+}
+
+// defaultRetryPolicy applies when the provider configuration has no retry block.
+var defaultRetryPolicy = retryPolicy{
+	maxAttempts: 3,
+	baseDelay:   1 * time.Second,
+	maxDelay:    30 * time.Second,
+	jitter:      true,
+}
+
+// newRetryPolicy builds the retry policy from the provider's retry block,
+// filling unset attributes from defaultRetryPolicy. Invalid durations are
+// reported as attribute errors.
+func newRetryPolicy(model *ProviderRetryModel, diags *diag.Diagnostics) retryPolicy {
+	policy := defaultRetryPolicy
+	if model == nil {
+		return policy
+	}
+	if !model.MaxAttempts.IsNull() && !model.MaxAttempts.IsUnknown() {
+		policy.maxAttempts = int(model.MaxAttempts.ValueInt64())
+	}
+	if !model.Jitter.IsNull() && !model.Jitter.IsUnknown() {
+		policy.jitter = model.Jitter.ValueBool()
+	}
+	policy.baseDelay = parseRetryDelay(model.BaseDelay, "base_delay", policy.baseDelay, diags)
+	policy.maxDelay = parseRetryDelay(model.MaxDelay, "max_delay", policy.maxDelay, diags)
+	if policy.maxDelay < policy.baseDelay {
+		diags.AddAttributeError(
+			path.Root("retry").AtName("max_delay"),
+			"Invalid retry max_delay",
+			fmt.Sprintf("max_delay (%s) must not be shorter than base_delay (%s).", policy.maxDelay, policy.baseDelay),
+		)
+	}
+	return policy
+}
+
+// parseRetryDelay parses one duration attribute of the retry block.
+func parseRetryDelay(value types.String, attr string, defaultDelay time.Duration, diags *diag.Diagnostics) time.Duration {
+	if value.IsNull() || value.IsUnknown() || value.ValueString() == "" {
+		return defaultDelay
+	}
+	d, err := time.ParseDuration(value.ValueString())
+	if err != nil || d < 0 {
+		diags.AddAttributeError(
+			path.Root("retry").AtName(attr),
+			"Invalid retry "+attr,
+			fmt.Sprintf("Could not parse %q as a non-negative duration (e.g. \"500ms\", \"2s\").", value.ValueString()),
+		)
+		return defaultDelay
+	}
+	return d
+}
+
+// delay returns the back-off before the attempt following attempt: baseDelay
+// doubled per attempt, capped at maxDelay, and jittered when enabled.
+func (p retryPolicy) delay(attempt int) time.Duration {
+	d := p.baseDelay
+	for i := 1; i < attempt && d < p.maxDelay; i++ {
+		d *= 2
+	}
+	if d > p.maxDelay {
+		d = p.maxDelay
+	}
+	if p.jitter && d > 1 {
+		d = d/2 + time.Duration(rand.Int63n(int64(d/2)+1))
+	}
+	return d
+}
+
+// isRetryableMethod reports whether apiTransport retries requests with
+// method: reads (GET) and updates (PUT, PATCH).
+func isRetryableMethod(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodPut, http.MethodPatch:
+		return true
+	}
+	return false
+}
+
+// shouldRetry reports whether a request that ended with resp or err may be
+// sent again after attempt attempts. Only reads and updates are retried (see
+// isRetryableMethod), and only when the failure classifies as
+// ErrorIsTechnical or ErrorIsTransportFailure — the same classification
+// CheckResponseErr would give the SDK error — or is a 429. apiTransport
+// handles a 429 with a Retry-After header itself, so the policy's back-off
+// only applies to one without.
+func (p retryPolicy) shouldRetry(req *http.Request, resp *http.Response, err error, attempt int) bool {
+	if attempt >= p.maxAttempts || req.Context().Err() != nil {
+		return false
+	}
+	if !isRetryableMethod(req.Method) {
+		return false
+	}
+
+	var provErr *ProviderError
+	switch {
+	case err != nil:
+		provErr = NewTransportError(req.Method, req.URL.Path, err)
+	case resp.StatusCode == http.StatusTooManyRequests:
+		return true
+	case resp.StatusCode >= 500:
+		provErr = newResponseError(req.Method, req.URL.Path, resp.StatusCode, "", "", "", false)
+	default:
+		return false
+	}
+	return ErrorIsTechnical(provErr) || ErrorIsTransportFailure(provErr)
+}
@@ -0,0 +1,268 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// fastRetry retries quickly so transport tests do not sleep for seconds.
+var fastRetry = retryPolicy{maxAttempts: 3, baseDelay: time.Millisecond, maxDelay: 5 * time.Millisecond}
+
+func TestNewRetryPolicy(t *testing.T) {
+	var diags diag.Diagnostics
+	if got := newRetryPolicy(nil, &diags); got != defaultRetryPolicy || diags.HasError() {
+		t.Errorf("newRetryPolicy(nil) = %+v (%v), want defaults", got, diags)
+	}
+
+	got := newRetryPolicy(&ProviderRetryModel{
+		MaxAttempts: types.Int64Value(5),
+		BaseDelay:   types.StringValue("200ms"),
+		MaxDelay:    types.StringNull(),
+		Jitter:      types.BoolValue(false),
+	}, &diags)
+	want := retryPolicy{maxAttempts: 5, baseDelay: 200 * time.Millisecond, maxDelay: 30 * time.Second}
+	if got != want || diags.HasError() {
+		t.Errorf("newRetryPolicy() = %+v (%v), want %+v", got, diags, want)
+	}
+}
+
+func TestNewRetryPolicy_Invalid(t *testing.T) {
+	cases := map[string]ProviderRetryModel{
+		"bad base_delay":      {BaseDelay: types.StringValue("soon")},
+		"negative max_delay":  {MaxDelay: types.StringValue("-1s")},
+		"max below base":      {BaseDelay: types.StringValue("10s"), MaxDelay: types.StringValue("1s")},
+		"max below default 1": {MaxDelay: types.StringValue("100ms")},
+	}
+	for name, model := range cases {
+		t.Run(name, func(t *testing.T) {
+			var diags diag.Diagnostics
+			newRetryPolicy(&model, &diags)
+			if !diags.HasError() {
+				t.Error("expected an attribute error")
+			}
+		})
+	}
+}
+
+func TestRetryPolicy_Delay(t *testing.T) {
+	p := retryPolicy{maxAttempts: 10, baseDelay: time.Second, maxDelay: 5 * time.Second}
+	want := []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 5 * time.Second, 5 * time.Second}
+	for i, w := range want {
+		if got := p.delay(i + 1); got != w {
+			t.Errorf("delay(%d) = %s, want %s", i+1, got, w)
+		}
+	}
+
+	p.jitter = true
+	for attempt := 1; attempt <= 5; attempt++ {
+		nominal := want[attempt-1]
+		for i := 0; i < 20; i++ {
+			if got := p.delay(attempt); got < nominal/2 || got > nominal {
+				t.Fatalf("jittered delay(%d) = %s, want within [%s, %s]", attempt, got, nominal/2, nominal)
+			}
+		}
+	}
+}
+
+func TestRetryPolicy_ShouldRetry(t *testing.T) {
+	statusResp := func(code int) *http.Response { return &http.Response{StatusCode: code} }
+	eof := io.ErrUnexpectedEOF
+
+	cases := []struct {
+		name    string
+		method  string
+		resp    *http.Response
+		err     error
+		attempt int
+		want    bool
+	}{
+		{"GET 502", http.MethodGet, statusResp(502), nil, 1, true},
+		{"GET 500", http.MethodGet, statusResp(500), nil, 2, true},
+		{"GET transport failure", http.MethodGet, nil, eof, 1, true},
+		{"PUT 503", http.MethodPut, statusResp(503), nil, 1, true},
+		{"PATCH 504", http.MethodPatch, statusResp(504), nil, 1, true},
+		{"GET 429", http.MethodGet, statusResp(429), nil, 1, true},
+		{"POST 429", http.MethodPost, statusResp(429), nil, 1, false},
+		{"GET 404", http.MethodGet, statusResp(404), nil, 1, false},
+		{"GET 400", http.MethodGet, statusResp(400), nil, 1, false},
+		{"GET 200", http.MethodGet, statusResp(200), nil, 1, false},
+		{"POST 502", http.MethodPost, statusResp(502), nil, 1, false},
+		{"DELETE 502", http.MethodDelete, statusResp(502), nil, 1, false},
+		{"GET 502 attempts exhausted", http.MethodGet, statusResp(502), nil, 3, false},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			req, _ := http.NewRequest(tc.method, "http://example.com/projects", nil)
+			if got := fastRetry.shouldRetry(req, tc.resp, tc.err, tc.attempt); got != tc.want {
+				t.Errorf("shouldRetry() = %v, want %v", got, tc.want)
+			}
+		})
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, "http://example.com/projects", nil)
+	if fastRetry.shouldRetry(req, nil, context.Canceled, 1) {
+		t.Error("a cancelled request must not be retried")
+	}
+}
+
+func TestAPITransport_RetriesTechnicalErrors(t *testing.T) {
+	cases := []struct {
+		name      string
+		method    string
+		failures  int32
+		status    int
+		wantCalls int32
+		wantCode  int
+	}{
+		{"GET recovers", http.MethodGet, 2, http.StatusBadGateway, 3, http.StatusOK},
+		{"PUT recovers", http.MethodPut, 1, http.StatusServiceUnavailable, 2, http.StatusOK},
+		{"GET gives up", http.MethodGet, 10, http.StatusBadGateway, 3, http.StatusBadGateway},
+		{"POST not retried", http.MethodPost, 1, http.StatusBadGateway, 1, http.StatusBadGateway},
+		{"GET 404 not retried", http.MethodGet, 1, http.StatusNotFound, 1, http.StatusNotFound},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			var calls int32
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				body, _ := io.ReadAll(r.Body)
+				if r.Method != http.MethodGet && string(body) != `{"name":"x"}` {
+					t.Errorf("request body = %q, want it replayed intact", body)
+				}
+				if atomic.AddInt32(&calls, 1) <= tc.failures {
+					apiError(w, tc.status)
+					return
+				}
+				w.WriteHeader(http.StatusOK)
+			}))
+			t.Cleanup(srv.Close)
+
+			var body io.Reader
+			if tc.method != http.MethodGet {
+				body = strings.NewReader(`{"name":"x"}`)
+			}
+			req, _ := http.NewRequest(tc.method, srv.URL, body)
+			resp, err := newAPIHTTPClient(nil, fastRetry).Do(req)
+			if err != nil {
+				t.Fatalf("request error: %v", err)
+			}
+			resp.Body.Close()
+			if resp.StatusCode != tc.wantCode {
+				t.Errorf("status = %d, want %d", resp.StatusCode, tc.wantCode)
+			}
+			if got := atomic.LoadInt32(&calls); got != tc.wantCalls {
+				t.Errorf("server calls = %d, want %d", got, tc.wantCalls)
+			}
+		})
+	}
+}
+
+func TestAPITransport_RetriesTransportFailures(t *testing.T) {
+	var calls int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) == 1 {
+			// Drop the connection without a response.
+			conn, _, err := w.(http.Hijacker).Hijack()
+			if err == nil {
+				conn.Close()
+			}
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	t.Cleanup(srv.Close)
+
+	resp, err := newAPIHTTPClient(nil, fastRetry).Get(srv.URL)
+	if err != nil {
+		t.Fatalf("GET error after retry: %v", err)
+	}
+	resp.Body.Close()
+	if got := atomic.LoadInt32(&calls); got != 2 {
+		t.Errorf("server calls = %d, want 2", got)
+	}
+}
+
+func TestAPITransport_RetryHonoursContext(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		apiError(w, http.StatusBadGateway)
+	}))
+	t.Cleanup(srv.Close)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	slow := retryPolicy{maxAttempts: 5, baseDelay: time.Hour, maxDelay: time.Hour}
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, srv.URL, nil)
+
+	start := time.Now()
+	resp, err := newAPIHTTPClient(nil, slow).Do(req)
+	if err == nil {
+		// The back-off does not fit before the deadline, so the 502 is
+		// returned right away.
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusBadGateway {
+			t.Errorf("status = %d, want 502", resp.StatusCode)
+		}
+	} else if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("error = %v, want a deadline error", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("took %s, want the retry to stop at the context deadline", elapsed)
+	}
+}
+
+// TestReadRetriesTechnicalErrors checks that a resource Read and a data
+// source Read both survive a transient 502 through the shared transport.
+func TestReadRetriesTechnicalErrors(t *testing.T) {
+	ctx := context.Background()
+
+	flaky := func() http.HandlerFunc {
+		var calls int32
+		return func(w http.ResponseWriter, r *http.Request) {
+			if atomic.AddInt32(&calls, 1) == 1 {
+				apiError(w, http.StatusBadGateway)
+				return
+			}
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(minimalActiveJSON)) //nolint:errcheck
+		}
+	}
+
+	t.Run("resource", func(t *testing.T) {
+		_, mockClient := newMockArubaClientTransport(t, flaky(), nil, fastRetry)
+		res := NewVPCResource()
+		configureResource(ctx, t, res, mockClient)
+		req, resp := resourceReadReq(ctx, t, res)
+		res.Read(ctx, req, resp)
+		if resp.Diagnostics.HasError() {
+			t.Errorf("Read() reported error after a retried 502: %v", resp.Diagnostics)
+		}
+	})
+
+	t.Run("data source", func(t *testing.T) {
+		_, mockClient := newMockArubaClientTransport(t, flaky(), nil, fastRetry)
+		ds := NewVPCDataSource()
+		configureDatasource(ctx, t, ds, mockClient)
+		schemaResp := &datasource.SchemaResponse{}
+		ds.Schema(ctx, datasource.SchemaRequest{}, schemaResp)
+		req := dsReadReq(ctx, t, ds, nil)
+		resp := &datasource.ReadResponse{State: tfsdk.State{Schema: schemaResp.Schema}}
+		ds.Read(ctx, req, resp)
+		if resp.Diagnostics.HasError() {
+			t.Errorf("Read() reported error after a retried 502: %v", resp.Diagnostics)
+		}
+	})
+}
@@ -4,7 +4,6 @@ import (
 	"context"
 	"fmt"
 
-	aruba "github.com/Arubacloud/sdk-go/pkg/aruba"
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
 	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/types"
@@ -82,11 +81,15 @@ func (d *ScheduleJobDataSource) Configure(ctx context.Context, req datasource.Co
 }
 
 func (d *ScheduleJobDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	ctx = withCorrelationID(ctx)
 	var data ScheduleJobDataSourceModel
 	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
+	if !d.client.checkGuardrails("read", "ScheduleJob", data.ProjectID, &resp.Diagnostics) {
+		return
+	}
 
 	projectID := data.ProjectID.ValueString()
 	jobID := data.Id.ValueString()
@@ -95,10 +98,10 @@ func (d *ScheduleJobDataSource) Read(ctx context.Context, req datasource.ReadReq
 		return
 	}
 
-	ref := aruba.URI("/projects/" + projectID + "/providers/Aruba.Schedule/jobs/" + jobID)
+	ref := newResourceURI(uriKindScheduleJob, projectID, jobID).Ref()
 	job, err := d.client.Client.FromSchedule().Jobs().Get(ctx, ref)
 	if provErr := CheckResponseErr("read", "ScheduleJob", err); provErr != nil {
-		resp.Diagnostics.AddError("API Error", provErr.Error())
+		AppendAPIError(&resp.Diagnostics, provErr)
 		return
 	}
 
@@ -6,6 +6,7 @@ import (
 	"time"
 
 	aruba "github.com/Arubacloud/sdk-go/pkg/aruba"
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
 	"github.com/hashicorp/terraform-plugin-framework/attr"
 	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/path"
@@ -27,14 +28,16 @@ func normalizeRFC3339(s string) string {
 }
 
 type ScheduleJobResourceModel struct {
-	Id         types.String `tfsdk:"id"`
-	Uri        types.String `tfsdk:"uri"`
-	Name       types.String `tfsdk:"name"`
-	ProjectID  types.String `tfsdk:"project_id"`
-	Tags       types.List   `tfsdk:"tags"`
-	Location   types.String `tfsdk:"location"`
-	Properties types.Object `tfsdk:"properties"`
-	Timeout    types.String `tfsdk:"timeout"`
+	Id         types.String   `tfsdk:"id"`
+	Uri        types.String   `tfsdk:"uri"`
+	Name       types.String   `tfsdk:"name"`
+	ProjectID  types.String   `tfsdk:"project_id"`
+	Tags       types.List     `tfsdk:"tags"`
+	TagsAll    types.List     `tfsdk:"tags_all"`
+	Location   types.String   `tfsdk:"location"`
+	Properties types.Object   `tfsdk:"properties"`
+	Timeout    types.String   `tfsdk:"timeout"`
+	Timeouts   timeouts.Value `tfsdk:"timeouts"`
 }
 
 type ScheduleJobResource struct {
@@ -42,6 +45,7 @@ type ScheduleJobResource struct {
 }
 
 var _ resource.Resource = &ScheduleJobResource{}
+var _ resource.ResourceWithModifyPlan = &ScheduleJobResource{}
 var _ resource.ResourceWithImportState = &ScheduleJobResource{}
 
 func NewScheduleJobResource() resource.Resource {
@@ -75,20 +79,26 @@ func (r *ScheduleJobResource) Schema(ctx context.Context, req resource.SchemaReq
 				Required:            true,
 			},
 			"project_id": schema.StringAttribute{
-				MarkdownDescription: "ID of the project that owns this resource.",
-				Required:            true,
+				MarkdownDescription: defaultedDescription("ID of the project that owns this resource.", "project_id"),
+				Optional:            true,
+				Computed:            true,
+				PlanModifiers:       []planmodifier.String{stringplanmodifier.UseStateForUnknown()},
 			},
 			"tags": schema.ListAttribute{
 				ElementType:         types.StringType,
 				MarkdownDescription: "List of string tags attached to the resource for filtering and organisation.",
 				Optional:            true,
 			},
+			"tags_all": tagsAllAttribute(),
 			"location": schema.StringAttribute{
-				MarkdownDescription: "Region identifier (e.g., `ITBG-Bergamo`). See the [available locations and zones](https://api.arubacloud.com/docs/metadata/#location-and-data-center).",
-				Required:            true,
+				MarkdownDescription: defaultedDescription("Region identifier (e.g., `ITBG-Bergamo`). See the [available locations and zones](https://api.arubacloud.com/docs/metadata/#location-and-data-center).", "location"),
+				Optional:            true,
+				Computed:            true,
+				PlanModifiers:       []planmodifier.String{stringplanmodifier.UseStateForUnknown()},
 			},
 			"timeout": schema.StringAttribute{
-				MarkdownDescription: "Per-resource timeout override (e.g. `\"15m\"`, `\"1h\"`). Overrides the provider-level `resource_timeout` for this resource's Create and Delete operations. Uses Go duration syntax.",
+				MarkdownDescription: "Per-resource timeout override (e.g. `\"15m\"`, `\"1h\"`) applied to every operation not set in the `timeouts` block. Overrides the provider-level `resource_timeout`. Uses Go duration syntax. Deprecated — use the `timeouts` block instead.",
+				DeprecationMessage:  "Use the timeouts block, which sets the create, read, update and delete timeouts individually.",
 				Optional:            true,
 			},
 			"properties": schema.SingleNestedAttribute{
@@ -146,6 +156,9 @@ func (r *ScheduleJobResource) Schema(ctx context.Context, req resource.SchemaReq
 				Required: true,
 			},
 		},
+		Blocks: map[string]schema.Block{
+			"timeouts": timeouts.BlockAll(ctx),
+		},
 	}
 }
 
@@ -165,11 +178,7 @@ func (r *ScheduleJobResource) Configure(ctx context.Context, req resource.Config
 }
 
 func jobRef(data *ScheduleJobResourceModel) aruba.Ref {
-	if !data.Uri.IsNull() && data.Uri.ValueString() != "" {
-		return aruba.URI(data.Uri.ValueString())
-	}
-	return aruba.URI("/projects/" + data.ProjectID.ValueString() +
-		"/providers/Aruba.Schedule/jobs/" + data.Id.ValueString())
+	return uriRef(data.Uri, newResourceURI(uriKindScheduleJob, data.ProjectID.ValueString(), data.Id.ValueString()))
 }
 
 // stepObjectAttrTypes returns the attr.Type map for a step object.
@@ -260,7 +269,7 @@ func applyJobToModel(job *aruba.Job, data *ScheduleJobResourceModel, diagnostics
 	if r := string(job.Region()); r != "" {
 		data.Location = types.StringValue(r)
 	}
-	data.Tags = TagsToListPreserveNull(job.Tags(), data.Tags)
+	data.Tags, data.TagsAll = flattenTags(job.Tags(), data.Tags, data.TagsAll)
 
 	raw := job.Raw()
 	if raw == nil {
@@ -336,12 +345,27 @@ func applyJobToModel(job *aruba.Job, data *ScheduleJobResourceModel, diagnostics
 	}
 }
 
+func (r *ScheduleJobResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	r.client.planTagsAll(ctx, req, resp, true)
+	r.client.planProviderDefaults(ctx, req, resp,
+		providerDefault{attr: "project_id", replace: false},
+		providerDefault{attr: "location", replace: false},
+	)
+}
+
 func (r *ScheduleJobResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	ctx = withCorrelationID(ctx)
 	var data ScheduleJobResourceModel
 	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	timeout := r.client.operationTimeout(ctx, data.Timeouts.Create, data.Timeout, &resp.Diagnostics)
 	if resp.Diagnostics.HasError() {
 		return
 	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	if !r.client.checkGuardrails("create", "ScheduleJob", data.ProjectID, &resp.Diagnostics) {
+		return
+	}
 
 	projectID := data.ProjectID.ValueString()
 	if projectID == "" {
@@ -349,7 +373,7 @@ func (r *ScheduleJobResource) Create(ctx context.Context, req resource.CreateReq
 		return
 	}
 
-	tags := ListToTags(ctx, data.Tags, &resp.Diagnostics)
+	tags := r.client.requestTags(ctx, data.Tags, &data.TagsAll, &resp.Diagnostics)
 	if resp.Diagnostics.HasError() {
 		return
 	}
@@ -401,7 +425,7 @@ func (r *ScheduleJobResource) Create(ctx context.Context, req resource.CreateReq
 
 	builder := aruba.NewJob().
 		Named(data.Name.ValueString()).
-		InProject(aruba.URI("/projects/" + projectID)).
+		InProject(newResourceURI(uriKindProject, projectID, "").Ref()).
 		InRegion(aruba.Region(data.Location.ValueString())).
 		Tagged(tags...).
 		WithSteps(steps...)
@@ -444,7 +468,7 @@ func (r *ScheduleJobResource) Create(ctx context.Context, req resource.CreateReq
 
 	job, err := r.client.Client.FromSchedule().Jobs().Create(ctx, builder)
 	if provErr := CheckResponseErr("create", "ScheduleJob", err); provErr != nil {
-		resp.Diagnostics.AddError("API Error", provErr.Error())
+		AppendAPIError(&resp.Diagnostics, provErr)
 		return
 	}
 
@@ -460,7 +484,7 @@ func (r *ScheduleJobResource) Create(ctx context.Context, req resource.CreateReq
 		return
 	}
 
-	if waitErr := job.WaitUntilReady(ctx, sdkWaitOptions(effectiveTimeout(data.Timeout, r.client.ResourceTimeout))...); waitErr != nil {
+	if waitErr := job.WaitUntilReady(ctx, sdkWaitOptions(timeout)...); waitErr != nil {
 		ReportWaitResult(&resp.Diagnostics, waitErr, "ScheduleJob", data.Id.ValueString())
 		resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
 		return
@@ -479,11 +503,18 @@ func (r *ScheduleJobResource) Create(ctx context.Context, req resource.CreateReq
 }
 
 func (r *ScheduleJobResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	ctx = withCorrelationID(ctx)
 	var data ScheduleJobResourceModel
 	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	timeout := r.client.operationTimeout(ctx, data.Timeouts.Read, data.Timeout, &resp.Diagnostics)
 	if resp.Diagnostics.HasError() {
 		return
 	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	if !r.client.checkGuardrails("read", "ScheduleJob", data.ProjectID, &resp.Diagnostics) {
+		return
+	}
 
 	if data.Id.IsUnknown() || data.Id.IsNull() || data.Id.ValueString() == "" {
 		resp.State.RemoveResource(ctx)
@@ -496,7 +527,7 @@ func (r *ScheduleJobResource) Read(ctx context.Context, req resource.ReadRequest
 			resp.State.RemoveResource(ctx)
 			return
 		}
-		resp.Diagnostics.AddError("API Error", provErr.Error())
+		AppendAPIError(&resp.Diagnostics, provErr)
 		return
 	}
 
@@ -513,13 +544,13 @@ func (r *ScheduleJobResource) Read(ctx context.Context, req resource.ReadRequest
 			fmt.Sprintf("ScheduleJob %q is in a terminal failure state (%s). "+
 				"Run `terraform destroy` to clean it up, or `terraform apply -replace=<address>` to recreate it.", data.Id.ValueString(), st))
 	case IsCreatingState(st):
-		if waitErr := job.WaitUntilReady(ctx, sdkWaitOptions(r.client.ResourceTimeout)...); waitErr != nil {
+		if waitErr := job.WaitUntilReady(ctx, sdkWaitOptions(timeout)...); waitErr != nil {
 			ReportWaitResult(&resp.Diagnostics, waitErr, "ScheduleJob", data.Id.ValueString())
 			return
 		}
 		job, err = r.client.Client.FromSchedule().Jobs().Get(ctx, jobRef(&data))
 		if provErr := CheckResponseErr("read", "ScheduleJob", err); provErr != nil {
-			resp.Diagnostics.AddError("API Error", provErr.Error())
+			AppendAPIError(&resp.Diagnostics, provErr)
 			return
 		}
 	}
@@ -534,23 +565,30 @@ func (r *ScheduleJobResource) Read(ctx context.Context, req resource.ReadRequest
 }
 
 func (r *ScheduleJobResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	ctx = withCorrelationID(ctx)
 	var data ScheduleJobResourceModel
 	var state ScheduleJobResourceModel
 
 	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
 	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	timeout := r.client.operationTimeout(ctx, data.Timeouts.Update, data.Timeout, &resp.Diagnostics)
 	if resp.Diagnostics.HasError() {
 		return
 	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	if !r.client.checkGuardrails("update", "ScheduleJob", data.ProjectID, &resp.Diagnostics) {
+		return
+	}
 
-	tags := ListToTags(ctx, data.Tags, &resp.Diagnostics)
+	tags := r.client.requestTags(ctx, data.Tags, &data.TagsAll, &resp.Diagnostics)
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
 	job, err := r.client.Client.FromSchedule().Jobs().Get(ctx, jobRef(&state))
 	if provErr := CheckResponseErr("read", "ScheduleJob", err); provErr != nil {
-		resp.Diagnostics.AddError("API Error", provErr.Error())
+		AppendAPIError(&resp.Diagnostics, provErr)
 		return
 	}
 
@@ -574,7 +612,7 @@ func (r *ScheduleJobResource) Update(ctx context.Context, req resource.UpdateReq
 
 	updated, err := r.client.Client.FromSchedule().Jobs().Update(ctx, job)
 	if provErr := CheckResponseErr("update", "ScheduleJob", err); provErr != nil {
-		resp.Diagnostics.AddError("API Error", provErr.Error())
+		AppendAPIError(&resp.Diagnostics, provErr)
 		return
 	}
 
@@ -591,11 +629,18 @@ func (r *ScheduleJobResource) Update(ctx context.Context, req resource.UpdateReq
 }
 
 func (r *ScheduleJobResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	ctx = withCorrelationID(ctx)
 	var data ScheduleJobResourceModel
 	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	timeout := r.client.operationTimeout(ctx, data.Timeouts.Delete, data.Timeout, &resp.Diagnostics)
 	if resp.Diagnostics.HasError() {
 		return
 	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	if !r.client.checkGuardrails("delete", "ScheduleJob", data.ProjectID, &resp.Diagnostics) {
+		return
+	}
 
 	if data.Id.IsUnknown() || data.Id.IsNull() || data.Id.ValueString() == "" {
 		return
@@ -640,13 +685,13 @@ func (r *ScheduleJobResource) Delete(ctx context.Context, req resource.DeleteReq
 	err := DeleteResourceWithRetry(ctx, func() error {
 		return CheckResponseErrAsError("delete", "ScheduleJob",
 			r.client.Client.FromSchedule().Jobs().Delete(ctx, ref))
-	}, "ScheduleJob", jobID, effectiveTimeout(data.Timeout, r.client.ResourceTimeout), deletionChecker)
+	}, "ScheduleJob", jobID, timeout, deletionChecker)
 	if err != nil {
 		resp.Diagnostics.AddError("Error deleting schedule job", err.Error())
 		return
 	}
 
-	if waitErr := WaitForResourceDeleted(ctx, deletionChecker, "ScheduleJob", jobID, remainingTimeout(deleteStart, effectiveTimeout(data.Timeout, r.client.ResourceTimeout))); waitErr != nil {
+	if waitErr := WaitForResourceDeleted(ctx, deletionChecker, "ScheduleJob", jobID, remainingTimeout(deleteStart, timeout)); waitErr != nil {
 		if IsWaitTimeout(waitErr) {
 			resp.Diagnostics.AddWarning(
 				"ScheduleJob Deletion Pending",
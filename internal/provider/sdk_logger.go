@@ -65,12 +65,41 @@ func ParseLogLevel(s string) (LogLevel, error) {
 	}
 }
 
+// logRedactor redacts the SDK log messages, with the same fields as the
+// default http_trace_redact list.
+var logRedactor = newRedactor(defaultRedactFields)
+
+// maskedLogFieldKeys are the tflog field keys whose values are masked.
+func maskedLogFieldKeys() []string {
+	keys := append(append([]string{}, defaultRedactFields...), credentialFields...)
+	return append(keys, "authorization", "token")
+}
+
+// maskSensitiveLogFields registers tflog masking of the sensitive field keys
+// on ctx for the provider's own logs. withCorrelationID applies it to the
+// context of every resource and data source operation.
+func maskSensitiveLogFields(ctx context.Context) context.Context {
+	return tflog.MaskFieldValuesWithFieldKeys(ctx, maskedLogFieldKeys()...)
+}
+
+// maskSensitiveSDKLogFields is maskSensitiveLogFields for the context the SDK
+// logs with, registering the masking on the arubacloud-sdk subsystem too.
+// Configure applies it: the SDK logger has no per-request context, so SDK
+// messages are always logged with the Configure context and never carry the
+// masking or the request_id of an operation.
+func maskSensitiveSDKLogFields(ctx context.Context) context.Context {
+	ctx = maskSensitiveLogFields(ctx)
+	return tflog.SubsystemMaskFieldValuesWithFieldKeys(ctx, subsystemName, maskedLogFieldKeys()...)
+}
+
 // sdkLogAdapter bridges the ArubaCloud SDK logger interface to the tflog subsystem
 // "arubacloud-sdk". It satisfies the SDK's internal logger.Logger interface via
 // Go structural typing — no import of the internal SDK package is needed.
 //
 // Messages are only forwarded when the configured level permits them, giving the
-// user a second filter on top of TF_LOG / TF_LOG_PROVIDER.
+// user a second filter on top of TF_LOG / TF_LOG_PROVIDER. The SDK logs request
+// and response bodies, URLs and headers as formatted text, so every message is
+// redacted by logRedactor before it is forwarded.
 type sdkLogAdapter struct {
 	ctx   context.Context
 	level LogLevel
@@ -82,24 +111,24 @@ func newSDKLogAdapter(ctx context.Context, level LogLevel) *sdkLogAdapter {
 
 func (a *sdkLogAdapter) Debugf(format string, args ...interface{}) {
 	if a.level >= LogLevelDebug {
-		tflog.SubsystemDebug(a.ctx, subsystemName, fmt.Sprintf(format, args...))
+		tflog.SubsystemDebug(a.ctx, subsystemName, logRedactor.text(fmt.Sprintf(format, args...)))
 	}
 }
 
 func (a *sdkLogAdapter) Infof(format string, args ...interface{}) {
 	if a.level >= LogLevelInfo {
-		tflog.SubsystemInfo(a.ctx, subsystemName, fmt.Sprintf(format, args...))
+		tflog.SubsystemInfo(a.ctx, subsystemName, logRedactor.text(fmt.Sprintf(format, args...)))
 	}
 }
 
 func (a *sdkLogAdapter) Warnf(format string, args ...interface{}) {
 	if a.level >= LogLevelWarn {
-		tflog.SubsystemWarn(a.ctx, subsystemName, fmt.Sprintf(format, args...))
+		tflog.SubsystemWarn(a.ctx, subsystemName, logRedactor.text(fmt.Sprintf(format, args...)))
 	}
 }
 
 func (a *sdkLogAdapter) Errorf(format string, args ...interface{}) {
 	if a.level >= LogLevelError {
-		tflog.SubsystemError(a.ctx, subsystemName, fmt.Sprintf(format, args...))
+		tflog.SubsystemError(a.ctx, subsystemName, logRedactor.text(fmt.Sprintf(format, args...)))
 	}
 }
@@ -88,11 +88,15 @@ func (d *SecurityGroupDataSource) Configure(ctx context.Context, req datasource.
 }
 
 func (d *SecurityGroupDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	ctx = withCorrelationID(ctx)
 	var data SecurityGroupDataSourceModel
 	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
+	if !d.client.checkGuardrails("read", "SecurityGroup", data.ProjectId, &resp.Diagnostics) {
+		return
+	}
 
 	projectID := data.ProjectId.ValueString()
 	vpcID := data.VpcId.ValueString()
@@ -105,7 +109,7 @@ func (d *SecurityGroupDataSource) Read(ctx context.Context, req datasource.ReadR
 	sg, err := d.client.Client.FromNetwork().SecurityGroups().Get(ctx,
 		aruba.SecurityGroupRef(projectID, vpcID, sgID))
 	if provErr := CheckResponseErr("read", "SecurityGroup", err); provErr != nil {
-		resp.Diagnostics.AddError("API Error", provErr.Error())
+		AppendAPIError(&resp.Diagnostics, provErr)
 		return
 	}
 
@@ -6,6 +6,7 @@ import (
 	"time"
 
 	aruba "github.com/Arubacloud/sdk-go/pkg/aruba"
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
@@ -16,6 +17,7 @@ import (
 )
 
 var _ resource.Resource = &SecurityGroupResource{}
+var _ resource.ResourceWithModifyPlan = &SecurityGroupResource{}
 var _ resource.ResourceWithImportState = &SecurityGroupResource{}
 
 func NewSecurityGroupResource() resource.Resource {
@@ -27,14 +29,16 @@ type SecurityGroupResource struct {
 }
 
 type SecurityGroupResourceModel struct {
-	Id        types.String `tfsdk:"id"`
-	Uri       types.String `tfsdk:"uri"`
-	Name      types.String `tfsdk:"name"`
-	Location  types.String `tfsdk:"location"`
-	Tags      types.List   `tfsdk:"tags"`
-	ProjectId types.String `tfsdk:"project_id"`
-	VpcId     types.String `tfsdk:"vpc_id"`
-	Timeout   types.String `tfsdk:"timeout"`
+	Id        types.String   `tfsdk:"id"`
+	Uri       types.String   `tfsdk:"uri"`
+	Name      types.String   `tfsdk:"name"`
+	Location  types.String   `tfsdk:"location"`
+	Tags      types.List     `tfsdk:"tags"`
+	TagsAll   types.List     `tfsdk:"tags_all"`
+	ProjectId types.String   `tfsdk:"project_id"`
+	VpcId     types.String   `tfsdk:"vpc_id"`
+	Timeout   types.String   `tfsdk:"timeout"`
+	Timeouts  timeouts.Value `tfsdk:"timeouts"`
 }
 
 func (r *SecurityGroupResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
@@ -64,9 +68,11 @@ func (r *SecurityGroupResource) Schema(ctx context.Context, req resource.SchemaR
 				Required:            true,
 			},
 			"location": schema.StringAttribute{
-				MarkdownDescription: "Region identifier for the resource (e.g., `ITBG-Bergamo`). See the [available locations and zones](https://api.arubacloud.com/docs/metadata/#location-and-data-center). (Immutable — changing this value forces the resource to be destroyed and re-created.)",
-				Required:            true,
+				MarkdownDescription: defaultedDescription("Region identifier for the resource (e.g., `ITBG-Bergamo`). See the [available locations and zones](https://api.arubacloud.com/docs/metadata/#location-and-data-center). (Immutable — changing this value forces the resource to be destroyed and re-created.)", "location"),
+				Optional:            true,
+				Computed:            true,
 				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
 					stringplanmodifier.RequiresReplace(),
 				},
 			},
@@ -75,10 +81,13 @@ func (r *SecurityGroupResource) Schema(ctx context.Context, req resource.SchemaR
 				MarkdownDescription: "List of string tags attached to the resource for filtering and organisation.",
 				Optional:            true,
 			},
+			"tags_all": tagsAllAttribute(),
 			"project_id": schema.StringAttribute{
-				MarkdownDescription: "ID of the project that owns this resource. (Immutable — changing this value forces the resource to be destroyed and re-created.)",
-				Required:            true,
+				MarkdownDescription: defaultedDescription("ID of the project that owns this resource. (Immutable — changing this value forces the resource to be destroyed and re-created.)", "project_id"),
+				Optional:            true,
+				Computed:            true,
 				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
 					stringplanmodifier.RequiresReplace(),
 				},
 			},
@@ -90,10 +99,14 @@ func (r *SecurityGroupResource) Schema(ctx context.Context, req resource.SchemaR
 				},
 			},
 			"timeout": schema.StringAttribute{
-				MarkdownDescription: "Per-resource timeout override (e.g. `\"15m\"`, `\"1h\"`). Overrides the provider-level `resource_timeout` for this resource's Create and Delete operations. Uses Go duration syntax.",
+				MarkdownDescription: "Per-resource timeout override (e.g. `\"15m\"`, `\"1h\"`) applied to every operation not set in the `timeouts` block. Overrides the provider-level `resource_timeout`. Uses Go duration syntax. Deprecated — use the `timeouts` block instead.",
+				DeprecationMessage:  "Use the timeouts block, which sets the create, read, update and delete timeouts individually.",
 				Optional:            true,
 			},
 		},
+		Blocks: map[string]schema.Block{
+			"timeouts": timeouts.BlockAll(ctx),
+		},
 	}
 }
 
@@ -113,39 +126,51 @@ func (r *SecurityGroupResource) Configure(ctx context.Context, req resource.Conf
 }
 
 func sgRef(data *SecurityGroupResourceModel) aruba.Ref {
-	if !data.Uri.IsNull() && data.Uri.ValueString() != "" {
-		return aruba.URI(data.Uri.ValueString())
-	}
-	return aruba.SecurityGroupRef(data.ProjectId.ValueString(), data.VpcId.ValueString(), data.Id.ValueString())
+	return uriRef(data.Uri, newResourceURI(uriKindSecurityGroup, data.ProjectId.ValueString(), data.Id.ValueString(), data.VpcId.ValueString()))
 }
 
 func applySecurityGroupToModel(sg *aruba.SecurityGroup, data *SecurityGroupResourceModel) {
 	data.Id = types.StringValue(sg.ID())
 	data.Uri = strVal(sg.URI())
 	data.Name = types.StringValue(sg.Name())
-	data.Tags = TagsToListPreserveNull(sg.Tags(), data.Tags)
+	data.Tags, data.TagsAll = flattenTags(sg.Tags(), data.Tags, data.TagsAll)
 	raw := sg.Raw()
 	if raw != nil && raw.Metadata.LocationResponse != nil {
 		data.Location = types.StringValue(string(raw.Metadata.LocationResponse.Value))
 	}
 }
 
+func (r *SecurityGroupResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	r.client.planTagsAll(ctx, req, resp, true)
+	r.client.planProviderDefaults(ctx, req, resp,
+		providerDefault{attr: "project_id", replace: true},
+		providerDefault{attr: "location", replace: true},
+	)
+}
+
 func (r *SecurityGroupResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	ctx = withCorrelationID(ctx)
 	var data SecurityGroupResourceModel
 	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	timeout := r.client.operationTimeout(ctx, data.Timeouts.Create, data.Timeout, &resp.Diagnostics)
 	if resp.Diagnostics.HasError() {
 		return
 	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	if !r.client.checkGuardrails("create", "SecurityGroup", data.ProjectId, &resp.Diagnostics) {
+		return
+	}
 
 	projectID := data.ProjectId.ValueString()
 	vpcID := data.VpcId.ValueString()
 
-	tags := ListToTags(ctx, data.Tags, &resp.Diagnostics)
+	tags := r.client.requestTags(ctx, data.Tags, &data.TagsAll, &resp.Diagnostics)
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
-	vpcURI := aruba.URI("/projects/" + projectID + "/network/vpcs/" + vpcID)
+	vpcURI := newResourceURI(uriKindVPC, projectID, vpcID).Ref()
 	sg, err := r.client.Client.FromNetwork().SecurityGroups().Create(ctx,
 		aruba.NewSecurityGroup().
 			Named(data.Name.ValueString()).
@@ -154,7 +179,7 @@ func (r *SecurityGroupResource) Create(ctx context.Context, req resource.CreateR
 			Tagged(tags...),
 	)
 	if provErr := CheckResponseErr("create", "SecurityGroup", err); provErr != nil {
-		resp.Diagnostics.AddError("API Error", provErr.Error())
+		AppendAPIError(&resp.Diagnostics, provErr)
 		return
 	}
 
@@ -166,7 +191,7 @@ func (r *SecurityGroupResource) Create(ctx context.Context, req resource.CreateR
 		return
 	}
 
-	if waitErr := sg.WaitUntilReady(ctx, sdkWaitOptions(effectiveTimeout(data.Timeout, r.client.ResourceTimeout))...); waitErr != nil {
+	if waitErr := sg.WaitUntilReady(ctx, sdkWaitOptions(timeout)...); waitErr != nil {
 		ReportWaitResult(&resp.Diagnostics, waitErr, "SecurityGroup", data.Id.ValueString())
 		return
 	}
@@ -186,11 +211,18 @@ func (r *SecurityGroupResource) Create(ctx context.Context, req resource.CreateR
 }
 
 func (r *SecurityGroupResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	ctx = withCorrelationID(ctx)
 	var data SecurityGroupResourceModel
 	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	timeout := r.client.operationTimeout(ctx, data.Timeouts.Read, data.Timeout, &resp.Diagnostics)
 	if resp.Diagnostics.HasError() {
 		return
 	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	if !r.client.checkGuardrails("read", "SecurityGroup", data.ProjectId, &resp.Diagnostics) {
+		return
+	}
 	if data.Id.IsNull() || data.Id.ValueString() == "" {
 		resp.State.RemoveResource(ctx)
 		return
@@ -202,7 +234,7 @@ func (r *SecurityGroupResource) Read(ctx context.Context, req resource.ReadReque
 			resp.State.RemoveResource(ctx)
 			return
 		}
-		resp.Diagnostics.AddError("API Error", provErr.Error())
+		AppendAPIError(&resp.Diagnostics, provErr)
 		return
 	}
 
@@ -213,13 +245,13 @@ func (r *SecurityGroupResource) Read(ctx context.Context, req resource.ReadReque
 			fmt.Sprintf("SecurityGroup %q is in a terminal failure state (%s). "+
 				"Run `terraform destroy` to clean it up, or `terraform apply -replace=<address>` to recreate it.", data.Id.ValueString(), st))
 	case IsCreatingState(st):
-		if waitErr := sg.WaitUntilReady(ctx, sdkWaitOptions(r.client.ResourceTimeout)...); waitErr != nil {
+		if waitErr := sg.WaitUntilReady(ctx, sdkWaitOptions(timeout)...); waitErr != nil {
 			ReportWaitResult(&resp.Diagnostics, waitErr, "SecurityGroup", data.Id.ValueString())
 			return
 		}
 		sg, err = r.client.Client.FromNetwork().SecurityGroups().Get(ctx, sgRef(&data))
 		if provErr := CheckResponseErr("read", "SecurityGroup", err); provErr != nil {
-			resp.Diagnostics.AddError("API Error", provErr.Error())
+			AppendAPIError(&resp.Diagnostics, provErr)
 			return
 		}
 	}
@@ -233,22 +265,29 @@ func (r *SecurityGroupResource) Read(ctx context.Context, req resource.ReadReque
 }
 
 func (r *SecurityGroupResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	ctx = withCorrelationID(ctx)
 	var data SecurityGroupResourceModel
 	var state SecurityGroupResourceModel
 	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
 	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	timeout := r.client.operationTimeout(ctx, data.Timeouts.Update, data.Timeout, &resp.Diagnostics)
 	if resp.Diagnostics.HasError() {
 		return
 	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	if !r.client.checkGuardrails("update", "SecurityGroup", data.ProjectId, &resp.Diagnostics) {
+		return
+	}
 
-	tags := ListToTags(ctx, data.Tags, &resp.Diagnostics)
+	tags := r.client.requestTags(ctx, data.Tags, &data.TagsAll, &resp.Diagnostics)
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
 	sg, err := r.client.Client.FromNetwork().SecurityGroups().Get(ctx, sgRef(&state))
 	if provErr := CheckResponseErr("read", "SecurityGroup", err); provErr != nil {
-		resp.Diagnostics.AddError("API Error", provErr.Error())
+		AppendAPIError(&resp.Diagnostics, provErr)
 		return
 	}
 
@@ -261,7 +300,7 @@ func (r *SecurityGroupResource) Update(ctx context.Context, req resource.UpdateR
 
 	updated, err := r.client.Client.FromNetwork().SecurityGroups().Update(ctx, sg)
 	if provErr := CheckResponseErr("update", "SecurityGroup", err); provErr != nil {
-		resp.Diagnostics.AddError("API Error", provErr.Error())
+		AppendAPIError(&resp.Diagnostics, provErr)
 		return
 	}
 
@@ -270,17 +309,24 @@ func (r *SecurityGroupResource) Update(ctx context.Context, req resource.UpdateR
 	data.ProjectId = state.ProjectId
 	data.VpcId = state.VpcId
 	data.Name = types.StringValue(updated.Name())
-	data.Tags = TagsToListPreserveNull(updated.Tags(), data.Tags)
+	data.Tags, data.TagsAll = flattenTags(updated.Tags(), data.Tags, data.TagsAll)
 
 	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
 }
 
 func (r *SecurityGroupResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	ctx = withCorrelationID(ctx)
 	var data SecurityGroupResourceModel
 	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	timeout := r.client.operationTimeout(ctx, data.Timeouts.Delete, data.Timeout, &resp.Diagnostics)
 	if resp.Diagnostics.HasError() {
 		return
 	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	if !r.client.checkGuardrails("delete", "SecurityGroup", data.ProjectId, &resp.Diagnostics) {
+		return
+	}
 
 	ref := sgRef(&data)
 	sgID := data.Id.ValueString()
@@ -300,12 +346,12 @@ func (r *SecurityGroupResource) Delete(ctx context.Context, req resource.DeleteR
 	err := DeleteResourceWithRetry(ctx, func() error {
 		return CheckResponseErrAsError("delete", "SecurityGroup",
 			r.client.Client.FromNetwork().SecurityGroups().Delete(ctx, ref))
-	}, "SecurityGroup", sgID, effectiveTimeout(data.Timeout, r.client.ResourceTimeout), deletionChecker)
+	}, "SecurityGroup", sgID, timeout, deletionChecker)
 	if err != nil {
 		resp.Diagnostics.AddError("Error deleting SecurityGroup", err.Error())
 		return
 	}
-	if waitErr := WaitForResourceDeleted(ctx, deletionChecker, "SecurityGroup", sgID, remainingTimeout(deleteStart, effectiveTimeout(data.Timeout, r.client.ResourceTimeout))); waitErr != nil {
+	if waitErr := WaitForResourceDeleted(ctx, deletionChecker, "SecurityGroup", sgID, remainingTimeout(deleteStart, timeout)); waitErr != nil {
 		resp.Diagnostics.AddError("Error waiting for SecurityGroup deletion", waitErr.Error())
 		return
 	}
@@ -121,11 +121,15 @@ func (d *SecurityRuleDataSource) Configure(ctx context.Context, req datasource.C
 }
 
 func (d *SecurityRuleDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	ctx = withCorrelationID(ctx)
 	var data SecurityRuleDataSourceModel
 	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
+	if !d.client.checkGuardrails("read", "SecurityRule", data.ProjectId, &resp.Diagnostics) {
+		return
+	}
 
 	projectID := data.ProjectId.ValueString()
 	vpcID := data.VpcId.ValueString()
@@ -139,7 +143,7 @@ func (d *SecurityRuleDataSource) Read(ctx context.Context, req datasource.ReadRe
 	rule, err := d.client.Client.FromNetwork().SecurityGroupRules().Get(ctx,
 		aruba.SecurityRuleRef(projectID, vpcID, securityGroupID, ruleID))
 	if provErr := CheckResponseErr("read", "SecurityRule", err); provErr != nil {
-		resp.Diagnostics.AddError("API Error", provErr.Error())
+		AppendAPIError(&resp.Diagnostics, provErr)
 		return
 	}
 
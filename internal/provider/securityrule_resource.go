@@ -7,6 +7,7 @@ import (
 	"time"
 
 	aruba "github.com/Arubacloud/sdk-go/pkg/aruba"
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
 	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
 	"github.com/hashicorp/terraform-plugin-framework/attr"
 	"github.com/hashicorp/terraform-plugin-framework/path"
@@ -57,19 +58,22 @@ type SecurityRuleResource struct {
 }
 
 type SecurityRuleResourceModel struct {
-	Id              types.String `tfsdk:"id"`
-	Uri             types.String `tfsdk:"uri"`
-	Name            types.String `tfsdk:"name"`
-	Location        types.String `tfsdk:"location"`
-	Tags            types.List   `tfsdk:"tags"`
-	ProjectId       types.String `tfsdk:"project_id"`
-	VpcId           types.String `tfsdk:"vpc_id"`
-	SecurityGroupId types.String `tfsdk:"security_group_id"`
-	Properties      types.Object `tfsdk:"properties"`
-	Timeout         types.String `tfsdk:"timeout"`
+	Id              types.String   `tfsdk:"id"`
+	Uri             types.String   `tfsdk:"uri"`
+	Name            types.String   `tfsdk:"name"`
+	Location        types.String   `tfsdk:"location"`
+	Tags            types.List     `tfsdk:"tags"`
+	TagsAll         types.List     `tfsdk:"tags_all"`
+	ProjectId       types.String   `tfsdk:"project_id"`
+	VpcId           types.String   `tfsdk:"vpc_id"`
+	SecurityGroupId types.String   `tfsdk:"security_group_id"`
+	Properties      types.Object   `tfsdk:"properties"`
+	Timeout         types.String   `tfsdk:"timeout"`
+	Timeouts        timeouts.Value `tfsdk:"timeouts"`
 }
 
 var _ resource.Resource = &SecurityRuleResource{}
+var _ resource.ResourceWithModifyPlan = &SecurityRuleResource{}
 var _ resource.ResourceWithImportState = &SecurityRuleResource{}
 
 func NewSecurityRuleResource() resource.Resource {
@@ -103,16 +107,20 @@ func (r *SecurityRuleResource) Schema(ctx context.Context, req resource.SchemaRe
 				Required:            true,
 			},
 			"location": schema.StringAttribute{
-				MarkdownDescription: "Region identifier for the resource (e.g., `ITBG-Bergamo`). See the [available locations and zones](https://api.arubacloud.com/docs/metadata/#location-and-data-center). (Immutable — changing this value forces the resource to be destroyed and re-created.)",
-				Required:            true,
+				MarkdownDescription: defaultedDescription("Region identifier for the resource (e.g., `ITBG-Bergamo`). See the [available locations and zones](https://api.arubacloud.com/docs/metadata/#location-and-data-center). (Immutable — changing this value forces the resource to be destroyed and re-created.)", "location"),
+				Optional:            true,
+				Computed:            true,
 				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
 					stringplanmodifier.RequiresReplace(),
 				},
 			},
 			"project_id": schema.StringAttribute{
-				MarkdownDescription: "ID of the project that owns this resource. (Immutable — changing this value forces the resource to be destroyed and re-created.)",
-				Required:            true,
+				MarkdownDescription: defaultedDescription("ID of the project that owns this resource. (Immutable — changing this value forces the resource to be destroyed and re-created.)", "project_id"),
+				Optional:            true,
+				Computed:            true,
 				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
 					stringplanmodifier.RequiresReplace(),
 				},
 			},
@@ -135,8 +143,10 @@ func (r *SecurityRuleResource) Schema(ctx context.Context, req resource.SchemaRe
 				MarkdownDescription: "List of string tags attached to the resource for filtering and organisation.",
 				Optional:            true,
 			},
+			"tags_all": tagsAllAttribute(),
 			"timeout": schema.StringAttribute{
-				MarkdownDescription: "Per-resource timeout override (e.g. `\"15m\"`, `\"1h\"`). Overrides the provider-level `resource_timeout` for this resource's Create and Delete operations. Uses Go duration syntax.",
+				MarkdownDescription: "Per-resource timeout override (e.g. `\"15m\"`, `\"1h\"`) applied to every operation not set in the `timeouts` block. Overrides the provider-level `resource_timeout`. Uses Go duration syntax. Deprecated — use the `timeouts` block instead.",
+				DeprecationMessage:  "Use the timeouts block, which sets the create, read, update and delete timeouts individually.",
 				Optional:            true,
 			},
 			"properties": schema.SingleNestedAttribute{
@@ -190,6 +200,9 @@ func (r *SecurityRuleResource) Schema(ctx context.Context, req resource.SchemaRe
 				},
 			},
 		},
+		Blocks: map[string]schema.Block{
+			"timeouts": timeouts.BlockAll(ctx),
+		},
 	}
 }
 
@@ -209,15 +222,12 @@ func (r *SecurityRuleResource) Configure(ctx context.Context, req resource.Confi
 }
 
 func sgRuleRef(data *SecurityRuleResourceModel) aruba.Ref {
-	if !data.Uri.IsNull() && data.Uri.ValueString() != "" {
-		return aruba.URI(data.Uri.ValueString())
-	}
-	return aruba.SecurityRuleRef(
+	return uriRef(data.Uri, newResourceURI(uriKindSecurityRule,
 		data.ProjectId.ValueString(),
+		data.Id.ValueString(),
 		data.VpcId.ValueString(),
 		data.SecurityGroupId.ValueString(),
-		data.Id.ValueString(),
-	)
+	))
 }
 
 // priorProtocolAndKind extracts the protocol and target.kind strings already
@@ -243,7 +253,7 @@ func applySecurityRuleToModel(_ context.Context, rule *aruba.SecurityRule, data
 	data.Id = types.StringValue(rule.ID())
 	data.Uri = strVal(rule.URI())
 	data.Name = types.StringValue(rule.Name())
-	data.Tags = TagsToListPreserveNull(rule.Tags(), data.Tags)
+	data.Tags, data.TagsAll = flattenTags(rule.Tags(), data.Tags, data.TagsAll)
 
 	if rule.Region() != "" {
 		data.Location = types.StringValue(string(rule.Region()))
@@ -344,18 +354,33 @@ func extractRuleProperties(_ context.Context, data *SecurityRuleResourceModel) (
 	return
 }
 
+func (r *SecurityRuleResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	r.client.planTagsAll(ctx, req, resp, true)
+	r.client.planProviderDefaults(ctx, req, resp,
+		providerDefault{attr: "project_id", replace: true},
+		providerDefault{attr: "location", replace: true},
+	)
+}
+
 func (r *SecurityRuleResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	ctx = withCorrelationID(ctx)
 	var data SecurityRuleResourceModel
 	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	timeout := r.client.operationTimeout(ctx, data.Timeouts.Create, data.Timeout, &resp.Diagnostics)
 	if resp.Diagnostics.HasError() {
 		return
 	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	if !r.client.checkGuardrails("create", "SecurityRule", data.ProjectId, &resp.Diagnostics) {
+		return
+	}
 
 	projectID := data.ProjectId.ValueString()
 	vpcID := data.VpcId.ValueString()
 	securityGroupID := data.SecurityGroupId.ValueString()
 
-	tags := ListToTags(ctx, data.Tags, &resp.Diagnostics)
+	tags := r.client.requestTags(ctx, data.Tags, &data.TagsAll, &resp.Diagnostics)
 	if resp.Diagnostics.HasError() {
 		return
 	}
@@ -387,7 +412,7 @@ func (r *SecurityRuleResource) Create(ctx context.Context, req resource.CreateRe
 
 	rule, err := r.client.Client.FromNetwork().SecurityGroupRules().Create(ctx, builder)
 	if provErr := CheckResponseErr("create", "SecurityRule", err); provErr != nil {
-		resp.Diagnostics.AddError("API Error", provErr.Error())
+		AppendAPIError(&resp.Diagnostics, provErr)
 		return
 	}
 
@@ -399,7 +424,7 @@ func (r *SecurityRuleResource) Create(ctx context.Context, req resource.CreateRe
 		return
 	}
 
-	if waitErr := rule.WaitUntilReady(ctx, sdkWaitOptions(effectiveTimeout(data.Timeout, r.client.ResourceTimeout))...); waitErr != nil {
+	if waitErr := rule.WaitUntilReady(ctx, sdkWaitOptions(timeout)...); waitErr != nil {
 		ReportWaitResult(&resp.Diagnostics, waitErr, "SecurityRule", data.Id.ValueString())
 		return
 	}
@@ -419,11 +444,18 @@ func (r *SecurityRuleResource) Create(ctx context.Context, req resource.CreateRe
 }
 
 func (r *SecurityRuleResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	ctx = withCorrelationID(ctx)
 	var data SecurityRuleResourceModel
 	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	timeout := r.client.operationTimeout(ctx, data.Timeouts.Read, data.Timeout, &resp.Diagnostics)
 	if resp.Diagnostics.HasError() {
 		return
 	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	if !r.client.checkGuardrails("read", "SecurityRule", data.ProjectId, &resp.Diagnostics) {
+		return
+	}
 	if data.Id.IsNull() || data.Id.ValueString() == "" {
 		resp.State.RemoveResource(ctx)
 		return
@@ -435,7 +467,7 @@ func (r *SecurityRuleResource) Read(ctx context.Context, req resource.ReadReques
 			resp.State.RemoveResource(ctx)
 			return
 		}
-		resp.Diagnostics.AddError("API Error", provErr.Error())
+		AppendAPIError(&resp.Diagnostics, provErr)
 		return
 	}
 
@@ -446,13 +478,13 @@ func (r *SecurityRuleResource) Read(ctx context.Context, req resource.ReadReques
 			fmt.Sprintf("SecurityRule %q is in a terminal failure state (%s). "+
 				"Run `terraform destroy` to clean it up, or `terraform apply -replace=<address>` to recreate it.", data.Id.ValueString(), st))
 	case IsCreatingState(st):
-		if waitErr := rule.WaitUntilReady(ctx, sdkWaitOptions(r.client.ResourceTimeout)...); waitErr != nil {
+		if waitErr := rule.WaitUntilReady(ctx, sdkWaitOptions(timeout)...); waitErr != nil {
 			ReportWaitResult(&resp.Diagnostics, waitErr, "SecurityRule", data.Id.ValueString())
 			return
 		}
 		rule, err = r.client.Client.FromNetwork().SecurityGroupRules().Get(ctx, sgRuleRef(&data))
 		if provErr := CheckResponseErr("read", "SecurityRule", err); provErr != nil {
-			resp.Diagnostics.AddError("API Error", provErr.Error())
+			AppendAPIError(&resp.Diagnostics, provErr)
 			return
 		}
 	}
@@ -468,22 +500,29 @@ func (r *SecurityRuleResource) Read(ctx context.Context, req resource.ReadReques
 }
 
 func (r *SecurityRuleResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	ctx = withCorrelationID(ctx)
 	var data SecurityRuleResourceModel
 	var state SecurityRuleResourceModel
 
 	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
 	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	timeout := r.client.operationTimeout(ctx, data.Timeouts.Update, data.Timeout, &resp.Diagnostics)
 	if resp.Diagnostics.HasError() {
 		return
 	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	if !r.client.checkGuardrails("update", "SecurityRule", data.ProjectId, &resp.Diagnostics) {
+		return
+	}
 
 	rule, err := r.client.Client.FromNetwork().SecurityGroupRules().Get(ctx, sgRuleRef(&state))
 	if provErr := CheckResponseErr("read", "SecurityRule", err); provErr != nil {
-		resp.Diagnostics.AddError("API Error", provErr.Error())
+		AppendAPIError(&resp.Diagnostics, provErr)
 		return
 	}
 
-	tags := ListToTags(ctx, data.Tags, &resp.Diagnostics)
+	tags := r.client.requestTags(ctx, data.Tags, &data.TagsAll, &resp.Diagnostics)
 	if resp.Diagnostics.HasError() {
 		return
 	}
@@ -500,7 +539,7 @@ func (r *SecurityRuleResource) Update(ctx context.Context, req resource.UpdateRe
 
 	updated, err := r.client.Client.FromNetwork().SecurityGroupRules().Update(ctx, rule)
 	if provErr := CheckResponseErr("update", "SecurityRule", err); provErr != nil {
-		resp.Diagnostics.AddError("API Error", provErr.Error())
+		AppendAPIError(&resp.Diagnostics, provErr)
 		return
 	}
 
@@ -511,18 +550,25 @@ func (r *SecurityRuleResource) Update(ctx context.Context, req resource.UpdateRe
 	data.VpcId = state.VpcId
 	data.SecurityGroupId = state.SecurityGroupId
 	data.Name = types.StringValue(updated.Name())
-	data.Tags = TagsToListPreserveNull(updated.Tags(), data.Tags)
+	data.Tags, data.TagsAll = flattenTags(updated.Tags(), data.Tags, data.TagsAll)
 	// Properties remain unchanged.
 
 	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
 }
 
 func (r *SecurityRuleResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	ctx = withCorrelationID(ctx)
 	var data SecurityRuleResourceModel
 	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	timeout := r.client.operationTimeout(ctx, data.Timeouts.Delete, data.Timeout, &resp.Diagnostics)
 	if resp.Diagnostics.HasError() {
 		return
 	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	if !r.client.checkGuardrails("delete", "SecurityRule", data.ProjectId, &resp.Diagnostics) {
+		return
+	}
 
 	ref := sgRuleRef(&data)
 	ruleID := data.Id.ValueString()
@@ -542,12 +588,12 @@ func (r *SecurityRuleResource) Delete(ctx context.Context, req resource.DeleteRe
 	err := DeleteResourceWithRetry(ctx, func() error {
 		return CheckResponseErrAsError("delete", "SecurityRule",
 			r.client.Client.FromNetwork().SecurityGroupRules().Delete(ctx, ref))
-	}, "SecurityRule", ruleID, effectiveTimeout(data.Timeout, r.client.ResourceTimeout), deletionChecker)
+	}, "SecurityRule", ruleID, timeout, deletionChecker)
 	if err != nil {
 		resp.Diagnostics.AddError("Error deleting SecurityRule", err.Error())
 		return
 	}
-	if waitErr := WaitForResourceDeleted(ctx, deletionChecker, "SecurityRule", ruleID, remainingTimeout(deleteStart, effectiveTimeout(data.Timeout, r.client.ResourceTimeout))); waitErr != nil {
+	if waitErr := WaitForResourceDeleted(ctx, deletionChecker, "SecurityRule", ruleID, remainingTimeout(deleteStart, timeout)); waitErr != nil {
 		resp.Diagnostics.AddError("Error waiting for SecurityRule deletion", waitErr.Error())
 		return
 	}
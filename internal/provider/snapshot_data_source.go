@@ -5,7 +5,6 @@ import (
 	"fmt"
 	"strings"
 
-	aruba "github.com/Arubacloud/sdk-go/pkg/aruba"
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
 	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/types"
@@ -88,11 +87,15 @@ func (d *SnapshotDataSource) Configure(ctx context.Context, req datasource.Confi
 }
 
 func (d *SnapshotDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	ctx = withCorrelationID(ctx)
 	var data SnapshotDataSourceModel
 	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
+	if !d.client.checkGuardrails("read", "Snapshot", data.ProjectId, &resp.Diagnostics) {
+		return
+	}
 
 	projectID := data.ProjectId.ValueString()
 	snapshotID := data.Id.ValueString()
@@ -102,9 +105,9 @@ func (d *SnapshotDataSource) Read(ctx context.Context, req datasource.ReadReques
 	}
 
 	snap, err := d.client.Client.FromStorage().Snapshots().Get(ctx,
-		aruba.URI("/projects/"+projectID+"/providers/Aruba.Storage/snapshots/"+snapshotID))
+		newResourceURI(uriKindSnapshot, projectID, snapshotID).Ref())
 	if provErr := CheckResponseErr("read", "Snapshot", err); provErr != nil {
-		resp.Diagnostics.AddError("API Error", provErr.Error())
+		AppendAPIError(&resp.Diagnostics, provErr)
 		return
 	}
 
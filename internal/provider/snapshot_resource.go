@@ -6,6 +6,7 @@ import (
 	"time"
 
 	aruba "github.com/Arubacloud/sdk-go/pkg/aruba"
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
 	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
 	"github.com/hashicorp/terraform-plugin-framework/attr"
 	"github.com/hashicorp/terraform-plugin-framework/path"
@@ -19,6 +20,7 @@ import (
 )
 
 var _ resource.Resource = &SnapshotResource{}
+var _ resource.ResourceWithModifyPlan = &SnapshotResource{}
 var _ resource.ResourceWithImportState = &SnapshotResource{}
 
 func NewSnapshotResource() resource.Resource {
@@ -26,15 +28,17 @@ func NewSnapshotResource() resource.Resource {
 }
 
 type SnapshotResourceModel struct {
-	Id            types.String `tfsdk:"id"`
-	Uri           types.String `tfsdk:"uri"`
-	Name          types.String `tfsdk:"name"`
-	ProjectId     types.String `tfsdk:"project_id"`
-	Location      types.String `tfsdk:"location"`
-	BillingPeriod types.String `tfsdk:"billing_period"`
-	VolumeUri     types.String `tfsdk:"volume_uri"`
-	Tags          types.List   `tfsdk:"tags"`
-	Timeout       types.String `tfsdk:"timeout"`
+	Id            types.String   `tfsdk:"id"`
+	Uri           types.String   `tfsdk:"uri"`
+	Name          types.String   `tfsdk:"name"`
+	ProjectId     types.String   `tfsdk:"project_id"`
+	Location      types.String   `tfsdk:"location"`
+	BillingPeriod types.String   `tfsdk:"billing_period"`
+	VolumeUri     types.String   `tfsdk:"volume_uri"`
+	Tags          types.List     `tfsdk:"tags"`
+	TagsAll       types.List     `tfsdk:"tags_all"`
+	Timeout       types.String   `tfsdk:"timeout"`
+	Timeouts      timeouts.Value `tfsdk:"timeouts"`
 }
 
 type SnapshotResource struct {
@@ -68,16 +72,20 @@ func (r *SnapshotResource) Schema(ctx context.Context, req resource.SchemaReques
 				Required:            true,
 			},
 			"project_id": schema.StringAttribute{
-				MarkdownDescription: "ID of the project that owns this resource. (Immutable — changing this value forces the resource to be destroyed and re-created.)",
-				Required:            true,
+				MarkdownDescription: defaultedDescription("ID of the project that owns this resource. (Immutable — changing this value forces the resource to be destroyed and re-created.)", "project_id"),
+				Optional:            true,
+				Computed:            true,
 				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
 					stringplanmodifier.RequiresReplace(),
 				},
 			},
 			"location": schema.StringAttribute{
-				MarkdownDescription: "Region identifier (e.g., `ITBG-Bergamo`). See the [available locations and zones](https://api.arubacloud.com/docs/metadata/#location-and-data-center). (Immutable — changing this value forces the resource to be destroyed and re-created.)",
-				Required:            true,
+				MarkdownDescription: defaultedDescription("Region identifier (e.g., `ITBG-Bergamo`). See the [available locations and zones](https://api.arubacloud.com/docs/metadata/#location-and-data-center). (Immutable — changing this value forces the resource to be destroyed and re-created.)", "location"),
+				Optional:            true,
+				Computed:            true,
 				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
 					stringplanmodifier.RequiresReplace(),
 				},
 			},
@@ -100,11 +108,16 @@ func (r *SnapshotResource) Schema(ctx context.Context, req resource.SchemaReques
 				MarkdownDescription: "List of string tags attached to the resource for filtering and organisation.",
 				Optional:            true,
 			},
+			"tags_all": tagsAllAttribute(),
 			"timeout": schema.StringAttribute{
-				MarkdownDescription: "Per-resource timeout override (e.g. `\"15m\"`, `\"1h\"`). Overrides the provider-level `resource_timeout` for this resource's Create and Delete operations. Uses Go duration syntax.",
+				MarkdownDescription: "Per-resource timeout override (e.g. `\"15m\"`, `\"1h\"`) applied to every operation not set in the `timeouts` block. Overrides the provider-level `resource_timeout`. Uses Go duration syntax. Deprecated — use the `timeouts` block instead.",
+				DeprecationMessage:  "Use the timeouts block, which sets the create, read, update and delete timeouts individually.",
 				Optional:            true,
 			},
 		},
+		Blocks: map[string]schema.Block{
+			"timeouts": timeouts.BlockAll(ctx),
+		},
 	}
 }
 
@@ -124,18 +137,30 @@ func (r *SnapshotResource) Configure(ctx context.Context, req resource.Configure
 }
 
 func snapshotRef(data *SnapshotResourceModel) aruba.Ref {
-	if !data.Uri.IsNull() && data.Uri.ValueString() != "" {
-		return aruba.URI(data.Uri.ValueString())
-	}
-	return aruba.URI("/projects/" + data.ProjectId.ValueString() + "/providers/Aruba.Storage/snapshots/" + data.Id.ValueString())
+	return uriRef(data.Uri, newResourceURI(uriKindSnapshot, data.ProjectId.ValueString(), data.Id.ValueString()))
+}
+
+func (r *SnapshotResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	r.client.planTagsAll(ctx, req, resp, true)
+	r.client.planProviderDefaults(ctx, req, resp,
+		providerDefault{attr: "project_id", replace: true},
+		providerDefault{attr: "location", replace: true},
+	)
 }
 
 func (r *SnapshotResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	ctx = withCorrelationID(ctx)
 	var data SnapshotResourceModel
 	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	timeout := r.client.operationTimeout(ctx, data.Timeouts.Create, data.Timeout, &resp.Diagnostics)
 	if resp.Diagnostics.HasError() {
 		return
 	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	if !r.client.checkGuardrails("create", "Snapshot", data.ProjectId, &resp.Diagnostics) {
+		return
+	}
 
 	projectID := data.ProjectId.ValueString()
 	volumeURI := data.VolumeUri.ValueString()
@@ -144,7 +169,7 @@ func (r *SnapshotResource) Create(ctx context.Context, req resource.CreateReques
 		return
 	}
 
-	tags := ListToTags(ctx, data.Tags, &resp.Diagnostics)
+	tags := r.client.requestTags(ctx, data.Tags, &data.TagsAll, &resp.Diagnostics)
 	if resp.Diagnostics.HasError() {
 		return
 	}
@@ -152,14 +177,14 @@ func (r *SnapshotResource) Create(ctx context.Context, req resource.CreateReques
 	snap, err := r.client.Client.FromStorage().Snapshots().Create(ctx,
 		aruba.NewSnapshot().
 			Named(data.Name.ValueString()).
-			InProject(aruba.URI("/projects/"+projectID)).
+			InProject(newResourceURI(uriKindProject, projectID, "").Ref()).
 			InRegion(aruba.Region(data.Location.ValueString())).
 			BilledBy(aruba.BillingPeriod(data.BillingPeriod.ValueString())).
 			FromVolume(aruba.URI(volumeURI)).
 			Tagged(tags...),
 	)
 	if provErr := CheckResponseErr("create", "Snapshot", err); provErr != nil {
-		resp.Diagnostics.AddError("API Error", provErr.Error())
+		AppendAPIError(&resp.Diagnostics, provErr)
 		return
 	}
 
@@ -171,7 +196,7 @@ func (r *SnapshotResource) Create(ctx context.Context, req resource.CreateReques
 		return
 	}
 
-	if waitErr := snap.WaitUntilReady(ctx, sdkWaitOptions(effectiveTimeout(data.Timeout, r.client.ResourceTimeout))...); waitErr != nil {
+	if waitErr := snap.WaitUntilReady(ctx, sdkWaitOptions(timeout)...); waitErr != nil {
 		ReportWaitResult(&resp.Diagnostics, waitErr, "Snapshot", data.Id.ValueString())
 		return
 	}
@@ -181,7 +206,7 @@ func (r *SnapshotResource) Create(ctx context.Context, req resource.CreateReques
 		data.Id = types.StringValue(fresh.ID())
 		data.Uri = strVal(fresh.URI())
 		data.Name = types.StringValue(fresh.Name())
-		data.Tags = TagsToListPreserveNull(fresh.Tags(), data.Tags)
+		data.Tags, data.TagsAll = flattenTags(fresh.Tags(), data.Tags, data.TagsAll)
 		if fresh.Region() != "" {
 			data.Location = types.StringValue(string(fresh.Region()))
 		}
@@ -198,11 +223,18 @@ func (r *SnapshotResource) Create(ctx context.Context, req resource.CreateReques
 }
 
 func (r *SnapshotResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	ctx = withCorrelationID(ctx)
 	var data SnapshotResourceModel
 	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	timeout := r.client.operationTimeout(ctx, data.Timeouts.Read, data.Timeout, &resp.Diagnostics)
 	if resp.Diagnostics.HasError() {
 		return
 	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	if !r.client.checkGuardrails("read", "Snapshot", data.ProjectId, &resp.Diagnostics) {
+		return
+	}
 	if data.Id.IsNull() || data.Id.ValueString() == "" {
 		resp.State.RemoveResource(ctx)
 		return
@@ -214,7 +246,7 @@ func (r *SnapshotResource) Read(ctx context.Context, req resource.ReadRequest, r
 			resp.State.RemoveResource(ctx)
 			return
 		}
-		resp.Diagnostics.AddError("API Error", provErr.Error())
+		AppendAPIError(&resp.Diagnostics, provErr)
 		return
 	}
 
@@ -225,13 +257,13 @@ func (r *SnapshotResource) Read(ctx context.Context, req resource.ReadRequest, r
 			fmt.Sprintf("Snapshot %q is in a terminal failure state (%s). "+
 				"Run `terraform destroy` to clean it up, or `terraform apply -replace=<address>` to recreate it.", data.Id.ValueString(), st))
 	case IsCreatingState(st):
-		if waitErr := snap.WaitUntilReady(ctx, sdkWaitOptions(r.client.ResourceTimeout)...); waitErr != nil {
+		if waitErr := snap.WaitUntilReady(ctx, sdkWaitOptions(timeout)...); waitErr != nil {
 			ReportWaitResult(&resp.Diagnostics, waitErr, "Snapshot", data.Id.ValueString())
 			return
 		}
 		snap, err = r.client.Client.FromStorage().Snapshots().Get(ctx, snapshotRef(&data))
 		if provErr := CheckResponseErr("read", "Snapshot", err); provErr != nil {
-			resp.Diagnostics.AddError("API Error", provErr.Error())
+			AppendAPIError(&resp.Diagnostics, provErr)
 			return
 		}
 	}
@@ -273,23 +305,30 @@ func (r *SnapshotResource) Read(ctx context.Context, req resource.ReadRequest, r
 }
 
 func (r *SnapshotResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	ctx = withCorrelationID(ctx)
 	var data SnapshotResourceModel
 	var state SnapshotResourceModel
 
 	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
 	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	timeout := r.client.operationTimeout(ctx, data.Timeouts.Update, data.Timeout, &resp.Diagnostics)
 	if resp.Diagnostics.HasError() {
 		return
 	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	if !r.client.checkGuardrails("update", "Snapshot", data.ProjectId, &resp.Diagnostics) {
+		return
+	}
 
-	tags := ListToTags(ctx, data.Tags, &resp.Diagnostics)
+	tags := r.client.requestTags(ctx, data.Tags, &data.TagsAll, &resp.Diagnostics)
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
 	snap, err := r.client.Client.FromStorage().Snapshots().Get(ctx, snapshotRef(&state))
 	if provErr := CheckResponseErr("read", "Snapshot", err); provErr != nil {
-		resp.Diagnostics.AddError("API Error", provErr.Error())
+		AppendAPIError(&resp.Diagnostics, provErr)
 		return
 	}
 
@@ -302,7 +341,7 @@ func (r *SnapshotResource) Update(ctx context.Context, req resource.UpdateReques
 
 	updated, err := r.client.Client.FromStorage().Snapshots().Update(ctx, snap)
 	if provErr := CheckResponseErr("update", "Snapshot", err); provErr != nil {
-		resp.Diagnostics.AddError("API Error", provErr.Error())
+		AppendAPIError(&resp.Diagnostics, provErr)
 		return
 	}
 
@@ -313,17 +352,24 @@ func (r *SnapshotResource) Update(ctx context.Context, req resource.UpdateReques
 	data.Location = state.Location
 	data.Uri = state.Uri
 	data.Name = types.StringValue(updated.Name())
-	data.Tags = TagsToListPreserveNull(updated.Tags(), data.Tags)
+	data.Tags, data.TagsAll = flattenTags(updated.Tags(), data.Tags, data.TagsAll)
 
 	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
 }
 
 func (r *SnapshotResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	ctx = withCorrelationID(ctx)
 	var data SnapshotResourceModel
 	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	timeout := r.client.operationTimeout(ctx, data.Timeouts.Delete, data.Timeout, &resp.Diagnostics)
 	if resp.Diagnostics.HasError() {
 		return
 	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	if !r.client.checkGuardrails("delete", "Snapshot", data.ProjectId, &resp.Diagnostics) {
+		return
+	}
 
 	ref := snapshotRef(&data)
 	snapshotID := data.Id.ValueString()
@@ -343,12 +389,12 @@ func (r *SnapshotResource) Delete(ctx context.Context, req resource.DeleteReques
 	err := DeleteResourceWithRetry(ctx, func() error {
 		return CheckResponseErrAsError("delete", "Snapshot",
 			r.client.Client.FromStorage().Snapshots().Delete(ctx, ref))
-	}, "Snapshot", snapshotID, effectiveTimeout(data.Timeout, r.client.ResourceTimeout), deletionChecker)
+	}, "Snapshot", snapshotID, timeout, deletionChecker)
 	if err != nil {
 		resp.Diagnostics.AddError("Error deleting Snapshot", err.Error())
 		return
 	}
-	if waitErr := WaitForResourceDeleted(ctx, deletionChecker, "Snapshot", snapshotID, remainingTimeout(deleteStart, effectiveTimeout(data.Timeout, r.client.ResourceTimeout))); waitErr != nil {
+	if waitErr := WaitForResourceDeleted(ctx, deletionChecker, "Snapshot", snapshotID, remainingTimeout(deleteStart, timeout)); waitErr != nil {
 		resp.Diagnostics.AddError("Error waiting for Snapshot deletion", waitErr.Error())
 		return
 	}
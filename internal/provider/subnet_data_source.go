@@ -144,11 +144,15 @@ func (d *SubnetDataSource) Configure(ctx context.Context, req datasource.Configu
 }
 
 func (d *SubnetDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	ctx = withCorrelationID(ctx)
 	var data SubnetDataSourceModel
 	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
+	if !d.client.checkGuardrails("read", "Subnet", data.ProjectId, &resp.Diagnostics) {
+		return
+	}
 
 	projectID := data.ProjectId.ValueString()
 	vpcID := data.VpcId.ValueString()
@@ -161,7 +165,7 @@ func (d *SubnetDataSource) Read(ctx context.Context, req datasource.ReadRequest,
 	subnet, err := d.client.Client.FromNetwork().Subnets().Get(ctx,
 		aruba.SubnetRef(projectID, vpcID, subnetID))
 	if provErr := CheckResponseErr("read", "Subnet", err); provErr != nil {
-		resp.Diagnostics.AddError("API Error", provErr.Error())
+		AppendAPIError(&resp.Diagnostics, provErr)
 		return
 	}
 
@@ -7,6 +7,7 @@ import (
 	"time"
 
 	aruba "github.com/Arubacloud/sdk-go/pkg/aruba"
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
 	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
 	"github.com/hashicorp/terraform-plugin-framework/attr"
 	"github.com/hashicorp/terraform-plugin-framework/diag"
@@ -22,6 +23,7 @@ import (
 )
 
 var _ resource.Resource = &SubnetResource{}
+var _ resource.ResourceWithModifyPlan = &SubnetResource{}
 var _ resource.ResourceWithImportState = &SubnetResource{}
 var _ resource.ResourceWithConfigValidators = &SubnetResource{}
 
@@ -34,16 +36,18 @@ type SubnetResource struct {
 }
 
 type SubnetResourceModel struct {
-	Id        types.String `tfsdk:"id"`
-	Uri       types.String `tfsdk:"uri"`
-	Name      types.String `tfsdk:"name"`
-	Location  types.String `tfsdk:"location"`
-	Tags      types.List   `tfsdk:"tags"`
-	ProjectId types.String `tfsdk:"project_id"`
-	VpcId     types.String `tfsdk:"vpc_id"`
-	Type      types.String `tfsdk:"type"`
-	Network   types.Object `tfsdk:"network"`
-	Timeout   types.String `tfsdk:"timeout"`
+	Id        types.String   `tfsdk:"id"`
+	Uri       types.String   `tfsdk:"uri"`
+	Name      types.String   `tfsdk:"name"`
+	Location  types.String   `tfsdk:"location"`
+	Tags      types.List     `tfsdk:"tags"`
+	TagsAll   types.List     `tfsdk:"tags_all"`
+	ProjectId types.String   `tfsdk:"project_id"`
+	VpcId     types.String   `tfsdk:"vpc_id"`
+	Type      types.String   `tfsdk:"type"`
+	Network   types.Object   `tfsdk:"network"`
+	Timeout   types.String   `tfsdk:"timeout"`
+	Timeouts  timeouts.Value `tfsdk:"timeouts"`
 }
 
 type NetworkModel struct {
@@ -95,9 +99,11 @@ func (r *SubnetResource) Schema(ctx context.Context, req resource.SchemaRequest,
 				Required:            true,
 			},
 			"location": schema.StringAttribute{
-				MarkdownDescription: "Region identifier for the resource (e.g., `ITBG-Bergamo`). See the [available locations and zones](https://api.arubacloud.com/docs/metadata/#location-and-data-center). (Immutable — changing this value forces the resource to be destroyed and re-created.)",
-				Required:            true,
+				MarkdownDescription: defaultedDescription("Region identifier for the resource (e.g., `ITBG-Bergamo`). See the [available locations and zones](https://api.arubacloud.com/docs/metadata/#location-and-data-center). (Immutable — changing this value forces the resource to be destroyed and re-created.)", "location"),
+				Optional:            true,
+				Computed:            true,
 				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
 					stringplanmodifier.RequiresReplace(),
 				},
 			},
@@ -106,10 +112,13 @@ func (r *SubnetResource) Schema(ctx context.Context, req resource.SchemaRequest,
 				MarkdownDescription: "List of string tags attached to the resource for filtering and organisation.",
 				Optional:            true,
 			},
+			"tags_all": tagsAllAttribute(),
 			"project_id": schema.StringAttribute{
-				MarkdownDescription: "ID of the project that owns this resource. (Immutable — changing this value forces the resource to be destroyed and re-created.)",
-				Required:            true,
+				MarkdownDescription: defaultedDescription("ID of the project that owns this resource. (Immutable — changing this value forces the resource to be destroyed and re-created.)", "project_id"),
+				Optional:            true,
+				Computed:            true,
 				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
 					stringplanmodifier.RequiresReplace(),
 				},
 			},
@@ -131,7 +140,8 @@ func (r *SubnetResource) Schema(ctx context.Context, req resource.SchemaRequest,
 				},
 			},
 			"timeout": schema.StringAttribute{
-				MarkdownDescription: "Per-resource timeout override (e.g. `\"15m\"`, `\"1h\"`). Overrides the provider-level `resource_timeout` for this resource's Create and Delete operations. Uses Go duration syntax.",
+				MarkdownDescription: "Per-resource timeout override (e.g. `\"15m\"`, `\"1h\"`) applied to every operation not set in the `timeouts` block. Overrides the provider-level `resource_timeout`. Uses Go duration syntax. Deprecated — use the `timeouts` block instead.",
+				DeprecationMessage:  "Use the timeouts block, which sets the create, read, update and delete timeouts individually.",
 				Optional:            true,
 			},
 			"network": schema.SingleNestedAttribute{
@@ -190,6 +200,9 @@ func (r *SubnetResource) Schema(ctx context.Context, req resource.SchemaRequest,
 				Optional: true,
 			},
 		},
+		Blocks: map[string]schema.Block{
+			"timeouts": timeouts.BlockAll(ctx),
+		},
 	}
 }
 
@@ -209,10 +222,7 @@ func (r *SubnetResource) Configure(ctx context.Context, req resource.ConfigureRe
 }
 
 func subnetRef(data *SubnetResourceModel) aruba.Ref {
-	if !data.Uri.IsNull() && data.Uri.ValueString() != "" {
-		return aruba.URI(data.Uri.ValueString())
-	}
-	return aruba.SubnetRef(data.ProjectId.ValueString(), data.VpcId.ValueString(), data.Id.ValueString())
+	return uriRef(data.Uri, newResourceURI(uriKindSubnet, data.ProjectId.ValueString(), data.Id.ValueString(), data.VpcId.ValueString()))
 }
 
 // buildSubnetDHCP builds a *aruba.SubnetDHCPCommon from a dhcp Object attribute.
@@ -297,7 +307,7 @@ func applySubnetToModel(_ context.Context, subnet *aruba.Subnet, data *SubnetRes
 	data.Id = types.StringValue(subnet.ID())
 	data.Uri = strVal(subnet.URI())
 	data.Name = types.StringValue(subnet.Name())
-	data.Tags = TagsToListPreserveNull(subnet.Tags(), data.Tags)
+	data.Tags, data.TagsAll = flattenTags(subnet.Tags(), data.Tags, data.TagsAll)
 	if subnet.Region() != "" {
 		data.Location = types.StringValue(string(subnet.Region()))
 	}
@@ -410,18 +420,33 @@ func routeObjectType() types.ObjectType {
 	}
 }
 
+func (r *SubnetResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	r.client.planTagsAll(ctx, req, resp, true)
+	r.client.planProviderDefaults(ctx, req, resp,
+		providerDefault{attr: "project_id", replace: true},
+		providerDefault{attr: "location", replace: true},
+	)
+}
+
 func (r *SubnetResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	ctx = withCorrelationID(ctx)
 	var data SubnetResourceModel
 	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	timeout := r.client.operationTimeout(ctx, data.Timeouts.Create, data.Timeout, &resp.Diagnostics)
 	if resp.Diagnostics.HasError() {
 		return
 	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	if !r.client.checkGuardrails("create", "Subnet", data.ProjectId, &resp.Diagnostics) {
+		return
+	}
 
 	projectID := data.ProjectId.ValueString()
 	vpcID := data.VpcId.ValueString()
 	subnetTypeStr := data.Type.ValueString()
 
-	tags := ListToTags(ctx, data.Tags, &resp.Diagnostics)
+	tags := r.client.requestTags(ctx, data.Tags, &data.TagsAll, &resp.Diagnostics)
 	if resp.Diagnostics.HasError() {
 		return
 	}
@@ -435,7 +460,7 @@ func (r *SubnetResource) Create(ctx context.Context, req resource.CreateRequest,
 		}
 	}
 
-	vpcURI := aruba.URI("/projects/" + projectID + "/network/vpcs/" + vpcID)
+	vpcURI := newResourceURI(uriKindVPC, projectID, vpcID).Ref()
 	subnetType := aruba.SubnetTypeBasic
 	if subnetTypeStr == "Advanced" {
 		subnetType = aruba.SubnetTypeAdvanced
@@ -527,7 +552,7 @@ func (r *SubnetResource) Create(ctx context.Context, req resource.CreateRequest,
 
 	subnet, err := r.client.Client.FromNetwork().Subnets().Create(ctx, builder)
 	if provErr := CheckResponseErr("create", "Subnet", err); provErr != nil {
-		resp.Diagnostics.AddError("API Error", provErr.Error())
+		AppendAPIError(&resp.Diagnostics, provErr)
 		return
 	}
 
@@ -540,14 +565,14 @@ func (r *SubnetResource) Create(ctx context.Context, req resource.CreateRequest,
 	}
 
 	subnetChecker := func(ctx context.Context) (string, error) {
-		ref := aruba.SubnetRef(data.ProjectId.ValueString(), data.VpcId.ValueString(), data.Id.ValueString())
+		ref := subnetRef(&data)
 		s, getErr := r.client.Client.FromNetwork().Subnets().Get(ctx, ref)
 		if provErr := CheckResponseErr("read", "Subnet", getErr); provErr != nil {
 			return "", provErr
 		}
 		return string(s.State()), nil
 	}
-	if waitErr := WaitForResourceActive(ctx, subnetChecker, "Subnet", data.Id.ValueString(), effectiveTimeout(data.Timeout, r.client.ResourceTimeout)); waitErr != nil {
+	if waitErr := WaitForResourceActive(ctx, subnetChecker, "Subnet", data.Id.ValueString(), timeout); waitErr != nil {
 		ReportWaitResult(&resp.Diagnostics, waitErr, "Subnet", data.Id.ValueString())
 		return
 	}
@@ -571,11 +596,18 @@ func (r *SubnetResource) Create(ctx context.Context, req resource.CreateRequest,
 }
 
 func (r *SubnetResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	ctx = withCorrelationID(ctx)
 	var data SubnetResourceModel
 	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	timeout := r.client.operationTimeout(ctx, data.Timeouts.Read, data.Timeout, &resp.Diagnostics)
 	if resp.Diagnostics.HasError() {
 		return
 	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	if !r.client.checkGuardrails("read", "Subnet", data.ProjectId, &resp.Diagnostics) {
+		return
+	}
 	if data.Id.IsNull() || data.Id.ValueString() == "" {
 		resp.State.RemoveResource(ctx)
 		return
@@ -587,7 +619,7 @@ func (r *SubnetResource) Read(ctx context.Context, req resource.ReadRequest, res
 			resp.State.RemoveResource(ctx)
 			return
 		}
-		resp.Diagnostics.AddError("API Error", provErr.Error())
+		AppendAPIError(&resp.Diagnostics, provErr)
 		return
 	}
 
@@ -599,20 +631,20 @@ func (r *SubnetResource) Read(ctx context.Context, req resource.ReadRequest, res
 				"Run `terraform destroy` to clean it up, or `terraform apply -replace=<address>` to recreate it.", data.Id.ValueString(), st))
 	case IsCreatingState(st):
 		subnetChecker := func(ctx context.Context) (string, error) {
-			ref := aruba.SubnetRef(data.ProjectId.ValueString(), data.VpcId.ValueString(), data.Id.ValueString())
+			ref := subnetRef(&data)
 			s, getErr := r.client.Client.FromNetwork().Subnets().Get(ctx, ref)
 			if provErr := CheckResponseErr("read", "Subnet", getErr); provErr != nil {
 				return "", provErr
 			}
 			return string(s.State()), nil
 		}
-		if waitErr := WaitForResourceActive(ctx, subnetChecker, "Subnet", data.Id.ValueString(), r.client.ResourceTimeout); waitErr != nil {
+		if waitErr := WaitForResourceActive(ctx, subnetChecker, "Subnet", data.Id.ValueString(), timeout); waitErr != nil {
 			ReportWaitResult(&resp.Diagnostics, waitErr, "Subnet", data.Id.ValueString())
 			return
 		}
 		subnet, err = r.client.Client.FromNetwork().Subnets().Get(ctx, subnetRef(&data))
 		if provErr := CheckResponseErr("read", "Subnet", err); provErr != nil {
-			resp.Diagnostics.AddError("API Error", provErr.Error())
+			AppendAPIError(&resp.Diagnostics, provErr)
 			return
 		}
 	}
@@ -629,22 +661,29 @@ func (r *SubnetResource) Read(ctx context.Context, req resource.ReadRequest, res
 }
 
 func (r *SubnetResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	ctx = withCorrelationID(ctx)
 	var data SubnetResourceModel
 	var state SubnetResourceModel
 
 	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
 	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	timeout := r.client.operationTimeout(ctx, data.Timeouts.Update, data.Timeout, &resp.Diagnostics)
 	if resp.Diagnostics.HasError() {
 		return
 	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	if !r.client.checkGuardrails("update", "Subnet", data.ProjectId, &resp.Diagnostics) {
+		return
+	}
 
 	subnet, err := r.client.Client.FromNetwork().Subnets().Get(ctx, subnetRef(&state))
 	if provErr := CheckResponseErr("read", "Subnet", err); provErr != nil {
-		resp.Diagnostics.AddError("API Error", provErr.Error())
+		AppendAPIError(&resp.Diagnostics, provErr)
 		return
 	}
 
-	tags := ListToTags(ctx, data.Tags, &resp.Diagnostics)
+	tags := r.client.requestTags(ctx, data.Tags, &data.TagsAll, &resp.Diagnostics)
 	if resp.Diagnostics.HasError() {
 		return
 	}
@@ -679,7 +718,7 @@ func (r *SubnetResource) Update(ctx context.Context, req resource.UpdateRequest,
 
 	updated, err := r.client.Client.FromNetwork().Subnets().Update(ctx, subnet)
 	if provErr := CheckResponseErr("update", "Subnet", err); provErr != nil {
-		resp.Diagnostics.AddError("API Error", provErr.Error())
+		AppendAPIError(&resp.Diagnostics, provErr)
 		return
 	}
 
@@ -703,11 +742,18 @@ func (r *SubnetResource) Update(ctx context.Context, req resource.UpdateRequest,
 }
 
 func (r *SubnetResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	ctx = withCorrelationID(ctx)
 	var data SubnetResourceModel
 	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	timeout := r.client.operationTimeout(ctx, data.Timeouts.Delete, data.Timeout, &resp.Diagnostics)
 	if resp.Diagnostics.HasError() {
 		return
 	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	if !r.client.checkGuardrails("delete", "Subnet", data.ProjectId, &resp.Diagnostics) {
+		return
+	}
 
 	ref := subnetRef(&data)
 	subnetID := data.Id.ValueString()
@@ -727,12 +773,12 @@ func (r *SubnetResource) Delete(ctx context.Context, req resource.DeleteRequest,
 	err := DeleteResourceWithRetry(ctx, func() error {
 		return CheckResponseErrAsError("delete", "Subnet",
 			r.client.Client.FromNetwork().Subnets().Delete(ctx, ref))
-	}, "Subnet", subnetID, effectiveTimeout(data.Timeout, r.client.ResourceTimeout), deletionChecker)
+	}, "Subnet", subnetID, timeout, deletionChecker)
 	if err != nil {
 		resp.Diagnostics.AddError("Error deleting Subnet", err.Error())
 		return
 	}
-	if waitErr := WaitForResourceDeleted(ctx, deletionChecker, "Subnet", subnetID, remainingTimeout(deleteStart, effectiveTimeout(data.Timeout, r.client.ResourceTimeout))); waitErr != nil {
+	if waitErr := WaitForResourceDeleted(ctx, deletionChecker, "Subnet", subnetID, remainingTimeout(deleteStart, timeout)); waitErr != nil {
 		resp.Diagnostics.AddError("Error waiting for Subnet deletion", waitErr.Error())
 		return
 	}
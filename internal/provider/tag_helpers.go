@@ -2,9 +2,13 @@ package provider
 
 import (
 	"context"
+	"strings"
 
 	"github.com/hashicorp/terraform-plugin-framework/attr"
 	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 )
 
@@ -42,6 +46,182 @@ func ListToTags(ctx context.Context, list types.List, diags *diag.Diagnostics) [
 	return tags
 }
 
+// tagsAllAttribute returns the schema of the computed tags_all attribute shared
+// by every taggable resource. Its planned value is set by planTagsAll.
+func tagsAllAttribute() schema.ListAttribute {
+	return schema.ListAttribute{
+		ElementType:         types.StringType,
+		MarkdownDescription: "All tags applied to the resource: `tags` merged with the provider `default_tags`.",
+		Computed:            true,
+	}
+}
+
+// tagKey returns the key of a "key:value" tag, or the whole tag when it has
+// no value part.
+func tagKey(tag string) string {
+	if k, _, ok := strings.Cut(tag, ":"); ok {
+		return k
+	}
+	return tag
+}
+
+// mergeTags returns the provider default tags followed by the resource tags.
+// A resource tag overrides a default tag with the same key, and duplicates
+// are dropped. Returns nil when both inputs are empty.
+func mergeTags(defaults, tags []string) []string {
+	if len(defaults) == 0 && len(tags) == 0 {
+		return nil
+	}
+	keys := make(map[string]bool, len(tags))
+	for _, t := range tags {
+		keys[tagKey(t)] = true
+	}
+	seen := make(map[string]bool, len(defaults)+len(tags))
+	merged := make([]string, 0, len(defaults)+len(tags))
+	for _, t := range defaults {
+		if keys[tagKey(t)] || seen[t] {
+			continue
+		}
+		seen[t] = true
+		merged = append(merged, t)
+	}
+	for _, t := range tags {
+		if seen[t] {
+			continue
+		}
+		seen[t] = true
+		merged = append(merged, t)
+	}
+	return merged
+}
+
+// sameTags reports whether a and b hold the same tags, ignoring order.
+func sameTags(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	counts := make(map[string]int, len(a))
+	for _, t := range a {
+		counts[t]++
+	}
+	for _, t := range b {
+		if counts[t] == 0 {
+			return false
+		}
+		counts[t]--
+	}
+	return true
+}
+
+// listElements returns the string elements of a known list, or nil.
+func listElements(list types.List) []string {
+	if list.IsNull() || list.IsUnknown() {
+		return nil
+	}
+	tags := make([]string, 0, len(list.Elements()))
+	for _, v := range list.Elements() {
+		if s, ok := v.(types.String); ok {
+			tags = append(tags, s.ValueString())
+		}
+	}
+	return tags
+}
+
+// requestTags returns the tags to send on Create or Update. The planned
+// tags_all already includes the provider default_tags, so it is sent whenever
+// it is known. Returns nil when neither tags nor default_tags are configured,
+// so Update keeps the tags the API already has.
+func (c *ArubaCloudClient) requestTags(ctx context.Context, tags types.List, tagsAll *types.List, diags *diag.Diagnostics) []string {
+	c.resolveTagsAll(tags, tagsAll)
+	if tagsAll.IsNull() {
+		return ListToTags(ctx, tags, diags)
+	}
+	if tags.IsNull() && len(tagsAll.Elements()) == 0 {
+		return nil
+	}
+	return ListToTags(ctx, *tagsAll, diags)
+}
+
+// resolveTagsAll fills in a tags_all that was still unknown at plan time
+// because tags depended on values known only after apply.
+func (c *ArubaCloudClient) resolveTagsAll(tags types.List, tagsAll *types.List) {
+	if !tagsAll.IsUnknown() {
+		return
+	}
+	var defaults []string
+	if c != nil {
+		defaults = c.DefaultTags
+	}
+	*tagsAll = TagsToList(mergeTags(defaults, listElements(tags)))
+}
+
+// flattenTags converts the tags returned by the API into the tags and
+// tags_all state values. Tags present in the prior tags_all but not in the
+// prior tags came only from the provider default_tags; they are left out of
+// tags so that they never show up as drift on the user-facing attribute.
+// The prior tags_all is kept when it holds the same tags as the API, so a
+// different ordering does not produce a diff either.
+func flattenTags(apiTags []string, priorTags, priorTagsAll types.List) (types.List, types.List) {
+	own := make(map[string]bool)
+	for _, t := range listElements(priorTags) {
+		own[t] = true
+	}
+	defaultOnly := make(map[string]bool)
+	for _, t := range listElements(priorTagsAll) {
+		if !own[t] {
+			defaultOnly[t] = true
+		}
+	}
+	tags := make([]string, 0, len(apiTags))
+	for _, t := range apiTags {
+		if !defaultOnly[t] {
+			tags = append(tags, t)
+		}
+	}
+
+	tagsAll := TagsToList(apiTags)
+	if !priorTagsAll.IsNull() && !priorTagsAll.IsUnknown() && sameTags(listElements(priorTagsAll), apiTags) {
+		tagsAll = priorTagsAll
+	}
+	return TagsToListPreserveNull(tags, priorTags), tagsAll
+}
+
+// planTagsAll sets the planned tags_all to the resource tags merged with the
+// provider default_tags. It is called from ModifyPlan on every taggable
+// resource. When updatable is false the API cannot retag an existing
+// resource, so the prior tags_all is kept until tags itself changes; this
+// stops a default_tags change from planning an update that can never
+// converge. tags_all stays unknown while the tags or the provider
+// configuration are unknown.
+func (c *ArubaCloudClient) planTagsAll(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse, updatable bool) {
+	if c == nil || req.Plan.Raw.IsNull() {
+		return
+	}
+
+	var tags types.List
+	resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("tags"), &tags)...)
+	if resp.Diagnostics.HasError() || tags.IsUnknown() {
+		return
+	}
+	merged := mergeTags(c.DefaultTags, listElements(tags))
+
+	var prior, priorTags types.List
+	if !req.State.Raw.IsNull() {
+		resp.Diagnostics.Append(req.State.GetAttribute(ctx, path.Root("tags_all"), &prior)...)
+		resp.Diagnostics.Append(req.State.GetAttribute(ctx, path.Root("tags"), &priorTags)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+	keepPrior := !prior.IsNull() && !prior.IsUnknown() &&
+		((!updatable && tags.Equal(priorTags)) || sameTags(listElements(prior), merged))
+	if keepPrior {
+		resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("tags_all"), prior)...)
+		return
+	}
+	resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("tags_all"), TagsToList(merged))...)
+}
+
 // billingPeriodFromAPI normalizes legacy API billing period values to their
 // canonical Terraform form. The API has historically returned lowercase variants
 // ("hourly", "monthly", "yearly") that differ from the accepted input values
@@ -2,11 +2,17 @@ package provider
 
 import (
 	"context"
+	"reflect"
 	"testing"
 
 	"github.com/hashicorp/terraform-plugin-framework/attr"
 	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
 	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
 )
 
 func TestBillingPeriodFromAPI(t *testing.T) {
@@ -248,3 +254,251 @@ func TestListToTags(t *testing.T) {
 		})
 	}
 }
+
+func TestMergeTags(t *testing.T) {
+	cases := []struct {
+		name     string
+		defaults []string
+		tags     []string
+		want     []string
+	}{
+		{"both empty", nil, nil, nil},
+		{"defaults only", []string{"owner:ops"}, nil, []string{"owner:ops"}},
+		{"tags only", nil, []string{"app:web"}, []string{"app:web"}},
+		{"defaults first", []string{"owner:ops"}, []string{"app:web"}, []string{"owner:ops", "app:web"}},
+		{"resource tag overrides same key", []string{"env:prod", "owner:ops"}, []string{"env:dev"}, []string{"owner:ops", "env:dev"}},
+		{"duplicates dropped", []string{"owner:ops", "backup"}, []string{"backup", "app:web", "app:web"}, []string{"owner:ops", "backup", "app:web"}},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := mergeTags(tc.defaults, tc.tags); !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("mergeTags(%v, %v) = %v, want %v", tc.defaults, tc.tags, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestFlattenTags(t *testing.T) {
+	cases := []struct {
+		name        string
+		apiTags     []string
+		priorTags   types.List
+		priorAll    types.List
+		wantTags    types.List
+		wantTagsAll types.List
+	}{
+		{
+			name:        "default-only tags are left out of tags",
+			apiTags:     []string{"owner:ops", "app:web"},
+			priorTags:   TagsToList([]string{"app:web"}),
+			priorAll:    TagsToList([]string{"owner:ops", "app:web"}),
+			wantTags:    TagsToList([]string{"app:web"}),
+			wantTagsAll: TagsToList([]string{"owner:ops", "app:web"}),
+		},
+		{
+			name:        "null tags stay null when only defaults are set",
+			apiTags:     []string{"owner:ops"},
+			priorTags:   types.ListNull(types.StringType),
+			priorAll:    TagsToList([]string{"owner:ops"}),
+			wantTags:    types.ListNull(types.StringType),
+			wantTagsAll: TagsToList([]string{"owner:ops"}),
+		},
+		{
+			name:        "tag set both in tags and default_tags is kept",
+			apiTags:     []string{"owner:ops"},
+			priorTags:   TagsToList([]string{"owner:ops"}),
+			priorAll:    TagsToList([]string{"owner:ops"}),
+			wantTags:    TagsToList([]string{"owner:ops"}),
+			wantTagsAll: TagsToList([]string{"owner:ops"}),
+		},
+		{
+			name:        "tags added outside Terraform are reported as drift",
+			apiTags:     []string{"owner:ops", "app:web", "manual"},
+			priorTags:   TagsToList([]string{"app:web"}),
+			priorAll:    TagsToList([]string{"owner:ops", "app:web"}),
+			wantTags:    TagsToList([]string{"app:web", "manual"}),
+			wantTagsAll: TagsToList([]string{"owner:ops", "app:web", "manual"}),
+		},
+		{
+			name:        "API ordering does not change tags_all",
+			apiTags:     []string{"app:web", "owner:ops"},
+			priorTags:   TagsToList([]string{"app:web"}),
+			priorAll:    TagsToList([]string{"owner:ops", "app:web"}),
+			wantTags:    TagsToList([]string{"app:web"}),
+			wantTagsAll: TagsToList([]string{"owner:ops", "app:web"}),
+		},
+		{
+			name:        "imported resource keeps every API tag",
+			apiTags:     []string{"owner:ops"},
+			priorTags:   types.ListNull(types.StringType),
+			priorAll:    types.ListNull(types.StringType),
+			wantTags:    TagsToList([]string{"owner:ops"}),
+			wantTagsAll: TagsToList([]string{"owner:ops"}),
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			tags, tagsAll := flattenTags(tc.apiTags, tc.priorTags, tc.priorAll)
+			if !tags.Equal(tc.wantTags) {
+				t.Errorf("tags = %s, want %s", tags, tc.wantTags)
+			}
+			if !tagsAll.Equal(tc.wantTagsAll) {
+				t.Errorf("tags_all = %s, want %s", tagsAll, tc.wantTagsAll)
+			}
+		})
+	}
+}
+
+func TestRequestTags(t *testing.T) {
+	ctx := context.Background()
+	client := &ArubaCloudClient{DefaultTags: []string{"owner:ops"}}
+
+	cases := []struct {
+		name        string
+		client      *ArubaCloudClient
+		tags        types.List
+		tagsAll     types.List
+		want        []string
+		wantTagsAll types.List
+	}{
+		{
+			name:        "planned tags_all is sent",
+			client:      client,
+			tags:        TagsToList([]string{"app:web"}),
+			tagsAll:     TagsToList([]string{"owner:ops", "app:web"}),
+			want:        []string{"owner:ops", "app:web"},
+			wantTagsAll: TagsToList([]string{"owner:ops", "app:web"}),
+		},
+		{
+			name:        "unknown tags_all is resolved from default_tags",
+			client:      client,
+			tags:        TagsToList([]string{"app:web"}),
+			tagsAll:     types.ListUnknown(types.StringType),
+			want:        []string{"owner:ops", "app:web"},
+			wantTagsAll: TagsToList([]string{"owner:ops", "app:web"}),
+		},
+		{
+			name:        "nothing configured keeps existing API tags",
+			client:      &ArubaCloudClient{},
+			tags:        types.ListNull(types.StringType),
+			tagsAll:     TagsToList(nil),
+			want:        nil,
+			wantTagsAll: TagsToList(nil),
+		},
+		{
+			name:        "null tags_all falls back to tags",
+			client:      nil,
+			tags:        TagsToList([]string{"app:web"}),
+			tagsAll:     types.ListNull(types.StringType),
+			want:        []string{"app:web"},
+			wantTagsAll: types.ListNull(types.StringType),
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			var d diag.Diagnostics
+			tagsAll := tc.tagsAll
+			got := tc.client.requestTags(ctx, tc.tags, &tagsAll, &d)
+			if d.HasError() {
+				t.Fatalf("unexpected diagnostics errors: %v", d)
+			}
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("requestTags() = %v, want %v", got, tc.want)
+			}
+			if !tagsAll.Equal(tc.wantTagsAll) {
+				t.Errorf("tags_all = %s, want %s", tagsAll, tc.wantTagsAll)
+			}
+		})
+	}
+}
+
+func TestPlanTagsAll(t *testing.T) {
+	ctx := context.Background()
+
+	s := schema.Schema{
+		Attributes: map[string]schema.Attribute{
+			"tags":     schema.ListAttribute{ElementType: types.StringType, Optional: true},
+			"tags_all": tagsAllAttribute(),
+		},
+	}
+	objType := s.Type().TerraformType(ctx).(tftypes.Object)
+	listType := tftypes.List{ElementType: tftypes.String}
+	tfList := func(tags ...string) tftypes.Value {
+		if tags == nil {
+			return tftypes.NewValue(listType, nil)
+		}
+		vals := make([]tftypes.Value, len(tags))
+		for i, tag := range tags {
+			vals[i] = tftypes.NewValue(tftypes.String, tag)
+		}
+		return tftypes.NewValue(listType, vals)
+	}
+	obj := func(tags, tagsAll tftypes.Value) tftypes.Value {
+		return tftypes.NewValue(objType, map[string]tftypes.Value{"tags": tags, "tags_all": tagsAll})
+	}
+	unknownAll := tftypes.NewValue(listType, tftypes.UnknownValue)
+	noState := tftypes.NewValue(objType, nil)
+
+	cases := []struct {
+		name      string
+		updatable bool
+		state     tftypes.Value
+		plan      tftypes.Value
+		want      types.List
+	}{
+		{
+			name:      "create merges default_tags",
+			updatable: true,
+			state:     noState,
+			plan:      obj(tfList("app:web"), unknownAll),
+			want:      TagsToList([]string{"owner:ops", "app:web"}),
+		},
+		{
+			name:      "unchanged tags in a different order keep the prior value",
+			updatable: true,
+			state:     obj(tfList("app:web"), tfList("app:web", "owner:ops")),
+			plan:      obj(tfList("app:web"), unknownAll),
+			want:      TagsToList([]string{"app:web", "owner:ops"}),
+		},
+		{
+			name:      "default_tags change is planned on updatable resources",
+			updatable: true,
+			state:     obj(tfList("app:web"), tfList("owner:dev", "app:web")),
+			plan:      obj(tfList("app:web"), unknownAll),
+			want:      TagsToList([]string{"owner:ops", "app:web"}),
+		},
+		{
+			name:      "default_tags change is ignored when tags cannot be updated",
+			updatable: false,
+			state:     obj(tfList("app:web"), tfList("owner:dev", "app:web")),
+			plan:      obj(tfList("app:web"), unknownAll),
+			want:      TagsToList([]string{"owner:dev", "app:web"}),
+		},
+		{
+			name:      "unknown tags leave tags_all unknown",
+			updatable: true,
+			state:     noState,
+			plan:      obj(tftypes.NewValue(listType, tftypes.UnknownValue), unknownAll),
+			want:      types.ListUnknown(types.StringType),
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			client := &ArubaCloudClient{DefaultTags: []string{"owner:ops"}}
+			req := resource.ModifyPlanRequest{
+				State: tfsdk.State{Raw: tc.state, Schema: s},
+				Plan:  tfsdk.Plan{Raw: tc.plan, Schema: s},
+			}
+			resp := &resource.ModifyPlanResponse{Plan: tfsdk.Plan{Raw: tc.plan, Schema: s}}
+			client.planTagsAll(ctx, req, resp, tc.updatable)
+			if resp.Diagnostics.HasError() {
+				t.Fatalf("planTagsAll() error: %v", resp.Diagnostics)
+			}
+			var got types.List
+			resp.Diagnostics.Append(resp.Plan.GetAttribute(ctx, path.Root("tags_all"), &got)...)
+			if !got.Equal(tc.want) {
+				t.Errorf("tags_all = %s, want %s", got, tc.want)
+			}
+		})
+	}
+}
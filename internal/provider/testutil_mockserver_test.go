@@ -8,6 +8,7 @@ import (
 	"time"
 
 	aruba "github.com/Arubacloud/sdk-go/pkg/aruba"
+	"github.com/Arubacloud/terraform-provider-arubacloud/internal/fakeapi"
 )
 
 // newMockArubaClient spins up a single httptest.Server that serves both the
@@ -60,6 +61,73 @@ func newMockArubaClientFast(t *testing.T, apiHandler http.HandlerFunc) (*httptes
 	return srv, client
 }
 
+// newMockArubaClientLimited is like newMockArubaClient but routes the SDK's
+// HTTP traffic through the provider's apiTransport, with a shared
+// requestLimiter built from the given max_concurrent_requests and
+// requests_per_second settings (0, 0 for no limits). Retries are disabled.
+func newMockArubaClientLimited(t *testing.T, apiHandler http.HandlerFunc, maxConcurrent int64, requestsPerSecond float64) (*httptest.Server, *ArubaCloudClient) {
+	t.Helper()
+	return newMockArubaClientTransport(t, apiHandler, newRequestLimiter(maxConcurrent, requestsPerSecond), noRetry)
+}
+
+// noRetry is a retryPolicy that sends every request exactly once.
+var noRetry = retryPolicy{maxAttempts: 1}
+
+// newMockArubaClientTransport is like newMockArubaClient but routes the SDK's
+// HTTP traffic through the provider's apiTransport with the given limiter
+// (nil for none) and retry policy.
+func newMockArubaClientTransport(t *testing.T, apiHandler http.HandlerFunc, limiter *requestLimiter, retry retryPolicy) (*httptest.Server, *ArubaCloudClient) {
+	t.Helper()
+	srv, client := newMockArubaClient(t, apiHandler)
+
+	opts := aruba.DefaultOptions("test-key", "test-secret").
+		WithBaseURL(srv.URL).
+		WithTokenIssuerURL(srv.URL + "/token").
+		WithCustomHTTPClient(newAPIHTTPClient(limiter, retry))
+
+	sdkClient, err := aruba.NewClient(opts)
+	if err != nil {
+		t.Fatalf("newMockArubaClientTransport: failed to create SDK client: %v", err)
+	}
+	client.Client = sdkClient
+	client.limiter = limiter
+	return srv, client
+}
+
+// newFakeArubaClient starts the stateful fake API of internal/fakeapi and
+// returns it with a client whose SDK traffic goes through the provider's
+// apiTransport, retrying reads three times without delay. Unlike the
+// handlers above, the fake keeps resources between requests, so a test can
+// run a full Create, Read, Update and Delete cycle. Wait-loop poll intervals
+// are shortened for the duration of the test.
+func newFakeArubaClient(t *testing.T, opts ...fakeapi.Option) (*fakeapi.Server, *ArubaCloudClient) {
+	t.Helper()
+	srv := fakeapi.New(opts...)
+	t.Cleanup(srv.Close)
+
+	oldActivePoll, oldDeletedPoll, oldDeleteRetry := waitForActivePollInterval, waitForDeletedPollInterval, deleteRetryBaseWait
+	waitForActivePollInterval, waitForDeletedPollInterval, deleteRetryBaseWait = time.Millisecond, time.Millisecond, time.Millisecond
+	t.Cleanup(func() {
+		waitForActivePollInterval, waitForDeletedPollInterval, deleteRetryBaseWait = oldActivePoll, oldDeletedPoll, oldDeleteRetry
+	})
+
+	retry := retryPolicy{maxAttempts: 3, baseDelay: time.Millisecond, maxDelay: time.Millisecond}
+	sdkOpts := aruba.DefaultOptions("test-key", "test-secret").
+		WithBaseURL(srv.URL).
+		WithTokenIssuerURL(srv.TokenURL()).
+		WithCustomHTTPClient(newAPIHTTPClient(nil, retry))
+	sdkClient, err := aruba.NewClient(sdkOpts)
+	if err != nil {
+		t.Fatalf("newFakeArubaClient: failed to create SDK client: %v", err)
+	}
+	return srv, &ArubaCloudClient{
+		ClientID:        "test-client-id",
+		ClientSecret:    "test-client-secret",
+		Client:          sdkClient,
+		ResourceTimeout: 10 * time.Second,
+	}
+}
+
 // apiError writes an RFC-7807 problem-details JSON body with the given HTTP
 // status code.  Pass statusCode 404 or 500 to exercise the two most common
 // API error branches in Read() methods.
@@ -0,0 +1,147 @@
+package provider
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// timeNow is the clock used to resolve HTTP-date Retry-After values.
+// Overridable in tests.
+var timeNow = time.Now
+
+// maxProblemBodySize bounds how much of an error response body is read when
+// the Retry-After delay or the request IDs are recorded in it.
+const maxProblemBodySize = 1 << 20
+
+// parseRetryAfter parses a Retry-After header value, which is either a number
+// of seconds or an HTTP date (RFC 9110 §10.2.3). A date in the past yields
+// zero. ok is false when the header is empty or malformed.
+func parseRetryAfter(value string, now time.Time) (time.Duration, bool) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0, false
+	}
+	if secs, err := strconv.ParseInt(value, 10, 64); err == nil {
+		if secs < 0 {
+			return 0, false
+		}
+		return time.Duration(secs) * time.Second, true
+	}
+	at, err := http.ParseTime(value)
+	if err != nil {
+		return 0, false
+	}
+	if d := at.Sub(now); d > 0 {
+		return d, true
+	}
+	return 0, true
+}
+
+// annotateRetryAfter records the Retry-After delay of a 429 response in its
+// RFC 7807 problem-details body, as the "retryAfter" extension member in
+// seconds. The SDK's HTTPError keeps the response body but not its headers,
+// so this is how the delay reaches CheckResponseErr.
+func annotateRetryAfter(resp *http.Response, d time.Duration) {
+	annotateProblem(resp, map[string]json.RawMessage{
+		"retryAfter": json.RawMessage(strconv.FormatFloat(d.Seconds(), 'f', -1, 64)),
+	})
+}
+
+// annotateProblem adds members to the RFC 7807 problem-details body of an
+// error response. A body that is not a JSON object is replaced with a
+// minimal problem document.
+func annotateProblem(resp *http.Response, members map[string]json.RawMessage) {
+	raw, _ := io.ReadAll(io.LimitReader(resp.Body, maxProblemBodySize))
+	_ = resp.Body.Close()
+
+	problem := map[string]json.RawMessage{}
+	if err := json.Unmarshal(raw, &problem); err != nil || problem == nil {
+		problem = map[string]json.RawMessage{
+			"title":  json.RawMessage(strconv.Quote(http.StatusText(resp.StatusCode))),
+			"status": json.RawMessage(strconv.Itoa(resp.StatusCode)),
+		}
+		resp.Header.Set("Content-Type", "application/problem+json")
+	}
+	for k, v := range members {
+		problem[k] = v
+	}
+
+	body, err := json.Marshal(problem)
+	if err != nil {
+		body = raw
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+	resp.ContentLength = int64(len(body))
+	resp.Header.Set("Content-Length", strconv.Itoa(len(body)))
+}
+
+// retryAfterFromBody returns the delay recorded by annotateRetryAfter, or
+// zero when the body carries none.
+func retryAfterFromBody(body []byte) time.Duration {
+	var problem struct {
+		RetryAfter *float64 `json:"retryAfter"`
+	}
+	if len(body) == 0 || json.Unmarshal(body, &problem) != nil || problem.RetryAfter == nil || *problem.RetryAfter <= 0 {
+		return 0
+	}
+	return time.Duration(*problem.RetryAfter * float64(time.Second))
+}
+
+// throttleGate holds back every request of a client once the API has
+// answered 429 with a Retry-After delay, so that concurrent operations stop
+// hammering the API instead of each discovering the throttling on its own.
+type throttleGate struct {
+	mu    sync.Mutex
+	until time.Time
+}
+
+// hold delays all requests until d from now, unless an earlier 429 already
+// asked for a longer pause.
+func (g *throttleGate) hold(ctx context.Context, d time.Duration) {
+	if g == nil || d <= 0 {
+		return
+	}
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if until := timeNow().Add(d); until.After(g.until) {
+		g.until = until
+	}
+	tflog.Debug(ctx, "API throttled a request (HTTP 429), pausing all requests", map[string]interface{}{
+		"retry_after": d.String(),
+	})
+}
+
+// wait blocks until the pause requested by the last 429 has passed, or ctx
+// is done.
+func (g *throttleGate) wait(ctx context.Context) error {
+	if g == nil {
+		return nil
+	}
+	g.mu.Lock()
+	wait := g.until.Sub(timeNow())
+	g.mu.Unlock()
+	if wait <= 0 {
+		return nil
+	}
+
+	tflog.Debug(ctx, "waiting for API throttling to clear", map[string]interface{}{
+		"wait": wait.String(),
+	})
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
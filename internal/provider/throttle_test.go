@@ -0,0 +1,248 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2026, 1, 2, 15, 4, 5, 0, time.UTC)
+	cases := []struct {
+		value  string
+		want   time.Duration
+		wantOK bool
+	}{
+		{"", 0, false},
+		{"  ", 0, false},
+		{"0", 0, true},
+		{"3", 3 * time.Second, true},
+		{" 120 ", 2 * time.Minute, true},
+		{"-1", 0, false},
+		{"soon", 0, false},
+		{now.Add(90 * time.Second).Format(http.TimeFormat), 90 * time.Second, true},
+		{now.Add(-time.Minute).Format(http.TimeFormat), 0, true},
+	}
+	for _, tc := range cases {
+		got, ok := parseRetryAfter(tc.value, now)
+		if got != tc.want || ok != tc.wantOK {
+			t.Errorf("parseRetryAfter(%q) = (%s, %v), want (%s, %v)", tc.value, got, ok, tc.want, tc.wantOK)
+		}
+	}
+}
+
+func TestAnnotateRetryAfter(t *testing.T) {
+	cases := map[string]string{
+		"problem details": `{"title":"Too Many Requests","status":429,"detail":"slow down"}`,
+		"empty body":      ``,
+		"plain text":      `rate limited`,
+	}
+	for name, body := range cases {
+		t.Run(name, func(t *testing.T) {
+			resp := &http.Response{
+				StatusCode: http.StatusTooManyRequests,
+				Header:     http.Header{},
+				Body:       io.NopCloser(strings.NewReader(body)),
+			}
+			annotateRetryAfter(resp, 1500*time.Millisecond)
+
+			raw, err := io.ReadAll(resp.Body)
+			if err != nil {
+				t.Fatalf("reading annotated body: %v", err)
+			}
+			if resp.ContentLength != int64(len(raw)) {
+				t.Errorf("ContentLength = %d, want %d", resp.ContentLength, len(raw))
+			}
+			var problem map[string]interface{}
+			if err := json.Unmarshal(raw, &problem); err != nil {
+				t.Fatalf("annotated body is not JSON: %s", raw)
+			}
+			if problem["status"] != float64(429) {
+				t.Errorf("status = %v, want 429", problem["status"])
+			}
+			if got := retryAfterFromBody(raw); got != 1500*time.Millisecond {
+				t.Errorf("retryAfterFromBody() = %s, want 1.5s", got)
+			}
+		})
+	}
+}
+
+func TestRetryAfterFromBody_Absent(t *testing.T) {
+	for _, body := range []string{"", "not json", `{"title":"x"}`, `{"retryAfter":-3}`} {
+		if got := retryAfterFromBody([]byte(body)); got != 0 {
+			t.Errorf("retryAfterFromBody(%q) = %s, want 0", body, got)
+		}
+	}
+}
+
+func TestThrottleGate_WaitHonoursContext(t *testing.T) {
+	g := &throttleGate{}
+	g.hold(context.Background(), time.Hour)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if err := g.wait(ctx); err == nil {
+		t.Fatal("wait() should return the context error while the gate is held")
+	}
+
+	var nilGate *throttleGate
+	if err := nilGate.wait(context.Background()); err != nil {
+		t.Errorf("nil gate wait() = %v, want nil", err)
+	}
+}
+
+// TestAPITransport_ReplaysShortRetryAfter checks that a 429 with a short
+// Retry-After is replayed by the transport, body included, once the delay
+// has passed.
+func TestAPITransport_ReplaysShortRetryAfter(t *testing.T) {
+	var calls int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if string(body) != `{"name":"vpc"}` {
+			t.Errorf("request %d body = %q", atomic.LoadInt32(&calls)+1, body)
+		}
+		if atomic.AddInt32(&calls, 1) == 1 {
+			w.Header().Set("Retry-After", "1")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+	}))
+	t.Cleanup(srv.Close)
+
+	start := time.Now()
+	resp, err := newAPIHTTPClient(nil, noRetry).Post(srv.URL, "application/json", strings.NewReader(`{"name":"vpc"}`))
+	if err != nil {
+		t.Fatalf("POST error: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusCreated {
+		t.Fatalf("status = %d, want 201 after the replay", resp.StatusCode)
+	}
+	if n := atomic.LoadInt32(&calls); n != 2 {
+		t.Errorf("server calls = %d, want 2", n)
+	}
+	if elapsed := time.Since(start); elapsed < 900*time.Millisecond {
+		t.Errorf("replayed after %s, want it held back by Retry-After", elapsed)
+	}
+}
+
+// TestAPITransport_ReturnsLongRetryAfter checks that a Retry-After too long
+// to replay reaches the caller annotated with the delay, and that the next
+// request through the same transport still waits for it.
+func TestAPITransport_ReturnsLongRetryAfter(t *testing.T) {
+	orig := maxThrottleReplayWait
+	maxThrottleReplayWait = 0
+	t.Cleanup(func() { maxThrottleReplayWait = orig })
+
+	var calls int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) == 1 {
+			w.Header().Set("Retry-After", "1")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	t.Cleanup(srv.Close)
+
+	client := newAPIHTTPClient(nil, noRetry)
+
+	resp, err := client.Get(srv.URL)
+	if err != nil {
+		t.Fatalf("first GET error: %v", err)
+	}
+	raw, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusTooManyRequests {
+		t.Fatalf("status = %d, want 429", resp.StatusCode)
+	}
+	if got := retryAfterFromBody(raw); got != time.Second {
+		t.Errorf("annotated retryAfter = %s, want 1s", got)
+	}
+
+	start := time.Now()
+	resp, err = client.Get(srv.URL)
+	if err != nil {
+		t.Fatalf("second GET error: %v", err)
+	}
+	resp.Body.Close()
+	if elapsed := time.Since(start); elapsed < 900*time.Millisecond {
+		t.Errorf("second request started after %s, want it held back by Retry-After", elapsed)
+	}
+}
+
+// TestAPITransport_CapsRetryAfterForEveryMethod checks that no method waits
+// out a Retry-After longer than maxThrottleReplayWait: the 429 is returned at
+// once so that the caller can weigh the delay against its own deadline.
+func TestAPITransport_CapsRetryAfterForEveryMethod(t *testing.T) {
+	orig := maxThrottleReplayWait
+	maxThrottleReplayWait = 0
+	t.Cleanup(func() { maxThrottleReplayWait = orig })
+
+	for _, method := range []string{http.MethodGet, http.MethodPut, http.MethodPatch, http.MethodPost, http.MethodDelete} {
+		t.Run(method, func(t *testing.T) {
+			var calls int32
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				atomic.AddInt32(&calls, 1)
+				w.Header().Set("Retry-After", "1")
+				w.WriteHeader(http.StatusTooManyRequests)
+			}))
+			t.Cleanup(srv.Close)
+
+			req, _ := http.NewRequest(method, srv.URL, nil)
+			start := time.Now()
+			resp, err := newAPIHTTPClient(nil, noRetry).Do(req)
+			if err != nil {
+				t.Fatalf("%s error: %v", method, err)
+			}
+			resp.Body.Close()
+			if resp.StatusCode != http.StatusTooManyRequests || atomic.LoadInt32(&calls) != 1 {
+				t.Errorf("status = %d after %d calls, want the 429 returned without a replay", resp.StatusCode, calls)
+			}
+			if elapsed := time.Since(start); elapsed >= 900*time.Millisecond {
+				t.Errorf("%s returned after %s, want it not to wait out Retry-After", method, elapsed)
+			}
+		})
+	}
+}
+
+// TestAPITransport_RetriesThrottleWithoutRetryAfter checks that a read
+// throttled without a Retry-After header backs off per the retry policy,
+// while a create is returned to its caller.
+func TestAPITransport_RetriesThrottleWithoutRetryAfter(t *testing.T) {
+	var calls int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1)%2 == 1 {
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	t.Cleanup(srv.Close)
+	client := newAPIHTTPClient(nil, fastRetry)
+
+	resp, err := client.Get(srv.URL)
+	if err != nil {
+		t.Fatalf("GET error: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK || atomic.LoadInt32(&calls) != 2 {
+		t.Fatalf("GET status = %d after %d calls, want 200 after one retry", resp.StatusCode, calls)
+	}
+
+	resp, err = client.Post(srv.URL, "application/json", strings.NewReader(`{}`))
+	if err != nil {
+		t.Fatalf("POST error: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusTooManyRequests || atomic.LoadInt32(&calls) != 3 {
+		t.Errorf("POST status = %d after %d calls, want the 429 returned", resp.StatusCode, calls)
+	}
+}
@@ -0,0 +1,248 @@
+package provider
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// tokenRefreshSkew is how long before its expiry a cached token is refreshed.
+// Until it actually expires, the cached token is still served when the
+// refresh fails, so a short token-issuer outage does not fail the run.
+var tokenRefreshSkew = 5 * time.Minute
+
+// tokenLockTimeout bounds the wait for another provider process holding the
+// lock of a cache entry; past it the token is fetched without the cache.
+var tokenLockTimeout = 10 * time.Second
+
+// staleTokenLockAge is the age past which a lock file is considered left
+// behind by a crashed process and removed.
+const staleTokenLockAge = 30 * time.Second
+
+// tokenCache stores OAuth2 tokens in a directory shared by provider
+// processes, one file per client ID and token issuer, so that consecutive
+// validate, plan and apply runs reuse a token instead of each requesting
+// one. Files are written with 0600 permissions; a lock file per entry
+// serialises processes refreshing the same token.
+type tokenCache struct {
+	dir string
+}
+
+// cachedToken is the content of a token cache file.
+type cachedToken struct {
+	AccessToken string    `json:"access_token"`
+	TokenType   string    `json:"token_type"`
+	Expiry      time.Time `json:"expiry"`
+}
+
+// newTokenCache returns a cache in dir, creating it with 0700 permissions.
+func newTokenCache(dir string) (*tokenCache, error) {
+	dir, err := expandHome(dir)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, fmt.Errorf("cannot create token cache directory: %w", err)
+	}
+	return &tokenCache{dir: dir}, nil
+}
+
+// tokenCacheKey identifies the cache entry of a token request: the client ID,
+// taken from the form or from HTTP basic authentication, and the issuer URL.
+// It reports false when the request has no client ID.
+func tokenCacheKey(req *http.Request, form url.Values) (string, bool) {
+	clientID := form.Get("client_id")
+	if user, _, ok := req.BasicAuth(); ok && user != "" {
+		clientID = user
+	}
+	if clientID == "" {
+		return "", false
+	}
+	issuer := *req.URL
+	issuer.RawQuery, issuer.Fragment = "", ""
+	sum := sha256.Sum256([]byte(issuer.String() + "\x00" + clientID))
+	return hex.EncodeToString(sum[:]), true
+}
+
+// roundTrip answers the token request req from the cache, or sends it with
+// next and caches the response. Cache failures are logged and never fail
+// the request.
+func (c *tokenCache) roundTrip(req *http.Request, next func(*http.Request) (*http.Response, error)) (*http.Response, error) {
+	ctx := req.Context()
+	body, err := readRequestBody(req)
+	if err != nil {
+		return nil, err
+	}
+	form, _ := url.ParseQuery(string(body))
+	key, ok := tokenCacheKey(req, form)
+	if !ok || form.Get("grant_type") != "client_credentials" {
+		return next(req)
+	}
+	file := filepath.Join(c.dir, key+".json")
+
+	if token, ok := c.load(ctx, file); ok && timeNow().Add(tokenRefreshSkew).Before(token.Expiry) {
+		tflog.Debug(ctx, "Using cached ArubaCloud token", map[string]interface{}{"expiry": token.Expiry.Format(time.RFC3339)})
+		return token.response(req), nil
+	}
+
+	unlock, locked := c.lock(ctx, file)
+	defer unlock()
+	if locked {
+		// Another process may have refreshed the token while we waited.
+		if token, ok := c.load(ctx, file); ok && timeNow().Add(tokenRefreshSkew).Before(token.Expiry) {
+			return token.response(req), nil
+		}
+	}
+
+	resp, err := next(req)
+	if err != nil || resp.StatusCode >= 500 {
+		// Ride out an issuer outage with a token that has not expired yet.
+		if token, ok := c.load(ctx, file); ok && timeNow().Before(token.Expiry) {
+			tflog.Warn(ctx, "Token issuer unavailable, using cached ArubaCloud token", map[string]interface{}{
+				"expiry": token.Expiry.Format(time.RFC3339),
+			})
+			if resp != nil {
+				_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, maxProblemBodySize))
+				_ = resp.Body.Close()
+			}
+			return token.response(req), nil
+		}
+		return resp, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return resp, nil
+	}
+
+	raw, err := io.ReadAll(io.LimitReader(resp.Body, maxProblemBodySize))
+	_ = resp.Body.Close()
+	resp.Body = io.NopCloser(bytes.NewReader(raw))
+	if err != nil {
+		return nil, err
+	}
+	var issued struct {
+		AccessToken string      `json:"access_token"`
+		TokenType   string      `json:"token_type"`
+		ExpiresIn   json.Number `json:"expires_in"`
+	}
+	if json.Unmarshal(raw, &issued) != nil || issued.AccessToken == "" {
+		return resp, nil
+	}
+	seconds, err := issued.ExpiresIn.Int64()
+	if err != nil || seconds <= 0 {
+		// Without a lifetime the token cannot be reused safely.
+		return resp, nil
+	}
+	c.store(ctx, file, cachedToken{
+		AccessToken: issued.AccessToken,
+		TokenType:   issued.TokenType,
+		Expiry:      timeNow().Add(time.Duration(seconds) * time.Second),
+	})
+	return resp, nil
+}
+
+// readRequestBody reads the body of req and replaces it so that req can
+// still be sent.
+func readRequestBody(req *http.Request) ([]byte, error) {
+	if req.Body == nil || req.Body == http.NoBody {
+		return nil, nil
+	}
+	body, err := io.ReadAll(req.Body)
+	_ = req.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	req.Body = io.NopCloser(bytes.NewReader(body))
+	req.GetBody = func() (io.ReadCloser, error) { return io.NopCloser(bytes.NewReader(body)), nil }
+	return body, nil
+}
+
+// load reads a cache entry. A missing, unreadable or corrupt entry is a miss.
+func (c *tokenCache) load(ctx context.Context, file string) (cachedToken, bool) {
+	raw, err := os.ReadFile(file)
+	if err != nil {
+		if !errors.Is(err, fs.ErrNotExist) {
+			tflog.Warn(ctx, "Cannot read the token cache", map[string]interface{}{"file": file, "error": err.Error()})
+		}
+		return cachedToken{}, false
+	}
+	var token cachedToken
+	if err := json.Unmarshal(raw, &token); err != nil || token.AccessToken == "" {
+		return cachedToken{}, false
+	}
+	return token, true
+}
+
+// store writes a cache entry with 0600 permissions. The entry is written to
+// a temporary file and renamed, so readers never see a partial token.
+func (c *tokenCache) store(ctx context.Context, file string, token cachedToken) {
+	raw, _ := json.Marshal(token)
+	tmp, err := os.CreateTemp(c.dir, ".token-*")
+	if err == nil {
+		_, err = tmp.Write(raw)
+		if closeErr := tmp.Close(); err == nil {
+			err = closeErr
+		}
+		if err == nil {
+			// CreateTemp uses 0600 already; state it rather than rely on it.
+			err = os.Chmod(tmp.Name(), 0o600)
+		}
+		if err == nil {
+			err = os.Rename(tmp.Name(), file)
+		}
+		if err != nil {
+			_ = os.Remove(tmp.Name())
+		}
+	}
+	if err != nil {
+		tflog.Warn(ctx, "Cannot write the token cache", map[string]interface{}{"file": file, "error": err.Error()})
+	}
+}
+
+// lock takes the lock of a cache entry, waiting for another process holding
+// it. It reports false, with a no-op unlock, when the lock cannot be taken
+// within tokenLockTimeout.
+func (c *tokenCache) lock(ctx context.Context, file string) (func(), bool) {
+	lockFile := file + ".lock"
+	deadline := timeNow().Add(tokenLockTimeout)
+	for {
+		f, err := os.OpenFile(lockFile, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o600)
+		if err == nil {
+			_ = f.Close()
+			return func() { _ = os.Remove(lockFile) }, true
+		}
+		if !errors.Is(err, fs.ErrExist) {
+			tflog.Warn(ctx, "Cannot lock the token cache", map[string]interface{}{"file": lockFile, "error": err.Error()})
+			return func() {}, false
+		}
+		if info, statErr := os.Stat(lockFile); statErr == nil && timeNow().Sub(info.ModTime()) > staleTokenLockAge {
+			_ = os.Remove(lockFile)
+			continue
+		}
+		if !timeNow().Before(deadline) || !sleepCtx(ctx, 50*time.Millisecond) {
+			tflog.Warn(ctx, "Timed out waiting for the token cache lock", map[string]interface{}{"file": lockFile})
+			return func() {}, false
+		}
+	}
+}
+
+// response answers a token request with the cached token.
+func (t cachedToken) response(req *http.Request) *http.Response {
+	tokenType := t.TokenType
+	if tokenType == "" {
+		tokenType = "Bearer"
+	}
+	return tokenJSONResponse(req, t.AccessToken, tokenType, t.Expiry.Sub(timeNow()))
+}
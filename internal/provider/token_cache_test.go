@@ -0,0 +1,251 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	providerframe "github.com/hashicorp/terraform-plugin-framework/provider"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+// tokenIssuer is a fake OAuth2 token endpoint that counts the tokens it
+// issues. While down is set it answers 503.
+type tokenIssuer struct {
+	*httptest.Server
+	issued int32
+	down   atomic.Bool
+}
+
+func newTokenIssuer(t *testing.T) *tokenIssuer {
+	t.Helper()
+	issuer := &tokenIssuer{}
+	issuer.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if issuer.down.Load() {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		time.Sleep(20 * time.Millisecond)
+		n := atomic.AddInt32(&issuer.issued, 1)
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, `{"access_token":"token-%d","token_type":"Bearer","expires_in":3600}`, n)
+	}))
+	t.Cleanup(issuer.Close)
+	return issuer
+}
+
+// requestToken sends a client-credentials request through a fresh transport
+// using the cache in dir, as a separate provider process would, and returns
+// the access token.
+func requestToken(t *testing.T, dir, issuerURL, clientID string) (string, int) {
+	t.Helper()
+	cache, err := newTokenCache(dir)
+	if err != nil {
+		t.Errorf("newTokenCache() error: %v", err)
+		return "", 0
+	}
+	transport := newAPITransport(nil, noRetry)
+	transport.tokenCache = cache
+	resp, err := (&http.Client{Transport: transport}).PostForm(issuerURL, url.Values{
+		"grant_type":    {"client_credentials"},
+		"client_id":     {clientID},
+		"client_secret": {"secret"},
+	})
+	if err != nil {
+		t.Errorf("token request error: %v", err)
+		return "", 0
+	}
+	defer resp.Body.Close()
+	var token struct {
+		AccessToken string `json:"access_token"`
+	}
+	_ = json.NewDecoder(resp.Body).Decode(&token)
+	return token.AccessToken, resp.StatusCode
+}
+
+func TestTokenCache_SharedAcrossProcesses(t *testing.T) {
+	issuer := newTokenIssuer(t)
+	dir := filepath.Join(t.TempDir(), "tokens")
+
+	first, _ := requestToken(t, dir, issuer.URL, "client-a")
+	second, _ := requestToken(t, dir, issuer.URL, "client-a")
+	if first != "token-1" || second != "token-1" {
+		t.Errorf("tokens = %q, %q, want the first one reused", first, second)
+	}
+	if other, _ := requestToken(t, dir, issuer.URL, "client-b"); other != "token-2" {
+		t.Errorf("client-b token = %q, want its own token", other)
+	}
+	if other, _ := requestToken(t, dir, issuer.URL+"/other-realm", "client-a"); other != "token-3" {
+		t.Errorf("other issuer token = %q, want its own token", other)
+	}
+
+	if runtime.GOOS != "windows" {
+		info, err := os.Stat(dir)
+		if err != nil || info.Mode().Perm() != 0o700 {
+			t.Errorf("cache directory mode = %v (%v), want 0700", info.Mode().Perm(), err)
+		}
+		entries, _ := filepath.Glob(filepath.Join(dir, "*.json"))
+		if len(entries) != 3 {
+			t.Fatalf("cache entries = %v, want 3", entries)
+		}
+		for _, entry := range entries {
+			if info, err := os.Stat(entry); err != nil || info.Mode().Perm() != 0o600 {
+				t.Errorf("%s mode = %v (%v), want 0600", entry, info.Mode().Perm(), err)
+			}
+		}
+	}
+}
+
+func TestTokenCache_ConcurrentProcessesFetchOnce(t *testing.T) {
+	issuer := newTokenIssuer(t)
+	dir := t.TempDir()
+
+	var wg sync.WaitGroup
+	tokens := make([]string, 6)
+	for i := range tokens {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			tokens[i], _ = requestToken(t, dir, issuer.URL, "client-a")
+		}(i)
+	}
+	wg.Wait()
+
+	if n := atomic.LoadInt32(&issuer.issued); n != 1 {
+		t.Errorf("issuer issued %d tokens, want 1", n)
+	}
+	for i, token := range tokens {
+		if token != "token-1" {
+			t.Errorf("request %d got %q, want token-1", i, token)
+		}
+	}
+}
+
+func TestTokenCache_Expiry(t *testing.T) {
+	issuer := newTokenIssuer(t)
+	dir := t.TempDir()
+	requestToken(t, dir, issuer.URL, "client-a")
+
+	orig := timeNow
+	t.Cleanup(func() { timeNow = orig })
+
+	// Within the refresh window the token is refreshed.
+	refreshed := time.Hour - tokenRefreshSkew + time.Minute
+	timeNow = func() time.Time { return time.Now().Add(refreshed) }
+	if token, _ := requestToken(t, dir, issuer.URL, "client-a"); token != "token-2" {
+		t.Errorf("token = %q, want a refreshed token", token)
+	}
+
+	// If the issuer is down, the cached token is used until it expires.
+	issuer.down.Store(true)
+	timeNow = func() time.Time { return time.Now().Add(refreshed + time.Hour - time.Minute) }
+	if token, status := requestToken(t, dir, issuer.URL, "client-a"); token != "token-2" || status != http.StatusOK {
+		t.Errorf("during outage got %q (%d), want the cached token-2", token, status)
+	}
+	timeNow = func() time.Time { return time.Now().Add(3 * time.Hour) }
+	if _, status := requestToken(t, dir, issuer.URL, "client-a"); status != http.StatusServiceUnavailable {
+		t.Errorf("status = %d, want the issuer error once the cached token expired", status)
+	}
+}
+
+func TestTokenCache_Lock(t *testing.T) {
+	issuer := newTokenIssuer(t)
+	dir := t.TempDir()
+	cache, _ := newTokenCache(dir)
+
+	form := url.Values{"grant_type": {"client_credentials"}, "client_id": {"client-a"}}
+	req, _ := http.NewRequest(http.MethodPost, issuer.URL, nil)
+	key, _ := tokenCacheKey(req, form)
+	lockFile := filepath.Join(dir, key+".json.lock")
+
+	// A lock held by another process delays the request, then the token is
+	// fetched without it.
+	origTimeout := tokenLockTimeout
+	tokenLockTimeout = 100 * time.Millisecond
+	t.Cleanup(func() { tokenLockTimeout = origTimeout })
+	if err := os.WriteFile(lockFile, nil, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	start := time.Now()
+	if token, _ := requestToken(t, dir, issuer.URL, "client-a"); token != "token-1" {
+		t.Errorf("token = %q, want token-1", token)
+	}
+	if elapsed := time.Since(start); elapsed < 100*time.Millisecond {
+		t.Errorf("request took %s, want it to wait for the lock", elapsed)
+	}
+
+	// A lock left behind by a crashed process is removed.
+	old := time.Now().Add(-time.Minute)
+	if err := os.Chtimes(lockFile, old, old); err != nil {
+		t.Fatal(err)
+	}
+	unlock, locked := cache.lock(req.Context(), filepath.Join(dir, key+".json"))
+	if !locked {
+		t.Fatal("stale lock was not taken over")
+	}
+	unlock()
+	if _, err := os.Stat(lockFile); !os.IsNotExist(err) {
+		t.Errorf("lock file still present after unlock: %v", err)
+	}
+}
+
+func TestTokenCacheKey_BasicAuth(t *testing.T) {
+	req, _ := http.NewRequest(http.MethodPost, "https://login.example.com/token?x=1", nil)
+	req.SetBasicAuth("client-a", "secret")
+	fromBasic, ok := tokenCacheKey(req, url.Values{})
+	if !ok {
+		t.Fatal("no key for a basic-auth token request")
+	}
+	req, _ = http.NewRequest(http.MethodPost, "https://login.example.com/token", nil)
+	fromForm, _ := tokenCacheKey(req, url.Values{"client_id": {"client-a"}})
+	if fromBasic != fromForm {
+		t.Error("the same client and issuer should share a cache entry")
+	}
+	if _, ok := tokenCacheKey(req, url.Values{}); ok {
+		t.Error("a request without client ID should not be cached")
+	}
+}
+
+// TestProviderConfigure_TokenCacheDir verifies that Configure() creates the
+// token cache directory and reports one it cannot create.
+func TestProviderConfigure_TokenCacheDir(t *testing.T) {
+	ctx := context.Background()
+	p := newTestProvider(t)
+	creds := map[string]tftypes.Value{
+		"client_id":     tftypes.NewValue(tftypes.String, "test-key"),
+		"client_secret": tftypes.NewValue(tftypes.String, "test-secret"),
+	}
+
+	dir := filepath.Join(t.TempDir(), "tokens")
+	t.Setenv("ARUBACLOUD_TOKEN_CACHE_DIR", dir)
+	resp := &providerframe.ConfigureResponse{}
+	p.Configure(ctx, providerframe.ConfigureRequest{Config: buildProviderConfig(t, p, creds)}, resp)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("unexpected error from Configure(): %v", resp.Diagnostics)
+	}
+	if _, err := os.Stat(dir); err != nil {
+		t.Errorf("token cache directory not created: %v", err)
+	}
+
+	file := filepath.Join(t.TempDir(), "file")
+	if err := os.WriteFile(file, nil, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	creds["token_cache_dir"] = tftypes.NewValue(tftypes.String, filepath.Join(file, "tokens"))
+	resp = &providerframe.ConfigureResponse{}
+	p.Configure(ctx, providerframe.ConfigureRequest{Config: buildProviderConfig(t, p, creds)}, resp)
+	errs := resp.Diagnostics.Errors()
+	if len(errs) != 1 || errs[0].Summary() != "Invalid token_cache_dir" {
+		t.Errorf("expected one Invalid token_cache_dir error, got %v", resp.Diagnostics)
+	}
+}
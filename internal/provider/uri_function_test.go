@@ -0,0 +1,144 @@
+package provider
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// runFunction invokes fn with args and returns the result value and error.
+func runFunction(t *testing.T, fn function.Function, args ...attr.Value) (attr.Value, *function.FuncError) {
+	t.Helper()
+	ctx := context.Background()
+
+	defResp := &function.DefinitionResponse{}
+	fn.Definition(ctx, function.DefinitionRequest{}, defResp)
+
+	resp := &function.RunResponse{
+		Result: function.NewResultData(defResp.Definition.Return.GetType().ValueType(ctx)),
+	}
+	fn.Run(ctx, function.RunRequest{Arguments: function.NewArgumentsData(args)}, resp)
+	return resp.Result.Value(), resp.Error
+}
+
+func TestParseURIFunction(t *testing.T) {
+	got, funcErr := runFunction(t, NewParseURIFunction(),
+		types.StringValue("/projects/p1/providers/Aruba.Network/vpcs/v1/subnets/s1"))
+	if funcErr != nil {
+		t.Fatalf("unexpected error: %s", funcErr)
+	}
+
+	obj, ok := got.(types.Object)
+	if !ok {
+		t.Fatalf("expected types.Object, got %T", got)
+	}
+	attrs := obj.Attributes()
+	for name, want := range map[string]string{
+		"project_id": "p1",
+		"provider":   "Aruba.Network",
+		"kind":       "subnet",
+		"id":         "s1",
+	} {
+		if attrs[name].(types.String).ValueString() != want {
+			t.Errorf("%s = %s, want %q", name, attrs[name], want)
+		}
+	}
+	parents := attrs["parents"].(types.List).Elements()
+	if len(parents) != 1 {
+		t.Fatalf("expected 1 parent, got %d", len(parents))
+	}
+	parent := parents[0].(types.Object).Attributes()
+	if parent["kind"].(types.String).ValueString() != "vpc" || parent["id"].(types.String).ValueString() != "v1" {
+		t.Errorf("unexpected parent %v", parent)
+	}
+}
+
+func TestParseURIFunction_ProjectHasNullProvider(t *testing.T) {
+	got, funcErr := runFunction(t, NewParseURIFunction(), types.StringValue("/projects/p1"))
+	if funcErr != nil {
+		t.Fatalf("unexpected error: %s", funcErr)
+	}
+	attrs := got.(types.Object).Attributes()
+	if !attrs["provider"].IsNull() {
+		t.Errorf("provider = %s, want null", attrs["provider"])
+	}
+	if len(attrs["parents"].(types.List).Elements()) != 0 {
+		t.Errorf("expected no parents, got %s", attrs["parents"])
+	}
+}
+
+func TestParseURIFunction_Invalid(t *testing.T) {
+	_, funcErr := runFunction(t, NewParseURIFunction(), types.StringValue("not-a-uri"))
+	if funcErr == nil {
+		t.Fatal("expected an error for an invalid URI")
+	}
+	if funcErr.FunctionArgument == nil || *funcErr.FunctionArgument != 0 {
+		t.Errorf("expected the error to point at argument 0, got %v", funcErr.FunctionArgument)
+	}
+}
+
+func TestBuildURIFunction(t *testing.T) {
+	parents, _ := types.TupleValue([]attr.Type{types.StringType, types.StringType},
+		[]attr.Value{types.StringValue("v1"), types.StringValue("sg1")})
+	got, funcErr := runFunction(t, NewBuildURIFunction(),
+		types.StringValue("securityrule"), types.StringValue("p1"), types.StringValue("r1"), parents)
+	if funcErr != nil {
+		t.Fatalf("unexpected error: %s", funcErr)
+	}
+	want := "/projects/p1/providers/Aruba.Network/vpcs/v1/securityGroups/sg1/securityRules/r1"
+	if got.(types.String).ValueString() != want {
+		t.Errorf("got %s, want %q", got, want)
+	}
+}
+
+func TestBuildURIFunction_WrongParentCount(t *testing.T) {
+	noParents, _ := types.TupleValue([]attr.Type{}, []attr.Value{})
+	_, funcErr := runFunction(t, NewBuildURIFunction(),
+		types.StringValue("subnet"), types.StringValue("p1"), types.StringValue("s1"), noParents)
+	if funcErr == nil {
+		t.Fatal("expected an error when parent IDs are missing")
+	}
+}
+
+func TestIsURIFunction(t *testing.T) {
+	cases := map[string]bool{
+		"/projects/p1/providers/Aruba.Compute/cloudServers/s1": true,
+		"/projects/p1": true,
+		"":             false,
+		"vpc-123":      false,
+	}
+	for in, want := range cases {
+		got, funcErr := runFunction(t, NewIsURIFunction(), types.StringValue(in))
+		if funcErr != nil {
+			t.Fatalf("is_uri(%q): unexpected error: %s", in, funcErr)
+		}
+		if got.(types.Bool).ValueBool() != want {
+			t.Errorf("is_uri(%q) = %s, want %t", in, got, want)
+		}
+	}
+}
+
+// TestProviderFunctions verifies the URI functions are registered under the
+// names documented for provider::arubacloud::<name>.
+func TestProviderFunctions(t *testing.T) {
+	ctx := context.Background()
+	want := map[string]bool{"parse_uri": false, "build_uri": false, "is_uri": false}
+
+	for _, newFn := range newTestProvider(t).Functions(ctx) {
+		resp := &function.MetadataResponse{}
+		newFn().Metadata(ctx, function.MetadataRequest{}, resp)
+		if _, ok := want[resp.Name]; !ok {
+			t.Errorf("unexpected function %q", resp.Name)
+			continue
+		}
+		want[resp.Name] = true
+	}
+	for name, found := range want {
+		if !found {
+			t.Errorf("function %q is not registered", name)
+		}
+	}
+}
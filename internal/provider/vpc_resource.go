@@ -6,6 +6,7 @@ import (
 	"time"
 
 	aruba "github.com/Arubacloud/sdk-go/pkg/aruba"
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
@@ -20,16 +21,19 @@ type VPCResource struct {
 }
 
 type VPCResourceModel struct {
-	Id        types.String `tfsdk:"id"`
-	Uri       types.String `tfsdk:"uri"`
-	Name      types.String `tfsdk:"name"`
-	Location  types.String `tfsdk:"location"`
-	ProjectID types.String `tfsdk:"project_id"`
-	Tags      types.List   `tfsdk:"tags"`
-	Timeout   types.String `tfsdk:"timeout"`
+	Id        types.String   `tfsdk:"id"`
+	Uri       types.String   `tfsdk:"uri"`
+	Name      types.String   `tfsdk:"name"`
+	Location  types.String   `tfsdk:"location"`
+	ProjectID types.String   `tfsdk:"project_id"`
+	Tags      types.List     `tfsdk:"tags"`
+	TagsAll   types.List     `tfsdk:"tags_all"`
+	Timeout   types.String   `tfsdk:"timeout"`
+	Timeouts  timeouts.Value `tfsdk:"timeouts"`
 }
 
 var _ resource.Resource = &VPCResource{}
+var _ resource.ResourceWithModifyPlan = &VPCResource{}
 var _ resource.ResourceWithImportState = &VPCResource{}
 
 func NewVPCResource() resource.Resource {
@@ -59,25 +63,38 @@ func (r *VPCResource) Schema(ctx context.Context, req resource.SchemaRequest, re
 				Required:            true,
 			},
 			"location": schema.StringAttribute{
-				MarkdownDescription: "Region identifier for the resource (e.g., `ITBG-Bergamo`). Changing this value forces a new resource.",
-				Required:            true,
-				PlanModifiers:       []planmodifier.String{stringplanmodifier.RequiresReplace()},
+				MarkdownDescription: defaultedDescription("Region identifier for the resource (e.g., `ITBG-Bergamo`). Changing this value forces a new resource.", "location"),
+				Optional:            true,
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+					stringplanmodifier.RequiresReplace(),
+				},
 			},
 			"project_id": schema.StringAttribute{
-				MarkdownDescription: "ID of the project that owns this resource. Changing this value forces a new resource.",
-				Required:            true,
-				PlanModifiers:       []planmodifier.String{stringplanmodifier.RequiresReplace()},
+				MarkdownDescription: defaultedDescription("ID of the project that owns this resource. Changing this value forces a new resource.", "project_id"),
+				Optional:            true,
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+					stringplanmodifier.RequiresReplace(),
+				},
 			},
 			"tags": schema.ListAttribute{
 				ElementType:         types.StringType,
 				MarkdownDescription: "List of string tags attached to the resource for filtering and organisation.",
 				Optional:            true,
 			},
+			"tags_all": tagsAllAttribute(),
 			"timeout": schema.StringAttribute{
-				MarkdownDescription: "Per-resource timeout override (e.g. `\"15m\"`, `\"1h\"`). Overrides the provider-level `resource_timeout` for this resource's Create and Delete operations. Uses Go duration syntax.",
+				MarkdownDescription: "Per-resource timeout override (e.g. `\"15m\"`, `\"1h\"`) applied to every operation not set in the `timeouts` block. Overrides the provider-level `resource_timeout`. Uses Go duration syntax. Deprecated — use the `timeouts` block instead.",
+				DeprecationMessage:  "Use the timeouts block, which sets the create, read, update and delete timeouts individually.",
 				Optional:            true,
 			},
 		},
+		Blocks: map[string]schema.Block{
+			"timeouts": timeouts.BlockAll(ctx),
+		},
 	}
 }
 
@@ -94,15 +111,30 @@ func (r *VPCResource) Configure(ctx context.Context, req resource.ConfigureReque
 	r.client = client
 }
 
+func (r *VPCResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	r.client.planTagsAll(ctx, req, resp, true)
+	r.client.planProviderDefaults(ctx, req, resp,
+		providerDefault{attr: "project_id", replace: true},
+		providerDefault{attr: "location", replace: true},
+	)
+}
+
 func (r *VPCResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	ctx = withCorrelationID(ctx)
 	var data VPCResourceModel
 	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	timeout := r.client.operationTimeout(ctx, data.Timeouts.Create, data.Timeout, &resp.Diagnostics)
 	if resp.Diagnostics.HasError() {
 		return
 	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	if !r.client.checkGuardrails("create", "VPC", data.ProjectID, &resp.Diagnostics) {
+		return
+	}
 
 	projectID := data.ProjectID.ValueString()
-	tags := ListToTags(ctx, data.Tags, &resp.Diagnostics)
+	tags := r.client.requestTags(ctx, data.Tags, &data.TagsAll, &resp.Diagnostics)
 	if resp.Diagnostics.HasError() {
 		return
 	}
@@ -110,14 +142,14 @@ func (r *VPCResource) Create(ctx context.Context, req resource.CreateRequest, re
 	vpc, err := r.client.Client.FromNetwork().VPCs().Create(ctx,
 		aruba.NewVPC().
 			Named(data.Name.ValueString()).
-			InProject(aruba.URI("/projects/"+projectID)).
+			InProject(newResourceURI(uriKindProject, projectID, "").Ref()).
 			InRegion(aruba.Region(data.Location.ValueString())).
 			NotDefault().
 			WithoutPreset().
 			Tagged(tags...),
 	)
 	if provErr := CheckResponseErr("create", "VPC", err); provErr != nil {
-		resp.Diagnostics.AddError("API Error", provErr.Error())
+		AppendAPIError(&resp.Diagnostics, provErr)
 		return
 	}
 
@@ -129,7 +161,7 @@ func (r *VPCResource) Create(ctx context.Context, req resource.CreateRequest, re
 		return
 	}
 
-	if waitErr := vpc.WaitUntilReady(ctx, sdkWaitOptions(effectiveTimeout(data.Timeout, r.client.ResourceTimeout))...); waitErr != nil {
+	if waitErr := vpc.WaitUntilReady(ctx, sdkWaitOptions(timeout)...); waitErr != nil {
 		ReportWaitResult(&resp.Diagnostics, waitErr, "VPC", data.Id.ValueString())
 		return
 	}
@@ -147,17 +179,14 @@ func (r *VPCResource) Create(ctx context.Context, req resource.CreateRequest, re
 }
 
 func vpcRef(data *VPCResourceModel) aruba.Ref {
-	if !data.Uri.IsNull() && data.Uri.ValueString() != "" {
-		return aruba.URI(data.Uri.ValueString())
-	}
-	return aruba.URI("/projects/" + data.ProjectID.ValueString() + "/network/vpcs/" + data.Id.ValueString())
+	return uriRef(data.Uri, newResourceURI(uriKindVPC, data.ProjectID.ValueString(), data.Id.ValueString()))
 }
 
 func applyVPCToModel(vpc *aruba.VPC, data *VPCResourceModel) {
 	data.Id = types.StringValue(vpc.ID())
 	data.Uri = strVal(vpc.URI())
 	data.Name = types.StringValue(vpc.Name())
-	data.Tags = TagsToListPreserveNull(vpc.Tags(), data.Tags)
+	data.Tags, data.TagsAll = flattenTags(vpc.Tags(), data.Tags, data.TagsAll)
 	raw := vpc.Raw()
 	if raw != nil && raw.Metadata.LocationResponse != nil {
 		data.Location = types.StringValue(string(raw.Metadata.LocationResponse.Value))
@@ -165,11 +194,18 @@ func applyVPCToModel(vpc *aruba.VPC, data *VPCResourceModel) {
 }
 
 func (r *VPCResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	ctx = withCorrelationID(ctx)
 	var data VPCResourceModel
 	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	timeout := r.client.operationTimeout(ctx, data.Timeouts.Read, data.Timeout, &resp.Diagnostics)
 	if resp.Diagnostics.HasError() {
 		return
 	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	if !r.client.checkGuardrails("read", "VPC", data.ProjectID, &resp.Diagnostics) {
+		return
+	}
 	if data.Id.IsNull() || data.Id.ValueString() == "" {
 		resp.State.RemoveResource(ctx)
 		return
@@ -186,7 +222,7 @@ func (r *VPCResource) Read(ctx context.Context, req resource.ReadRequest, resp *
 			resp.State.RemoveResource(ctx)
 			return
 		}
-		resp.Diagnostics.AddError("API Error", provErr.Error())
+		AppendAPIError(&resp.Diagnostics, provErr)
 		return
 	}
 
@@ -196,13 +232,13 @@ func (r *VPCResource) Read(ctx context.Context, req resource.ReadRequest, resp *
 		resp.Diagnostics.AddWarning("Resource in Failed State",
 			fmt.Sprintf("VPC %q is in a terminal failure state (%s).", data.Id.ValueString(), st))
 	case IsCreatingState(st):
-		if waitErr := vpc.WaitUntilReady(ctx, sdkWaitOptions(r.client.ResourceTimeout)...); waitErr != nil {
+		if waitErr := vpc.WaitUntilReady(ctx, sdkWaitOptions(timeout)...); waitErr != nil {
 			ReportWaitResult(&resp.Diagnostics, waitErr, "VPC", data.Id.ValueString())
 			return
 		}
 		vpc, err = r.client.Client.FromNetwork().VPCs().Get(ctx, vpcRef(&data))
 		if provErr := CheckResponseErr("read", "VPC", err); provErr != nil {
-			resp.Diagnostics.AddError("API Error", provErr.Error())
+			AppendAPIError(&resp.Diagnostics, provErr)
 			return
 		}
 	}
@@ -214,22 +250,29 @@ func (r *VPCResource) Read(ctx context.Context, req resource.ReadRequest, resp *
 }
 
 func (r *VPCResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	ctx = withCorrelationID(ctx)
 	var data VPCResourceModel
 	var state VPCResourceModel
 	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
 	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	timeout := r.client.operationTimeout(ctx, data.Timeouts.Update, data.Timeout, &resp.Diagnostics)
 	if resp.Diagnostics.HasError() {
 		return
 	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	if !r.client.checkGuardrails("update", "VPC", data.ProjectID, &resp.Diagnostics) {
+		return
+	}
 
-	tags := ListToTags(ctx, data.Tags, &resp.Diagnostics)
+	tags := r.client.requestTags(ctx, data.Tags, &data.TagsAll, &resp.Diagnostics)
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
 	vpc, err := r.client.Client.FromNetwork().VPCs().Get(ctx, vpcRef(&state))
 	if provErr := CheckResponseErr("read", "VPC", err); provErr != nil {
-		resp.Diagnostics.AddError("API Error", provErr.Error())
+		AppendAPIError(&resp.Diagnostics, provErr)
 		return
 	}
 
@@ -242,7 +285,7 @@ func (r *VPCResource) Update(ctx context.Context, req resource.UpdateRequest, re
 
 	updated, err := r.client.Client.FromNetwork().VPCs().Update(ctx, vpc)
 	if provErr := CheckResponseErr("update", "VPC", err); provErr != nil {
-		resp.Diagnostics.AddError("API Error", provErr.Error())
+		AppendAPIError(&resp.Diagnostics, provErr)
 		return
 	}
 
@@ -250,17 +293,24 @@ func (r *VPCResource) Update(ctx context.Context, req resource.UpdateRequest, re
 	data.Uri = state.Uri
 	data.ProjectID = state.ProjectID
 	data.Name = types.StringValue(updated.Name())
-	data.Tags = TagsToListPreserveNull(updated.Tags(), data.Tags)
+	data.Tags, data.TagsAll = flattenTags(updated.Tags(), data.Tags, data.TagsAll)
 
 	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
 }
 
 func (r *VPCResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	ctx = withCorrelationID(ctx)
 	var data VPCResourceModel
 	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	timeout := r.client.operationTimeout(ctx, data.Timeouts.Delete, data.Timeout, &resp.Diagnostics)
 	if resp.Diagnostics.HasError() {
 		return
 	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	if !r.client.checkGuardrails("delete", "VPC", data.ProjectID, &resp.Diagnostics) {
+		return
+	}
 
 	ref := vpcRef(&data)
 	vpcID := data.Id.ValueString()
@@ -280,12 +330,12 @@ func (r *VPCResource) Delete(ctx context.Context, req resource.DeleteRequest, re
 	err := DeleteResourceWithRetry(ctx, func() error {
 		delErr := r.client.Client.FromNetwork().VPCs().Delete(ctx, ref)
 		return CheckResponseErrAsError("delete", "VPC", delErr)
-	}, "VPC", vpcID, effectiveTimeout(data.Timeout, r.client.ResourceTimeout), deletionChecker)
+	}, "VPC", vpcID, timeout, deletionChecker)
 	if err != nil {
 		resp.Diagnostics.AddError("Error deleting VPC", err.Error())
 		return
 	}
-	if waitErr := WaitForResourceDeleted(ctx, deletionChecker, "VPC", vpcID, remainingTimeout(deleteStart, effectiveTimeout(data.Timeout, r.client.ResourceTimeout))); waitErr != nil {
+	if waitErr := WaitForResourceDeleted(ctx, deletionChecker, "VPC", vpcID, remainingTimeout(deleteStart, timeout)); waitErr != nil {
 		resp.Diagnostics.AddError("Error waiting for VPC deletion", waitErr.Error())
 		return
 	}
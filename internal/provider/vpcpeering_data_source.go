@@ -77,11 +77,15 @@ func (d *VPCPeeringDataSource) Configure(ctx context.Context, req datasource.Con
 }
 
 func (d *VPCPeeringDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	ctx = withCorrelationID(ctx)
 	var data VPCPeeringDataSourceModel
 	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
+	if !d.client.checkGuardrails("read", "VPCPeering", data.ProjectId, &resp.Diagnostics) {
+		return
+	}
 
 	projectID := data.ProjectId.ValueString()
 	vpcID := data.VpcId.ValueString()
@@ -94,7 +98,7 @@ func (d *VPCPeeringDataSource) Read(ctx context.Context, req datasource.ReadRequ
 	peering, err := d.client.Client.FromNetwork().VPCPeerings().Get(ctx,
 		aruba.VPCPeeringRef(projectID, vpcID, peeringID))
 	if provErr := CheckResponseErr("read", "VPCPeering", err); provErr != nil {
-		resp.Diagnostics.AddError("API Error", provErr.Error())
+		AppendAPIError(&resp.Diagnostics, provErr)
 		return
 	}
 
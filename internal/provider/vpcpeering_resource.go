@@ -7,6 +7,7 @@ import (
 	"time"
 
 	aruba "github.com/Arubacloud/sdk-go/pkg/aruba"
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
@@ -17,6 +18,7 @@ import (
 )
 
 var _ resource.Resource = &VpcPeeringResource{}
+var _ resource.ResourceWithModifyPlan = &VpcPeeringResource{}
 var _ resource.ResourceWithImportState = &VpcPeeringResource{}
 
 func NewVpcPeeringResource() resource.Resource {
@@ -28,15 +30,17 @@ type VpcPeeringResource struct {
 }
 
 type VpcPeeringResourceModel struct {
-	Id        types.String `tfsdk:"id"`
-	Uri       types.String `tfsdk:"uri"`
-	Name      types.String `tfsdk:"name"`
-	Location  types.String `tfsdk:"location"`
-	Tags      types.List   `tfsdk:"tags"`
-	ProjectId types.String `tfsdk:"project_id"`
-	VpcId     types.String `tfsdk:"vpc_id"`
-	PeerVpc   types.String `tfsdk:"peer_vpc"`
-	Timeout   types.String `tfsdk:"timeout"`
+	Id        types.String   `tfsdk:"id"`
+	Uri       types.String   `tfsdk:"uri"`
+	Name      types.String   `tfsdk:"name"`
+	Location  types.String   `tfsdk:"location"`
+	Tags      types.List     `tfsdk:"tags"`
+	TagsAll   types.List     `tfsdk:"tags_all"`
+	ProjectId types.String   `tfsdk:"project_id"`
+	VpcId     types.String   `tfsdk:"vpc_id"`
+	PeerVpc   types.String   `tfsdk:"peer_vpc"`
+	Timeout   types.String   `tfsdk:"timeout"`
+	Timeouts  timeouts.Value `tfsdk:"timeouts"`
 }
 
 func (r *VpcPeeringResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
@@ -66,17 +70,22 @@ func (r *VpcPeeringResource) Schema(ctx context.Context, req resource.SchemaRequ
 				Required:            true,
 			},
 			"location": schema.StringAttribute{
-				MarkdownDescription: "Region identifier for the resource (e.g., `ITBG-Bergamo`). See the [available locations and zones](https://api.arubacloud.com/docs/metadata/#location-and-data-center).",
-				Required:            true,
+				MarkdownDescription: defaultedDescription("Region identifier for the resource (e.g., `ITBG-Bergamo`). See the [available locations and zones](https://api.arubacloud.com/docs/metadata/#location-and-data-center).", "location"),
+				Optional:            true,
+				Computed:            true,
+				PlanModifiers:       []planmodifier.String{stringplanmodifier.UseStateForUnknown()},
 			},
 			"tags": schema.ListAttribute{
 				ElementType:         types.StringType,
 				MarkdownDescription: "List of string tags attached to the resource for filtering and organisation.",
 				Optional:            true,
 			},
+			"tags_all": tagsAllAttribute(),
 			"project_id": schema.StringAttribute{
-				MarkdownDescription: "ID of the project that owns this resource.",
-				Required:            true,
+				MarkdownDescription: defaultedDescription("ID of the project that owns this resource.", "project_id"),
+				Optional:            true,
+				Computed:            true,
+				PlanModifiers:       []planmodifier.String{stringplanmodifier.UseStateForUnknown()},
 			},
 			"vpc_id": schema.StringAttribute{
 				MarkdownDescription: "ID of the local VPC initiating this peering connection.",
@@ -87,10 +96,14 @@ func (r *VpcPeeringResource) Schema(ctx context.Context, req resource.SchemaRequ
 				Required:            true,
 			},
 			"timeout": schema.StringAttribute{
-				MarkdownDescription: "Per-resource timeout override (e.g. `\"15m\"`, `\"1h\"`). Overrides the provider-level `resource_timeout` for this resource's Create and Delete operations. Uses Go duration syntax.",
+				MarkdownDescription: "Per-resource timeout override (e.g. `\"15m\"`, `\"1h\"`) applied to every operation not set in the `timeouts` block. Overrides the provider-level `resource_timeout`. Uses Go duration syntax. Deprecated — use the `timeouts` block instead.",
+				DeprecationMessage:  "Use the timeouts block, which sets the create, read, update and delete timeouts individually.",
 				Optional:            true,
 			},
 		},
+		Blocks: map[string]schema.Block{
+			"timeouts": timeouts.BlockAll(ctx),
+		},
 	}
 }
 
@@ -110,17 +123,14 @@ func (r *VpcPeeringResource) Configure(ctx context.Context, req resource.Configu
 }
 
 func vpcPeeringRef(data *VpcPeeringResourceModel) aruba.Ref {
-	if !data.Uri.IsNull() && data.Uri.ValueString() != "" {
-		return aruba.URI(data.Uri.ValueString())
-	}
-	return aruba.VPCPeeringRef(data.ProjectId.ValueString(), data.VpcId.ValueString(), data.Id.ValueString())
+	return uriRef(data.Uri, newResourceURI(uriKindVPCPeering, data.ProjectId.ValueString(), data.Id.ValueString(), data.VpcId.ValueString()))
 }
 
 func applyVPCPeeringToModel(p *aruba.VPCPeering, data *VpcPeeringResourceModel) {
 	data.Id = types.StringValue(p.ID())
 	data.Uri = strVal(p.URI())
 	data.Name = types.StringValue(p.Name())
-	data.Tags = TagsToListPreserveNull(p.Tags(), data.Tags)
+	data.Tags, data.TagsAll = flattenTags(p.Tags(), data.Tags, data.TagsAll)
 	if remoteURI := p.RemoteVPCURI(); remoteURI != "" {
 		// Always store the short VPC ID (last path segment) so that state
 		// matches the user-provided value (arubacloud_vpc.peer.id).
@@ -133,17 +143,32 @@ func applyVPCPeeringToModel(p *aruba.VPCPeering, data *VpcPeeringResourceModel)
 	}
 }
 
+func (r *VpcPeeringResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	r.client.planTagsAll(ctx, req, resp, true)
+	r.client.planProviderDefaults(ctx, req, resp,
+		providerDefault{attr: "project_id", replace: false},
+		providerDefault{attr: "location", replace: false},
+	)
+}
+
 func (r *VpcPeeringResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	ctx = withCorrelationID(ctx)
 	var data VpcPeeringResourceModel
 	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	timeout := r.client.operationTimeout(ctx, data.Timeouts.Create, data.Timeout, &resp.Diagnostics)
 	if resp.Diagnostics.HasError() {
 		return
 	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	if !r.client.checkGuardrails("create", "VPCPeering", data.ProjectId, &resp.Diagnostics) {
+		return
+	}
 
 	projectID := data.ProjectId.ValueString()
 	vpcID := data.VpcId.ValueString()
 
-	tags := ListToTags(ctx, data.Tags, &resp.Diagnostics)
+	tags := r.client.requestTags(ctx, data.Tags, &data.TagsAll, &resp.Diagnostics)
 	if resp.Diagnostics.HasError() {
 		return
 	}
@@ -151,10 +176,10 @@ func (r *VpcPeeringResource) Create(ctx context.Context, req resource.CreateRequ
 	// Normalise peer VPC value: if bare ID, construct the full URI.
 	peerVPCURI := data.PeerVpc.ValueString()
 	if !strings.HasPrefix(peerVPCURI, "/") {
-		peerVPCURI = fmt.Sprintf("/projects/%s/providers/Aruba.Network/vpcs/%s", projectID, peerVPCURI)
+		peerVPCURI = newResourceURI(uriKindVPC, projectID, peerVPCURI).String()
 	}
 
-	vpcURI := aruba.URI("/projects/" + projectID + "/network/vpcs/" + vpcID)
+	vpcURI := newResourceURI(uriKindVPC, projectID, vpcID).Ref()
 	peering, err := r.client.Client.FromNetwork().VPCPeerings().Create(ctx,
 		aruba.NewVPCPeering().
 			Named(data.Name.ValueString()).
@@ -164,7 +189,7 @@ func (r *VpcPeeringResource) Create(ctx context.Context, req resource.CreateRequ
 			PeeredWith(aruba.URI(peerVPCURI)),
 	)
 	if provErr := CheckResponseErr("create", "VPCPeering", err); provErr != nil {
-		resp.Diagnostics.AddError("API Error", provErr.Error())
+		AppendAPIError(&resp.Diagnostics, provErr)
 		return
 	}
 
@@ -176,7 +201,7 @@ func (r *VpcPeeringResource) Create(ctx context.Context, req resource.CreateRequ
 		return
 	}
 
-	if waitErr := peering.WaitUntilReady(ctx, sdkWaitOptions(effectiveTimeout(data.Timeout, r.client.ResourceTimeout))...); waitErr != nil {
+	if waitErr := peering.WaitUntilReady(ctx, sdkWaitOptions(timeout)...); waitErr != nil {
 		ReportWaitResult(&resp.Diagnostics, waitErr, "VPCPeering", data.Id.ValueString())
 		return
 	}
@@ -200,11 +225,18 @@ func (r *VpcPeeringResource) Create(ctx context.Context, req resource.CreateRequ
 }
 
 func (r *VpcPeeringResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	ctx = withCorrelationID(ctx)
 	var data VpcPeeringResourceModel
 	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	timeout := r.client.operationTimeout(ctx, data.Timeouts.Read, data.Timeout, &resp.Diagnostics)
 	if resp.Diagnostics.HasError() {
 		return
 	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	if !r.client.checkGuardrails("read", "VPCPeering", data.ProjectId, &resp.Diagnostics) {
+		return
+	}
 	if data.Id.IsNull() || data.Id.ValueString() == "" {
 		resp.State.RemoveResource(ctx)
 		return
@@ -216,7 +248,7 @@ func (r *VpcPeeringResource) Read(ctx context.Context, req resource.ReadRequest,
 			resp.State.RemoveResource(ctx)
 			return
 		}
-		resp.Diagnostics.AddError("API Error", provErr.Error())
+		AppendAPIError(&resp.Diagnostics, provErr)
 		return
 	}
 
@@ -227,13 +259,13 @@ func (r *VpcPeeringResource) Read(ctx context.Context, req resource.ReadRequest,
 			fmt.Sprintf("VPCPeering %q is in a terminal failure state (%s). "+
 				"Run `terraform destroy` to clean it up, or `terraform apply -replace=<address>` to recreate it.", data.Id.ValueString(), st))
 	case IsCreatingState(st):
-		if waitErr := peering.WaitUntilReady(ctx, sdkWaitOptions(r.client.ResourceTimeout)...); waitErr != nil {
+		if waitErr := peering.WaitUntilReady(ctx, sdkWaitOptions(timeout)...); waitErr != nil {
 			ReportWaitResult(&resp.Diagnostics, waitErr, "VPCPeering", data.Id.ValueString())
 			return
 		}
 		peering, err = r.client.Client.FromNetwork().VPCPeerings().Get(ctx, vpcPeeringRef(&data))
 		if provErr := CheckResponseErr("read", "VPCPeering", err); provErr != nil {
-			resp.Diagnostics.AddError("API Error", provErr.Error())
+			AppendAPIError(&resp.Diagnostics, provErr)
 			return
 		}
 	}
@@ -247,23 +279,30 @@ func (r *VpcPeeringResource) Read(ctx context.Context, req resource.ReadRequest,
 }
 
 func (r *VpcPeeringResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	ctx = withCorrelationID(ctx)
 	var data VpcPeeringResourceModel
 	var state VpcPeeringResourceModel
 
 	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
 	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	timeout := r.client.operationTimeout(ctx, data.Timeouts.Update, data.Timeout, &resp.Diagnostics)
 	if resp.Diagnostics.HasError() {
 		return
 	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	if !r.client.checkGuardrails("update", "VPCPeering", data.ProjectId, &resp.Diagnostics) {
+		return
+	}
 
-	tags := ListToTags(ctx, data.Tags, &resp.Diagnostics)
+	tags := r.client.requestTags(ctx, data.Tags, &data.TagsAll, &resp.Diagnostics)
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
 	peering, err := r.client.Client.FromNetwork().VPCPeerings().Get(ctx, vpcPeeringRef(&state))
 	if provErr := CheckResponseErr("read", "VPCPeering", err); provErr != nil {
-		resp.Diagnostics.AddError("API Error", provErr.Error())
+		AppendAPIError(&resp.Diagnostics, provErr)
 		return
 	}
 
@@ -276,7 +315,7 @@ func (r *VpcPeeringResource) Update(ctx context.Context, req resource.UpdateRequ
 
 	updated, err := r.client.Client.FromNetwork().VPCPeerings().Update(ctx, peering)
 	if provErr := CheckResponseErr("update", "VPCPeering", err); provErr != nil {
-		resp.Diagnostics.AddError("API Error", provErr.Error())
+		AppendAPIError(&resp.Diagnostics, provErr)
 		return
 	}
 
@@ -287,17 +326,24 @@ func (r *VpcPeeringResource) Update(ctx context.Context, req resource.UpdateRequ
 	data.PeerVpc = state.PeerVpc
 	data.Location = state.Location
 	data.Name = types.StringValue(updated.Name())
-	data.Tags = TagsToListPreserveNull(updated.Tags(), data.Tags)
+	data.Tags, data.TagsAll = flattenTags(updated.Tags(), data.Tags, data.TagsAll)
 
 	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
 }
 
 func (r *VpcPeeringResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	ctx = withCorrelationID(ctx)
 	var data VpcPeeringResourceModel
 	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	timeout := r.client.operationTimeout(ctx, data.Timeouts.Delete, data.Timeout, &resp.Diagnostics)
 	if resp.Diagnostics.HasError() {
 		return
 	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	if !r.client.checkGuardrails("delete", "VPCPeering", data.ProjectId, &resp.Diagnostics) {
+		return
+	}
 
 	ref := vpcPeeringRef(&data)
 	peeringID := data.Id.ValueString()
@@ -317,12 +363,12 @@ func (r *VpcPeeringResource) Delete(ctx context.Context, req resource.DeleteRequ
 	err := DeleteResourceWithRetry(ctx, func() error {
 		return CheckResponseErrAsError("delete", "VPCPeering",
 			r.client.Client.FromNetwork().VPCPeerings().Delete(ctx, ref))
-	}, "VPCPeering", peeringID, effectiveTimeout(data.Timeout, r.client.ResourceTimeout), deletionChecker)
+	}, "VPCPeering", peeringID, timeout, deletionChecker)
 	if err != nil {
 		resp.Diagnostics.AddError("Error deleting VPCPeering", err.Error())
 		return
 	}
-	if waitErr := WaitForResourceDeleted(ctx, deletionChecker, "VPCPeering", peeringID, remainingTimeout(deleteStart, effectiveTimeout(data.Timeout, r.client.ResourceTimeout))); waitErr != nil {
+	if waitErr := WaitForResourceDeleted(ctx, deletionChecker, "VPCPeering", peeringID, remainingTimeout(deleteStart, timeout)); waitErr != nil {
 		resp.Diagnostics.AddError("Error waiting for VPCPeering deletion", waitErr.Error())
 		return
 	}
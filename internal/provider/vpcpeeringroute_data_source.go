@@ -82,11 +82,15 @@ func (d *VPCPeeringRouteDataSource) Configure(ctx context.Context, req datasourc
 }
 
 func (d *VPCPeeringRouteDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	ctx = withCorrelationID(ctx)
 	var data VPCPeeringRouteDataSourceModel
 	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
+	if !d.client.checkGuardrails("read", "VPCPeeringRoute", data.ProjectId, &resp.Diagnostics) {
+		return
+	}
 
 	projectID := data.ProjectId.ValueString()
 	vpcID := data.VpcId.ValueString()
@@ -100,7 +104,7 @@ func (d *VPCPeeringRouteDataSource) Read(ctx context.Context, req datasource.Rea
 	route, err := d.client.Client.FromNetwork().VPCPeeringRoutes().Get(ctx,
 		aruba.VPCPeeringRouteRef(projectID, vpcID, peeringID, routeID))
 	if provErr := CheckResponseErr("read", "VPCPeeringRoute", err); provErr != nil {
-		resp.Diagnostics.AddError("API Error", provErr.Error())
+		AppendAPIError(&resp.Diagnostics, provErr)
 		return
 	}
 
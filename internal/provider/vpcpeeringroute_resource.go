@@ -6,6 +6,7 @@ import (
 	"time"
 
 	aruba "github.com/Arubacloud/sdk-go/pkg/aruba"
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
 	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
@@ -18,6 +19,7 @@ import (
 )
 
 var _ resource.Resource = &VpcPeeringRouteResource{}
+var _ resource.ResourceWithModifyPlan = &VpcPeeringRouteResource{}
 var _ resource.ResourceWithImportState = &VpcPeeringRouteResource{}
 
 func NewVpcPeeringRouteResource() resource.Resource {
@@ -29,17 +31,19 @@ type VpcPeeringRouteResource struct {
 }
 
 type VpcPeeringRouteResourceModel struct {
-	Id                   types.String `tfsdk:"id"`
-	Uri                  types.String `tfsdk:"uri"`
-	Name                 types.String `tfsdk:"name"`
-	Tags                 types.List   `tfsdk:"tags"`
-	ProjectId            types.String `tfsdk:"project_id"`
-	VpcId                types.String `tfsdk:"vpc_id"`
-	VpcPeeringId         types.String `tfsdk:"vpc_peering_id"`
-	LocalNetworkAddress  types.String `tfsdk:"local_network_address"`
-	RemoteNetworkAddress types.String `tfsdk:"remote_network_address"`
-	BillingPeriod        types.String `tfsdk:"billing_period"`
-	Timeout              types.String `tfsdk:"timeout"`
+	Id                   types.String   `tfsdk:"id"`
+	Uri                  types.String   `tfsdk:"uri"`
+	Name                 types.String   `tfsdk:"name"`
+	Tags                 types.List     `tfsdk:"tags"`
+	TagsAll              types.List     `tfsdk:"tags_all"`
+	ProjectId            types.String   `tfsdk:"project_id"`
+	VpcId                types.String   `tfsdk:"vpc_id"`
+	VpcPeeringId         types.String   `tfsdk:"vpc_peering_id"`
+	LocalNetworkAddress  types.String   `tfsdk:"local_network_address"`
+	RemoteNetworkAddress types.String   `tfsdk:"remote_network_address"`
+	BillingPeriod        types.String   `tfsdk:"billing_period"`
+	Timeout              types.String   `tfsdk:"timeout"`
+	Timeouts             timeouts.Value `tfsdk:"timeouts"`
 }
 
 func (r *VpcPeeringRouteResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
@@ -73,9 +77,12 @@ func (r *VpcPeeringRouteResource) Schema(ctx context.Context, req resource.Schem
 				MarkdownDescription: "List of string tags attached to the resource for filtering and organisation.",
 				Optional:            true,
 			},
+			"tags_all": tagsAllAttribute(),
 			"project_id": schema.StringAttribute{
-				MarkdownDescription: "ID of the project that owns this resource.",
-				Required:            true,
+				MarkdownDescription: defaultedDescription("ID of the project that owns this resource.", "project_id"),
+				Optional:            true,
+				Computed:            true,
+				PlanModifiers:       []planmodifier.String{stringplanmodifier.UseStateForUnknown()},
 			},
 			"vpc_id": schema.StringAttribute{
 				MarkdownDescription: "ID of the VPC this peering route belongs to.",
@@ -99,10 +106,14 @@ func (r *VpcPeeringRouteResource) Schema(ctx context.Context, req resource.Schem
 				Validators:          []validator.String{stringvalidator.OneOf("Hour", "Month", "Year")},
 			},
 			"timeout": schema.StringAttribute{
-				MarkdownDescription: "Per-resource timeout override (e.g. `\"15m\"`, `\"1h\"`). Overrides the provider-level `resource_timeout` for this resource's Create and Delete operations. Uses Go duration syntax.",
+				MarkdownDescription: "Per-resource timeout override (e.g. `\"15m\"`, `\"1h\"`) applied to every operation not set in the `timeouts` block. Overrides the provider-level `resource_timeout`. Uses Go duration syntax. Deprecated — use the `timeouts` block instead.",
+				DeprecationMessage:  "Use the timeouts block, which sets the create, read, update and delete timeouts individually.",
 				Optional:            true,
 			},
 		},
+		Blocks: map[string]schema.Block{
+			"timeouts": timeouts.BlockAll(ctx),
+		},
 	}
 }
 
@@ -138,7 +149,7 @@ func applyVPCPeeringRouteToModel(route *aruba.VPCPeeringRoute, data *VpcPeeringR
 	data.Id = types.StringValue(route.Name())
 	data.Uri = strVal(route.URI())
 	data.Name = types.StringValue(route.Name())
-	data.Tags = TagsToListPreserveNull(route.Tags(), data.Tags)
+	data.Tags, data.TagsAll = flattenTags(route.Tags(), data.Tags, data.TagsAll)
 	if route.LocalCIDR() != "" {
 		data.LocalNetworkAddress = types.StringValue(route.LocalCIDR())
 	}
@@ -150,18 +161,32 @@ func applyVPCPeeringRouteToModel(route *aruba.VPCPeeringRoute, data *VpcPeeringR
 	}
 }
 
+func (r *VpcPeeringRouteResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	r.client.planTagsAll(ctx, req, resp, true)
+	r.client.planProviderDefaults(ctx, req, resp,
+		providerDefault{attr: "project_id", replace: false},
+	)
+}
+
 func (r *VpcPeeringRouteResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	ctx = withCorrelationID(ctx)
 	var data VpcPeeringRouteResourceModel
 	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	timeout := r.client.operationTimeout(ctx, data.Timeouts.Create, data.Timeout, &resp.Diagnostics)
 	if resp.Diagnostics.HasError() {
 		return
 	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	if !r.client.checkGuardrails("create", "VPCPeeringRoute", data.ProjectId, &resp.Diagnostics) {
+		return
+	}
 
 	projectID := data.ProjectId.ValueString()
 	vpcID := data.VpcId.ValueString()
 	peeringID := data.VpcPeeringId.ValueString()
 
-	tags := ListToTags(ctx, data.Tags, &resp.Diagnostics)
+	tags := r.client.requestTags(ctx, data.Tags, &data.TagsAll, &resp.Diagnostics)
 	if resp.Diagnostics.HasError() {
 		return
 	}
@@ -177,7 +202,7 @@ func (r *VpcPeeringRouteResource) Create(ctx context.Context, req resource.Creat
 			BilledBy(aruba.BillingPeriod(data.BillingPeriod.ValueString())),
 	)
 	if provErr := CheckResponseErr("create", "VPCPeeringRoute", err); provErr != nil {
-		resp.Diagnostics.AddError("API Error", provErr.Error())
+		AppendAPIError(&resp.Diagnostics, provErr)
 		return
 	}
 
@@ -190,7 +215,7 @@ func (r *VpcPeeringRouteResource) Create(ctx context.Context, req resource.Creat
 		return
 	}
 
-	if waitErr := route.WaitUntilReady(ctx, sdkWaitOptions(effectiveTimeout(data.Timeout, r.client.ResourceTimeout))...); waitErr != nil {
+	if waitErr := route.WaitUntilReady(ctx, sdkWaitOptions(timeout)...); waitErr != nil {
 		ReportWaitResult(&resp.Diagnostics, waitErr, "VPCPeeringRoute", data.Id.ValueString())
 		return
 	}
@@ -203,11 +228,18 @@ func (r *VpcPeeringRouteResource) Create(ctx context.Context, req resource.Creat
 }
 
 func (r *VpcPeeringRouteResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	ctx = withCorrelationID(ctx)
 	var data VpcPeeringRouteResourceModel
 	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	timeout := r.client.operationTimeout(ctx, data.Timeouts.Read, data.Timeout, &resp.Diagnostics)
 	if resp.Diagnostics.HasError() {
 		return
 	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	if !r.client.checkGuardrails("read", "VPCPeeringRoute", data.ProjectId, &resp.Diagnostics) {
+		return
+	}
 	if data.Id.IsNull() || data.Id.ValueString() == "" {
 		resp.State.RemoveResource(ctx)
 		return
@@ -219,7 +251,7 @@ func (r *VpcPeeringRouteResource) Read(ctx context.Context, req resource.ReadReq
 			resp.State.RemoveResource(ctx)
 			return
 		}
-		resp.Diagnostics.AddError("API Error", provErr.Error())
+		AppendAPIError(&resp.Diagnostics, provErr)
 		return
 	}
 
@@ -230,13 +262,13 @@ func (r *VpcPeeringRouteResource) Read(ctx context.Context, req resource.ReadReq
 			fmt.Sprintf("VPCPeeringRoute %q is in a terminal failure state (%s). "+
 				"Run `terraform destroy` to clean it up, or `terraform apply -replace=<address>` to recreate it.", data.Id.ValueString(), st))
 	case IsCreatingState(st):
-		if waitErr := route.WaitUntilReady(ctx, sdkWaitOptions(r.client.ResourceTimeout)...); waitErr != nil {
+		if waitErr := route.WaitUntilReady(ctx, sdkWaitOptions(timeout)...); waitErr != nil {
 			ReportWaitResult(&resp.Diagnostics, waitErr, "VPCPeeringRoute", data.Id.ValueString())
 			return
 		}
 		route, err = r.client.Client.FromNetwork().VPCPeeringRoutes().Get(ctx, vpcPeeringRouteRef(&data))
 		if provErr := CheckResponseErr("read", "VPCPeeringRoute", err); provErr != nil {
-			resp.Diagnostics.AddError("API Error", provErr.Error())
+			AppendAPIError(&resp.Diagnostics, provErr)
 			return
 		}
 	}
@@ -252,23 +284,30 @@ func (r *VpcPeeringRouteResource) Read(ctx context.Context, req resource.ReadReq
 }
 
 func (r *VpcPeeringRouteResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	ctx = withCorrelationID(ctx)
 	var data VpcPeeringRouteResourceModel
 	var state VpcPeeringRouteResourceModel
 
 	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
 	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	timeout := r.client.operationTimeout(ctx, data.Timeouts.Update, data.Timeout, &resp.Diagnostics)
 	if resp.Diagnostics.HasError() {
 		return
 	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	if !r.client.checkGuardrails("update", "VPCPeeringRoute", data.ProjectId, &resp.Diagnostics) {
+		return
+	}
 
-	tags := ListToTags(ctx, data.Tags, &resp.Diagnostics)
+	tags := r.client.requestTags(ctx, data.Tags, &data.TagsAll, &resp.Diagnostics)
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
 	route, err := r.client.Client.FromNetwork().VPCPeeringRoutes().Get(ctx, vpcPeeringRouteRef(&state))
 	if provErr := CheckResponseErr("read", "VPCPeeringRoute", err); provErr != nil {
-		resp.Diagnostics.AddError("API Error", provErr.Error())
+		AppendAPIError(&resp.Diagnostics, provErr)
 		return
 	}
 
@@ -281,7 +320,7 @@ func (r *VpcPeeringRouteResource) Update(ctx context.Context, req resource.Updat
 
 	updated, err := r.client.Client.FromNetwork().VPCPeeringRoutes().Update(ctx, route)
 	if provErr := CheckResponseErr("update", "VPCPeeringRoute", err); provErr != nil {
-		resp.Diagnostics.AddError("API Error", provErr.Error())
+		AppendAPIError(&resp.Diagnostics, provErr)
 		return
 	}
 
@@ -294,17 +333,24 @@ func (r *VpcPeeringRouteResource) Update(ctx context.Context, req resource.Updat
 	data.RemoteNetworkAddress = state.RemoteNetworkAddress
 	data.BillingPeriod = state.BillingPeriod
 	data.Name = types.StringValue(updated.Name())
-	data.Tags = TagsToListPreserveNull(updated.Tags(), data.Tags)
+	data.Tags, data.TagsAll = flattenTags(updated.Tags(), data.Tags, data.TagsAll)
 
 	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
 }
 
 func (r *VpcPeeringRouteResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	ctx = withCorrelationID(ctx)
 	var data VpcPeeringRouteResourceModel
 	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	timeout := r.client.operationTimeout(ctx, data.Timeouts.Delete, data.Timeout, &resp.Diagnostics)
 	if resp.Diagnostics.HasError() {
 		return
 	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	if !r.client.checkGuardrails("delete", "VPCPeeringRoute", data.ProjectId, &resp.Diagnostics) {
+		return
+	}
 
 	ref := vpcPeeringRouteRef(&data)
 	routeID := data.Id.ValueString()
@@ -324,12 +370,12 @@ func (r *VpcPeeringRouteResource) Delete(ctx context.Context, req resource.Delet
 	err := DeleteResourceWithRetry(ctx, func() error {
 		return CheckResponseErrAsError("delete", "VPCPeeringRoute",
 			r.client.Client.FromNetwork().VPCPeeringRoutes().Delete(ctx, ref))
-	}, "VPCPeeringRoute", routeID, effectiveTimeout(data.Timeout, r.client.ResourceTimeout), deletionChecker)
+	}, "VPCPeeringRoute", routeID, timeout, deletionChecker)
 	if err != nil {
 		resp.Diagnostics.AddError("Error deleting VPCPeeringRoute", err.Error())
 		return
 	}
-	if waitErr := WaitForResourceDeleted(ctx, deletionChecker, "VPCPeeringRoute", routeID, remainingTimeout(deleteStart, effectiveTimeout(data.Timeout, r.client.ResourceTimeout))); waitErr != nil {
+	if waitErr := WaitForResourceDeleted(ctx, deletionChecker, "VPCPeeringRoute", routeID, remainingTimeout(deleteStart, timeout)); waitErr != nil {
 		resp.Diagnostics.AddError("Error waiting for VPCPeeringRoute deletion", waitErr.Error())
 		return
 	}
@@ -87,11 +87,15 @@ func (d *VPNRouteDataSource) Configure(ctx context.Context, req datasource.Confi
 }
 
 func (d *VPNRouteDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	ctx = withCorrelationID(ctx)
 	var data VPNRouteDataSourceModel
 	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
+	if !d.client.checkGuardrails("read", "VPNRoute", data.ProjectId, &resp.Diagnostics) {
+		return
+	}
 
 	projectID := data.ProjectId.ValueString()
 	vpnTunnelID := data.VpnTunnelId.ValueString()
@@ -104,7 +108,7 @@ func (d *VPNRouteDataSource) Read(ctx context.Context, req datasource.ReadReques
 	route, err := d.client.Client.FromNetwork().VPNRoutes().Get(ctx,
 		aruba.VPNRouteRef(projectID, vpnTunnelID, routeID))
 	if provErr := CheckResponseErr("read", "VPNRoute", err); provErr != nil {
-		resp.Diagnostics.AddError("API Error", provErr.Error())
+		AppendAPIError(&resp.Diagnostics, provErr)
 		return
 	}
 
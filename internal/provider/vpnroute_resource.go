@@ -6,6 +6,7 @@ import (
 	"time"
 
 	aruba "github.com/Arubacloud/sdk-go/pkg/aruba"
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
 	"github.com/hashicorp/terraform-plugin-framework/attr"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
@@ -17,6 +18,7 @@ import (
 )
 
 var _ resource.Resource = &VPNRouteResource{}
+var _ resource.ResourceWithModifyPlan = &VPNRouteResource{}
 var _ resource.ResourceWithImportState = &VPNRouteResource{}
 
 func NewVPNRouteResource() resource.Resource {
@@ -24,15 +26,17 @@ func NewVPNRouteResource() resource.Resource {
 }
 
 type VPNRouteResourceModel struct {
-	Id          types.String `tfsdk:"id"`
-	Uri         types.String `tfsdk:"uri"`
-	Name        types.String `tfsdk:"name"`
-	Location    types.String `tfsdk:"location"`
-	Tags        types.List   `tfsdk:"tags"`
-	ProjectId   types.String `tfsdk:"project_id"`
-	VPNTunnelId types.String `tfsdk:"vpn_tunnel_id"`
-	Properties  types.Object `tfsdk:"properties"`
-	Timeout     types.String `tfsdk:"timeout"`
+	Id          types.String   `tfsdk:"id"`
+	Uri         types.String   `tfsdk:"uri"`
+	Name        types.String   `tfsdk:"name"`
+	Location    types.String   `tfsdk:"location"`
+	Tags        types.List     `tfsdk:"tags"`
+	TagsAll     types.List     `tfsdk:"tags_all"`
+	ProjectId   types.String   `tfsdk:"project_id"`
+	VPNTunnelId types.String   `tfsdk:"vpn_tunnel_id"`
+	Properties  types.Object   `tfsdk:"properties"`
+	Timeout     types.String   `tfsdk:"timeout"`
+	Timeouts    timeouts.Value `tfsdk:"timeouts"`
 }
 
 type VPNRouteResource struct {
@@ -66,24 +70,30 @@ func (r *VPNRouteResource) Schema(ctx context.Context, req resource.SchemaReques
 				Required:            true,
 			},
 			"location": schema.StringAttribute{
-				MarkdownDescription: "Region identifier for the resource (e.g., `ITBG-Bergamo`). See the [available locations and zones](https://api.arubacloud.com/docs/metadata/#location-and-data-center).",
-				Required:            true,
+				MarkdownDescription: defaultedDescription("Region identifier for the resource (e.g., `ITBG-Bergamo`). See the [available locations and zones](https://api.arubacloud.com/docs/metadata/#location-and-data-center).", "location"),
+				Optional:            true,
+				Computed:            true,
+				PlanModifiers:       []planmodifier.String{stringplanmodifier.UseStateForUnknown()},
 			},
 			"tags": schema.ListAttribute{
 				ElementType:         types.StringType,
 				MarkdownDescription: "List of string tags attached to the resource for filtering and organisation.",
 				Optional:            true,
 			},
+			"tags_all": tagsAllAttribute(),
 			"project_id": schema.StringAttribute{
-				MarkdownDescription: "ID of the project that owns this resource.",
-				Required:            true,
+				MarkdownDescription: defaultedDescription("ID of the project that owns this resource.", "project_id"),
+				Optional:            true,
+				Computed:            true,
+				PlanModifiers:       []planmodifier.String{stringplanmodifier.UseStateForUnknown()},
 			},
 			"vpn_tunnel_id": schema.StringAttribute{
 				MarkdownDescription: "ID of the VPN tunnel this route is associated with.",
 				Required:            true,
 			},
 			"timeout": schema.StringAttribute{
-				MarkdownDescription: "Per-resource timeout override (e.g. `\"15m\"`, `\"1h\"`). Overrides the provider-level `resource_timeout` for this resource's Create and Delete operations. Uses Go duration syntax.",
+				MarkdownDescription: "Per-resource timeout override (e.g. `\"15m\"`, `\"1h\"`) applied to every operation not set in the `timeouts` block. Overrides the provider-level `resource_timeout`. Uses Go duration syntax. Deprecated — use the `timeouts` block instead.",
+				DeprecationMessage:  "Use the timeouts block, which sets the create, read, update and delete timeouts individually.",
 				Optional:            true,
 			},
 			"properties": schema.SingleNestedAttribute{
@@ -101,6 +111,9 @@ func (r *VPNRouteResource) Schema(ctx context.Context, req resource.SchemaReques
 				},
 			},
 		},
+		Blocks: map[string]schema.Block{
+			"timeouts": timeouts.BlockAll(ctx),
+		},
 	}
 }
 
@@ -120,10 +133,7 @@ func (r *VPNRouteResource) Configure(ctx context.Context, req resource.Configure
 }
 
 func vpnRouteRef(data *VPNRouteResourceModel) aruba.Ref {
-	if !data.Uri.IsNull() && data.Uri.ValueString() != "" {
-		return aruba.URI(data.Uri.ValueString())
-	}
-	return aruba.VPNRouteRef(data.ProjectId.ValueString(), data.VPNTunnelId.ValueString(), data.Id.ValueString())
+	return uriRef(data.Uri, newResourceURI(uriKindVPNRoute, data.ProjectId.ValueString(), data.Id.ValueString(), data.VPNTunnelId.ValueString()))
 }
 
 func extractVPNRouteSubnets(data *VPNRouteResourceModel) (cloudSubnet, onPremSubnet string) {
@@ -144,17 +154,32 @@ func extractVPNRouteSubnets(data *VPNRouteResourceModel) (cloudSubnet, onPremSub
 	return
 }
 
+func (r *VPNRouteResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	r.client.planTagsAll(ctx, req, resp, true)
+	r.client.planProviderDefaults(ctx, req, resp,
+		providerDefault{attr: "project_id", replace: false},
+		providerDefault{attr: "location", replace: false},
+	)
+}
+
 func (r *VPNRouteResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	ctx = withCorrelationID(ctx)
 	var data VPNRouteResourceModel
 	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	timeout := r.client.operationTimeout(ctx, data.Timeouts.Create, data.Timeout, &resp.Diagnostics)
 	if resp.Diagnostics.HasError() {
 		return
 	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	if !r.client.checkGuardrails("create", "VPNRoute", data.ProjectId, &resp.Diagnostics) {
+		return
+	}
 
 	projectID := data.ProjectId.ValueString()
 	vpnTunnelID := data.VPNTunnelId.ValueString()
 
-	tags := ListToTags(ctx, data.Tags, &resp.Diagnostics)
+	tags := r.client.requestTags(ctx, data.Tags, &data.TagsAll, &resp.Diagnostics)
 	if resp.Diagnostics.HasError() {
 		return
 	}
@@ -172,7 +197,7 @@ func (r *VPNRouteResource) Create(ctx context.Context, req resource.CreateReques
 			WithOnPremSubnet(onPremSubnet),
 	)
 	if provErr := CheckResponseErr("create", "VPNRoute", err); provErr != nil {
-		resp.Diagnostics.AddError("API Error", provErr.Error())
+		AppendAPIError(&resp.Diagnostics, provErr)
 		return
 	}
 
@@ -184,7 +209,7 @@ func (r *VPNRouteResource) Create(ctx context.Context, req resource.CreateReques
 		return
 	}
 
-	if waitErr := route.WaitUntilReady(ctx, sdkWaitOptions(effectiveTimeout(data.Timeout, r.client.ResourceTimeout))...); waitErr != nil {
+	if waitErr := route.WaitUntilReady(ctx, sdkWaitOptions(timeout)...); waitErr != nil {
 		ReportWaitResult(&resp.Diagnostics, waitErr, "VPNRoute", data.Id.ValueString())
 		return
 	}
@@ -197,11 +222,18 @@ func (r *VPNRouteResource) Create(ctx context.Context, req resource.CreateReques
 }
 
 func (r *VPNRouteResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	ctx = withCorrelationID(ctx)
 	var data VPNRouteResourceModel
 	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	timeout := r.client.operationTimeout(ctx, data.Timeouts.Read, data.Timeout, &resp.Diagnostics)
 	if resp.Diagnostics.HasError() {
 		return
 	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	if !r.client.checkGuardrails("read", "VPNRoute", data.ProjectId, &resp.Diagnostics) {
+		return
+	}
 	if data.Id.IsNull() || data.Id.ValueString() == "" {
 		resp.State.RemoveResource(ctx)
 		return
@@ -213,7 +245,7 @@ func (r *VPNRouteResource) Read(ctx context.Context, req resource.ReadRequest, r
 			resp.State.RemoveResource(ctx)
 			return
 		}
-		resp.Diagnostics.AddError("API Error", provErr.Error())
+		AppendAPIError(&resp.Diagnostics, provErr)
 		return
 	}
 
@@ -224,13 +256,13 @@ func (r *VPNRouteResource) Read(ctx context.Context, req resource.ReadRequest, r
 			fmt.Sprintf("VPNRoute %q is in a terminal failure state (%s). "+
 				"Run `terraform destroy` to clean it up, or `terraform apply -replace=<address>` to recreate it.", data.Id.ValueString(), st))
 	case IsCreatingState(st):
-		if waitErr := route.WaitUntilReady(ctx, sdkWaitOptions(r.client.ResourceTimeout)...); waitErr != nil {
+		if waitErr := route.WaitUntilReady(ctx, sdkWaitOptions(timeout)...); waitErr != nil {
 			ReportWaitResult(&resp.Diagnostics, waitErr, "VPNRoute", data.Id.ValueString())
 			return
 		}
 		route, err = r.client.Client.FromNetwork().VPNRoutes().Get(ctx, vpnRouteRef(&data))
 		if provErr := CheckResponseErr("read", "VPNRoute", err); provErr != nil {
-			resp.Diagnostics.AddError("API Error", provErr.Error())
+			AppendAPIError(&resp.Diagnostics, provErr)
 			return
 		}
 	}
@@ -238,7 +270,7 @@ func (r *VPNRouteResource) Read(ctx context.Context, req resource.ReadRequest, r
 	data.Id = types.StringValue(route.ID())
 	data.Uri = strVal(route.URI())
 	data.Name = types.StringValue(route.Name())
-	data.Tags = TagsToListPreserveNull(route.Tags(), data.Tags)
+	data.Tags, data.TagsAll = flattenTags(route.Tags(), data.Tags, data.TagsAll)
 	if route.Region() != "" {
 		data.Location = types.StringValue(string(route.Region()))
 	}
@@ -262,23 +294,30 @@ func (r *VPNRouteResource) Read(ctx context.Context, req resource.ReadRequest, r
 }
 
 func (r *VPNRouteResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	ctx = withCorrelationID(ctx)
 	var data VPNRouteResourceModel
 	var state VPNRouteResourceModel
 
 	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
 	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	timeout := r.client.operationTimeout(ctx, data.Timeouts.Update, data.Timeout, &resp.Diagnostics)
 	if resp.Diagnostics.HasError() {
 		return
 	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	if !r.client.checkGuardrails("update", "VPNRoute", data.ProjectId, &resp.Diagnostics) {
+		return
+	}
 
-	tags := ListToTags(ctx, data.Tags, &resp.Diagnostics)
+	tags := r.client.requestTags(ctx, data.Tags, &data.TagsAll, &resp.Diagnostics)
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
 	route, err := r.client.Client.FromNetwork().VPNRoutes().Get(ctx, vpnRouteRef(&state))
 	if provErr := CheckResponseErr("read", "VPNRoute", err); provErr != nil {
-		resp.Diagnostics.AddError("API Error", provErr.Error())
+		AppendAPIError(&resp.Diagnostics, provErr)
 		return
 	}
 
@@ -299,7 +338,7 @@ func (r *VPNRouteResource) Update(ctx context.Context, req resource.UpdateReques
 
 	updated, err := r.client.Client.FromNetwork().VPNRoutes().Update(ctx, route)
 	if provErr := CheckResponseErr("update", "VPNRoute", err); provErr != nil {
-		resp.Diagnostics.AddError("API Error", provErr.Error())
+		AppendAPIError(&resp.Diagnostics, provErr)
 		return
 	}
 
@@ -309,7 +348,7 @@ func (r *VPNRouteResource) Update(ctx context.Context, req resource.UpdateReques
 	data.VPNTunnelId = state.VPNTunnelId
 	data.Location = state.Location
 	data.Name = types.StringValue(updated.Name())
-	data.Tags = TagsToListPreserveNull(updated.Tags(), data.Tags)
+	data.Tags, data.TagsAll = flattenTags(updated.Tags(), data.Tags, data.TagsAll)
 
 	propertiesObj, diags := types.ObjectValue(
 		map[string]attr.Type{
@@ -330,11 +369,18 @@ func (r *VPNRouteResource) Update(ctx context.Context, req resource.UpdateReques
 }
 
 func (r *VPNRouteResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	ctx = withCorrelationID(ctx)
 	var data VPNRouteResourceModel
 	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	timeout := r.client.operationTimeout(ctx, data.Timeouts.Delete, data.Timeout, &resp.Diagnostics)
 	if resp.Diagnostics.HasError() {
 		return
 	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	if !r.client.checkGuardrails("delete", "VPNRoute", data.ProjectId, &resp.Diagnostics) {
+		return
+	}
 
 	ref := vpnRouteRef(&data)
 	routeID := data.Id.ValueString()
@@ -354,12 +400,12 @@ func (r *VPNRouteResource) Delete(ctx context.Context, req resource.DeleteReques
 	err := DeleteResourceWithRetry(ctx, func() error {
 		return CheckResponseErrAsError("delete", "VPNRoute",
 			r.client.Client.FromNetwork().VPNRoutes().Delete(ctx, ref))
-	}, "VPNRoute", routeID, effectiveTimeout(data.Timeout, r.client.ResourceTimeout), deletionChecker)
+	}, "VPNRoute", routeID, timeout, deletionChecker)
 	if err != nil {
 		resp.Diagnostics.AddError("Error deleting VPNRoute", err.Error())
 		return
 	}
-	if waitErr := WaitForResourceDeleted(ctx, deletionChecker, "VPNRoute", routeID, remainingTimeout(deleteStart, effectiveTimeout(data.Timeout, r.client.ResourceTimeout))); waitErr != nil {
+	if waitErr := WaitForResourceDeleted(ctx, deletionChecker, "VPNRoute", routeID, remainingTimeout(deleteStart, timeout)); waitErr != nil {
 		resp.Diagnostics.AddError("Error waiting for VPNRoute deletion", waitErr.Error())
 		return
 	}
@@ -82,11 +82,15 @@ func (d *VPNTunnelDataSource) Configure(ctx context.Context, req datasource.Conf
 }
 
 func (d *VPNTunnelDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	ctx = withCorrelationID(ctx)
 	var data VPNTunnelDataSourceModel
 	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
+	if !d.client.checkGuardrails("read", "VPNTunnel", data.ProjectId, &resp.Diagnostics) {
+		return
+	}
 
 	projectID := data.ProjectId.ValueString()
 	tunnelID := data.Id.ValueString()
@@ -98,7 +102,7 @@ func (d *VPNTunnelDataSource) Read(ctx context.Context, req datasource.ReadReque
 	tunnel, err := d.client.Client.FromNetwork().VPNTunnels().Get(ctx,
 		aruba.VPNTunnelRef(projectID, tunnelID))
 	if provErr := CheckResponseErr("read", "VPNTunnel", err); provErr != nil {
-		resp.Diagnostics.AddError("API Error", provErr.Error())
+		AppendAPIError(&resp.Diagnostics, provErr)
 		return
 	}
 
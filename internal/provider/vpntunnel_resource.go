@@ -7,18 +7,24 @@ import (
 	"time"
 
 	aruba "github.com/Arubacloud/sdk-go/pkg/aruba"
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
 	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
 )
 
 var _ resource.Resource = &VPNTunnelResource{}
+var _ resource.ResourceWithModifyPlan = &VPNTunnelResource{}
 var _ resource.ResourceWithImportState = &VPNTunnelResource{}
 
 func NewVPNTunnelResource() resource.Resource {
@@ -26,14 +32,16 @@ func NewVPNTunnelResource() resource.Resource {
 }
 
 type VPNTunnelResourceModel struct {
-	Id         types.String `tfsdk:"id"`
-	Uri        types.String `tfsdk:"uri"`
-	Name       types.String `tfsdk:"name"`
-	Location   types.String `tfsdk:"location"`
-	Tags       types.List   `tfsdk:"tags"`
-	ProjectId  types.String `tfsdk:"project_id"`
-	Properties types.Object `tfsdk:"properties"`
-	Timeout    types.String `tfsdk:"timeout"`
+	Id         types.String   `tfsdk:"id"`
+	Uri        types.String   `tfsdk:"uri"`
+	Name       types.String   `tfsdk:"name"`
+	Location   types.String   `tfsdk:"location"`
+	Tags       types.List     `tfsdk:"tags"`
+	TagsAll    types.List     `tfsdk:"tags_all"`
+	ProjectId  types.String   `tfsdk:"project_id"`
+	Properties types.Object   `tfsdk:"properties"`
+	Timeout    types.String   `tfsdk:"timeout"`
+	Timeouts   timeouts.Value `tfsdk:"timeouts"`
 }
 
 type VPNTunnelResource struct {
@@ -67,20 +75,26 @@ func (r *VPNTunnelResource) Schema(ctx context.Context, req resource.SchemaReque
 				Required:            true,
 			},
 			"location": schema.StringAttribute{
-				MarkdownDescription: "Region identifier for the resource (e.g., `ITBG-Bergamo`). See the [available locations and zones](https://api.arubacloud.com/docs/metadata/#location-and-data-center).",
-				Required:            true,
+				MarkdownDescription: defaultedDescription("Region identifier for the resource (e.g., `ITBG-Bergamo`). See the [available locations and zones](https://api.arubacloud.com/docs/metadata/#location-and-data-center).", "location"),
+				Optional:            true,
+				Computed:            true,
+				PlanModifiers:       []planmodifier.String{stringplanmodifier.UseStateForUnknown()},
 			},
 			"tags": schema.ListAttribute{
 				ElementType:         types.StringType,
 				MarkdownDescription: "List of string tags attached to the resource for filtering and organisation.",
 				Optional:            true,
 			},
+			"tags_all": tagsAllAttribute(),
 			"project_id": schema.StringAttribute{
-				MarkdownDescription: "ID of the project that owns this resource.",
-				Required:            true,
+				MarkdownDescription: defaultedDescription("ID of the project that owns this resource.", "project_id"),
+				Optional:            true,
+				Computed:            true,
+				PlanModifiers:       []planmodifier.String{stringplanmodifier.UseStateForUnknown()},
 			},
 			"timeout": schema.StringAttribute{
-				MarkdownDescription: "Per-resource timeout override (e.g. `\"15m\"`, `\"1h\"`). Overrides the provider-level `resource_timeout` for this resource's Create and Delete operations. Uses Go duration syntax.",
+				MarkdownDescription: "Per-resource timeout override (e.g. `\"15m\"`, `\"1h\"`) applied to every operation not set in the `timeouts` block. Overrides the provider-level `resource_timeout`. Uses Go duration syntax. Deprecated — use the `timeouts` block instead.",
+				DeprecationMessage:  "Use the timeouts block, which sets the create, read, update and delete timeouts individually.",
 				Optional:            true,
 			},
 			"properties": schema.SingleNestedAttribute{
@@ -211,19 +225,66 @@ func (r *VPNTunnelResource) Schema(ctx context.Context, req resource.SchemaReque
 								Optional:            true,
 								Attributes: map[string]schema.Attribute{
 									"cloud_site": schema.StringAttribute{
-										MarkdownDescription: "Pre-shared key for the ArubaCloud side of the tunnel.",
+										MarkdownDescription: "Pre-shared key for the ArubaCloud side of the tunnel. Deprecated — the value is stored in Terraform state; use `cloud_site_wo` instead.",
 										Optional:            true,
+										Sensitive:           true,
+										DeprecationMessage:  "Use cloud_site_wo (Terraform 1.11+) so the key is never stored in state.",
+										Validators: []validator.String{
+											stringvalidator.ConflictsWith(path.MatchRelative().AtParent().AtName("cloud_site_wo")),
+										},
 									},
 									"on_prem_site": schema.StringAttribute{
-										MarkdownDescription: "Pre-shared key for the on-premises side of the tunnel.",
+										MarkdownDescription: "Pre-shared key for the on-premises side of the tunnel. Deprecated — the value is stored in Terraform state; use `on_prem_site_wo` instead.",
 										Optional:            true,
+										Sensitive:           true,
+										DeprecationMessage:  "Use on_prem_site_wo (Terraform 1.11+) so the key is never stored in state.",
+										Validators: []validator.String{
+											stringvalidator.ConflictsWith(path.MatchRelative().AtParent().AtName("on_prem_site_wo")),
+										},
 									},
 									"secret": schema.StringAttribute{
-										MarkdownDescription: "Shared secret used to authenticate the VPN tunnel. Write-only — this value is sent to the API but is not returned in subsequent read responses.",
+										MarkdownDescription: "Shared secret used to authenticate the VPN tunnel. Deprecated — the value is stored in Terraform state; use `secret_wo` instead.",
 										Optional:            true,
+										Sensitive:           true,
+										DeprecationMessage:  "Use secret_wo (Terraform 1.11+) so the secret is never stored in state.",
+										Validators: []validator.String{
+											stringvalidator.ConflictsWith(path.MatchRelative().AtParent().AtName("secret_wo")),
+										},
+									},
+									"cloud_site_wo": schema.StringAttribute{
+										MarkdownDescription: "Pre-shared key for the ArubaCloud side of the tunnel. Write-only (Terraform 1.11+) — the value is sent to the API but never stored in plan or state.",
+										Optional:            true,
+										Sensitive:           true,
+										WriteOnly:           true,
+									},
+									"on_prem_site_wo": schema.StringAttribute{
+										MarkdownDescription: "Pre-shared key for the on-premises side of the tunnel. Write-only (Terraform 1.11+) — the value is sent to the API but never stored in plan or state.",
+										Optional:            true,
+										Sensitive:           true,
+										WriteOnly:           true,
+									},
+									"secret_wo": schema.StringAttribute{
+										MarkdownDescription: "Shared secret used to authenticate the VPN tunnel. Write-only (Terraform 1.11+) — the value is sent to the API but never stored in plan or state.",
+										Optional:            true,
+										Sensitive:           true,
+										WriteOnly:           true,
 									},
 								},
 							},
+							"psk_wo_version": schema.Int64Attribute{
+								MarkdownDescription: "Version of the `psk.*_wo` values. Terraform cannot detect changes to write-only values, so change this number whenever any of them changes. Tunnel properties cannot be updated in place, so changing this value forces the tunnel to be destroyed and re-created.",
+								Optional:            true,
+								PlanModifiers: []planmodifier.Int64{
+									int64planmodifier.RequiresReplace(),
+								},
+								Validators: []validator.Int64{
+									int64validator.Any(
+										int64validator.AlsoRequires(path.MatchRelative().AtParent().AtName("psk").AtName("secret_wo")),
+										int64validator.AlsoRequires(path.MatchRelative().AtParent().AtName("psk").AtName("cloud_site_wo")),
+										int64validator.AlsoRequires(path.MatchRelative().AtParent().AtName("psk").AtName("on_prem_site_wo")),
+									),
+								},
+							},
 							"peer_client_public_ip": schema.StringAttribute{
 								MarkdownDescription: "Public IP address of the remote peer (on-premises gateway).",
 								Optional:            true,
@@ -233,6 +294,9 @@ func (r *VPNTunnelResource) Schema(ctx context.Context, req resource.SchemaReque
 				},
 			},
 		},
+		Blocks: map[string]schema.Block{
+			"timeouts": timeouts.BlockAll(ctx),
+		},
 	}
 }
 
@@ -252,24 +316,23 @@ func (r *VPNTunnelResource) Configure(ctx context.Context, req resource.Configur
 }
 
 func vpnTunnelRef(data *VPNTunnelResourceModel) aruba.Ref {
-	if !data.Uri.IsNull() && data.Uri.ValueString() != "" {
-		return aruba.URI(data.Uri.ValueString())
-	}
-	return aruba.VPNTunnelRef(data.ProjectId.ValueString(), data.Id.ValueString())
+	return uriRef(data.Uri, newResourceURI(uriKindVPNTunnel, data.ProjectId.ValueString(), data.Id.ValueString()))
 }
 
 // buildVPNTunnel constructs a *aruba.VPNTunnel builder from model properties.
-func buildVPNTunnel(_ context.Context, data *VPNTunnelResourceModel, tags []string) *aruba.VPNTunnel {
+// PSK write-only values are read from config, as they are always null in the plan.
+func buildVPNTunnel(ctx context.Context, config tfsdk.Config, data *VPNTunnelResourceModel, tags []string) (*aruba.VPNTunnel, diag.Diagnostics) {
+	var diags diag.Diagnostics
 	projectID := data.ProjectId.ValueString()
 
 	builder := aruba.NewVPNTunnel().
 		Named(data.Name.ValueString()).
-		InProject(aruba.URI("/projects/" + projectID)).
+		InProject(newResourceURI(uriKindProject, projectID, "").Ref()).
 		InRegion(aruba.Region(data.Location.ValueString())).
 		Tagged(tags...)
 
 	if data.Properties.IsNull() || data.Properties.IsUnknown() {
-		return builder
+		return builder, diags
 	}
 	attrs := data.Properties.Attributes()
 
@@ -301,7 +364,7 @@ func buildVPNTunnel(_ context.Context, data *VPNTunnelResourceModel, tags []stri
 						if s, ok := idAttr.(types.String); ok && !s.IsNull() {
 							vpcID := s.ValueString()
 							if !strings.HasPrefix(vpcID, "/") {
-								vpcID = fmt.Sprintf("/projects/%s/providers/Aruba.Network/vpcs/%s", projectID, vpcID)
+								vpcID = newResourceURI(uriKindVPC, projectID, vpcID).String()
 							}
 							ipCfg.WithVPC(aruba.URI(vpcID))
 						}
@@ -334,7 +397,7 @@ func buildVPNTunnel(_ context.Context, data *VPNTunnelResourceModel, tags []stri
 						if s, ok := idAttr.(types.String); ok && !s.IsNull() {
 							eipID := s.ValueString()
 							if !strings.HasPrefix(eipID, "/") {
-								eipID = fmt.Sprintf("/projects/%s/providers/Aruba.Network/elasticips/%s", projectID, eipID)
+								eipID = newResourceURI(uriKindElasticIP, projectID, eipID).String()
 							}
 							ipCfg.WithElasticIP(aruba.URI(eipID))
 						}
@@ -431,20 +494,21 @@ func buildVPNTunnel(_ context.Context, data *VPNTunnelResourceModel, tags []stri
 				if pskObj, ok := pskAttr.(types.Object); ok && !pskObj.IsNull() {
 					psk := aruba.NewVPNPSK()
 					pskAttrs := pskObj.Attributes()
-					if v, ok := pskAttrs["cloud_site"]; ok {
-						if s, ok := v.(types.String); ok && !s.IsNull() {
-							psk.WithCloudSite(s.ValueString())
-						}
+					pskPath := path.Root("properties").AtName("vpn_client_settings").AtName("psk")
+					pskValue := func(name string) types.String {
+						deprecated, _ := pskAttrs[name].(types.String)
+						v, d := writeOnlyOrDeprecated(ctx, config, pskPath.AtName(name+"_wo"), deprecated)
+						diags.Append(d...)
+						return v
 					}
-					if v, ok := pskAttrs["on_prem_site"]; ok {
-						if s, ok := v.(types.String); ok && !s.IsNull() {
-							psk.WithOnPremSite(s.ValueString())
-						}
+					if s := pskValue("cloud_site"); !s.IsNull() {
+						psk.WithCloudSite(s.ValueString())
 					}
-					if v, ok := pskAttrs["secret"]; ok {
-						if s, ok := v.(types.String); ok && !s.IsNull() {
-							psk.WithKey(s.ValueString())
-						}
+					if s := pskValue("on_prem_site"); !s.IsNull() {
+						psk.WithOnPremSite(s.ValueString())
+					}
+					if s := pskValue("secret"); !s.IsNull() {
+						psk.WithKey(s.ValueString())
 					}
 					builder = builder.WithPSKSettings(psk)
 				}
@@ -452,24 +516,45 @@ func buildVPNTunnel(_ context.Context, data *VPNTunnelResourceModel, tags []stri
 		}
 	}
 
-	return builder
+	return builder, diags
+}
+
+func (r *VPNTunnelResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	r.client.planTagsAll(ctx, req, resp, true)
+	r.client.planProviderDefaults(ctx, req, resp,
+		providerDefault{attr: "project_id", replace: false},
+		providerDefault{attr: "location", replace: false},
+	)
 }
 
 func (r *VPNTunnelResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	ctx = withCorrelationID(ctx)
 	var data VPNTunnelResourceModel
 	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	timeout := r.client.operationTimeout(ctx, data.Timeouts.Create, data.Timeout, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	if !r.client.checkGuardrails("create", "VPNTunnel", data.ProjectId, &resp.Diagnostics) {
+		return
+	}
+
+	tags := r.client.requestTags(ctx, data.Tags, &data.TagsAll, &resp.Diagnostics)
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
-	tags := ListToTags(ctx, data.Tags, &resp.Diagnostics)
+	builder, diags := buildVPNTunnel(ctx, req.Config, &data, tags)
+	resp.Diagnostics.Append(diags...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
-	tunnel, err := r.client.Client.FromNetwork().VPNTunnels().Create(ctx, buildVPNTunnel(ctx, &data, tags))
+	tunnel, err := r.client.Client.FromNetwork().VPNTunnels().Create(ctx, builder)
 	if provErr := CheckResponseErr("create", "VPNTunnel", err); provErr != nil {
-		resp.Diagnostics.AddError("API Error", provErr.Error())
+		AppendAPIError(&resp.Diagnostics, provErr)
 		return
 	}
 
@@ -481,7 +566,7 @@ func (r *VPNTunnelResource) Create(ctx context.Context, req resource.CreateReque
 		return
 	}
 
-	if waitErr := tunnel.WaitUntilReady(ctx, sdkWaitOptions(effectiveTimeout(data.Timeout, r.client.ResourceTimeout))...); waitErr != nil {
+	if waitErr := tunnel.WaitUntilReady(ctx, sdkWaitOptions(timeout)...); waitErr != nil {
 		ReportWaitResult(&resp.Diagnostics, waitErr, "VPNTunnel", data.Id.ValueString())
 		return
 	}
@@ -494,11 +579,18 @@ func (r *VPNTunnelResource) Create(ctx context.Context, req resource.CreateReque
 }
 
 func (r *VPNTunnelResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	ctx = withCorrelationID(ctx)
 	var data VPNTunnelResourceModel
 	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	timeout := r.client.operationTimeout(ctx, data.Timeouts.Read, data.Timeout, &resp.Diagnostics)
 	if resp.Diagnostics.HasError() {
 		return
 	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	if !r.client.checkGuardrails("read", "VPNTunnel", data.ProjectId, &resp.Diagnostics) {
+		return
+	}
 	if data.Id.IsNull() || data.Id.ValueString() == "" {
 		resp.State.RemoveResource(ctx)
 		return
@@ -510,7 +602,7 @@ func (r *VPNTunnelResource) Read(ctx context.Context, req resource.ReadRequest,
 			resp.State.RemoveResource(ctx)
 			return
 		}
-		resp.Diagnostics.AddError("API Error", provErr.Error())
+		AppendAPIError(&resp.Diagnostics, provErr)
 		return
 	}
 
@@ -521,13 +613,13 @@ func (r *VPNTunnelResource) Read(ctx context.Context, req resource.ReadRequest,
 			fmt.Sprintf("VPNTunnel %q is in a terminal failure state (%s). "+
 				"Run `terraform destroy` to clean it up, or `terraform apply -replace=<address>` to recreate it.", data.Id.ValueString(), st))
 	case IsCreatingState(st):
-		if waitErr := tunnel.WaitUntilReady(ctx, sdkWaitOptions(r.client.ResourceTimeout)...); waitErr != nil {
+		if waitErr := tunnel.WaitUntilReady(ctx, sdkWaitOptions(timeout)...); waitErr != nil {
 			ReportWaitResult(&resp.Diagnostics, waitErr, "VPNTunnel", data.Id.ValueString())
 			return
 		}
 		tunnel, err = r.client.Client.FromNetwork().VPNTunnels().Get(ctx, vpnTunnelRef(&data))
 		if provErr := CheckResponseErr("read", "VPNTunnel", err); provErr != nil {
-			resp.Diagnostics.AddError("API Error", provErr.Error())
+			AppendAPIError(&resp.Diagnostics, provErr)
 			return
 		}
 	}
@@ -535,7 +627,7 @@ func (r *VPNTunnelResource) Read(ctx context.Context, req resource.ReadRequest,
 	data.Id = types.StringValue(tunnel.ID())
 	data.Uri = strVal(tunnel.URI())
 	data.Name = types.StringValue(tunnel.Name())
-	data.Tags = TagsToListPreserveNull(tunnel.Tags(), data.Tags)
+	data.Tags, data.TagsAll = flattenTags(tunnel.Tags(), data.Tags, data.TagsAll)
 	if tunnel.Region() != "" {
 		data.Location = types.StringValue(string(tunnel.Region()))
 	}
@@ -545,23 +637,30 @@ func (r *VPNTunnelResource) Read(ctx context.Context, req resource.ReadRequest,
 }
 
 func (r *VPNTunnelResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	ctx = withCorrelationID(ctx)
 	var data VPNTunnelResourceModel
 	var state VPNTunnelResourceModel
 
 	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
 	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	timeout := r.client.operationTimeout(ctx, data.Timeouts.Update, data.Timeout, &resp.Diagnostics)
 	if resp.Diagnostics.HasError() {
 		return
 	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	if !r.client.checkGuardrails("update", "VPNTunnel", data.ProjectId, &resp.Diagnostics) {
+		return
+	}
 
-	tags := ListToTags(ctx, data.Tags, &resp.Diagnostics)
+	tags := r.client.requestTags(ctx, data.Tags, &data.TagsAll, &resp.Diagnostics)
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
 	tunnel, err := r.client.Client.FromNetwork().VPNTunnels().Get(ctx, vpnTunnelRef(&state))
 	if provErr := CheckResponseErr("read", "VPNTunnel", err); provErr != nil {
-		resp.Diagnostics.AddError("API Error", provErr.Error())
+		AppendAPIError(&resp.Diagnostics, provErr)
 		return
 	}
 
@@ -574,7 +673,7 @@ func (r *VPNTunnelResource) Update(ctx context.Context, req resource.UpdateReque
 
 	updated, err := r.client.Client.FromNetwork().VPNTunnels().Update(ctx, tunnel)
 	if provErr := CheckResponseErr("update", "VPNTunnel", err); provErr != nil {
-		resp.Diagnostics.AddError("API Error", provErr.Error())
+		AppendAPIError(&resp.Diagnostics, provErr)
 		return
 	}
 
@@ -584,17 +683,24 @@ func (r *VPNTunnelResource) Update(ctx context.Context, req resource.UpdateReque
 	data.Location = state.Location
 	data.Properties = state.Properties // Properties are immutable — preserve from state.
 	data.Name = types.StringValue(updated.Name())
-	data.Tags = TagsToListPreserveNull(updated.Tags(), data.Tags)
+	data.Tags, data.TagsAll = flattenTags(updated.Tags(), data.Tags, data.TagsAll)
 
 	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
 }
 
 func (r *VPNTunnelResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	ctx = withCorrelationID(ctx)
 	var data VPNTunnelResourceModel
 	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	timeout := r.client.operationTimeout(ctx, data.Timeouts.Delete, data.Timeout, &resp.Diagnostics)
 	if resp.Diagnostics.HasError() {
 		return
 	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	if !r.client.checkGuardrails("delete", "VPNTunnel", data.ProjectId, &resp.Diagnostics) {
+		return
+	}
 
 	ref := vpnTunnelRef(&data)
 	tunnelID := data.Id.ValueString()
@@ -614,12 +720,12 @@ func (r *VPNTunnelResource) Delete(ctx context.Context, req resource.DeleteReque
 	err := DeleteResourceWithRetry(ctx, func() error {
 		return CheckResponseErrAsError("delete", "VPNTunnel",
 			r.client.Client.FromNetwork().VPNTunnels().Delete(ctx, ref))
-	}, "VPNTunnel", tunnelID, effectiveTimeout(data.Timeout, r.client.ResourceTimeout), deletionChecker)
+	}, "VPNTunnel", tunnelID, timeout, deletionChecker)
 	if err != nil {
 		resp.Diagnostics.AddError("Error deleting VPNTunnel", err.Error())
 		return
 	}
-	if waitErr := WaitForResourceDeleted(ctx, deletionChecker, "VPNTunnel", tunnelID, remainingTimeout(deleteStart, effectiveTimeout(data.Timeout, r.client.ResourceTimeout))); waitErr != nil {
+	if waitErr := WaitForResourceDeleted(ctx, deletionChecker, "VPNTunnel", tunnelID, remainingTimeout(deleteStart, timeout)); waitErr != nil {
 		resp.Diagnostics.AddError("Error waiting for VPNTunnel deletion", waitErr.Error())
 		return
 	}